@@ -0,0 +1,58 @@
+package main
+
+import (
+	"context"
+	"time"
+
+	"github.com/rohanchauhan02/internal-transfer/domain/apikey"
+	"github.com/rohanchauhan02/internal-transfer/domain/audit"
+	"github.com/rohanchauhan02/internal-transfer/domain/banking"
+	BankingServer "github.com/rohanchauhan02/internal-transfer/domain/banking/delivery/grpc"
+	"github.com/rohanchauhan02/internal-transfer/pkg/config"
+	"github.com/rohanchauhan02/internal-transfer/pkg/interceptor"
+	"github.com/rohanchauhan02/internal-transfer/pkg/ratelimit"
+	"google.golang.org/grpc"
+)
+
+// newGRPCServer builds the gRPC server with the interceptor chain mirroring the REST middleware:
+// panic recovery first, then the request ID, error mapping and access logs, authentication, rate
+// limits, auditing and deadlines.
+func newGRPCServer(conf config.GRPC, auth config.Auth, rateLimit config.RateLimit, rateLimitStore ratelimit.Store,
+	apiKeyUsecase apikey.Usecase, auditUsecase audit.Usecase, bankingUsecase banking.Usecase) *grpc.Server {
+	timeout := time.Duration(conf.RequestTimeoutMS) * time.Millisecond
+	s := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(
+			interceptor.UnaryRecovery(),
+			interceptor.UnaryRequestID(),
+			interceptor.UnaryErrors(),
+			interceptor.UnaryAuth(apiKeyUsecase, auth.Enabled, BankingServer.MethodScopes),
+			interceptor.UnaryRateLimit(rateLimit, rateLimitStore, BankingServer.MethodRoutes),
+			interceptor.UnaryAudit(auditUsecase, BankingServer.AuditedMethods),
+			interceptor.UnaryTimeout(timeout),
+		),
+		grpc.ChainStreamInterceptor(
+			interceptor.StreamRecovery(),
+			interceptor.StreamRequestID(),
+			interceptor.StreamErrors(),
+			interceptor.StreamAuth(apiKeyUsecase, auth.Enabled, BankingServer.MethodScopes),
+			interceptor.StreamRateLimit(rateLimit, rateLimitStore, BankingServer.MethodRoutes),
+			interceptor.StreamTimeout(timeout),
+		),
+	)
+	BankingServer.NewBankingServer(s, bankingUsecase)
+	return s
+}
+
+// stopGRPC lets in-flight calls finish until ctx ends, then closes the remaining connections.
+func stopGRPC(ctx context.Context, s *grpc.Server) {
+	done := make(chan struct{})
+	go func() {
+		s.GracefulStop()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-ctx.Done():
+		s.Stop()
+	}
+}
@@ -2,7 +2,12 @@ package main
 
 import (
 	"context"
+	"database/sql"
+	"errors"
+	"flag"
 	"fmt"
+	"log/slog"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
@@ -11,85 +16,210 @@ import (
 
 	"github.com/labstack/echo/v4"
 	"github.com/labstack/echo/v4/middleware"
-	"github.com/labstack/gommon/log"
 
+	"github.com/rohanchauhan02/internal-transfer/docs"
+	APIKeyHandler "github.com/rohanchauhan02/internal-transfer/domain/apikey/delivery/https"
+	APIKeyUsecase "github.com/rohanchauhan02/internal-transfer/domain/apikey/usecase"
+	AuditHandler "github.com/rohanchauhan02/internal-transfer/domain/audit/delivery/https"
+	AuditUsecase "github.com/rohanchauhan02/internal-transfer/domain/audit/usecase"
 	BankingHandler "github.com/rohanchauhan02/internal-transfer/domain/banking/delivery/https"
-	BankingRepository "github.com/rohanchauhan02/internal-transfer/domain/banking/repository"
 	BankingUsecase "github.com/rohanchauhan02/internal-transfer/domain/banking/usecase"
 	HealthzHandler "github.com/rohanchauhan02/internal-transfer/domain/health/delivery/https"
-	HealthzRepository "github.com/rohanchauhan02/internal-transfer/domain/health/repository"
 	HealthzUsecase "github.com/rohanchauhan02/internal-transfer/domain/health/usecase"
 	"github.com/rohanchauhan02/internal-transfer/utils"
 
-	"github.com/rohanchauhan02/internal-transfer/models"
 	"github.com/rohanchauhan02/internal-transfer/pkg/config"
 	"github.com/rohanchauhan02/internal-transfer/pkg/ctx"
 	"github.com/rohanchauhan02/internal-transfer/pkg/database"
+	"github.com/rohanchauhan02/internal-transfer/pkg/logger"
+	"github.com/rohanchauhan02/internal-transfer/pkg/metrics"
+	"github.com/rohanchauhan02/internal-transfer/pkg/migrations"
+	"github.com/rohanchauhan02/internal-transfer/pkg/ratelimit"
+	"github.com/rohanchauhan02/internal-transfer/pkg/tracing"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/labstack/echo/otelecho"
+	"google.golang.org/grpc"
+	"gorm.io/gorm"
+	gormtracing "gorm.io/plugin/opentelemetry/tracing"
 
 	CustomMiddileware "github.com/rohanchauhan02/internal-transfer/pkg/middleware"
 )
 
 func main() {
+	storage := flag.String("storage", storageDatabase, "storage backend: database (as configured in DB.DRIVER) or memory")
+	flag.Parse()
+
 	e := echo.New()
+	e.HideBanner = true
+	e.HidePort = true
 
 	// Load configuration
 	cnf := config.NewImmutableConfigs()
 
-	// Initialize PostgreSQL client
-	postgresClient := database.NewPostgres(cnf)
-	db, err := postgresClient.InitClient(context.Background())
+	// Configure structured logging before anything else writes logs
+	logger.Init(cnf.GetLoggingConf())
+	log := logger.For("app")
+
+	// `migrate up|down|status` manages the schema and exits without starting the server
+	if flag.Arg(0) == "migrate" {
+		_, sqlDB := openDatabase(cnf, log)
+		if err := migrations.RunCommand(context.Background(), sqlDB, migrationDialect(cnf), flag.Args()[1:], os.Stdout); err != nil {
+			fatal(log, "migration failed", err)
+		}
+		return
+	}
+
+	// Initialize tracing before anything that creates spans
+	shutdownTracing, err := tracing.Init(context.Background(), cnf.GetTracingConf())
 	if err != nil {
-		log.Panicf("Failed to initialize database: %s ", err.Error())
+		fatal(log, "failed to initialize tracing", err)
 	}
-	// Auto migrate models
-	if err := db.AutoMigrate(
-		&models.Account{},
-		&models.Transaction{},
-	); err != nil {
-		log.Panicf("Failed to auto migrate models: %s ", err.Error())
+
+	var repos repositories
+	switch *storage {
+	case storageDatabase:
+		db, sqlDB := openDatabase(cnf, log)
+
+		// Refuse to serve against a schema this binary was not built for
+		migrator, err := migrations.New(sqlDB, migrationDialect(cnf))
+		if err != nil {
+			fatal(log, "failed to load migrations", err)
+		}
+		if err := migrator.Check(context.Background()); err != nil {
+			fatal(log, "database schema mismatch, run `migrate up` with the matching release", err)
+		}
+
+		// Trace every GORM statement; bound values are left out so balances never reach the exporter
+		if err := db.Use(gormtracing.NewPlugin(gormtracing.WithoutMetrics(), gormtracing.WithoutQueryVariables())); err != nil {
+			fatal(log, "failed to enable database tracing", err)
+		}
+
+		// Expose connection pool statistics alongside the request metrics
+		if err := metrics.RegisterDBStats(sqlDB, cnf.GetDBConf().Name); err != nil {
+			log.Error("failed to register database metrics", slog.String("error", err.Error()))
+		}
+		repos = newDatabaseRepositories(db)
+	case storageMemory:
+		log.Warn("using in-memory storage, all data is lost when the server stops")
+		repos = newMemoryRepositories()
+	default:
+		fatal(log, "invalid storage backend", fmt.Errorf("unknown storage %q", *storage))
+	}
+
+	// Start the HTTP server span from any incoming traceparent, then derive the request ID from it
+	e.Use(otelecho.Middleware(cnf.GetTracingConf().ServiceName, otelecho.WithSkipper(func(c echo.Context) bool {
+		return c.Path() == "/metrics"
+	})))
+
+	// Resolve client addresses for api key allow lists and rate limits without trusting
+	// forwarded headers from arbitrary clients
+	ipExtractor, err := CustomMiddileware.IPExtractor(cnf.GetHTTPConf())
+	if err != nil {
+		fatal(log, "invalid configuration", err)
 	}
+	e.IPExtractor = ipExtractor
 
 	// add request ID middleware
 	e.Use(CustomMiddileware.MiddlewareRequestID())
 	e.Pre(middleware.RemoveTrailingSlash())
-	e.Use(middleware.Logger())
+	e.Use(CustomMiddileware.MiddlewareLogContext(cnf.GetHTTPConf()))
+	e.Use(CustomMiddileware.MiddlewareRequestLogger())
+	e.Use(CustomMiddileware.MiddlewareMetrics())
 	e.Use(middleware.Recover())
+	e.Use(CustomMiddileware.MiddlewareTimeout(cnf.GetHTTPConf()))
 	e.Use(middleware.CORS())
 	e.Use(middleware.Gzip())
 	e.Use(middleware.CORS())
 
-	// Middleware to inject dependencies into the request context
+	// Middleware to wrap the request in the application context
 	e.Use(func(next echo.HandlerFunc) echo.HandlerFunc {
 		return func(c echo.Context) error {
 			customCtx := &ctx.CustomApplicationContext{
-				Context:    c,
-				PostgresDB: db,
+				Context: c,
 			}
 			return next(customCtx)
 		}
 	})
 
+	// Set up api key authentication; the health check, metrics and API docs stay public for probes,
+	// scrapers and browsers
+	apiKeyUsecase := APIKeyUsecase.NewAPIKeyUsecase(repos.apiKey, cnf.GetAuthConf())
+	defer apiKeyUsecase.Close()
+	e.Use(CustomMiddileware.MiddlewareAPIKey(apiKeyUsecase, cnf.GetAuthConf().Enabled,
+		"/api/v1/healthz", "/metrics", docs.SpecPath, docs.UIPath, docs.UIPath+"/"))
+
+	// Throttle clients per the configured route rules
+	var rateLimitStore ratelimit.Store
+	switch {
+	case cnf.GetRateLimitConf().Store == "postgres" && repos.db != nil && migrationDialect(cnf) == migrations.Postgres:
+		rateLimitStore = ratelimit.NewPostgresStore(repos.db)
+	default:
+		rateLimitStore = ratelimit.NewMemoryStore()
+	}
+	e.Use(CustomMiddileware.MiddlewareRateLimit(cnf.GetRateLimitConf(), rateLimitStore))
+
+	// Replay responses to retried requests that carry an Idempotency-Key, before they are audited again
+	idempotencyStore := CustomMiddileware.NewMemoryIdempotencyStore(cnf.GetHTTPConf().IdempotencyMaxEntries)
+	defer idempotencyStore.Close()
+	e.Use(CustomMiddileware.MiddlewareIdempotency(cnf.GetHTTPConf(), idempotencyStore))
+
+	// Record every state-changing request in the audit trail
+	auditUsecase := AuditUsecase.NewAuditUsecase(repos.audit)
+	e.Use(CustomMiddileware.MiddlewareAudit(auditUsecase))
+
 	// Set validator globally
 	validator := utils.DefaultValidator()
 	e.Validator = validator
 
-	// Set up repositories for subdomains
-	healthzRepo := HealthzRepository.NewHealthRepository(db)
-	bankingRepo := BankingRepository.NewBankingRepository(db)
-
 	// Set up use cases for subdomains
-	healthzUsecase := HealthzUsecase.NewHealthUsecase(healthzRepo)
-	bankingUsecase := BankingUsecase.NewBankingUsecase(bankingRepo)
+	healthzUsecase := HealthzUsecase.NewHealthUsecase(repos.health)
+	bankingUsecase := BankingUsecase.NewBankingUsecase(repos.bankingUoW, repos.banking, cnf.GetBankingConf())
 
 	// Set up handlers for subdomains
+	e.GET("/metrics", echo.WrapHandler(metrics.Handler()))
 	HealthzHandler.NewHealthHandler(e, healthzUsecase)
-	BankingHandler.NewBankingHandler(e, bankingUsecase)
+	nonceStore := CustomMiddileware.NewMemoryNonceStore(cnf.GetSigningConf().MaxNonces)
+	defer nonceStore.Close()
+	signatureMiddleware := CustomMiddileware.MiddlewareSignature(cnf.GetSigningConf(), cnf.GetHTTPConf(), nonceStore)
+	BankingHandler.NewBankingHandler(e, bankingUsecase, signatureMiddleware)
+	APIKeyHandler.NewAPIKeyHandler(e, apiKeyUsecase)
+	AuditHandler.NewAuditHandler(e, auditUsecase)
+	docs.NewDocsHandler(e)
+
+	// Serve the same usecases over gRPC on a separate port
+	var grpcServer *grpc.Server
+	if grpcConf := cnf.GetGRPCConf(); grpcConf.Enabled {
+		// gRPC calls carry no HMAC signature, so serving them would bypass required signing
+		if cnf.GetSigningConf().Required {
+			fatal(log, "invalid configuration", errors.New("GRPC.ENABLED cannot be combined with SIGNING.REQUIRED, "+
+				"gRPC calls are not signed"))
+		}
+		grpcServer = newGRPCServer(grpcConf, cnf.GetAuthConf(), cnf.GetRateLimitConf(), rateLimitStore,
+			apiKeyUsecase, auditUsecase, bankingUsecase)
+		grpcAddr := fmt.Sprintf(":%d", grpcConf.Port)
+		lis, err := net.Listen("tcp", grpcAddr)
+		if err != nil {
+			fatal(log, "failed to listen for grpc", err)
+		}
+		go func() {
+			log.Info("starting grpc server", slog.String("addr", grpcAddr))
+			if err := grpcServer.Serve(lis); err != nil {
+				fatal(log, "grpc server shutdown unexpectedly", err)
+			}
+		}()
+	}
+
+	// Request contexts derive from baseCtx, so cancelling it aborts in-flight database work
+	// that outlives the shutdown grace period
+	baseCtx, cancelBase := context.WithCancel(context.Background())
+	defer cancelBase()
+	e.Server.BaseContext = func(net.Listener) context.Context { return baseCtx }
 
 	// Start server in a separate goroutine
 	serverAddr := fmt.Sprintf(":%d", cnf.GetPort())
 	go func() {
+		log.Info("starting server", slog.String("addr", serverAddr))
 		if err := e.Start(serverAddr); err != nil && err != http.ErrServerClosed {
-			log.Fatalf("Server shutdown unexpectedly: %v", err)
+			fatal(log, "server shutdown unexpectedly", err)
 		}
 	}()
 
@@ -98,13 +228,47 @@ func main() {
 	signal.Notify(quit, os.Interrupt, syscall.SIGTERM)
 	<-quit
 
-	log.Info("Shutting down server...")
+	log.Info("shutting down server")
 
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
 	if err := e.Shutdown(ctx); err != nil {
-		log.Errorf("Server forced to shutdown: %v", err)
+		log.Error("server forced to shutdown", slog.String("error", err.Error()))
+	}
+	if grpcServer != nil {
+		stopGRPC(ctx, grpcServer)
 	}
-	log.Info("Server exited properly.")
+	cancelBase()
+	if err := shutdownTracing(ctx); err != nil {
+		log.Error("failed to flush traces", slog.String("error", err.Error()))
+	}
+	log.Info("server exited properly")
+}
+
+// openDatabase connects to the configured database, exiting when it is unreachable.
+func openDatabase(cnf config.ImmutableConfigs, log *slog.Logger) (*gorm.DB, *sql.DB) {
+	db, err := database.Open(context.Background(), cnf)
+	if err != nil {
+		fatal(log, "failed to initialize database", err)
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		fatal(log, "failed to get database handle", err)
+	}
+	return db, sqlDB
+}
+
+// migrationDialect returns the migration set matching DB.DRIVER.
+func migrationDialect(cnf config.ImmutableConfigs) migrations.Dialect {
+	if cnf.GetDBConf().Driver == database.DriverSQLite {
+		return migrations.SQLite
+	}
+	return migrations.Postgres
+}
+
+// fatal logs err and exits the process; deferred cleanups do not run.
+func fatal(log *slog.Logger, msg string, err error) {
+	log.Error(msg, slog.String("error", err.Error()))
+	os.Exit(1)
 }
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"github.com/rohanchauhan02/internal-transfer/domain/apikey"
+	APIKeyRepository "github.com/rohanchauhan02/internal-transfer/domain/apikey/repository"
+	"github.com/rohanchauhan02/internal-transfer/domain/audit"
+	AuditRepository "github.com/rohanchauhan02/internal-transfer/domain/audit/repository"
+	"github.com/rohanchauhan02/internal-transfer/domain/banking"
+	BankingRepository "github.com/rohanchauhan02/internal-transfer/domain/banking/repository"
+	"github.com/rohanchauhan02/internal-transfer/domain/health"
+	HealthzRepository "github.com/rohanchauhan02/internal-transfer/domain/health/repository"
+	"gorm.io/gorm"
+)
+
+// Storage backends accepted by the --storage flag.
+const (
+	storageDatabase = "database"
+	storageMemory   = "memory"
+)
+
+// repositories holds the storage-backed dependencies of every subdomain.
+type repositories struct {
+	apiKey     apikey.Repository
+	audit      audit.Repository
+	health     health.Repository
+	banking    banking.Repository
+	bankingUoW banking.UnitOfWork
+	// db is nil when the service runs on in-memory storage.
+	db *gorm.DB
+}
+
+func newDatabaseRepositories(db *gorm.DB) repositories {
+	return repositories{
+		apiKey:     APIKeyRepository.NewAPIKeyRepository(db),
+		audit:      AuditRepository.NewAuditRepository(db),
+		health:     HealthzRepository.NewHealthRepository(db),
+		banking:    BankingRepository.NewBankingRepository(db),
+		bankingUoW: BankingRepository.NewUnitOfWork(db),
+		db:         db,
+	}
+}
+
+// newMemoryRepositories keeps everything in process memory, for demos; nothing survives a restart.
+func newMemoryRepositories() repositories {
+	store := BankingRepository.NewMemoryStore()
+	return repositories{
+		apiKey:     APIKeyRepository.NewMemoryAPIKeyRepository(),
+		audit:      AuditRepository.NewMemoryAuditRepository(),
+		health:     HealthzRepository.NewMemoryHealthRepository(),
+		banking:    store,
+		bankingUoW: store,
+	}
+}
@@ -0,0 +1,427 @@
+// Package client is a typed Go client for the internal-transfer REST API.
+//
+// State-changing calls carry an Idempotency-Key that is generated once per call and reused for
+// every retry, so a transfer whose response was lost is not applied twice. Calls are retried with
+// exponential backoff on transport errors, 429 and 5xx responses, honouring Retry-After. Error
+// responses are decoded into *APIError, which matches the sentinel errors with errors.Is.
+//
+//	c := client.New(client.Config{BaseURL: "http://localhost:11001", APIKey: key})
+//	err := c.Transfer(ctx, dto.TransactionRequest{SourceAccountID: 1, DestinationAccountID: 2, Amount: "10"})
+//	if errors.Is(err, client.ErrInsufficientBalance) { ... }
+package client
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	mrand "math/rand/v2"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rohanchauhan02/internal-transfer/dto"
+	"github.com/rohanchauhan02/internal-transfer/pkg/signing"
+)
+
+// Headers understood by the API.
+const (
+	HeaderAPIKey         = "X-API-Key"
+	HeaderRequestID      = "X-Request-ID"
+	HeaderIdempotencyKey = "Idempotency-Key"
+	HeaderRetryAfter     = "Retry-After"
+)
+
+// Defaults applied to zero Config fields.
+const (
+	DefaultTimeout    = 10 * time.Second
+	DefaultMaxRetries = 3
+	DefaultMinBackoff = 100 * time.Millisecond
+	DefaultMaxBackoff = 5 * time.Second
+)
+
+// Config configures a Client.
+type Config struct {
+	// BaseURL is the server address, e.g. http://localhost:11001.
+	BaseURL string
+	APIKey  string
+	// SigningClientID and SigningSecret sign transfers when both are set.
+	SigningClientID string
+	SigningSecret   string
+	// HTTPClient defaults to a client with DefaultTimeout per attempt.
+	HTTPClient *http.Client
+	// MaxRetries is the number of retries after the first attempt; negative disables retries.
+	MaxRetries int
+	// MinBackoff and MaxBackoff bound the jittered exponential delay between attempts.
+	MinBackoff time.Duration
+	MaxBackoff time.Duration
+}
+
+// Client calls the internal-transfer API. It is safe for concurrent use.
+type Client struct {
+	baseURL    string
+	apiKey     string
+	signer     *signing.Signer
+	httpClient *http.Client
+	maxRetries int
+	minBackoff time.Duration
+	maxBackoff time.Duration
+	// sleep waits between attempts and is only overridden in tests.
+	sleep func(ctx context.Context, d time.Duration) error
+}
+
+// New creates a Client from conf.
+func New(conf Config) *Client {
+	c := &Client{
+		baseURL:    strings.TrimSuffix(conf.BaseURL, "/"),
+		apiKey:     conf.APIKey,
+		httpClient: conf.HTTPClient,
+		maxRetries: conf.MaxRetries,
+		minBackoff: conf.MinBackoff,
+		maxBackoff: conf.MaxBackoff,
+		sleep:      sleepContext,
+	}
+	if conf.SigningClientID != "" && conf.SigningSecret != "" {
+		c.signer = signing.NewSigner(conf.SigningClientID, conf.SigningSecret)
+	}
+	if c.httpClient == nil {
+		c.httpClient = &http.Client{Timeout: DefaultTimeout}
+	}
+	switch {
+	case c.maxRetries == 0:
+		c.maxRetries = DefaultMaxRetries
+	case c.maxRetries < 0:
+		c.maxRetries = 0
+	}
+	if c.minBackoff <= 0 {
+		c.minBackoff = DefaultMinBackoff
+	}
+	if c.maxBackoff < c.minBackoff {
+		c.maxBackoff = max(DefaultMaxBackoff, c.minBackoff)
+	}
+	return c
+}
+
+type requestIDKey struct{}
+type idempotencyKey struct{}
+
+// WithRequestID returns a context whose calls send requestID as X-Request-ID, so the server logs,
+// traces and audit records of the call can be correlated with the caller's.
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, requestID)
+}
+
+// RequestIDFromContext returns the request ID set with WithRequestID.
+func RequestIDFromContext(ctx context.Context) (string, bool) {
+	id, ok := ctx.Value(requestIDKey{}).(string)
+	return id, ok && id != ""
+}
+
+// WithIdempotencyKey returns a context whose state-changing call uses key instead of a generated
+// one, e.g. to retry a call across process restarts.
+func WithIdempotencyKey(ctx context.Context, key string) context.Context {
+	return context.WithValue(ctx, idempotencyKey{}, key)
+}
+
+// CreateAccount creates an account with an initial balance.
+func (c *Client) CreateAccount(ctx context.Context, req dto.AccountCreationRequest) error {
+	return c.do(ctx, http.MethodPost, "/api/v1/accounts", req, nil)
+}
+
+// GetAccount returns an account and its balance.
+func (c *Client) GetAccount(ctx context.Context, accountID int) (dto.AccountResponse, error) {
+	var account dto.AccountResponse
+	err := c.do(ctx, http.MethodGet, "/api/v1/accounts/"+strconv.Itoa(accountID), nil, &account)
+	return account, err
+}
+
+// FreezeAccount stops an account from sending or receiving transfers. It needs the admin scope.
+func (c *Client) FreezeAccount(ctx context.Context, accountID int) (dto.AccountResponse, error) {
+	var account dto.AccountResponse
+	err := c.do(ctx, http.MethodPost, "/api/v1/accounts/"+strconv.Itoa(accountID)+"/freeze", nil, &account)
+	return account, err
+}
+
+// UnfreezeAccount lifts a freeze. It needs the admin scope.
+func (c *Client) UnfreezeAccount(ctx context.Context, accountID int) (dto.AccountResponse, error) {
+	var account dto.AccountResponse
+	err := c.do(ctx, http.MethodPost, "/api/v1/accounts/"+strconv.Itoa(accountID)+"/unfreeze", nil, &account)
+	return account, err
+}
+
+// CreateCustomer creates a customer, whose KYC status starts pending.
+func (c *Client) CreateCustomer(ctx context.Context, req dto.CustomerRequest) (dto.CustomerResponse, error) {
+	var customer dto.CustomerResponse
+	err := c.do(ctx, http.MethodPost, "/api/v1/customers", req, &customer)
+	return customer, err
+}
+
+// CreateCustomerAccount creates an account owned by a customer.
+func (c *Client) CreateCustomerAccount(ctx context.Context, customerID uint, req dto.AccountCreationRequest) error {
+	return c.do(ctx, http.MethodPost, customerPath(customerID)+"/accounts", req, nil)
+}
+
+// CustomerAccounts returns a customer with its accounts and their total balance.
+func (c *Client) CustomerAccounts(ctx context.Context, customerID uint) (dto.CustomerAccounts, error) {
+	var accounts dto.CustomerAccounts
+	err := c.do(ctx, http.MethodGet, customerPath(customerID)+"/accounts", nil, &accounts)
+	return accounts, err
+}
+
+// SetKYCStatus changes a customer's KYC status. It needs the admin scope.
+func (c *Client) SetKYCStatus(ctx context.Context, customerID uint, status string) (dto.CustomerResponse, error) {
+	var customer dto.CustomerResponse
+	err := c.do(ctx, http.MethodPut, customerPath(customerID)+"/kyc", dto.KYCStatusRequest{KYCStatus: status}, &customer)
+	return customer, err
+}
+
+func customerPath(customerID uint) string {
+	return "/api/v1/customers/" + strconv.FormatUint(uint64(customerID), 10)
+}
+
+// TransactionQuery narrows ListTransactions; zero fields use the server defaults.
+type TransactionQuery struct {
+	AccountID *int
+	// Reference matches transactions made with this client reference.
+	Reference *string
+	// MetadataKey matches transactions whose metadata has the key, with MetadataValue as its
+	// value when that is set too.
+	MetadataKey   string
+	MetadataValue *string
+	Limit         int
+}
+
+// ListTransactions returns transactions, newest first.
+func (c *Client) ListTransactions(ctx context.Context, query TransactionQuery) ([]dto.TransactionResponse, error) {
+	params := url.Values{}
+	if query.AccountID != nil {
+		params.Set("account_id", strconv.Itoa(*query.AccountID))
+	}
+	if query.Reference != nil {
+		params.Set("reference", *query.Reference)
+	}
+	if query.MetadataKey != "" {
+		params.Set("metadata_key", query.MetadataKey)
+	}
+	if query.MetadataValue != nil {
+		params.Set("metadata_value", *query.MetadataValue)
+	}
+	if query.Limit > 0 {
+		params.Set("limit", strconv.Itoa(query.Limit))
+	}
+	path := "/api/v1/transactions"
+	if len(params) > 0 {
+		path += "?" + params.Encode()
+	}
+	transactions := []dto.TransactionResponse{}
+	err := c.do(ctx, http.MethodGet, path, nil, &transactions)
+	return transactions, err
+}
+
+// ImportAccounts sends an account import CSV. The report is returned with the error when the
+// import has invalid rows or stopped part way; retrying it is safe, as rows whose account already
+// exists with the same values are skipped.
+func (c *Client) ImportAccounts(ctx context.Context, csv io.Reader, opts dto.AccountImportOptions) (dto.AccountImportReport, error) {
+	data, err := io.ReadAll(csv)
+	if err != nil {
+		return dto.AccountImportReport{}, fmt.Errorf("client: read import: %w", err)
+	}
+	params := url.Values{}
+	if opts.DryRun {
+		params.Set("dry_run", "true")
+	}
+	if opts.StartRow > 0 {
+		params.Set("start_row", strconv.Itoa(opts.StartRow))
+	}
+	if opts.ChunkSize > 0 {
+		params.Set("chunk_size", strconv.Itoa(opts.ChunkSize))
+	}
+	path := "/api/v1/accounts/import"
+	if len(params) > 0 {
+		path += "?" + params.Encode()
+	}
+	var report dto.AccountImportReport
+	err = c.do(ctx, http.MethodPost, path, rawBody{contentType: "text/csv", data: data}, &report)
+	return report, err
+}
+
+// Statement returns an account's transactions in [from, to), oldest first, with the running
+// balance. Zero times use the server defaults: to is now and from 30 days before to.
+func (c *Client) Statement(ctx context.Context, accountID int, from, to time.Time) (dto.Statement, error) {
+	params := url.Values{}
+	if !from.IsZero() {
+		params.Set("from", from.Format(time.RFC3339Nano))
+	}
+	if !to.IsZero() {
+		params.Set("to", to.Format(time.RFC3339Nano))
+	}
+	path := "/api/v1/accounts/" + strconv.Itoa(accountID) + "/statement"
+	if len(params) > 0 {
+		path += "?" + params.Encode()
+	}
+	var s dto.Statement
+	err := c.do(ctx, http.MethodGet, path, nil, &s)
+	return s, err
+}
+
+// Transfer moves an amount between two accounts.
+func (c *Client) Transfer(ctx context.Context, req dto.TransactionRequest) error {
+	return c.do(ctx, http.MethodPost, "/api/v1/transactions", req, nil)
+}
+
+// rawBody is a request body sent as it is instead of encoded as JSON.
+type rawBody struct {
+	contentType string
+	data        []byte
+}
+
+// do sends the request, retrying retryable failures, and decodes ResponsePattern.Data into out,
+// also when the response is an error that carries data.
+func (c *Client) do(ctx context.Context, method, path string, body, out any) error {
+	var payload []byte
+	contentType := "application/json"
+	switch b := body.(type) {
+	case nil:
+	case rawBody:
+		payload, contentType = b.data, b.contentType
+	default:
+		var err error
+		if payload, err = json.Marshal(body); err != nil {
+			return fmt.Errorf("client: encode request: %w", err)
+		}
+	}
+	requestID, ok := RequestIDFromContext(ctx)
+	if !ok {
+		requestID = newKey()
+	}
+	var key string
+	if method != http.MethodGet {
+		if key, _ = ctx.Value(idempotencyKey{}).(string); key == "" {
+			key = newKey()
+		}
+	}
+
+	for attempt := 0; ; attempt++ {
+		retryAfter, err := c.attempt(ctx, method, path, payload, contentType, requestID, key, out)
+		if err == nil || attempt >= c.maxRetries || !retryable(err) || ctx.Err() != nil {
+			return err
+		}
+		if err := c.sleep(ctx, c.backoff(attempt, retryAfter)); err != nil {
+			return err
+		}
+	}
+}
+
+// attempt performs one round trip and returns the server's Retry-After hint with any error.
+func (c *Client) attempt(ctx context.Context, method, path string, payload []byte, contentType, requestID, key string,
+	out any) (time.Duration, error) {
+	var body io.Reader
+	if payload != nil {
+		body = bytes.NewReader(payload)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, body)
+	if err != nil {
+		return 0, fmt.Errorf("client: build request: %w", err)
+	}
+	if payload != nil {
+		req.Header.Set("Content-Type", contentType)
+	}
+	req.Header.Set(HeaderRequestID, requestID)
+	if key != "" {
+		req.Header.Set(HeaderIdempotencyKey, key)
+	}
+	if c.apiKey != "" {
+		req.Header.Set(HeaderAPIKey, c.apiKey)
+	}
+	if c.signer != nil && method != http.MethodGet {
+		// Every attempt gets a fresh nonce; the idempotency key is what ties retries together.
+		if err := c.signer.Sign(req); err != nil {
+			return 0, fmt.Errorf("client: sign request: %w", err)
+		}
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return 0, &transportError{err: err}
+	}
+	defer resp.Body.Close()
+
+	var envelope struct {
+		dto.ResponsePattern
+		Data json.RawMessage `json:"data,omitempty"`
+	}
+	decodeErr := json.NewDecoder(resp.Body).Decode(&envelope)
+	if resp.StatusCode >= http.StatusBadRequest {
+		apiErr := &APIError{
+			StatusCode: resp.StatusCode,
+			Status:     envelope.Status,
+			Message:    envelope.ErrorMessage,
+			RequestID:  envelope.RequestID,
+		}
+		if apiErr.RequestID == "" {
+			apiErr.RequestID = resp.Header.Get(HeaderRequestID)
+		}
+		if apiErr.Message == "" {
+			apiErr.Message = http.StatusText(resp.StatusCode)
+		}
+		if out != nil && len(envelope.Data) > 0 {
+			// Best effort: the error is what the caller acts on.
+			_ = json.Unmarshal(envelope.Data, out)
+		}
+		return parseRetryAfter(resp.Header.Get(HeaderRetryAfter)), apiErr
+	}
+	if decodeErr != nil {
+		return 0, fmt.Errorf("client: decode response: %w", decodeErr)
+	}
+	if out != nil && len(envelope.Data) > 0 {
+		if err := json.Unmarshal(envelope.Data, out); err != nil {
+			return 0, fmt.Errorf("client: decode response data: %w", err)
+		}
+	}
+	return 0, nil
+}
+
+// backoff returns the delay before the retry following attempt: the server's Retry-After when
+// given, otherwise an exponential delay with full jitter.
+func (c *Client) backoff(attempt int, retryAfter time.Duration) time.Duration {
+	if retryAfter > 0 {
+		return min(retryAfter, c.maxBackoff)
+	}
+	ceiling := float64(c.minBackoff) * math.Pow(2, float64(attempt))
+	if ceiling > float64(c.maxBackoff) {
+		ceiling = float64(c.maxBackoff)
+	}
+	return c.minBackoff + time.Duration(mrand.Int64N(int64(ceiling)-int64(c.minBackoff)+1))
+}
+
+func parseRetryAfter(value string) time.Duration {
+	seconds, err := strconv.Atoi(value)
+	if err != nil || seconds <= 0 {
+		return 0
+	}
+	return time.Duration(seconds) * time.Second
+}
+
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// newKey returns a random 128-bit hex key.
+func newKey() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,400 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	APIKeyRepository "github.com/rohanchauhan02/internal-transfer/domain/apikey/repository"
+	APIKeyUsecase "github.com/rohanchauhan02/internal-transfer/domain/apikey/usecase"
+	BankingHandler "github.com/rohanchauhan02/internal-transfer/domain/banking/delivery/https"
+	BankingRepository "github.com/rohanchauhan02/internal-transfer/domain/banking/repository"
+	BankingUsecase "github.com/rohanchauhan02/internal-transfer/domain/banking/usecase"
+	"github.com/rohanchauhan02/internal-transfer/dto"
+	"github.com/rohanchauhan02/internal-transfer/pkg/config"
+	"github.com/rohanchauhan02/internal-transfer/pkg/ctx"
+	"github.com/rohanchauhan02/internal-transfer/pkg/logger"
+	CustomMiddileware "github.com/rohanchauhan02/internal-transfer/pkg/middleware"
+	"github.com/rohanchauhan02/internal-transfer/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testKey = "test-admin-key"
+
+// fault is injected in front of the next matching request.
+type fault struct {
+	status     int
+	retryAfter string
+	// afterHandler lets the request take effect and then replaces the response, as when the
+	// response is lost on the way back.
+	afterHandler bool
+}
+
+// testServer serves the real banking routes from the in-memory store, behind the request ID,
+// api key and idempotency middleware, with faults injected in front of them.
+type testServer struct {
+	*httptest.Server
+	mu     sync.Mutex
+	faults []fault
+	hits   []http.Header
+}
+
+func newTestServer(t *testing.T) *testServer {
+	t.Helper()
+	logger.Init(config.Logging{Level: "error"})
+	ts := &testServer{}
+	e := echo.New()
+	e.Validator = utils.DefaultValidator()
+	e.Use(CustomMiddileware.MiddlewareRequestID())
+	e.Use(ts.injectFaults)
+	e.Use(func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			return next(&ctx.CustomApplicationContext{Context: c})
+		}
+	})
+	apiKeys := APIKeyUsecase.NewAPIKeyUsecase(APIKeyRepository.NewMemoryAPIKeyRepository(), config.Auth{BootstrapKey: testKey})
+	t.Cleanup(apiKeys.Close)
+	e.Use(CustomMiddileware.MiddlewareAPIKey(apiKeys, true))
+	idempotency := CustomMiddileware.NewMemoryIdempotencyStore(0)
+	t.Cleanup(idempotency.Close)
+	e.Use(CustomMiddileware.MiddlewareIdempotency(config.HTTP{}, idempotency))
+
+	store := BankingRepository.NewMemoryStore()
+	BankingHandler.NewBankingHandler(e, BankingUsecase.NewBankingUsecase(store, store, config.Banking{}))
+
+	ts.Server = httptest.NewServer(e)
+	t.Cleanup(ts.Close)
+	return ts
+}
+
+func (ts *testServer) inject(faults ...fault) {
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+	ts.faults = append(ts.faults, faults...)
+}
+
+func (ts *testServer) requests() []http.Header {
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+	return ts.hits
+}
+
+func (ts *testServer) injectFaults(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		ts.mu.Lock()
+		ts.hits = append(ts.hits, c.Request().Header.Clone())
+		var f *fault
+		if len(ts.faults) > 0 {
+			f = &ts.faults[0]
+			ts.faults = ts.faults[1:]
+		}
+		ts.mu.Unlock()
+		if f == nil {
+			return next(c)
+		}
+		if f.afterHandler {
+			resp := c.Response()
+			w := resp.Writer
+			resp.Writer = httptest.NewRecorder()
+			if err := next(c); err != nil {
+				return err
+			}
+			resp.Writer, resp.Committed, resp.Size = w, false, 0
+		}
+		if f.retryAfter != "" {
+			c.Response().Header().Set(HeaderRetryAfter, f.retryAfter)
+		}
+		return c.JSON(f.status, dto.ResponsePattern{Status: http.StatusText(f.status), ErrorMessage: "injected", Code: f.status})
+	}
+}
+
+func newTestClient(ts *testServer, conf Config) (*Client, *[]time.Duration) {
+	conf.BaseURL = ts.URL
+	if conf.APIKey == "" {
+		conf.APIKey = testKey
+	}
+	c := New(conf)
+	var sleeps []time.Duration
+	c.sleep = func(ctx context.Context, d time.Duration) error {
+		sleeps = append(sleeps, d)
+		return ctx.Err()
+	}
+	return c, &sleeps
+}
+
+func TestClient_AccountsAndTransfers(t *testing.T) {
+	ts := newTestServer(t)
+	c, _ := newTestClient(ts, Config{})
+	ctx := context.Background()
+
+	require.NoError(t, c.CreateAccount(ctx, dto.AccountCreationRequest{AccountID: 1, InitialBalance: "100"}))
+	require.NoError(t, c.CreateAccount(ctx, dto.AccountCreationRequest{AccountID: 2, InitialBalance: "0"}))
+	require.NoError(t, c.Transfer(ctx, dto.TransactionRequest{SourceAccountID: 1, DestinationAccountID: 2, Amount: "40"}))
+
+	account, err := c.GetAccount(ctx, 2)
+	require.NoError(t, err)
+	assert.Equal(t, dto.AccountResponse{AccountID: 2, Balance: "40"}, account)
+	_, err = c.GetAccount(ctx, 3)
+	assert.ErrorIs(t, err, ErrAccountNotFound)
+
+	err = c.CreateAccount(ctx, dto.AccountCreationRequest{AccountID: 1, InitialBalance: "1"})
+	assert.ErrorIs(t, err, ErrAccountExists)
+	var apiErr *APIError
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, http.StatusConflict, apiErr.StatusCode)
+	assert.NotEmpty(t, apiErr.RequestID)
+
+	statusOf := func(err error) int {
+		t.Helper()
+		var apiErr *APIError
+		require.ErrorAs(t, err, &apiErr)
+		return apiErr.StatusCode
+	}
+	err = c.Transfer(ctx, dto.TransactionRequest{SourceAccountID: 2, DestinationAccountID: 1, Amount: "41"})
+	assert.ErrorIs(t, err, ErrInsufficientBalance)
+	assert.Equal(t, http.StatusUnprocessableEntity, statusOf(err))
+	err = c.Transfer(ctx, dto.TransactionRequest{SourceAccountID: 1, DestinationAccountID: 1, Amount: "1"})
+	assert.ErrorIs(t, err, ErrSameAccount)
+	assert.Equal(t, http.StatusBadRequest, statusOf(err))
+	err = c.Transfer(ctx, dto.TransactionRequest{SourceAccountID: 1, DestinationAccountID: 2, Amount: "-1"})
+	assert.ErrorIs(t, err, ErrInvalidAmount)
+	assert.Equal(t, http.StatusBadRequest, statusOf(err))
+	err = c.Transfer(ctx, dto.TransactionRequest{SourceAccountID: 1, DestinationAccountID: 99, Amount: "1"})
+	assert.ErrorIs(t, err, ErrAccountNotFound)
+	assert.Equal(t, http.StatusNotFound, statusOf(err))
+	err = c.Transfer(ctx, dto.TransactionRequest{SourceAccountID: 1})
+	assert.ErrorIs(t, err, ErrInvalidRequest)
+
+	frozen, err := c.FreezeAccount(ctx, 2)
+	require.NoError(t, err)
+	assert.True(t, frozen.Frozen)
+	err = c.Transfer(ctx, dto.TransactionRequest{SourceAccountID: 1, DestinationAccountID: 2, Amount: "1"})
+	assert.ErrorIs(t, err, ErrAccountFrozen)
+	assert.Equal(t, http.StatusUnprocessableEntity, statusOf(err))
+	_, err = c.UnfreezeAccount(ctx, 2)
+	require.NoError(t, err)
+	_, err = c.FreezeAccount(ctx, 99)
+	assert.ErrorIs(t, err, ErrAccountNotFound)
+
+	account1 := 1
+	history, err := c.ListTransactions(ctx, TransactionQuery{AccountID: &account1, Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, history, 1)
+	assert.Equal(t, "40", history[0].Amount)
+
+	invoice := dto.TransactionRequest{SourceAccountID: 1, DestinationAccountID: 2, Amount: "5",
+		Reference: "INV-1", Metadata: json.RawMessage(`{"order":"A-1"}`), UniqueReference: true}
+	require.NoError(t, c.Transfer(ctx, invoice))
+	err = c.Transfer(ctx, invoice)
+	assert.ErrorIs(t, err, ErrDuplicateReference)
+	assert.False(t, retryable(err))
+	err = c.Transfer(ctx, dto.TransactionRequest{SourceAccountID: 1, DestinationAccountID: 2, Amount: "1",
+		Metadata: json.RawMessage(`[]`)})
+	assert.ErrorIs(t, err, ErrInvalidRequest)
+	reference, order := "INV-1", "A-1"
+	for _, query := range []TransactionQuery{{Reference: &reference}, {MetadataKey: "order", MetadataValue: &order}} {
+		history, err = c.ListTransactions(ctx, query)
+		require.NoError(t, err)
+		require.Len(t, history, 1)
+		assert.Equal(t, "INV-1", history[0].Reference)
+		assert.JSONEq(t, `{"order":"A-1"}`, string(history[0].Metadata))
+	}
+
+	bad, _ := newTestClient(ts, Config{APIKey: "wrong"})
+	_, err = bad.GetAccount(ctx, 1)
+	assert.ErrorIs(t, err, ErrUnauthorized)
+}
+
+func TestClient_ImportAccounts(t *testing.T) {
+	ts := newTestServer(t)
+	c, _ := newTestClient(ts, Config{})
+	ctx := context.Background()
+
+	file := "account_id,initial_balance,currency,metadata\n" +
+		"1,100,USD,\"{\"\"unit\"\": \"\"emea\"\"}\"\n" +
+		"2,-5,USD,\n"
+	report, err := c.ImportAccounts(ctx, strings.NewReader(file), dto.AccountImportOptions{DryRun: true})
+	require.NoError(t, err)
+	assert.Equal(t, 1, report.InvalidRows)
+
+	report, err = c.ImportAccounts(ctx, strings.NewReader(file), dto.AccountImportOptions{})
+	assert.ErrorIs(t, err, ErrImportInvalid)
+	require.Len(t, report.Errors, 1, "the report comes with the error")
+	assert.Equal(t, 2, report.Errors[0].Row)
+
+	file = strings.Replace(file, "-5", "5", 1)
+	report, err = c.ImportAccounts(ctx, strings.NewReader(file), dto.AccountImportOptions{ChunkSize: 1})
+	require.NoError(t, err)
+	assert.Equal(t, 2, report.CreatedRows)
+	account, err := c.GetAccount(ctx, 1)
+	require.NoError(t, err)
+	assert.Equal(t, "USD", account.Currency)
+	assert.JSONEq(t, `{"unit":"emea"}`, string(account.Metadata))
+
+	_, err = c.ImportAccounts(ctx, strings.NewReader("id,balance\n1,1\n"), dto.AccountImportOptions{})
+	assert.ErrorIs(t, err, ErrInvalidRequest)
+}
+
+func TestClient_Statement(t *testing.T) {
+	ts := newTestServer(t)
+	c, _ := newTestClient(ts, Config{})
+	ctx := context.Background()
+
+	require.NoError(t, c.CreateAccount(ctx, dto.AccountCreationRequest{AccountID: 1, InitialBalance: "100"}))
+	require.NoError(t, c.CreateAccount(ctx, dto.AccountCreationRequest{AccountID: 2, InitialBalance: "0"}))
+	require.NoError(t, c.Transfer(ctx, dto.TransactionRequest{SourceAccountID: 1, DestinationAccountID: 2, Amount: "40"}))
+
+	s, err := c.Statement(ctx, 1, time.Time{}, time.Time{})
+	require.NoError(t, err)
+	assert.Equal(t, "100", s.OpeningBalance)
+	assert.Equal(t, "60", s.ClosingBalance)
+	require.Len(t, s.Entries, 1)
+	assert.Equal(t, dto.StatementDebit, s.Entries[0].Direction)
+
+	_, err = c.Statement(ctx, 99, time.Time{}, time.Time{})
+	assert.ErrorIs(t, err, ErrAccountNotFound)
+	_, err = c.Statement(ctx, 1, time.Now(), time.Now().Add(-time.Hour))
+	assert.ErrorIs(t, err, ErrInvalidRequest)
+
+	for format, contentType := range map[string]string{"csv": "text/csv", "pdf": "application/pdf"} {
+		req, err := http.NewRequest(http.MethodGet, ts.URL+"/api/v1/accounts/1/statement?from=2026-01-01&format="+format, nil)
+		require.NoError(t, err)
+		req.Header.Set(HeaderAPIKey, testKey)
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		resp.Body.Close()
+		assert.Equal(t, http.StatusOK, resp.StatusCode, format)
+		assert.Equal(t, contentType, resp.Header.Get("Content-Type"))
+		assert.Contains(t, resp.Header.Get("Content-Disposition"), "statement-1-20260101-", format)
+	}
+}
+
+func TestClient_Customers(t *testing.T) {
+	ts := newTestServer(t)
+	c, _ := newTestClient(ts, Config{})
+	ctx := context.Background()
+
+	_, err := c.CreateCustomer(ctx, dto.CustomerRequest{Name: "Alice", Type: "trust"})
+	assert.ErrorIs(t, err, ErrInvalidRequest)
+	customer, err := c.CreateCustomer(ctx, dto.CustomerRequest{Name: "Alice", Type: "individual", Email: "alice@example.com"})
+	require.NoError(t, err)
+	assert.Equal(t, "pending", customer.KYCStatus)
+
+	require.NoError(t, c.CreateCustomerAccount(ctx, customer.ID, dto.AccountCreationRequest{AccountID: 1, InitialBalance: "10.5"}))
+	require.NoError(t, c.CreateCustomerAccount(ctx, customer.ID, dto.AccountCreationRequest{AccountID: 2, InitialBalance: "2"}))
+	err = c.CreateCustomerAccount(ctx, customer.ID+1, dto.AccountCreationRequest{AccountID: 3, InitialBalance: "1"})
+	assert.ErrorIs(t, err, ErrCustomerNotFound)
+	assert.NotErrorIs(t, err, ErrAccountNotFound)
+	require.NoError(t, c.Transfer(ctx, dto.TransactionRequest{SourceAccountID: 1, DestinationAccountID: 2, Amount: "1"}))
+
+	accounts, err := c.CustomerAccounts(ctx, customer.ID)
+	require.NoError(t, err)
+	assert.Len(t, accounts.Accounts, 2)
+	assert.Equal(t, "12.5", accounts.TotalBalance)
+	transactions, err := c.ListTransactions(ctx, TransactionQuery{})
+	require.NoError(t, err)
+	require.Len(t, transactions, 1)
+	assert.True(t, transactions[0].Internal)
+
+	customer, err = c.SetKYCStatus(ctx, customer.ID, "verified")
+	require.NoError(t, err)
+	assert.Equal(t, "verified", customer.KYCStatus)
+	_, err = c.SetKYCStatus(ctx, customer.ID+1, "verified")
+	assert.ErrorIs(t, err, ErrCustomerNotFound)
+}
+
+func TestClient_RetriesKeepIdempotencyKeyAndRequestID(t *testing.T) {
+	ts := newTestServer(t)
+	c, sleeps := newTestClient(ts, Config{})
+	ctx := context.Background()
+	require.NoError(t, c.CreateAccount(ctx, dto.AccountCreationRequest{AccountID: 1, InitialBalance: "100"}))
+	require.NoError(t, c.CreateAccount(ctx, dto.AccountCreationRequest{AccountID: 2, InitialBalance: "0"}))
+	before := len(ts.requests())
+
+	ts.inject(fault{status: http.StatusTooManyRequests, retryAfter: "1"}, fault{status: http.StatusBadGateway, afterHandler: true})
+	err := c.Transfer(WithRequestID(ctx, "req-42"), dto.TransactionRequest{SourceAccountID: 1, DestinationAccountID: 2, Amount: "30"})
+	require.NoError(t, err)
+
+	hits := ts.requests()[before:]
+	require.Len(t, hits, 3)
+	key := hits[0].Get(HeaderIdempotencyKey)
+	assert.NotEmpty(t, key)
+	for _, h := range hits {
+		assert.Equal(t, key, h.Get(HeaderIdempotencyKey), "retries reuse the idempotency key")
+		assert.Equal(t, "req-42", h.Get(HeaderRequestID))
+	}
+	assert.Equal(t, time.Second, (*sleeps)[0], "Retry-After is honoured")
+
+	account, err := c.GetAccount(ctx, 2)
+	require.NoError(t, err)
+	assert.Equal(t, "30", account.Balance, "the transfer whose response was lost is applied once")
+
+	require.NoError(t, c.Transfer(ctx, dto.TransactionRequest{SourceAccountID: 1, DestinationAccountID: 2, Amount: "30"}))
+	assert.NotEqual(t, key, ts.requests()[len(ts.requests())-1].Get(HeaderIdempotencyKey), "each call gets a new key")
+}
+
+func TestClient_GivesUpAfterMaxRetries(t *testing.T) {
+	ts := newTestServer(t)
+	c, sleeps := newTestClient(ts, Config{MaxRetries: 2, MinBackoff: time.Millisecond, MaxBackoff: 4 * time.Millisecond})
+	ts.inject(fault{status: http.StatusServiceUnavailable}, fault{status: http.StatusServiceUnavailable}, fault{status: http.StatusServiceUnavailable})
+
+	_, err := c.GetAccount(context.Background(), 1)
+	assert.ErrorIs(t, err, ErrUnavailable)
+	assert.Len(t, ts.requests(), 3)
+	require.Len(t, *sleeps, 2)
+	for _, d := range *sleeps {
+		assert.GreaterOrEqual(t, d, time.Millisecond)
+		assert.LessOrEqual(t, d, 4*time.Millisecond)
+	}
+
+	noRetry, _ := newTestClient(ts, Config{MaxRetries: -1})
+	ts.inject(fault{status: http.StatusServiceUnavailable})
+	_, err = noRetry.GetAccount(context.Background(), 1)
+	assert.ErrorIs(t, err, ErrUnavailable)
+	assert.Len(t, ts.requests(), 4)
+}
+
+func TestClient_DoesNotRetryRejectedTransfers(t *testing.T) {
+	ts := newTestServer(t)
+	c, sleeps := newTestClient(ts, Config{})
+	ctx := context.Background()
+	require.NoError(t, c.CreateAccount(ctx, dto.AccountCreationRequest{AccountID: 1, InitialBalance: "1"}))
+	require.NoError(t, c.CreateAccount(ctx, dto.AccountCreationRequest{AccountID: 2, InitialBalance: "0"}))
+
+	err := c.Transfer(ctx, dto.TransactionRequest{SourceAccountID: 1, DestinationAccountID: 2, Amount: "5"})
+	assert.ErrorIs(t, err, ErrInsufficientBalance)
+	err = c.Transfer(ctx, dto.TransactionRequest{SourceAccountID: 1, DestinationAccountID: 99, Amount: "1"})
+	assert.ErrorIs(t, err, ErrAccountNotFound)
+	assert.Empty(t, *sleeps)
+	assert.Len(t, ts.requests(), 4)
+}
+
+func TestAPIError_MatchesByStatus(t *testing.T) {
+	serverError := &APIError{StatusCode: http.StatusInternalServerError, Message: "Transaction failed: insufficient balance"}
+	assert.NotErrorIs(t, serverError, ErrInsufficientBalance, "only a 422 is a rejected transfer")
+	assert.True(t, retryable(serverError))
+
+	rejected := &APIError{StatusCode: http.StatusUnprocessableEntity, Message: "Transaction failed: insufficient balance"}
+	assert.ErrorIs(t, rejected, ErrInsufficientBalance)
+	assert.False(t, retryable(rejected))
+}
+
+func TestClient_StopsRetryingWhenContextEnds(t *testing.T) {
+	ts := newTestServer(t)
+	c, _ := newTestClient(ts, Config{})
+	c.sleep = sleepContext
+	ts.inject(fault{status: http.StatusServiceUnavailable, retryAfter: "5"})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, err := c.GetAccount(ctx, 1)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Len(t, ts.requests(), 1)
+}
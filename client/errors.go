@@ -0,0 +1,114 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// Sentinel errors matched by *APIError with errors.Is.
+var (
+	ErrInvalidRequest      = errors.New("invalid request")
+	ErrUnauthorized        = errors.New("unauthorized")
+	ErrForbidden           = errors.New("forbidden")
+	ErrAccountExists       = errors.New("account already exists")
+	ErrAccountNotFound     = errors.New("account not found")
+	ErrCustomerNotFound    = errors.New("customer not found")
+	ErrKYCNotVerified      = errors.New("customer KYC is not verified")
+	ErrAccountFrozen       = errors.New("account is frozen")
+	ErrCurrencyMismatch    = errors.New("accounts are held in different currencies")
+	ErrIdempotencyConflict = errors.New("idempotency key conflict")
+	ErrInsufficientBalance = errors.New("insufficient balance")
+	ErrSameAccount         = errors.New("source and destination accounts must differ")
+	ErrInvalidAmount       = errors.New("invalid transfer amount")
+	ErrImportInvalid       = errors.New("import has invalid rows")
+	ErrDuplicateReference  = errors.New("reference already used by the source account")
+	ErrRateLimited         = errors.New("rate limited")
+	ErrTimeout             = errors.New("request timed out")
+	ErrUnavailable         = errors.New("service unavailable")
+)
+
+// APIError is an error response decoded from dto.ResponsePattern.
+type APIError struct {
+	// StatusCode is the HTTP status; Status is the envelope's status text.
+	StatusCode int
+	Status     string
+	Message    string
+	RequestID  string
+}
+
+func (e *APIError) Error() string {
+	if e.RequestID != "" {
+		return fmt.Sprintf("internal-transfer: %d %s (request %s)", e.StatusCode, e.Message, e.RequestID)
+	}
+	return fmt.Sprintf("internal-transfer: %d %s", e.StatusCode, e.Message)
+}
+
+// Is maps the response onto the sentinel errors by status code. Where a status covers several
+// sentinels, such as 422 for rejected transfers, the message tells them apart.
+func (e *APIError) Is(target error) bool {
+	msg := strings.ToLower(e.Message)
+	switch target {
+	case ErrInvalidRequest:
+		return e.StatusCode == http.StatusBadRequest
+	case ErrUnauthorized:
+		return e.StatusCode == http.StatusUnauthorized
+	case ErrForbidden:
+		return e.StatusCode == http.StatusForbidden
+	case ErrAccountExists:
+		return e.StatusCode == http.StatusConflict && strings.Contains(msg, "already exists")
+	case ErrAccountNotFound:
+		return e.StatusCode == http.StatusNotFound && !strings.Contains(msg, "customer")
+	case ErrCustomerNotFound:
+		return e.StatusCode == http.StatusNotFound && strings.Contains(msg, "customer")
+	case ErrKYCNotVerified:
+		return e.StatusCode == http.StatusUnprocessableEntity && strings.Contains(msg, ErrKYCNotVerified.Error())
+	case ErrAccountFrozen:
+		return e.StatusCode == http.StatusUnprocessableEntity && strings.Contains(msg, ErrAccountFrozen.Error())
+	case ErrCurrencyMismatch:
+		return e.StatusCode == http.StatusUnprocessableEntity && strings.Contains(msg, ErrCurrencyMismatch.Error())
+	case ErrIdempotencyConflict:
+		return (e.StatusCode == http.StatusConflict || e.StatusCode == http.StatusUnprocessableEntity) &&
+			strings.Contains(msg, "idempotency key")
+	case ErrInsufficientBalance:
+		return e.StatusCode == http.StatusUnprocessableEntity && strings.Contains(msg, ErrInsufficientBalance.Error())
+	case ErrSameAccount:
+		return e.StatusCode == http.StatusBadRequest && strings.Contains(msg, ErrSameAccount.Error())
+	case ErrInvalidAmount:
+		return e.StatusCode == http.StatusBadRequest && strings.Contains(msg, ErrInvalidAmount.Error())
+	case ErrDuplicateReference:
+		return e.StatusCode == http.StatusConflict && strings.Contains(msg, ErrDuplicateReference.Error())
+	case ErrImportInvalid:
+		return e.StatusCode == http.StatusUnprocessableEntity && strings.Contains(msg, ErrImportInvalid.Error())
+	case ErrRateLimited:
+		return e.StatusCode == http.StatusTooManyRequests
+	case ErrTimeout:
+		return e.StatusCode == http.StatusGatewayTimeout
+	case ErrUnavailable:
+		return e.StatusCode == http.StatusBadGateway || e.StatusCode == http.StatusServiceUnavailable
+	}
+	return false
+}
+
+// transportError is a request that got no response.
+type transportError struct {
+	err error
+}
+
+func (e *transportError) Error() string { return "internal-transfer: " + e.err.Error() }
+func (e *transportError) Unwrap() error { return e.err }
+
+// retryable reports whether err may succeed on another attempt: transport failures, rate limits
+// and server errors. Rejected requests come back as 4xx, which another attempt cannot change.
+func retryable(err error) bool {
+	var transport *transportError
+	if errors.As(err, &transport) {
+		return true
+	}
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+	return apiErr.StatusCode == http.StatusTooManyRequests || apiErr.StatusCode >= http.StatusInternalServerError
+}
@@ -0,0 +1,133 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"math/rand"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/rohanchauhan02/internal-transfer/dto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseMix(t *testing.T) {
+	m, err := parseMix("transfer=50, read=45,create=5")
+	require.NoError(t, err)
+	assert.Equal(t, "create=5,read=45,transfer=50", m.String())
+
+	m, err = parseMix("read=1,create=0")
+	require.NoError(t, err)
+	assert.Equal(t, opRead, m.pick(rand.New(rand.NewSource(1))))
+
+	for _, bad := range []string{"", "read", "read=x", "read=-1", "delete=1", "read=1,read=2", "read=0"} {
+		_, err := parseMix(bad)
+		assert.Error(t, err, bad)
+	}
+}
+
+func TestAccountPicker_ZipfFavoursLowRanks(t *testing.T) {
+	p, err := newAccountPicker(rand.New(rand.NewSource(1)), skewZipf, 100, 50, 1.5, 1)
+	require.NoError(t, err)
+	counts := make(map[int]int)
+	for i := 0; i < 10_000; i++ {
+		id := p.one()
+		require.GreaterOrEqual(t, id, 100)
+		require.Less(t, id, 150)
+		counts[id]++
+	}
+	assert.Greater(t, counts[100], counts[110])
+	assert.Greater(t, counts[100], 10_000/50)
+
+	from, to := p.pair()
+	assert.NotEqual(t, from, to)
+
+	_, err = newAccountPicker(rand.New(rand.NewSource(1)), skewZipf, 0, 10, 1, 1)
+	assert.Error(t, err, "s must be above 1")
+}
+
+func TestPercentile(t *testing.T) {
+	latencies := make([]time.Duration, 100)
+	for i := range latencies {
+		latencies[i] = time.Duration(i+1) * time.Millisecond
+	}
+	assert.Equal(t, 50*time.Millisecond, percentile(latencies, 50))
+	assert.Equal(t, 95*time.Millisecond, percentile(latencies, 95))
+	assert.Equal(t, 99*time.Millisecond, percentile(latencies, 99))
+	assert.Equal(t, time.Millisecond, percentile(latencies[:1], 99))
+}
+
+func TestRun_ReportsCodesPerOperation(t *testing.T) {
+	var (
+		mu       sync.Mutex
+		accounts = make(map[int]bool)
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "secret", r.Header.Get("X-API-Key"))
+		code := http.StatusOK
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/api/v1/accounts":
+			var req dto.AccountCreationRequest
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+			mu.Lock()
+			code = http.StatusCreated
+			if accounts[req.AccountID] {
+				code = http.StatusConflict
+			}
+			accounts[req.AccountID] = true
+			mu.Unlock()
+		case r.Method == http.MethodPost && r.URL.Path == "/api/v1/transactions":
+			// The service rejects every transfer, so they all show up as errors.
+			code = http.StatusTooManyRequests
+		}
+		w.WriteHeader(code)
+		json.NewEncoder(w).Encode(dto.ResponsePattern{Code: code})
+	}))
+	defer srv.Close()
+
+	opts := options{
+		url:            srv.URL,
+		apiKey:         "secret",
+		duration:       200 * time.Millisecond,
+		concurrency:    4,
+		timeout:        time.Second,
+		mix:            "create=1,read=1,transfer=1",
+		accounts:       10,
+		accountBase:    1,
+		createBase:     1000,
+		initialBalance: "100",
+		amount:         "1",
+		skew:           skewUniform,
+		seed:           7,
+		label:          "test",
+	}
+	var out bytes.Buffer
+	require.NoError(t, run(context.Background(), opts, &out, io.Discard))
+	for id := 1; id <= 10; id++ {
+		assert.True(t, accounts[id], "pool account %d created", id)
+	}
+
+	var report Report
+	require.NoError(t, json.Unmarshal(out.Bytes(), &report))
+	assert.Equal(t, "test", report.Label)
+	assert.Equal(t, "create=1,read=1,transfer=1", report.Config.Mix)
+	require.Contains(t, report.Operations, opCreate)
+	require.Contains(t, report.Operations, opRead)
+	require.Contains(t, report.Operations, opTransfer)
+
+	create, read, transfer := report.Operations[opCreate], report.Operations[opRead], report.Operations[opTransfer]
+	assert.Equal(t, map[string]int{"201": create.Requests}, create.Codes)
+	assert.Zero(t, read.Errors)
+	assert.Equal(t, map[string]int{"429": transfer.Requests}, transfer.Codes)
+	assert.Equal(t, transfer.Requests, transfer.Errors)
+	assert.Equal(t, create.Requests+read.Requests+transfer.Requests, report.Total.Requests)
+	assert.Equal(t, transfer.Errors, report.Total.Errors)
+	assert.Positive(t, report.Total.ThroughputRPS)
+	assert.LessOrEqual(t, report.Total.Latency.P50, report.Total.Latency.P99)
+}
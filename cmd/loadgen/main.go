@@ -0,0 +1,325 @@
+// Command loadgen drives the REST API with a configurable mix of account creations, balance
+// reads and transfers, then prints a JSON report with latency percentiles, response codes and
+// throughput per operation.
+//
+//	go run ./cmd/loadgen -url http://localhost:8080 -api-key $KEY -duration 1m -concurrency 64 \
+//		-mix create=5,read=45,transfer=50 -accounts 1000 -skew zipf > report.json
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	"github.com/rohanchauhan02/internal-transfer/dto"
+	"github.com/rohanchauhan02/internal-transfer/pkg/middleware"
+	"github.com/rohanchauhan02/internal-transfer/pkg/signing"
+)
+
+// options are the command line flags.
+type options struct {
+	url            string
+	apiKey         string
+	clientID       string
+	signingSecret  string
+	duration       time.Duration
+	concurrency    int
+	timeout        time.Duration
+	mix            string
+	accounts       int
+	accountBase    int
+	createBase     int
+	initialBalance string
+	amount         string
+	skew           string
+	zipfS          float64
+	zipfV          float64
+	seed           int64
+	label          string
+	out            string
+}
+
+func main() {
+	var opts options
+	flag.StringVar(&opts.url, "url", "http://localhost:8080", "base URL of the service")
+	flag.StringVar(&opts.apiKey, "api-key", os.Getenv("LOADGEN_API_KEY"), "api key sent as X-API-Key (default $LOADGEN_API_KEY)")
+	flag.StringVar(&opts.clientID, "client-id", "", "client ID for signed transfers, when signing is enabled")
+	flag.StringVar(&opts.signingSecret, "signing-secret", os.Getenv("LOADGEN_SIGNING_SECRET"), "shared secret for signed transfers (default $LOADGEN_SIGNING_SECRET)")
+	flag.DurationVar(&opts.duration, "duration", 30*time.Second, "how long to generate load")
+	flag.IntVar(&opts.concurrency, "concurrency", 16, "number of concurrent workers")
+	flag.DurationVar(&opts.timeout, "timeout", 10*time.Second, "per request timeout")
+	flag.StringVar(&opts.mix, "mix", "create=5,read=45,transfer=50", "weighted traffic mix of create, read and transfer")
+	flag.IntVar(&opts.accounts, "accounts", 1000, "size of the account pool that reads and transfers use")
+	flag.IntVar(&opts.accountBase, "account-base", 1_000_000, "first account ID of the pool")
+	flag.IntVar(&opts.createBase, "create-base", 0, "first account ID for create operations (default derived from the start time)")
+	flag.StringVar(&opts.initialBalance, "initial-balance", "1000000.00", "initial balance of pool and created accounts")
+	flag.StringVar(&opts.amount, "amount", "1.00", "amount of every transfer")
+	flag.StringVar(&opts.skew, "skew", skewUniform, "account popularity for reads and transfers: uniform or zipf")
+	flag.Float64Var(&opts.zipfS, "zipf-s", 1.1, "zipf exponent s (> 1); larger values concentrate traffic on fewer hot accounts")
+	flag.Float64Var(&opts.zipfV, "zipf-v", 1, "zipf offset v (>= 1)")
+	flag.Int64Var(&opts.seed, "seed", 0, "seed for the traffic pattern (default derived from the start time)")
+	flag.StringVar(&opts.label, "label", "", "free form label stored in the report, e.g. the release under test")
+	flag.StringVar(&opts.out, "out", "", "write the report to this file instead of stdout")
+	flag.Parse()
+
+	out := io.Writer(os.Stdout)
+	if opts.out != "" {
+		f, err := os.Create(opts.out)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "loadgen:", err)
+			os.Exit(1)
+		}
+		defer f.Close()
+		out = f
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	if err := run(ctx, opts, out, os.Stderr); err != nil {
+		fmt.Fprintln(os.Stderr, "loadgen:", err)
+		os.Exit(1)
+	}
+}
+
+// run sets up the account pool, generates load and writes the report to out. Interrupting it
+// ends the load phase early; the report then covers the time that actually ran.
+func run(ctx context.Context, opts options, out, progress io.Writer) error {
+	m, err := parseMix(opts.mix)
+	if err != nil {
+		return err
+	}
+	if opts.concurrency < 1 || opts.accounts < 2 {
+		return errors.New("concurrency must be at least 1 and accounts at least 2")
+	}
+	start := time.Now()
+	if opts.seed == 0 {
+		opts.seed = start.UnixNano()
+	}
+	if opts.createBase == 0 {
+		// Unique per run, so repeated runs against one database do not collide.
+		opts.createBase = int(start.UnixMicro())
+	}
+	if _, err := newAccountPicker(rand.New(rand.NewSource(0)), opts.skew, opts.accountBase, opts.accounts, opts.zipfS, opts.zipfV); err != nil {
+		return err
+	}
+
+	g := newGenerator(opts)
+	fmt.Fprintf(progress, "creating %d pool accounts from %d\n", opts.accounts, opts.accountBase)
+	if err := g.setup(ctx); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(progress, "generating load for %s with %d workers, mix %s\n", opts.duration, opts.concurrency, m)
+	loadCtx, cancel := context.WithTimeout(ctx, opts.duration)
+	defer cancel()
+	startedAt := time.Now()
+	rec := newRecorder()
+	var wg sync.WaitGroup
+	for w := 0; w < opts.concurrency; w++ {
+		rng := rand.New(rand.NewSource(opts.seed + int64(w)))
+		picker, _ := newAccountPicker(rng, opts.skew, opts.accountBase, opts.accounts, opts.zipfS, opts.zipfV)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for loadCtx.Err() == nil {
+				op := m.pick(rng)
+				res := g.do(loadCtx, op, picker)
+				// Requests cut short by the end of the run say nothing about the service.
+				if res.code == codeTransport && loadCtx.Err() != nil {
+					return
+				}
+				rec.record(res)
+			}
+		}()
+	}
+	wg.Wait()
+	elapsed := time.Since(startedAt)
+
+	total, ops := rec.summarise(elapsed)
+	report := Report{
+		Label:           opts.label,
+		Target:          opts.url,
+		StartedAt:       startedAt.UTC(),
+		DurationSeconds: round(elapsed.Seconds()),
+		Config: ReportConfig{
+			Concurrency: opts.concurrency,
+			Mix:         m.String(),
+			Accounts:    opts.accounts,
+			Skew:        opts.skew,
+			Amount:      opts.amount,
+			Seed:        opts.seed,
+		},
+		Total:      total,
+		Operations: ops,
+	}
+	if opts.skew == skewZipf {
+		report.Config.ZipfS, report.Config.ZipfV = opts.zipfS, opts.zipfV
+	}
+	fmt.Fprintf(progress, "%d requests, %d errors, %.1f req/s\n", total.Requests, total.Errors, total.ThroughputRPS)
+	enc := json.NewEncoder(out)
+	enc.SetIndent("", "  ")
+	return enc.Encode(report)
+}
+
+// generator issues API requests.
+type generator struct {
+	opts   options
+	client *http.Client
+	// signed is used for transfers when request signing is configured.
+	signed    *http.Client
+	nextNewID atomic.Int64
+}
+
+func newGenerator(opts options) *generator {
+	transport := &http.Transport{
+		MaxIdleConns:        opts.concurrency,
+		MaxIdleConnsPerHost: opts.concurrency,
+		IdleConnTimeout:     90 * time.Second,
+	}
+	g := &generator{
+		opts:   opts,
+		client: &http.Client{Transport: transport, Timeout: opts.timeout},
+	}
+	g.signed = g.client
+	if opts.clientID != "" && opts.signingSecret != "" {
+		g.signed = &http.Client{
+			Transport: &signing.Transport{Signer: signing.NewSigner(opts.clientID, opts.signingSecret), Base: transport},
+			Timeout:   opts.timeout,
+		}
+	}
+	g.nextNewID.Store(int64(opts.createBase))
+	return g
+}
+
+// setup creates the account pool. Accounts left over from an earlier run are reused.
+func (g *generator) setup(ctx context.Context) error {
+	ids := make(chan int)
+	errs := make(chan error, g.opts.concurrency)
+	var wg sync.WaitGroup
+	for w := 0; w < g.opts.concurrency; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for id := range ids {
+				if err := g.createPoolAccount(ctx, id); err != nil {
+					errs <- err
+					return
+				}
+			}
+		}()
+	}
+	var err error
+feed:
+	for id := g.opts.accountBase; id < g.opts.accountBase+g.opts.accounts; id++ {
+		select {
+		case ids <- id:
+		case err = <-errs:
+			break feed
+		case <-ctx.Done():
+			err = ctx.Err()
+			break feed
+		}
+	}
+	close(ids)
+	wg.Wait()
+	if err == nil {
+		select {
+		case err = <-errs:
+		default:
+		}
+	}
+	return err
+}
+
+// createPoolAccount creates one pool account, backing off while the service rate limits setup.
+func (g *generator) createPoolAccount(ctx context.Context, id int) error {
+	backoff := 100 * time.Millisecond
+	for {
+		res := g.createAccount(ctx, id)
+		switch res.code {
+		case strconv.Itoa(http.StatusCreated), strconv.Itoa(http.StatusConflict):
+			return nil
+		case strconv.Itoa(http.StatusTooManyRequests):
+		default:
+			return fmt.Errorf("creating pool account %d: response code %s", id, res.code)
+		}
+		select {
+		case <-time.After(backoff):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+		backoff = min(2*backoff, 2*time.Second)
+	}
+}
+
+func (g *generator) do(ctx context.Context, op string, picker *accountPicker) result {
+	switch op {
+	case opCreate:
+		return g.createAccount(ctx, int(g.nextNewID.Add(1)-1))
+	case opRead:
+		return g.send(ctx, g.client, opRead, http.MethodGet, "/api/v1/accounts/"+strconv.Itoa(picker.one()), nil)
+	default:
+		from, to := picker.pair()
+		return g.send(ctx, g.signed, opTransfer, http.MethodPost, "/api/v1/transactions", dto.TransactionRequest{
+			SourceAccountID:      from,
+			DestinationAccountID: to,
+			Amount:               g.opts.amount,
+		})
+	}
+}
+
+func (g *generator) createAccount(ctx context.Context, id int) result {
+	return g.send(ctx, g.client, opCreate, http.MethodPost, "/api/v1/accounts", dto.AccountCreationRequest{
+		AccountID:      id,
+		InitialBalance: g.opts.initialBalance,
+	})
+}
+
+// send issues one request and classifies it by the Code of the ResponsePattern it returns.
+func (g *generator) send(ctx context.Context, client *http.Client, op, method, path string, body any) result {
+	var payload io.Reader
+	if body != nil {
+		b, err := json.Marshal(body)
+		if err != nil {
+			return result{op: op, code: codeTransport}
+		}
+		payload = bytes.NewReader(b)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, strings.TrimSuffix(g.opts.url, "/")+path, payload)
+	if err != nil {
+		return result{op: op, code: codeTransport}
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if g.opts.apiKey != "" {
+		req.Header.Set(middleware.HeaderAPIKey, g.opts.apiKey)
+	}
+
+	start := time.Now()
+	resp, err := client.Do(req)
+	if err != nil {
+		return result{op: op, code: codeTransport, latency: time.Since(start)}
+	}
+	defer resp.Body.Close()
+	var pattern dto.ResponsePattern
+	err = json.NewDecoder(resp.Body).Decode(&pattern)
+	latency := time.Since(start)
+	if err != nil || pattern.Code == 0 {
+		// Not a ResponsePattern, e.g. echo's own 404 or a proxy error page.
+		return result{op: op, code: strconv.Itoa(resp.StatusCode), latency: latency}
+	}
+	return result{op: op, code: strconv.Itoa(pattern.Code), latency: latency}
+}
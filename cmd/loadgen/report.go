@@ -0,0 +1,145 @@
+package main
+
+import (
+	"math"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// codeTransport counts requests that got no response at all, e.g. connection errors or client
+// side timeouts. Responses that are not a ResponsePattern are counted by their HTTP status.
+const codeTransport = "transport"
+
+// Report is the JSON document written at the end of a run. Maps marshal with sorted keys, so
+// reports from two releases can be diffed directly.
+type Report struct {
+	Label           string                      `json:"label,omitempty"`
+	Target          string                      `json:"target"`
+	StartedAt       time.Time                   `json:"started_at"`
+	DurationSeconds float64                     `json:"duration_seconds"`
+	Config          ReportConfig                `json:"config"`
+	Total           OperationReport             `json:"total"`
+	Operations      map[string]*OperationReport `json:"operations"`
+}
+
+// ReportConfig records the parameters of the run.
+type ReportConfig struct {
+	Concurrency int     `json:"concurrency"`
+	Mix         string  `json:"mix"`
+	Accounts    int     `json:"accounts"`
+	Skew        string  `json:"skew"`
+	ZipfS       float64 `json:"zipf_s,omitempty"`
+	ZipfV       float64 `json:"zipf_v,omitempty"`
+	Amount      string  `json:"amount"`
+	Seed        int64   `json:"seed"`
+}
+
+// OperationReport summarises the requests of one operation, or of all of them.
+type OperationReport struct {
+	Requests      int     `json:"requests"`
+	Errors        int     `json:"errors"`
+	ThroughputRPS float64 `json:"throughput_rps"`
+	Latency       Latency `json:"latency_ms"`
+	// Codes counts responses by ResponsePattern.Code; errors are all codes outside 2xx.
+	Codes map[string]int `json:"codes"`
+}
+
+// Latency holds latency statistics in milliseconds.
+type Latency struct {
+	P50  float64 `json:"p50"`
+	P95  float64 `json:"p95"`
+	P99  float64 `json:"p99"`
+	Mean float64 `json:"mean"`
+	Max  float64 `json:"max"`
+}
+
+// result is the outcome of a single request.
+type result struct {
+	op      string
+	code    string
+	latency time.Duration
+}
+
+// recorder collects results from all workers.
+type recorder struct {
+	mu      sync.Mutex
+	results map[string][]result
+}
+
+func newRecorder() *recorder {
+	return &recorder{results: make(map[string][]result)}
+}
+
+func (r *recorder) record(res result) {
+	r.mu.Lock()
+	r.results[res.op] = append(r.results[res.op], res)
+	r.mu.Unlock()
+}
+
+// summarise builds the per operation and total sections of the report.
+func (r *recorder) summarise(elapsed time.Duration) (OperationReport, map[string]*OperationReport) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var all []result
+	ops := make(map[string]*OperationReport, len(r.results))
+	for op, results := range r.results {
+		summary := summarise(results, elapsed)
+		ops[op] = &summary
+		all = append(all, results...)
+	}
+	return summarise(all, elapsed), ops
+}
+
+func summarise(results []result, elapsed time.Duration) OperationReport {
+	rep := OperationReport{Requests: len(results), Codes: make(map[string]int)}
+	latencies := make([]time.Duration, len(results))
+	var sum time.Duration
+	for i, res := range results {
+		latencies[i] = res.latency
+		sum += res.latency
+		rep.Codes[res.code]++
+		if !isSuccess(res.code) {
+			rep.Errors++
+		}
+	}
+	if len(results) == 0 {
+		return rep
+	}
+	sort.Slice(latencies, func(i, j int) bool { return latencies[i] < latencies[j] })
+	rep.Latency = Latency{
+		P50:  millis(percentile(latencies, 50)),
+		P95:  millis(percentile(latencies, 95)),
+		P99:  millis(percentile(latencies, 99)),
+		Mean: millis(sum / time.Duration(len(latencies))),
+		Max:  millis(latencies[len(latencies)-1]),
+	}
+	if elapsed > 0 {
+		rep.ThroughputRPS = round(float64(len(results)) / elapsed.Seconds())
+	}
+	return rep
+}
+
+func isSuccess(code string) bool {
+	n, err := strconv.Atoi(code)
+	return err == nil && n >= 200 && n < 300
+}
+
+// percentile returns the nearest-rank percentile of sorted latencies.
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	rank := int(math.Ceil(p / 100 * float64(len(sorted))))
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
+
+func millis(d time.Duration) float64 {
+	return round(float64(d) / float64(time.Millisecond))
+}
+
+// round keeps three decimals so reports do not differ in noise digits.
+func round(f float64) float64 {
+	return math.Round(f*1000) / 1000
+}
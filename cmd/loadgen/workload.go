@@ -0,0 +1,127 @@
+package main
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Operations the load generator can issue.
+const (
+	opCreate   = "create"
+	opRead     = "read"
+	opTransfer = "transfer"
+)
+
+// Account skews for reads and transfers.
+const (
+	skewUniform = "uniform"
+	skewZipf    = "zipf"
+)
+
+// mix is the weighted traffic mix, e.g. parsed from "create=5,read=45,transfer=50".
+type mix struct {
+	ops     []string
+	weights []int
+	total   int
+}
+
+func parseMix(s string) (mix, error) {
+	var m mix
+	seen := make(map[string]bool)
+	for _, part := range strings.Split(s, ",") {
+		name, value, ok := strings.Cut(strings.TrimSpace(part), "=")
+		if !ok {
+			return mix{}, fmt.Errorf("invalid mix entry %q, want op=weight", part)
+		}
+		switch name {
+		case opCreate, opRead, opTransfer:
+		default:
+			return mix{}, fmt.Errorf("unknown operation %q in mix", name)
+		}
+		if seen[name] {
+			return mix{}, fmt.Errorf("operation %q listed twice in mix", name)
+		}
+		seen[name] = true
+		weight, err := strconv.Atoi(value)
+		if err != nil || weight < 0 {
+			return mix{}, fmt.Errorf("invalid weight %q for %s", value, name)
+		}
+		if weight == 0 {
+			continue
+		}
+		m.ops = append(m.ops, name)
+		m.weights = append(m.weights, weight)
+		m.total += weight
+	}
+	if m.total == 0 {
+		return mix{}, fmt.Errorf("mix %q has no operation with a positive weight", s)
+	}
+	return m, nil
+}
+
+// pick returns an operation with probability proportional to its weight.
+func (m mix) pick(rng *rand.Rand) string {
+	n := rng.Intn(m.total)
+	for i, w := range m.weights {
+		if n < w {
+			return m.ops[i]
+		}
+		n -= w
+	}
+	return m.ops[len(m.ops)-1]
+}
+
+// String renders the mix in flag syntax with operations sorted, for the report.
+func (m mix) String() string {
+	parts := make([]string, len(m.ops))
+	for i, op := range m.ops {
+		parts[i] = op + "=" + strconv.Itoa(m.weights[i])
+	}
+	sort.Strings(parts)
+	return strings.Join(parts, ",")
+}
+
+// accountPicker chooses accounts from the pre-created pool [base, base+size).
+type accountPicker struct {
+	base int
+	size int
+	zipf *rand.Zipf
+	rng  *rand.Rand
+}
+
+// newAccountPicker returns a picker for one worker. With zipf skew the account at base is the
+// hottest and popularity falls off with rank as 1/(v+rank)^s.
+func newAccountPicker(rng *rand.Rand, skew string, base, size int, s, v float64) (*accountPicker, error) {
+	p := &accountPicker{base: base, size: size, rng: rng}
+	switch skew {
+	case skewUniform:
+	case skewZipf:
+		if s <= 1 || v < 1 {
+			return nil, fmt.Errorf("zipf needs s > 1 and v >= 1, got s=%g v=%g", s, v)
+		}
+		p.zipf = rand.NewZipf(rng, s, v, uint64(size-1))
+	default:
+		return nil, fmt.Errorf("unknown skew %q, want %s or %s", skew, skewUniform, skewZipf)
+	}
+	return p, nil
+}
+
+func (p *accountPicker) one() int {
+	if p.zipf != nil {
+		return p.base + int(p.zipf.Uint64())
+	}
+	return p.base + p.rng.Intn(p.size)
+}
+
+// pair returns two distinct accounts for a transfer.
+func (p *accountPicker) pair() (int, int) {
+	from := p.one()
+	for {
+		if to := p.one(); to != from {
+			return from, to
+		}
+	}
+}
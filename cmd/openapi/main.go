@@ -0,0 +1,27 @@
+// Command openapi regenerates docs/openapi.json from the registered routes and DTOs.
+//
+//	go run ./cmd/openapi -out docs/openapi.json
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/rohanchauhan02/internal-transfer/docs"
+)
+
+func main() {
+	out := flag.String("out", "docs/openapi.json", "file to write the document to")
+	flag.Parse()
+
+	spec, err := docs.Build()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "openapi:", err)
+		os.Exit(1)
+	}
+	if err := os.WriteFile(*out, spec, 0o644); err != nil {
+		fmt.Fprintln(os.Stderr, "openapi:", err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"time"
+
+	"github.com/rohanchauhan02/internal-transfer/client"
+	"github.com/rohanchauhan02/internal-transfer/domain/banking"
+	"github.com/rohanchauhan02/internal-transfer/dto"
+)
+
+// apiUsecase serves the banking usecase over the REST API, so commands run the same against a
+// server as against the database.
+type apiUsecase struct {
+	client *client.Client
+}
+
+func newAPIUsecase(c *client.Client) banking.Usecase {
+	return &apiUsecase{client: c}
+}
+
+func (u *apiUsecase) CreateAccount(ctx context.Context, accountID int, balance string) error {
+	return u.client.CreateAccount(ctx, dto.AccountCreationRequest{AccountID: accountID, InitialBalance: balance})
+}
+
+func (u *apiUsecase) CreateCustomer(ctx context.Context, req dto.CustomerRequest) (dto.CustomerResponse, error) {
+	return u.client.CreateCustomer(ctx, req)
+}
+
+func (u *apiUsecase) CreateCustomerAccount(ctx context.Context, customerID uint, accountID int, balance string) error {
+	return u.client.CreateCustomerAccount(ctx, customerID, dto.AccountCreationRequest{AccountID: accountID, InitialBalance: balance})
+}
+
+func (u *apiUsecase) CustomerAccounts(ctx context.Context, customerID uint) (dto.CustomerAccounts, error) {
+	return u.client.CustomerAccounts(ctx, customerID)
+}
+
+func (u *apiUsecase) SetKYCStatus(ctx context.Context, customerID uint, status string) (dto.CustomerResponse, error) {
+	return u.client.SetKYCStatus(ctx, customerID, status)
+}
+
+func (u *apiUsecase) GetAccount(ctx context.Context, accountID int) (dto.AccountResponse, error) {
+	return u.client.GetAccount(ctx, accountID)
+}
+
+func (u *apiUsecase) Transaction(ctx context.Context, req dto.TransactionRequest) error {
+	return u.client.Transfer(ctx, req)
+}
+
+func (u *apiUsecase) ListTransactions(ctx context.Context, filter banking.TransactionFilter) ([]dto.TransactionResponse, error) {
+	return u.client.ListTransactions(ctx, client.TransactionQuery{
+		AccountID:     filter.AccountID,
+		Reference:     filter.Reference,
+		MetadataKey:   filter.MetadataKey,
+		MetadataValue: filter.MetadataValue,
+		Limit:         filter.Limit,
+	})
+}
+
+func (u *apiUsecase) SetFrozen(ctx context.Context, accountID int, frozen bool) (dto.AccountResponse, error) {
+	if frozen {
+		return u.client.FreezeAccount(ctx, accountID)
+	}
+	return u.client.UnfreezeAccount(ctx, accountID)
+}
+
+// ImportAccounts sends the rows back to the server as CSV; the server validates them.
+func (u *apiUsecase) ImportAccounts(ctx context.Context, rows []dto.AccountImportRow,
+	opts dto.AccountImportOptions) (dto.AccountImportReport, error) {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	w.Write(banking.ImportColumns)
+	for _, r := range rows {
+		w.Write([]string{r.AccountID, r.InitialBalance, r.Currency, r.Metadata})
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return dto.AccountImportReport{}, err
+	}
+	return u.client.ImportAccounts(ctx, &buf, opts)
+}
+
+func (u *apiUsecase) Statement(ctx context.Context, accountID int, from, to time.Time) (dto.Statement, error) {
+	return u.client.Statement(ctx, accountID, from, to)
+}
@@ -0,0 +1,267 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/rohanchauhan02/internal-transfer/domain/banking"
+	"github.com/rohanchauhan02/internal-transfer/dto"
+	"github.com/rohanchauhan02/internal-transfer/pkg/migrations"
+)
+
+func (c *cli) account(ctx context.Context, args []string) error {
+	if len(args) > 0 && args[0] == "import" {
+		return c.importAccounts(ctx, args[1:])
+	}
+	if len(args) < 2 {
+		return fmt.Errorf("%w: account needs a subcommand and an account ID", errUsage)
+	}
+	id, err := parseAccountID(args[1])
+	if err != nil {
+		return err
+	}
+
+	switch args[0] {
+	case "create":
+		if len(args) != 3 {
+			return fmt.Errorf("%w: account create <id> <initial-balance>", errUsage)
+		}
+		if err := c.usecase.CreateAccount(ctx, id, args[2]); err != nil {
+			return err
+		}
+		return c.showAccount(ctx, id)
+	case "show":
+		return c.showAccount(ctx, id)
+	case "freeze", "unfreeze":
+		frozen := args[0] == "freeze"
+		if err := c.confirm("%s account %d?", args[0], id); err != nil {
+			return err
+		}
+		account, err := c.usecase.SetFrozen(ctx, id, frozen)
+		if err != nil {
+			return err
+		}
+		return c.printAccounts(account)
+	default:
+		return fmt.Errorf("%w: unknown account subcommand %q", errUsage, args[0])
+	}
+}
+
+// importAccounts validates an import file in a dry run first, and only asks to create the
+// accounts when every row is valid.
+func (c *cli) importAccounts(ctx context.Context, args []string) error {
+	fs := c.flagSet("account import")
+	dryRun := fs.Bool("dry-run", false, "only validate the file")
+	startRow := fs.Int("start-row", 0, "first row to import, counting from 1 after the header")
+	chunkSize := fs.Int("chunk-size", 0, "accounts created per transaction")
+	if err := fs.Parse(args); err != nil {
+		return fmt.Errorf("%w: %v", errUsage, err)
+	}
+	if fs.NArg() != 1 {
+		return fmt.Errorf("%w: account import [-dry-run] [-start-row n] [-chunk-size n] <file>", errUsage)
+	}
+	f, err := os.Open(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	rows, err := banking.ReadImportCSV(f)
+	if err != nil {
+		return fmt.Errorf("%s: %w", fs.Arg(0), err)
+	}
+
+	opts := dto.AccountImportOptions{DryRun: true, StartRow: *startRow, ChunkSize: *chunkSize}
+	report, err := c.usecase.ImportAccounts(ctx, rows, opts)
+	if err != nil {
+		return err
+	}
+	if *dryRun || report.InvalidRows > 0 {
+		if err := c.printImportReport(report); err != nil {
+			return err
+		}
+		if report.InvalidRows > 0 {
+			return banking.ErrImportInvalid
+		}
+		return nil
+	}
+	if err := c.confirm("import %d accounts (%d already exist)?", report.ValidRows-report.ExistingRows,
+		report.ExistingRows); err != nil {
+		return err
+	}
+	opts.DryRun = false
+	report, err = c.usecase.ImportAccounts(ctx, rows, opts)
+	if printErr := c.printImportReport(report); err == nil {
+		err = printErr
+	}
+	return err
+}
+
+// printImportReport lists the rejected rows and writes the totals to errOut.
+func (c *cli) printImportReport(report dto.AccountImportReport) error {
+	r := result{header: []string{"ROW", "ACCOUNT", "FIELD", "ERROR"}, value: report}
+	for _, e := range report.Errors {
+		r.rows = append(r.rows, []string{strconv.Itoa(e.Row), e.AccountID, e.Field, e.Message})
+	}
+	if err := render(c.out, c.opts.output, r); err != nil {
+		return err
+	}
+	fmt.Fprintf(c.errOut, "checked %d rows: %d valid, %d invalid, %d created, %d already existed\n",
+		report.TotalRows, report.ValidRows, report.InvalidRows, report.CreatedRows, report.ExistingRows)
+	if report.NextRow > 0 {
+		fmt.Fprintf(c.errOut, "stopped before row %d; resume with -start-row %d\n", report.NextRow, report.NextRow)
+	}
+	return nil
+}
+
+func (c *cli) showAccount(ctx context.Context, id int) error {
+	account, err := c.usecase.GetAccount(ctx, id)
+	if err != nil {
+		return fmt.Errorf("account %d: %w", id, err)
+	}
+	return c.printAccounts(account)
+}
+
+func (c *cli) printAccounts(accounts ...dto.AccountResponse) error {
+	r := result{header: []string{"ACCOUNT", "BALANCE", "FROZEN"}, value: accounts}
+	if len(accounts) == 1 {
+		r.value = accounts[0]
+	}
+	for _, a := range accounts {
+		r.rows = append(r.rows, []string{strconv.Itoa(a.AccountID), a.Balance, strconv.FormatBool(a.Frozen)})
+	}
+	return render(c.out, c.opts.output, r)
+}
+
+func (c *cli) transfer(ctx context.Context, args []string) error {
+	fs := c.flagSet("transfer")
+	reference := fs.String("reference", "", "client reference for the transfer")
+	description := fs.String("description", "", "description of the transfer")
+	metadata := fs.String("metadata", "", "JSON object stored with the transfer")
+	unique := fs.Bool("unique-reference", false, "fail if the source account already used the reference")
+	if err := fs.Parse(args); err != nil {
+		return fmt.Errorf("%w: %v", errUsage, err)
+	}
+	if fs.NArg() != 3 {
+		return fmt.Errorf("%w: transfer [-reference ref] [-description text] [-metadata json] [-unique-reference] <from> <to> <amount>", errUsage)
+	}
+	from, err := parseAccountID(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	to, err := parseAccountID(fs.Arg(1))
+	if err != nil {
+		return err
+	}
+	amount := fs.Arg(2)
+	if err := c.confirm("transfer %s from account %d to account %d?", amount, from, to); err != nil {
+		return err
+	}
+	req := dto.TransactionRequest{
+		SourceAccountID:      from,
+		DestinationAccountID: to,
+		Amount:               amount,
+		Reference:            *reference,
+		Description:          *description,
+		UniqueReference:      *unique,
+	}
+	if *metadata != "" {
+		req.Metadata = json.RawMessage(*metadata)
+	}
+	if err := c.usecase.Transaction(ctx, req); err != nil {
+		return err
+	}
+
+	var accounts []dto.AccountResponse
+	for _, id := range []int{from, to} {
+		account, err := c.usecase.GetAccount(ctx, id)
+		if err != nil {
+			return err
+		}
+		accounts = append(accounts, account)
+	}
+	return c.printAccounts(accounts...)
+}
+
+func (c *cli) history(ctx context.Context, args []string) error {
+	fs := c.flagSet("history")
+	account := fs.Int("account", 0, "only transactions from or to this account")
+	reference := fs.String("reference", "", "only transactions with this client reference")
+	metadata := fs.String("metadata", "", "only transactions whose metadata has this key, or key=value")
+	limit := fs.Int("limit", 20, "number of transactions")
+	if err := fs.Parse(args); err != nil {
+		return fmt.Errorf("%w: %v", errUsage, err)
+	}
+	filter := banking.TransactionFilter{Limit: *limit}
+	if *account != 0 {
+		filter.AccountID = account
+	}
+	if *reference != "" {
+		filter.Reference = reference
+	}
+	if key, value, ok := strings.Cut(*metadata, "="); ok {
+		filter.MetadataKey, filter.MetadataValue = key, &value
+	} else {
+		filter.MetadataKey = key
+	}
+	transactions, err := c.usecase.ListTransactions(ctx, filter)
+	if err != nil {
+		return err
+	}
+	return render(c.out, c.opts.output, transactionsResult(transactions))
+}
+
+func transactionsResult(transactions []dto.TransactionResponse) result {
+	r := result{header: []string{"ID", "FROM", "TO", "AMOUNT", "REFERENCE", "CREATED_AT"}, value: transactions}
+	for _, t := range transactions {
+		r.rows = append(r.rows, []string{
+			strconv.FormatUint(uint64(t.ID), 10),
+			strconv.Itoa(t.SourceAccountID),
+			strconv.Itoa(t.DestinationAccountID),
+			t.Amount,
+			t.Reference,
+			formatTime(t.CreatedAt),
+		})
+	}
+	return r
+}
+
+func (c *cli) migrate(ctx context.Context, args []string) error {
+	if err := c.requireDatabase("migrate"); err != nil {
+		return err
+	}
+	if len(args) > 0 && args[0] == "down" {
+		steps := "1 migration"
+		if len(args) > 1 {
+			steps = args[1] + " migrations"
+		}
+		if err := c.confirm("roll back %s? Dropped columns and tables lose their data", steps); err != nil {
+			return err
+		}
+	}
+	err := migrations.RunCommand(ctx, c.db.sqlDB, c.db.dialect, args, c.out)
+	if err != nil && err.Error() == migrations.CommandUsage {
+		return fmt.Errorf("%w: %s", errUsage, err)
+	}
+	return err
+}
+
+// flagSet creates the flag set of a subcommand, reporting parse errors through the caller.
+func (c *cli) flagSet(name string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	return fs
+}
+
+func parseAccountID(s string) (int, error) {
+	id, err := strconv.Atoi(s)
+	if err != nil || id <= 0 {
+		return 0, fmt.Errorf("%w: invalid account ID %q", errUsage, s)
+	}
+	return id, nil
+}
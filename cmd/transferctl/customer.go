@@ -0,0 +1,114 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/rohanchauhan02/internal-transfer/dto"
+)
+
+func (c *cli) customer(ctx context.Context, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("%w: customer needs a subcommand", errUsage)
+	}
+	if args[0] == "create" {
+		return c.createCustomer(ctx, args[1:])
+	}
+	if len(args) < 2 {
+		return fmt.Errorf("%w: customer %s needs a customer ID", errUsage, args[0])
+	}
+	id, err := parseCustomerID(args[1])
+	if err != nil {
+		return err
+	}
+
+	switch args[0] {
+	case "show":
+		return c.showCustomer(ctx, id)
+	case "open":
+		if len(args) != 4 {
+			return fmt.Errorf("%w: customer open <id> <account-id> <initial-balance>", errUsage)
+		}
+		accountID, err := parseAccountID(args[2])
+		if err != nil {
+			return err
+		}
+		if err := c.usecase.CreateCustomerAccount(ctx, id, accountID, args[3]); err != nil {
+			return err
+		}
+		return c.showCustomer(ctx, id)
+	case "kyc":
+		if len(args) != 3 {
+			return fmt.Errorf("%w: customer kyc <id> pending | verified | rejected", errUsage)
+		}
+		if err := c.confirm("set the KYC status of customer %d to %s?", id, args[2]); err != nil {
+			return err
+		}
+		customer, err := c.usecase.SetKYCStatus(ctx, id, args[2])
+		if err != nil {
+			return err
+		}
+		return c.printCustomer(customer)
+	default:
+		return fmt.Errorf("%w: unknown customer subcommand %q", errUsage, args[0])
+	}
+}
+
+func (c *cli) createCustomer(ctx context.Context, args []string) error {
+	fs := c.flagSet("customer create")
+	email := fs.String("email", "", "contact email address")
+	phone := fs.String("phone", "", "contact phone number")
+	address := fs.String("address", "", "postal address")
+	if err := fs.Parse(args); err != nil {
+		return fmt.Errorf("%w: %v", errUsage, err)
+	}
+	if fs.NArg() != 2 {
+		return fmt.Errorf("%w: customer create [-email addr] [-phone number] [-address text] individual | business <name>", errUsage)
+	}
+	customer, err := c.usecase.CreateCustomer(ctx, dto.CustomerRequest{
+		Type:    fs.Arg(0),
+		Name:    fs.Arg(1),
+		Email:   *email,
+		Phone:   *phone,
+		Address: *address,
+	})
+	if err != nil {
+		return err
+	}
+	return c.printCustomer(customer)
+}
+
+// showCustomer lists the customer's accounts and writes the customer and total balance to errOut.
+func (c *cli) showCustomer(ctx context.Context, id uint) error {
+	accounts, err := c.usecase.CustomerAccounts(ctx, id)
+	if err != nil {
+		return err
+	}
+	r := result{header: []string{"ACCOUNT", "BALANCE", "FROZEN"}, value: accounts}
+	for _, a := range accounts.Accounts {
+		r.rows = append(r.rows, []string{strconv.Itoa(a.AccountID), a.Balance, strconv.FormatBool(a.Frozen)})
+	}
+	if err := render(c.out, c.opts.output, r); err != nil {
+		return err
+	}
+	fmt.Fprintf(c.errOut, "customer %d %s (%s, KYC %s): %d accounts, total balance %s\n", accounts.Customer.ID,
+		accounts.Customer.Name, accounts.Customer.Type, accounts.Customer.KYCStatus, len(accounts.Accounts),
+		accounts.TotalBalance)
+	return nil
+}
+
+func (c *cli) printCustomer(customer dto.CustomerResponse) error {
+	r := result{header: []string{"CUSTOMER", "NAME", "TYPE", "KYC", "EMAIL", "PHONE"}, value: customer}
+	r.rows = append(r.rows, []string{strconv.FormatUint(uint64(customer.ID), 10), customer.Name, customer.Type,
+		customer.KYCStatus, customer.Email, customer.Phone})
+	return render(c.out, c.opts.output, r)
+}
+
+func parseCustomerID(s string) (uint, error) {
+	id, err := strconv.ParseUint(s, 10, 0)
+	if err != nil || id == 0 {
+		return 0, fmt.Errorf("%w: invalid customer ID %q", errUsage, s)
+	}
+	return uint(id), nil
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"io"
+
+	"github.com/rohanchauhan02/internal-transfer/domain/banking"
+	BankingRepository "github.com/rohanchauhan02/internal-transfer/domain/banking/repository"
+	"github.com/rohanchauhan02/internal-transfer/pkg/config"
+	pkgdatabase "github.com/rohanchauhan02/internal-transfer/pkg/database"
+	"github.com/rohanchauhan02/internal-transfer/pkg/logger"
+	"github.com/rohanchauhan02/internal-transfer/pkg/migrations"
+	"gorm.io/gorm"
+)
+
+// database is the direct connection used when no -api is given.
+type database struct {
+	repo    banking.Repository
+	uow     banking.UnitOfWork
+	sqlDB   *sql.DB
+	dialect migrations.Dialect
+}
+
+// openDatabase connects with the server's configuration. Logs go to errOut at warn level so
+// stdout only carries command output. With checkSchema, a schema this binary was not built for
+// is refused, as the server does.
+func openDatabase(ctx context.Context, errOut io.Writer, checkSchema bool) (*database, error) {
+	cnf := config.NewImmutableConfigs()
+	logConf := cnf.GetLoggingConf()
+	logConf.Level, logConf.Format, logConf.PackageLevels = "warn", "text", nil
+	logger.InitTo(logConf, errOut)
+
+	db, err := pkgdatabase.Open(ctx, cnf)
+	if err != nil {
+		return nil, fmt.Errorf("connect to database: %w", err)
+	}
+	return newDatabase(ctx, db, dialectFor(cnf.GetDBConf().Driver), checkSchema)
+}
+
+func newDatabase(ctx context.Context, db *gorm.DB, dialect migrations.Dialect, checkSchema bool) (*database, error) {
+	sqlDB, err := db.DB()
+	if err != nil {
+		return nil, err
+	}
+	if checkSchema {
+		migrator, err := migrations.New(sqlDB, dialect)
+		if err != nil {
+			return nil, err
+		}
+		if err := migrator.Check(ctx); err != nil {
+			sqlDB.Close()
+			return nil, fmt.Errorf("%w, run `transferctl migrate up` with the matching release", err)
+		}
+	}
+	return &database{
+		repo:    BankingRepository.NewBankingRepository(db),
+		uow:     BankingRepository.NewUnitOfWork(db),
+		sqlDB:   sqlDB,
+		dialect: dialect,
+	}, nil
+}
+
+func (d *database) Close() error {
+	return d.sqlDB.Close()
+}
+
+// dialectFor returns the migration set matching DB.DRIVER.
+func dialectFor(driver string) migrations.Dialect {
+	if driver == pkgdatabase.DriverSQLite {
+		return migrations.SQLite
+	}
+	return migrations.Postgres
+}
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/rohanchauhan02/internal-transfer/domain/banking"
+	"github.com/rohanchauhan02/internal-transfer/dto"
+)
+
+// accountExport is one exported account row.
+type accountExport struct {
+	AccountID int    `json:"account_id"`
+	Balance   string `json:"balance"`
+	Frozen    bool   `json:"frozen"`
+	CreatedAt string `json:"created_at"`
+	UpdatedAt string `json:"updated_at"`
+}
+
+// export dumps every account or transaction, as CSV unless -o chooses another format.
+func (c *cli) export(ctx context.Context, args []string) error {
+	if err := c.requireDatabase("export"); err != nil {
+		return err
+	}
+	fs := c.flagSet("export")
+	file := fs.String("file", "", "write to this file instead of stdout")
+	if err := fs.Parse(args); err != nil {
+		return fmt.Errorf("%w: %v", errUsage, err)
+	}
+	if fs.NArg() == 0 {
+		return fmt.Errorf("%w: export accounts | transactions", errUsage)
+	}
+
+	var r result
+	switch fs.Arg(0) {
+	case "accounts":
+		accounts, err := c.db.repo.ListAccounts(ctx)
+		if err != nil {
+			return err
+		}
+		exported := make([]accountExport, len(accounts))
+		r.header = []string{"account_id", "balance", "frozen", "created_at", "updated_at"}
+		for i, a := range accounts {
+			exported[i] = accountExport{
+				AccountID: a.AccountID,
+				Balance:   a.Balance,
+				Frozen:    a.FrozenAt != nil,
+				CreatedAt: formatTime(a.CreatedAt),
+				UpdatedAt: formatTime(a.UpdatedAt),
+			}
+			r.rows = append(r.rows, []string{strconv.Itoa(a.AccountID), a.Balance,
+				strconv.FormatBool(a.FrozenAt != nil), exported[i].CreatedAt, exported[i].UpdatedAt})
+		}
+		r.value = exported
+	case "transactions":
+		sub := c.flagSet("export transactions")
+		account := sub.Int("account", 0, "only transactions from or to this account")
+		if err := sub.Parse(fs.Args()[1:]); err != nil {
+			return fmt.Errorf("%w: %v", errUsage, err)
+		}
+		filter := banking.TransactionFilter{}
+		if *account != 0 {
+			filter.AccountID = account
+		}
+		transactions, err := c.usecase.ListTransactions(ctx, filter)
+		if err != nil {
+			return err
+		}
+		if transactions == nil {
+			transactions = []dto.TransactionResponse{}
+		}
+		r = transactionsResult(transactions)
+		r.header = []string{"id", "source_account_id", "destination_account_id", "amount", "created_at"}
+	default:
+		return fmt.Errorf("%w: unknown export %q", errUsage, fs.Arg(0))
+	}
+
+	format := c.opts.output
+	if format == "" {
+		format = formatCSV
+	}
+	if *file == "" {
+		return render(c.out, format, r)
+	}
+	f, err := os.Create(*file)
+	if err != nil {
+		return err
+	}
+	if err := render(f, format, r); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
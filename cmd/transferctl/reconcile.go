@@ -0,0 +1,130 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/rohanchauhan02/internal-transfer/domain/banking"
+	"github.com/shopspring/decimal"
+)
+
+// errReconcileFailed makes reconcile exit non-zero when it finds problems.
+var errReconcileFailed = errors.New("reconciliation found problems")
+
+// reconciliation is the ledger view of one account. Accounts are created with a balance that
+// the ledger does not record, so the opening balance is implied: the current balance with every
+// transfer undone. It can never be negative.
+type reconciliation struct {
+	AccountID int      `json:"account_id"`
+	Balance   string   `json:"balance"`
+	Credits   string   `json:"credits"`
+	Debits    string   `json:"debits"`
+	Opening   string   `json:"implied_opening_balance"`
+	Problems  []string `json:"problems"`
+}
+
+// reconcile checks every balance against the ledger. It reads all accounts and transactions
+// outside a unit of work, so run it while transfers are paused for an exact result.
+func (c *cli) reconcile(ctx context.Context, args []string) error {
+	if err := c.requireDatabase("reconcile"); err != nil {
+		return err
+	}
+	fs := c.flagSet("reconcile")
+	onlyProblems := fs.Bool("problems", false, "only list accounts with problems")
+	if err := fs.Parse(args); err != nil {
+		return fmt.Errorf("%w: %v", errUsage, err)
+	}
+
+	accounts, err := c.db.repo.ListAccounts(ctx)
+	if err != nil {
+		return err
+	}
+	transactions, err := c.db.repo.ListTransactions(ctx, banking.TransactionFilter{})
+	if err != nil {
+		return err
+	}
+
+	type ledger struct {
+		credits, debits decimal.Decimal
+		problems        []string
+		known           bool
+		balance         string
+	}
+	ledgers := make(map[int]*ledger, len(accounts))
+	get := func(id int) *ledger {
+		l, ok := ledgers[id]
+		if !ok {
+			l = &ledger{}
+			ledgers[id] = l
+		}
+		return l
+	}
+	for _, a := range accounts {
+		l := get(a.AccountID)
+		l.known, l.balance = true, a.Balance
+	}
+	for _, t := range transactions {
+		from, to := get(t.SourceAccountID), get(t.DestinationAccountID)
+		amount, err := decimal.NewFromString(t.Amount)
+		if err != nil || !amount.IsPositive() {
+			problem := fmt.Sprintf("transaction %d has invalid amount %q", t.ID, t.Amount)
+			from.problems = append(from.problems, problem)
+			to.problems = append(to.problems, problem)
+			continue
+		}
+		from.debits = from.debits.Add(amount)
+		to.credits = to.credits.Add(amount)
+	}
+
+	var rows []reconciliation
+	problems := 0
+	for id, l := range ledgers {
+		row := reconciliation{AccountID: id, Balance: l.balance, Credits: l.credits.String(), Debits: l.debits.String()}
+		row.Problems = append(row.Problems, l.problems...)
+		if !l.known {
+			row.Problems = append(row.Problems, "referenced by transactions but does not exist")
+		} else if balance, err := decimal.NewFromString(l.balance); err != nil {
+			row.Problems = append(row.Problems, fmt.Sprintf("invalid balance %q", l.balance))
+		} else {
+			opening := balance.Sub(l.credits).Add(l.debits)
+			row.Opening = opening.String()
+			if balance.IsNegative() {
+				row.Problems = append(row.Problems, "negative balance")
+			}
+			if opening.IsNegative() {
+				row.Problems = append(row.Problems, "ledger moved more out of the account than it held")
+			}
+		}
+		if len(row.Problems) > 0 {
+			problems++
+		} else if *onlyProblems {
+			continue
+		}
+		if row.Problems == nil {
+			row.Problems = []string{}
+		}
+		rows = append(rows, row)
+	}
+	sort.Slice(rows, func(i, j int) bool { return rows[i].AccountID < rows[j].AccountID })
+
+	r := result{header: []string{"ACCOUNT", "BALANCE", "CREDITS", "DEBITS", "OPENING", "STATUS"}, value: rows}
+	for _, row := range rows {
+		status := "ok"
+		if len(row.Problems) > 0 {
+			status = strings.Join(row.Problems, "; ")
+		}
+		r.rows = append(r.rows, []string{strconv.Itoa(row.AccountID), row.Balance, row.Credits, row.Debits, row.Opening, status})
+	}
+	if err := render(c.out, c.opts.output, r); err != nil {
+		return err
+	}
+	fmt.Fprintf(c.errOut, "checked %d accounts and %d transactions: %d with problems\n", len(accounts), len(transactions), problems)
+	if problems > 0 {
+		return errReconcileFailed
+	}
+	return nil
+}
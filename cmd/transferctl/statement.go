@@ -0,0 +1,95 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/rohanchauhan02/internal-transfer/dto"
+	"github.com/rohanchauhan02/internal-transfer/pkg/statement"
+)
+
+// statement prints an account statement, or writes it as a PDF with -pdf, as camt.053 XML with
+// -camt053 and as MT940 with -mt940.
+func (c *cli) statement(ctx context.Context, args []string) error {
+	fs := c.flagSet("statement")
+	fromFlag := fs.String("from", "", "start of the period, a date or an RFC 3339 time")
+	toFlag := fs.String("to", "", "end of the period, a date (included) or an RFC 3339 time")
+	documents := map[string]*string{
+		statement.FormatPDF:     fs.String("pdf", "", "write the statement as a PDF to this file"),
+		statement.FormatCamt053: fs.String("camt053", "", "write the statement as ISO 20022 camt.053 XML to this file"),
+		statement.FormatMT940:   fs.String("mt940", "", "write the statement as SWIFT MT940 to this file"),
+	}
+	if err := fs.Parse(args); err != nil {
+		return fmt.Errorf("%w: %v", errUsage, err)
+	}
+	if fs.NArg() != 1 {
+		return fmt.Errorf("%w: statement [-from date] [-to date] [-pdf file] [-camt053 file] [-mt940 file] <id>", errUsage)
+	}
+	id, err := parseAccountID(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	to := time.Now().UTC()
+	if *toFlag != "" {
+		if to, err = statement.ParseTime(*toFlag, true); err != nil {
+			return fmt.Errorf("%w: -to %v", errUsage, err)
+		}
+	}
+	from := to.AddDate(0, 0, -statement.DefaultDays)
+	if *fromFlag != "" {
+		if from, err = statement.ParseTime(*fromFlag, false); err != nil {
+			return fmt.Errorf("%w: -from %v", errUsage, err)
+		}
+	}
+
+	s, err := c.usecase.Statement(ctx, id, from, to)
+	if err != nil {
+		return err
+	}
+	written := false
+	for _, format := range statement.Formats {
+		path, ok := documents[format]
+		if !ok || *path == "" {
+			continue
+		}
+		if err := writeStatement(*path, format, s); err != nil {
+			return err
+		}
+		fmt.Fprintf(c.errOut, "wrote %s\n", *path)
+		written = true
+	}
+	if written {
+		return nil
+	}
+	if c.opts.output == formatCSV {
+		return statement.WriteCSV(c.out, s)
+	}
+
+	r := result{header: []string{"DATE", "ID", "DESCRIPTION", "DEBIT", "CREDIT", "BALANCE"}, value: s}
+	r.rows = append(r.rows, []string{formatTime(s.From), "", "Opening balance", "", "", s.OpeningBalance})
+	for _, e := range s.Entries {
+		debit, credit := e.Amount, ""
+		if e.Direction != dto.StatementDebit {
+			debit, credit = "", e.Amount
+		}
+		r.rows = append(r.rows, []string{formatTime(e.Date), strconv.FormatUint(uint64(e.TransactionID), 10),
+			statement.Description(e), debit, credit, e.Balance})
+	}
+	r.rows = append(r.rows, []string{formatTime(s.To), "", "Closing balance", s.TotalDebits, s.TotalCredits, s.ClosingBalance})
+	return render(c.out, c.opts.output, r)
+}
+
+func writeStatement(path, format string, s dto.Statement) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := statement.Write(f, format, s); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
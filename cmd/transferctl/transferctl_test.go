@@ -0,0 +1,359 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/rohanchauhan02/internal-transfer/domain/banking"
+	BankingHandler "github.com/rohanchauhan02/internal-transfer/domain/banking/delivery/https"
+	BankingRepository "github.com/rohanchauhan02/internal-transfer/domain/banking/repository"
+	BankingUsecase "github.com/rohanchauhan02/internal-transfer/domain/banking/usecase"
+	"github.com/rohanchauhan02/internal-transfer/dto"
+	"github.com/rohanchauhan02/internal-transfer/pkg/config"
+	"github.com/rohanchauhan02/internal-transfer/pkg/ctx"
+	pkgdatabase "github.com/rohanchauhan02/internal-transfer/pkg/database"
+	"github.com/rohanchauhan02/internal-transfer/pkg/logger"
+	CustomMiddileware "github.com/rohanchauhan02/internal-transfer/pkg/middleware"
+	"github.com/rohanchauhan02/internal-transfer/pkg/migrations"
+	"github.com/rohanchauhan02/internal-transfer/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+// newDatabaseCLI runs commands against a migrated SQLite database in a temp dir.
+func newDatabaseCLI(t *testing.T) (*cli, *gorm.DB) {
+	t.Helper()
+	logger.Init(config.Logging{Level: "error"})
+	ctx := context.Background()
+	db, err := pkgdatabase.NewSQLite(config.DB{Path: filepath.Join(t.TempDir(), "transferctl.db")}).InitClient(ctx)
+	require.NoError(t, err)
+	sqlDB, err := db.DB()
+	require.NoError(t, err)
+	migrator, err := migrations.New(sqlDB, migrations.SQLite)
+	require.NoError(t, err)
+	_, err = migrator.Up(ctx)
+	require.NoError(t, err)
+
+	d, err := newDatabase(ctx, db, migrations.SQLite, true)
+	require.NoError(t, err)
+	t.Cleanup(func() { d.Close() })
+	return &cli{
+		opts:    options{yes: true},
+		in:      bufio.NewReader(strings.NewReader("")),
+		out:     &bytes.Buffer{},
+		errOut:  &bytes.Buffer{},
+		usecase: BankingUsecase.NewBankingUsecase(d.uow, d.repo, config.Banking{}),
+		db:      d,
+	}, db
+}
+
+// exec runs args and returns what the command printed.
+func exec(t *testing.T, c *cli, args ...string) (string, error) {
+	t.Helper()
+	out := c.out.(*bytes.Buffer)
+	out.Reset()
+	err := c.dispatch(context.Background(), args)
+	return out.String(), err
+}
+
+func TestAccountsAndTransfers(t *testing.T) {
+	c, _ := newDatabaseCLI(t)
+
+	out, err := exec(t, c, "account", "create", "1", "100")
+	require.NoError(t, err)
+	assert.Regexp(t, `ACCOUNT\s+BALANCE\s+FROZEN\n1\s+100\s+false`, out)
+	_, err = exec(t, c, "account", "create", "2", "0")
+	require.NoError(t, err)
+
+	c.opts.output = formatJSON
+	out, err = exec(t, c, "transfer", "1", "2", "25.5")
+	require.NoError(t, err)
+	var accounts []dto.AccountResponse
+	require.NoError(t, json.Unmarshal([]byte(out), &accounts))
+	assert.Equal(t, []dto.AccountResponse{{AccountID: 1, Balance: "74.5"}, {AccountID: 2, Balance: "25.5"}}, accounts)
+
+	c.opts.output = formatCSV
+	out, err = exec(t, c, "history", "-account", "2")
+	require.NoError(t, err)
+	records, err := csv.NewReader(strings.NewReader(out)).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, records, 2)
+	assert.Equal(t, []string{"1", "2", "25.5"}, records[1][1:4])
+
+	_, err = exec(t, c, "transfer", "-reference", "INV-7", "-metadata", `{"order":"A-7"}`, "-unique-reference", "1", "2", "4.5")
+	require.NoError(t, err)
+	_, err = exec(t, c, "transfer", "-reference", "INV-7", "-unique-reference", "1", "2", "4.5")
+	assert.ErrorIs(t, err, banking.ErrDuplicateReference)
+	for _, args := range [][]string{{"-reference", "INV-7"}, {"-metadata", "order=A-7"}} {
+		out, err = exec(t, c, append([]string{"history"}, args...)...)
+		require.NoError(t, err)
+		records, err = csv.NewReader(strings.NewReader(out)).ReadAll()
+		require.NoError(t, err)
+		require.Len(t, records, 2, "history %v", args)
+		assert.Equal(t, []string{"1", "2", "4.5", "INV-7"}, records[1][1:5])
+	}
+
+	_, err = exec(t, c, "account", "freeze", "2")
+	require.NoError(t, err)
+	_, err = exec(t, c, "transfer", "1", "2", "1")
+	assert.ErrorContains(t, err, "account is frozen")
+
+	_, err = exec(t, c, "account", "show", "9")
+	assert.ErrorContains(t, err, "account not found")
+	_, err = exec(t, c, "account", "show", "abc")
+	assert.ErrorIs(t, err, errUsage)
+}
+
+func TestDestructiveCommandsAskFirst(t *testing.T) {
+	c, _ := newDatabaseCLI(t)
+	_, err := exec(t, c, "account", "create", "1", "10")
+	require.NoError(t, err)
+	_, err = exec(t, c, "account", "create", "2", "10")
+	require.NoError(t, err)
+	c.opts.yes = false
+
+	c.in = bufio.NewReader(strings.NewReader("n\n"))
+	_, err = exec(t, c, "transfer", "1", "2", "5")
+	assert.ErrorIs(t, err, errAborted)
+	assert.Contains(t, c.errOut.(*bytes.Buffer).String(), "transfer 5 from account 1 to account 2? [y/N]")
+
+	c.in = bufio.NewReader(strings.NewReader(""))
+	_, err = exec(t, c, "migrate", "down")
+	assert.ErrorIs(t, err, errAborted, "no answer means no")
+
+	c.in = bufio.NewReader(strings.NewReader("yes\n"))
+	_, err = exec(t, c, "account", "freeze", "1")
+	require.NoError(t, err)
+	account, err := c.usecase.GetAccount(context.Background(), 1)
+	require.NoError(t, err)
+	assert.True(t, account.Frozen)
+}
+
+func TestCustomers(t *testing.T) {
+	c, _ := newDatabaseCLI(t)
+
+	out, err := exec(t, c, "customer", "create", "-email", "ops@acme.example", "business", "Acme Ltd")
+	require.NoError(t, err)
+	assert.Regexp(t, `1\s+Acme Ltd\s+business\s+pending\s+ops@acme.example`, out)
+	_, err = exec(t, c, "customer", "create", "trust", "Acme Trust")
+	assert.ErrorIs(t, err, banking.ErrInvalidCustomer)
+
+	_, err = exec(t, c, "customer", "open", "1", "1", "100")
+	require.NoError(t, err)
+	out, err = exec(t, c, "customer", "open", "1", "2", "0.5")
+	require.NoError(t, err)
+	assert.Regexp(t, `1\s+100\s+false\n2\s+0.5\s+false`, out)
+	assert.Contains(t, c.errOut.(*bytes.Buffer).String(), "customer 1 Acme Ltd (business, KYC pending): 2 accounts, total balance 100.5")
+	_, err = exec(t, c, "customer", "open", "2", "3", "1")
+	assert.ErrorIs(t, err, banking.ErrCustomerNotFound)
+
+	c.opts.output = formatCSV
+	_, err = exec(t, c, "transfer", "1", "2", "10")
+	require.NoError(t, err)
+	c.opts.output = formatJSON
+	out, err = exec(t, c, "history")
+	require.NoError(t, err)
+	var transactions []dto.TransactionResponse
+	require.NoError(t, json.Unmarshal([]byte(out), &transactions))
+	require.Len(t, transactions, 1)
+	assert.True(t, transactions[0].Internal)
+
+	out, err = exec(t, c, "customer", "kyc", "1", "verified")
+	require.NoError(t, err)
+	var customer dto.CustomerResponse
+	require.NoError(t, json.Unmarshal([]byte(out), &customer))
+	assert.Equal(t, "verified", customer.KYCStatus)
+	_, err = exec(t, c, "customer", "show", "x")
+	assert.ErrorIs(t, err, errUsage)
+}
+
+func TestImportAccounts(t *testing.T) {
+	c, _ := newDatabaseCLI(t)
+	path := filepath.Join(t.TempDir(), "accounts.csv")
+	require.NoError(t, os.WriteFile(path, []byte("currency,account_id,initial_balance\nUSD,1,10\nXX,2,5\n"), 0o600))
+
+	out, err := exec(t, c, "account", "import", path)
+	assert.ErrorIs(t, err, banking.ErrImportInvalid)
+	assert.Regexp(t, `2\s+2\s+currency\s+must be a three letter`, out)
+
+	require.NoError(t, os.WriteFile(path, []byte("currency,account_id,initial_balance\nUSD,1,10\nEUR,2,5\n"), 0o600))
+	c.opts.yes = false
+	c.in = bufio.NewReader(strings.NewReader("n\n"))
+	_, err = exec(t, c, "account", "import", path)
+	assert.ErrorIs(t, err, errAborted)
+	assert.Contains(t, c.errOut.(*bytes.Buffer).String(), "import 2 accounts (0 already exist)?")
+
+	c.opts.yes = true
+	_, err = exec(t, c, "account", "import", "-chunk-size", "1", path)
+	require.NoError(t, err)
+	assert.Contains(t, c.errOut.(*bytes.Buffer).String(), "2 valid, 0 invalid, 2 created")
+	account, err := c.usecase.GetAccount(context.Background(), 2)
+	require.NoError(t, err)
+	assert.Equal(t, "EUR", account.Currency)
+}
+
+func TestStatement(t *testing.T) {
+	c, _ := newDatabaseCLI(t)
+	for _, args := range [][]string{
+		{"account", "create", "1", "100"},
+		{"account", "create", "2", "0"},
+		{"transfer", "1", "2", "30"},
+		{"transfer", "2", "1", "5"},
+	} {
+		_, err := exec(t, c, args...)
+		require.NoError(t, err)
+	}
+
+	out, err := exec(t, c, "statement", "1")
+	require.NoError(t, err)
+	assert.Regexp(t, `Opening balance\s+100\n`, out)
+	assert.Regexp(t, `Transfer to account 2\s+30\s+70\n`, out)
+	assert.Regexp(t, `Transfer from account 2\s+5\s+75\n`, out)
+	assert.Regexp(t, `Closing balance\s+30\s+5\s+75\n`, out)
+
+	path := filepath.Join(t.TempDir(), "statement.pdf")
+	_, err = exec(t, c, "statement", "-from", "2020-01-01", "-pdf", path, "1")
+	require.NoError(t, err)
+	pdf, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.True(t, bytes.HasPrefix(pdf, []byte("%PDF-")))
+
+	path = filepath.Join(t.TempDir(), "statement.xml")
+	_, err = exec(t, c, "statement", "-camt053", path, "1")
+	require.NoError(t, err)
+	camt, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Contains(t, string(camt), `<Document xmlns="urn:iso:std:iso:20022:tech:xsd:camt.053.001.08">`)
+	assert.Contains(t, string(camt), "<NbOfNtries>2</NbOfNtries>")
+
+	path = filepath.Join(t.TempDir(), "statement.sta")
+	_, err = exec(t, c, "statement", "-mt940", path, "1")
+	require.NoError(t, err)
+	mt940, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Contains(t, string(mt940), ":60F:C")
+	assert.Regexp(t, `:61:\d{10}D30,NTRFNONREF//\d+\r\n:86:Transfer to account 2 TX \d+\r\n`, string(mt940))
+	assert.Regexp(t, `:62F:C\d{6}XXX75,\r\n-\r\n$`, string(mt940))
+
+	_, err = exec(t, c, "statement", "-from", "yesterday", "1")
+	assert.ErrorIs(t, err, errUsage)
+}
+
+func TestReconcile(t *testing.T) {
+	c, db := newDatabaseCLI(t)
+	for _, args := range [][]string{
+		{"account", "create", "1", "100"},
+		{"account", "create", "2", "0"},
+		{"transfer", "1", "2", "30"},
+	} {
+		_, err := exec(t, c, args...)
+		require.NoError(t, err)
+	}
+
+	out, err := exec(t, c, "reconcile")
+	require.NoError(t, err)
+	assert.Regexp(t, `1\s+70\s+0\s+30\s+100\s+ok`, out)
+	assert.Regexp(t, `2\s+30\s+30\s+0\s+0\s+ok`, out)
+
+	// A balance edited by hand leaves the ledger unable to explain it.
+	require.NoError(t, db.Exec(`UPDATE accounts SET balance = '10' WHERE account_id = 2`).Error)
+	c.opts.output = formatJSON
+	out, err = exec(t, c, "reconcile", "-problems")
+	assert.ErrorIs(t, err, errReconcileFailed)
+	var rows []reconciliation
+	require.NoError(t, json.Unmarshal([]byte(out), &rows))
+	require.Len(t, rows, 1)
+	assert.Equal(t, 2, rows[0].AccountID)
+	assert.Equal(t, "-20", rows[0].Opening)
+	assert.Equal(t, []string{"ledger moved more out of the account than it held"}, rows[0].Problems)
+}
+
+func TestExportAndMigrate(t *testing.T) {
+	c, _ := newDatabaseCLI(t)
+	for _, args := range [][]string{
+		{"account", "create", "2", "5"},
+		{"account", "create", "1", "5"},
+		{"transfer", "1", "2", "1"},
+	} {
+		_, err := exec(t, c, args...)
+		require.NoError(t, err)
+	}
+
+	out, err := exec(t, c, "export", "accounts")
+	require.NoError(t, err)
+	records, err := csv.NewReader(strings.NewReader(out)).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, records, 3)
+	assert.Equal(t, []string{"account_id", "balance", "frozen", "created_at", "updated_at"}, records[0])
+	assert.Equal(t, []string{"1", "4", "false"}, records[1][:3])
+
+	file := filepath.Join(t.TempDir(), "transactions.json")
+	c.opts.output = formatJSON
+	_, err = exec(t, c, "export", "-file", file, "transactions", "-account", "1")
+	require.NoError(t, err)
+	assert.FileExists(t, file)
+
+	out, err = exec(t, c, "migrate", "status")
+	require.NoError(t, err)
+	assert.Regexp(t, `0004\s+account_frozen_at\s+\d{4}-`, out)
+	_, err = exec(t, c, "migrate", "sideways")
+	assert.ErrorIs(t, err, errUsage)
+}
+
+func TestAPIMode(t *testing.T) {
+	logger.Init(config.Logging{Level: "error"})
+	e := echo.New()
+	e.Validator = utils.DefaultValidator()
+	e.Use(func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			return next(&ctx.CustomApplicationContext{Context: c})
+		}
+	})
+	// Authentication disabled, as in local development: every request is an admin.
+	e.Use(CustomMiddileware.MiddlewareAPIKey(nil, false))
+	store := BankingRepository.NewMemoryStore()
+	BankingHandler.NewBankingHandler(e, BankingUsecase.NewBankingUsecase(store, store, config.Banking{}))
+	server := httptest.NewServer(e)
+	defer server.Close()
+
+	transferctl := func(stdin string, args ...string) (int, string, string) {
+		var out, errOut bytes.Buffer
+		code := run(context.Background(), append([]string{"-api", server.URL}, args...), strings.NewReader(stdin), &out, &errOut)
+		return code, out.String(), errOut.String()
+	}
+
+	code, _, _ := transferctl("", "account", "create", "1", "50")
+	require.Equal(t, 0, code)
+	code, _, _ = transferctl("", "account", "create", "2", "0")
+	require.Equal(t, 0, code)
+	code, _, _ = transferctl("y\n", "transfer", "1", "2", "20")
+	require.Equal(t, 0, code)
+
+	code, out, _ := transferctl("", "-o", "json", "history")
+	require.Equal(t, 0, code)
+	var transactions []dto.TransactionResponse
+	require.NoError(t, json.Unmarshal([]byte(out), &transactions))
+	require.Len(t, transactions, 1)
+	assert.Equal(t, "20", transactions[0].Amount)
+
+	code, _, errOut := transferctl("", "account", "freeze", "1")
+	assert.Equal(t, 1, code, "stdin closed without an answer")
+	assert.Contains(t, errOut, "aborted")
+
+	code, _, errOut = transferctl("", "reconcile")
+	assert.Equal(t, 1, code)
+	assert.Contains(t, errOut, "needs direct database access")
+
+	code, _, _ = transferctl("", "bogus")
+	assert.Equal(t, 2, code)
+}
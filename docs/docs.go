@@ -0,0 +1,82 @@
+// Package docs holds the generated OpenAPI document and serves it together with Swagger UI.
+package docs
+
+import (
+	_ "embed"
+	"encoding/json"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	BankingHandler "github.com/rohanchauhan02/internal-transfer/domain/banking/delivery/https"
+	HealthzHandler "github.com/rohanchauhan02/internal-transfer/domain/health/delivery/https"
+	"github.com/rohanchauhan02/internal-transfer/pkg/openapi"
+	swaggerFiles "github.com/swaggo/files/v2"
+)
+
+// SpecPath is where the OpenAPI document is served.
+const SpecPath = "/api/v1/openapi.json"
+
+// UIPath is where Swagger UI is served.
+const UIPath = "/api/v1/docs"
+
+// Info describes the API in the generated document.
+var Info = openapi.Info{
+	Title:       "Internal Transfer API",
+	Version:     "1.0.0",
+	Description: "Accounts and internal transfers between them.",
+}
+
+// Spec is the committed document, regenerated with `make openapi`.
+//
+//go:embed openapi.json
+var Spec []byte
+
+// swaggerInitializer replaces the petstore default shipped with Swagger UI.
+const swaggerInitializer = `window.onload = function() {
+  window.ui = SwaggerUIBundle({
+    url: "` + SpecPath + `",
+    dom_id: '#swagger-ui',
+    deepLinking: true,
+    presets: [
+      SwaggerUIBundle.presets.apis,
+      SwaggerUIStandalonePreset
+    ],
+    plugins: [
+      SwaggerUIBundle.plugins.DownloadUrl
+    ],
+    layout: "StandaloneLayout"
+  });
+};
+`
+
+// Build generates the document from the routes registered by the banking and health handlers.
+func Build() ([]byte, error) {
+	e := echo.New()
+	BankingHandler.NewBankingHandler(e, nil)
+	HealthzHandler.NewHealthHandler(e, nil)
+
+	ops := append(BankingHandler.Operations(), HealthzHandler.Operations()...)
+	doc, err := openapi.Generate(Info, e.Routes(), ops)
+	if err != nil {
+		return nil, err
+	}
+	out, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(out, '\n'), nil
+}
+
+// NewDocsHandler serves the OpenAPI document and Swagger UI.
+func NewDocsHandler(e *echo.Echo) {
+	e.GET(SpecPath, func(c echo.Context) error {
+		return c.Blob(http.StatusOK, echo.MIMEApplicationJSON, Spec)
+	})
+	e.GET(UIPath, func(c echo.Context) error {
+		return c.Redirect(http.StatusMovedPermanently, UIPath+"/index.html")
+	})
+	e.GET(UIPath+"/swagger-initializer.js", func(c echo.Context) error {
+		return c.Blob(http.StatusOK, echo.MIMEApplicationJavaScriptCharsetUTF8, []byte(swaggerInitializer))
+	})
+	e.StaticFS(UIPath, swaggerFiles.FS)
+}
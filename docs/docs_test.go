@@ -0,0 +1,48 @@
+package docs
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestSpecIsUpToDate fails when a route or DTO changed without regenerating the document.
+func TestSpecIsUpToDate(t *testing.T) {
+	built, err := Build()
+	require.NoError(t, err, "every route needs an Operation next to where it is registered")
+	require.Equal(t, string(built), string(Spec), "docs/openapi.json is stale, run `make openapi`")
+}
+
+func TestNewDocsHandler(t *testing.T) {
+	e := echo.New()
+	NewDocsHandler(e)
+
+	get := func(path string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		return rec
+	}
+
+	rec := get(SpecPath)
+	require.Equal(t, http.StatusOK, rec.Code)
+	var doc map[string]any
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &doc))
+	assert.Equal(t, "3.1.0", doc["openapi"])
+
+	rec = get(UIPath)
+	assert.Equal(t, http.StatusMovedPermanently, rec.Code)
+	assert.Equal(t, UIPath+"/index.html", rec.Header().Get(echo.HeaderLocation))
+
+	rec = get(UIPath + "/index.html")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), "swagger-ui")
+
+	rec = get(UIPath + "/swagger-initializer.js")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), SpecPath)
+}
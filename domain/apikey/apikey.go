@@ -0,0 +1,70 @@
+package apikey
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/rohanchauhan02/internal-transfer/dto"
+	"github.com/rohanchauhan02/internal-transfer/models"
+)
+
+// Scopes that can be granted to an API key. ScopeAdmin implies every other scope.
+const (
+	ScopeAccountsRead   = "accounts:read"
+	ScopeAccountsWrite  = "accounts:write"
+	ScopeTransfersWrite = "transfers:write"
+	ScopeAdmin          = "admin"
+)
+
+// ValidScopes lists every scope that can be granted to an API key.
+var ValidScopes = []string{ScopeAccountsRead, ScopeAccountsWrite, ScopeTransfersWrite, ScopeAdmin}
+
+var (
+	ErrInvalidKey    = errors.New("invalid api key")
+	ErrKeyExpired    = errors.New("api key expired")
+	ErrKeyRevoked    = errors.New("api key revoked")
+	ErrIPNotAllowed  = errors.New("client ip not allowed for this api key")
+	ErrKeyNotFound   = errors.New("api key not found")
+	ErrInvalidScope  = errors.New("invalid scope")
+	ErrInvalidIPRule = errors.New("invalid ip allow-list entry")
+	ErrInvalidName   = errors.New("api key name is required")
+	ErrInvalidExpiry = errors.New("api key expiry must be in the future")
+)
+
+// Principal is the identity attached to an authenticated request.
+type Principal struct {
+	KeyID  uint
+	Name   string
+	Scopes []string
+}
+
+// HasScope reports whether the principal was granted scope, either directly or through admin.
+func (p Principal) HasScope(scope string) bool {
+	for _, s := range p.Scopes {
+		if s == scope || s == ScopeAdmin {
+			return true
+		}
+	}
+	return false
+}
+
+type Usecase interface {
+	Issue(context.Context, dto.APIKeyCreateRequest) (dto.APIKeyCreatedResponse, error)
+	List(context.Context) ([]dto.APIKeyResponse, error)
+	UpdateScopes(context.Context, uint, []string) (dto.APIKeyResponse, error)
+	Revoke(context.Context, uint) error
+	Rotate(context.Context, uint, time.Duration) (dto.APIKeyCreatedResponse, error)
+	Authenticate(context.Context, string, string) (Principal, error)
+	Close()
+}
+type Repository interface {
+	Create(context.Context, *models.APIKey) error
+	GetByID(context.Context, uint) (models.APIKey, error)
+	GetByPrefix(context.Context, string) (models.APIKey, error)
+	List(context.Context) ([]models.APIKey, error)
+	Update(context.Context, models.APIKey) error
+	// Rotate stores replacement and saves old pointing to it in one transaction.
+	Rotate(ctx context.Context, old models.APIKey, replacement *models.APIKey) error
+	TouchLastUsed(context.Context, map[uint]time.Time) error
+}
@@ -0,0 +1,129 @@
+package https
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/rohanchauhan02/internal-transfer/domain/apikey"
+	"github.com/rohanchauhan02/internal-transfer/dto"
+	"github.com/rohanchauhan02/internal-transfer/pkg/ctx"
+	CustomMiddileware "github.com/rohanchauhan02/internal-transfer/pkg/middleware"
+)
+
+// defaultGracePeriod applies when a rotation request does not specify one.
+const defaultGracePeriod = 24 * time.Hour
+
+type apiKeyHandler struct {
+	usecase apikey.Usecase
+}
+
+// NewAPIKeyHandler registers the admin api key management endpoints.
+func NewAPIKeyHandler(e *echo.Echo, usecase apikey.Usecase) {
+	handler := &apiKeyHandler{
+		usecase: usecase,
+	}
+
+	admin := e.Group("/api/v1/admin", CustomMiddileware.RequireScope(apikey.ScopeAdmin))
+	admin.POST("/api-keys", handler.Issue)
+	admin.GET("/api-keys", handler.List)
+	admin.PUT("/api-keys/:id/scopes", handler.UpdateScopes)
+	admin.POST("/api-keys/:id/rotate", handler.Rotate)
+	admin.DELETE("/api-keys/:id", handler.Revoke)
+}
+
+func (h *apiKeyHandler) Issue(c echo.Context) error {
+	ac := c.(*ctx.CustomApplicationContext)
+	var req dto.APIKeyCreateRequest
+	if err := ac.CustomBind(&req); err != nil {
+		return ac.CustomResponse("Bad Request", nil, "", "Invalid request", http.StatusBadRequest, nil)
+	}
+	key, err := h.usecase.Issue(c.Request().Context(), req)
+	if err != nil {
+		return h.errorResponse(ac, err, "Failed to issue api key")
+	}
+	return ac.CustomResponse("Success", key, "API key issued; store it now, it will not be shown again", "", http.StatusCreated, nil)
+}
+
+func (h *apiKeyHandler) List(c echo.Context) error {
+	ac := c.(*ctx.CustomApplicationContext)
+	keys, err := h.usecase.List(c.Request().Context())
+	if err != nil {
+		return ac.CustomResponse("Internal Server Error", nil, "", "Failed to list api keys", http.StatusInternalServerError, nil)
+	}
+	return ac.CustomResponse("Success", keys, "API keys retrieved successfully", "", http.StatusOK, nil)
+}
+
+func (h *apiKeyHandler) UpdateScopes(c echo.Context) error {
+	ac := c.(*ctx.CustomApplicationContext)
+	id, err := parseID(c)
+	if err != nil {
+		return ac.CustomResponse("Bad Request", nil, "", "Invalid api key ID format", http.StatusBadRequest, nil)
+	}
+	var req dto.APIKeyScopesRequest
+	if err := ac.CustomBind(&req); err != nil {
+		return ac.CustomResponse("Bad Request", nil, "", "Invalid request", http.StatusBadRequest, nil)
+	}
+	key, err := h.usecase.UpdateScopes(c.Request().Context(), id, req.Scopes)
+	if err != nil {
+		return h.errorResponse(ac, err, "Failed to update api key scopes")
+	}
+	return ac.CustomResponse("Success", key, "API key scopes updated successfully", "", http.StatusOK, nil)
+}
+
+func (h *apiKeyHandler) Rotate(c echo.Context) error {
+	ac := c.(*ctx.CustomApplicationContext)
+	id, err := parseID(c)
+	if err != nil {
+		return ac.CustomResponse("Bad Request", nil, "", "Invalid api key ID format", http.StatusBadRequest, nil)
+	}
+	var req dto.APIKeyRotateRequest
+	if err := ac.CustomBind(&req); err != nil {
+		return ac.CustomResponse("Bad Request", nil, "", "Invalid request", http.StatusBadRequest, nil)
+	}
+	if req.GracePeriodSeconds < 0 {
+		return ac.CustomResponse("Bad Request", nil, "", "grace_period_seconds must not be negative", http.StatusBadRequest, nil)
+	}
+	grace := defaultGracePeriod
+	if req.GracePeriodSeconds > 0 {
+		grace = time.Duration(req.GracePeriodSeconds) * time.Second
+	}
+	key, err := h.usecase.Rotate(c.Request().Context(), id, grace)
+	if err != nil {
+		return h.errorResponse(ac, err, "Failed to rotate api key")
+	}
+	return ac.CustomResponse("Success", key, "API key rotated; the previous key stays valid until the grace period ends", "", http.StatusCreated, nil)
+}
+
+func (h *apiKeyHandler) Revoke(c echo.Context) error {
+	ac := c.(*ctx.CustomApplicationContext)
+	id, err := parseID(c)
+	if err != nil {
+		return ac.CustomResponse("Bad Request", nil, "", "Invalid api key ID format", http.StatusBadRequest, nil)
+	}
+	if err := h.usecase.Revoke(c.Request().Context(), id); err != nil {
+		return h.errorResponse(ac, err, "Failed to revoke api key")
+	}
+	return ac.CustomResponse("Success", nil, "API key revoked successfully", "", http.StatusOK, nil)
+}
+
+func (h *apiKeyHandler) errorResponse(ac *ctx.CustomApplicationContext, err error, fallback string) error {
+	switch {
+	case errors.Is(err, apikey.ErrKeyNotFound):
+		return ac.CustomResponse("Not Found", nil, "", err.Error(), http.StatusNotFound, nil)
+	case errors.Is(err, apikey.ErrInvalidScope), errors.Is(err, apikey.ErrInvalidIPRule),
+		errors.Is(err, apikey.ErrInvalidName), errors.Is(err, apikey.ErrInvalidExpiry):
+		return ac.CustomResponse("Bad Request", nil, "", err.Error(), http.StatusBadRequest, nil)
+	case errors.Is(err, apikey.ErrKeyRevoked), errors.Is(err, apikey.ErrKeyExpired):
+		return ac.CustomResponse("Conflict", nil, "", err.Error(), http.StatusConflict, nil)
+	default:
+		return ac.CustomResponse("Internal Server Error", nil, "", fallback, http.StatusInternalServerError, nil)
+	}
+}
+
+func parseID(c echo.Context) (uint, error) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	return uint(id), err
+}
@@ -0,0 +1,115 @@
+package repository
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/rohanchauhan02/internal-transfer/domain/apikey"
+	"github.com/rohanchauhan02/internal-transfer/models"
+)
+
+type memoryAPIKeyRepository struct {
+	mu     sync.RWMutex
+	keys   map[uint]models.APIKey
+	nextID uint
+}
+
+// NewMemoryAPIKeyRepository creates a Repository that keeps api keys in process memory
+func NewMemoryAPIKeyRepository() apikey.Repository {
+	return &memoryAPIKeyRepository{
+		keys: make(map[uint]models.APIKey),
+	}
+}
+
+// Create stores a new api key and fills in its generated ID
+func (r *memoryAPIKeyRepository) Create(_ context.Context, key *models.APIKey) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.nextID++
+	key.ID = r.nextID
+	now := time.Now()
+	key.CreatedAt, key.UpdatedAt = now, now
+	r.keys[key.ID] = *key
+	return nil
+}
+
+// GetByID retrieves an api key by its primary key
+func (r *memoryAPIKeyRepository) GetByID(_ context.Context, id uint) (models.APIKey, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	key, ok := r.keys[id]
+	if !ok {
+		return models.APIKey{}, apikey.ErrKeyNotFound
+	}
+	return key, nil
+}
+
+// GetByPrefix retrieves an api key by its public lookup prefix
+func (r *memoryAPIKeyRepository) GetByPrefix(_ context.Context, prefix string) (models.APIKey, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	for _, key := range r.keys {
+		if key.Prefix == prefix {
+			return key, nil
+		}
+	}
+	return models.APIKey{}, apikey.ErrKeyNotFound
+}
+
+// List returns every api key, newest first
+func (r *memoryAPIKeyRepository) List(_ context.Context) ([]models.APIKey, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	keys := make([]models.APIKey, 0, len(r.keys))
+	for _, key := range r.keys {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i].ID > keys[j].ID })
+	return keys, nil
+}
+
+// Update saves every field of an existing api key
+func (r *memoryAPIKeyRepository) Update(_ context.Context, key models.APIKey) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.keys[key.ID]; !ok {
+		return apikey.ErrKeyNotFound
+	}
+	key.UpdatedAt = time.Now()
+	r.keys[key.ID] = key
+	return nil
+}
+
+// Rotate stores the replacement key and retires the old one under a single lock
+func (r *memoryAPIKeyRepository) Rotate(_ context.Context, old models.APIKey, replacement *models.APIKey) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.keys[old.ID]; !ok {
+		return apikey.ErrKeyNotFound
+	}
+	r.nextID++
+	replacement.ID = r.nextID
+	now := time.Now()
+	replacement.CreatedAt, replacement.UpdatedAt = now, now
+	r.keys[replacement.ID] = *replacement
+	old.RotatedTo = &replacement.ID
+	old.UpdatedAt = now
+	r.keys[old.ID] = old
+	return nil
+}
+
+// TouchLastUsed records the last-used timestamp for a batch of keys
+func (r *memoryAPIKeyRepository) TouchLastUsed(_ context.Context, usage map[uint]time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for id, at := range usage {
+		if key, ok := r.keys[id]; ok {
+			at := at
+			key.LastUsedAt = &at
+			r.keys[id] = key
+		}
+	}
+	return nil
+}
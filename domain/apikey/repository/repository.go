@@ -0,0 +1,90 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/rohanchauhan02/internal-transfer/domain/apikey"
+	"github.com/rohanchauhan02/internal-transfer/models"
+	"gorm.io/gorm"
+)
+
+type apiKeyRepository struct {
+	db *gorm.DB
+}
+
+// NewAPIKeyRepository creates a new Repository instance
+func NewAPIKeyRepository(db *gorm.DB) apikey.Repository {
+	return &apiKeyRepository{
+		db: db,
+	}
+}
+
+// Create stores a new api key and fills in its generated ID
+func (r *apiKeyRepository) Create(ctx context.Context, key *models.APIKey) error {
+	return r.db.WithContext(ctx).Create(key).Error
+}
+
+// GetByID retrieves an api key by its primary key
+func (r *apiKeyRepository) GetByID(ctx context.Context, id uint) (models.APIKey, error) {
+	var key models.APIKey
+	if err := r.db.WithContext(ctx).First(&key, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return models.APIKey{}, apikey.ErrKeyNotFound
+		}
+		return models.APIKey{}, err
+	}
+	return key, nil
+}
+
+// GetByPrefix retrieves an api key by its public lookup prefix
+func (r *apiKeyRepository) GetByPrefix(ctx context.Context, prefix string) (models.APIKey, error) {
+	var key models.APIKey
+	if err := r.db.WithContext(ctx).Where("prefix = ?", prefix).First(&key).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return models.APIKey{}, apikey.ErrKeyNotFound
+		}
+		return models.APIKey{}, err
+	}
+	return key, nil
+}
+
+// List returns every api key, newest first
+func (r *apiKeyRepository) List(ctx context.Context) ([]models.APIKey, error) {
+	var keys []models.APIKey
+	if err := r.db.WithContext(ctx).Order("id DESC").Find(&keys).Error; err != nil {
+		return nil, err
+	}
+	return keys, nil
+}
+
+// Update saves every field of an existing api key
+func (r *apiKeyRepository) Update(ctx context.Context, key models.APIKey) error {
+	return r.db.WithContext(ctx).Save(&key).Error
+}
+
+// Rotate stores the replacement key and retires the old one in one transaction, so a failure
+// leaves neither change behind
+func (r *apiKeyRepository) Rotate(ctx context.Context, old models.APIKey, replacement *models.APIKey) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(replacement).Error; err != nil {
+			return err
+		}
+		old.RotatedTo = &replacement.ID
+		return tx.Save(&old).Error
+	})
+}
+
+// TouchLastUsed records the last-used timestamp for a batch of keys
+func (r *apiKeyRepository) TouchLastUsed(ctx context.Context, usage map[uint]time.Time) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for id, at := range usage {
+			if err := tx.Model(&models.APIKey{}).Where("id = ?", id).
+				UpdateColumn("last_used_at", at).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
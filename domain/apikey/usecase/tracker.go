@@ -0,0 +1,99 @@
+package usecase
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/rohanchauhan02/internal-transfer/domain/apikey"
+	"github.com/rohanchauhan02/internal-transfer/pkg/logger"
+)
+
+var log = logger.For("apikey")
+
+type keyUsage struct {
+	id uint
+	at time.Time
+}
+
+// lastUsedTracker batches last-used timestamps in memory and writes them out periodically,
+// so authenticating a request never waits on a database write.
+type lastUsedTracker struct {
+	repo     apikey.Repository
+	interval time.Duration
+	events   chan keyUsage
+	quit     chan struct{}
+	done     chan struct{}
+	stopOnce sync.Once
+}
+
+func newLastUsedTracker(repo apikey.Repository, interval time.Duration) *lastUsedTracker {
+	t := &lastUsedTracker{
+		repo:     repo,
+		interval: interval,
+		events:   make(chan keyUsage, 1024),
+		quit:     make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+	go t.run()
+	return t
+}
+
+// touch queues a usage event. It never blocks: when the buffer is full the event is dropped,
+// which only makes the recorded timestamp slightly stale.
+func (t *lastUsedTracker) touch(id uint, at time.Time) {
+	select {
+	case t.events <- keyUsage{id: id, at: at}:
+	default:
+	}
+}
+
+func (t *lastUsedTracker) stop() {
+	t.stopOnce.Do(func() {
+		close(t.quit)
+		<-t.done
+	})
+}
+
+func (t *lastUsedTracker) run() {
+	defer close(t.done)
+	ticker := time.NewTicker(t.interval)
+	defer ticker.Stop()
+
+	pending := make(map[uint]time.Time)
+	for {
+		select {
+		case ev := <-t.events:
+			if ev.at.After(pending[ev.id]) {
+				pending[ev.id] = ev.at
+			}
+		case <-ticker.C:
+			pending = t.flush(pending)
+		case <-t.quit:
+			for {
+				select {
+				case ev := <-t.events:
+					if ev.at.After(pending[ev.id]) {
+						pending[ev.id] = ev.at
+					}
+				default:
+					t.flush(pending)
+					return
+				}
+			}
+		}
+	}
+}
+
+func (t *lastUsedTracker) flush(pending map[uint]time.Time) map[uint]time.Time {
+	if len(pending) == 0 {
+		return pending
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := t.repo.TouchLastUsed(ctx, pending); err != nil {
+		log.ErrorContext(ctx, "failed to record api key usage", slog.Int("keys", len(pending)), slog.String("error", err.Error()))
+	}
+	return make(map[uint]time.Time)
+}
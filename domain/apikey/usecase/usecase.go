@@ -0,0 +1,335 @@
+package usecase
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/rohanchauhan02/internal-transfer/domain/apikey"
+	"github.com/rohanchauhan02/internal-transfer/domain/audit"
+	"github.com/rohanchauhan02/internal-transfer/dto"
+	"github.com/rohanchauhan02/internal-transfer/models"
+	"github.com/rohanchauhan02/internal-transfer/pkg/config"
+)
+
+// keyPrefix marks strings issued by this service so they are easy to spot in logs and secret scanners.
+const keyPrefix = "itk_"
+
+type apiKeyUsecase struct {
+	repo         apikey.Repository
+	bootstrapKey string
+	tracker      *lastUsedTracker
+	now          func() time.Time
+}
+
+// NewAPIKeyUsecase creates a new api key usecase instance and starts the last-used tracker
+func NewAPIKeyUsecase(repo apikey.Repository, conf config.Auth) apikey.Usecase {
+	interval := time.Duration(conf.LastUsedFlushInterval) * time.Second
+	if interval <= 0 {
+		interval = 10 * time.Second
+	}
+	return &apiKeyUsecase{
+		repo:         repo,
+		bootstrapKey: conf.BootstrapKey,
+		tracker:      newLastUsedTracker(repo, interval),
+		now:          time.Now,
+	}
+}
+
+// Issue creates a new api key and returns its plaintext value once
+func (u *apiKeyUsecase) Issue(ctx context.Context, req dto.APIKeyCreateRequest) (dto.APIKeyCreatedResponse, error) {
+	if strings.TrimSpace(req.Name) == "" {
+		return dto.APIKeyCreatedResponse{}, apikey.ErrInvalidName
+	}
+	if req.ExpiresAt != nil && !req.ExpiresAt.After(u.now()) {
+		return dto.APIKeyCreatedResponse{}, apikey.ErrInvalidExpiry
+	}
+	if err := validateScopes(req.Scopes); err != nil {
+		return dto.APIKeyCreatedResponse{}, err
+	}
+	if err := validateAllowedIPs(req.AllowedIPs); err != nil {
+		return dto.APIKeyCreatedResponse{}, err
+	}
+	created, err := u.issue(ctx, models.APIKey{
+		Name:       req.Name,
+		Scopes:     strings.Join(req.Scopes, ","),
+		AllowedIPs: strings.Join(req.AllowedIPs, ","),
+		ExpiresAt:  req.ExpiresAt,
+	})
+	if err != nil {
+		return dto.APIKeyCreatedResponse{}, err
+	}
+	audit.EntryFromContext(ctx).SetAfter(created.APIKeyResponse)
+	return created, nil
+}
+
+// List returns every api key without any secret material
+func (u *apiKeyUsecase) List(ctx context.Context) ([]dto.APIKeyResponse, error) {
+	keys, err := u.repo.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	resp := make([]dto.APIKeyResponse, 0, len(keys))
+	for _, key := range keys {
+		resp = append(resp, toResponse(key))
+	}
+	return resp, nil
+}
+
+// UpdateScopes replaces the scopes granted to an api key
+func (u *apiKeyUsecase) UpdateScopes(ctx context.Context, id uint, scopes []string) (dto.APIKeyResponse, error) {
+	if err := validateScopes(scopes); err != nil {
+		return dto.APIKeyResponse{}, err
+	}
+	key, err := u.repo.GetByID(ctx, id)
+	if err != nil {
+		return dto.APIKeyResponse{}, err
+	}
+	entry := audit.EntryFromContext(ctx)
+	entry.SetBefore(toResponse(key))
+	key.Scopes = strings.Join(scopes, ",")
+	if err := u.repo.Update(ctx, key); err != nil {
+		return dto.APIKeyResponse{}, err
+	}
+	entry.SetAfter(toResponse(key))
+	return toResponse(key), nil
+}
+
+// Revoke disables an api key immediately
+func (u *apiKeyUsecase) Revoke(ctx context.Context, id uint) error {
+	key, err := u.repo.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if key.RevokedAt != nil {
+		return nil
+	}
+	entry := audit.EntryFromContext(ctx)
+	entry.SetBefore(toResponse(key))
+	now := u.now()
+	key.RevokedAt = &now
+	if err := u.repo.Update(ctx, key); err != nil {
+		return err
+	}
+	entry.SetAfter(toResponse(key))
+	return nil
+}
+
+// Rotate issues a replacement key and keeps the old one valid for the grace period
+func (u *apiKeyUsecase) Rotate(ctx context.Context, id uint, grace time.Duration) (dto.APIKeyCreatedResponse, error) {
+	old, err := u.repo.GetByID(ctx, id)
+	if err != nil {
+		return dto.APIKeyCreatedResponse{}, err
+	}
+	if err := u.checkActive(old); err != nil {
+		return dto.APIKeyCreatedResponse{}, err
+	}
+
+	replacement := models.APIKey{
+		Name:       old.Name,
+		Scopes:     old.Scopes,
+		AllowedIPs: old.AllowedIPs,
+		ExpiresAt:  old.ExpiresAt,
+	}
+	raw, err := generateKey(&replacement)
+	if err != nil {
+		return dto.APIKeyCreatedResponse{}, err
+	}
+
+	entry := audit.EntryFromContext(ctx)
+	entry.SetBefore(toResponse(old))
+	graceEnd := u.now().Add(grace)
+	if old.ExpiresAt == nil || graceEnd.Before(*old.ExpiresAt) {
+		old.ExpiresAt = &graceEnd
+	}
+	// Issuing and retiring happen in one transaction so a failure never leaves two valid keys.
+	if err := u.repo.Rotate(ctx, old, &replacement); err != nil {
+		return dto.APIKeyCreatedResponse{}, errors.New("failed to rotate api key: " + err.Error())
+	}
+	old.RotatedTo = &replacement.ID
+	created := dto.APIKeyCreatedResponse{APIKeyResponse: toResponse(replacement), Key: raw}
+	entry.SetAfter(map[string]dto.APIKeyResponse{"retired": toResponse(old), "issued": created.APIKeyResponse})
+	return created, nil
+}
+
+// Authenticate verifies a raw api key presented by a client connecting from clientIP
+func (u *apiKeyUsecase) Authenticate(ctx context.Context, rawKey string, clientIP string) (apikey.Principal, error) {
+	if u.bootstrapKey != "" && subtle.ConstantTimeCompare([]byte(rawKey), []byte(u.bootstrapKey)) == 1 {
+		return apikey.Principal{Name: "bootstrap", Scopes: []string{apikey.ScopeAdmin}}, nil
+	}
+
+	prefix, secret, ok := parseKey(rawKey)
+	if !ok {
+		return apikey.Principal{}, apikey.ErrInvalidKey
+	}
+	key, err := u.repo.GetByPrefix(ctx, prefix)
+	if err != nil {
+		if errors.Is(err, apikey.ErrKeyNotFound) {
+			return apikey.Principal{}, apikey.ErrInvalidKey
+		}
+		return apikey.Principal{}, err
+	}
+	if !hmac.Equal([]byte(hashSecret(key.Salt, secret)), []byte(key.KeyHash)) {
+		return apikey.Principal{}, apikey.ErrInvalidKey
+	}
+	if err := u.checkActive(key); err != nil {
+		return apikey.Principal{}, err
+	}
+	if !ipAllowed(key.AllowedIPs, clientIP) {
+		return apikey.Principal{}, apikey.ErrIPNotAllowed
+	}
+
+	u.tracker.touch(key.ID, u.now())
+	return apikey.Principal{
+		KeyID:  key.ID,
+		Name:   key.Name,
+		Scopes: splitList(key.Scopes),
+	}, nil
+}
+
+// Close flushes pending last-used timestamps and stops the tracker
+func (u *apiKeyUsecase) Close() {
+	u.tracker.stop()
+}
+
+func (u *apiKeyUsecase) issue(ctx context.Context, key models.APIKey) (dto.APIKeyCreatedResponse, error) {
+	raw, err := generateKey(&key)
+	if err != nil {
+		return dto.APIKeyCreatedResponse{}, err
+	}
+	if err := u.repo.Create(ctx, &key); err != nil {
+		return dto.APIKeyCreatedResponse{}, errors.New("failed to store api key: " + err.Error())
+	}
+	return dto.APIKeyCreatedResponse{
+		APIKeyResponse: toResponse(key),
+		Key:            raw,
+	}, nil
+}
+
+// generateKey fills in the prefix, salt and hash of key and returns the plaintext key to hand out.
+func generateKey(key *models.APIKey) (string, error) {
+	prefixBytes := make([]byte, 6)
+	secretBytes := make([]byte, 32)
+	saltBytes := make([]byte, 16)
+	for _, b := range [][]byte{prefixBytes, secretBytes, saltBytes} {
+		if _, err := rand.Read(b); err != nil {
+			return "", errors.New("failed to generate api key: " + err.Error())
+		}
+	}
+	prefix := hex.EncodeToString(prefixBytes)
+	secret := base64.RawURLEncoding.EncodeToString(secretBytes)
+
+	key.Prefix = prefix
+	key.Salt = hex.EncodeToString(saltBytes)
+	key.KeyHash = hashSecret(key.Salt, secret)
+	return keyPrefix + prefix + "." + secret, nil
+}
+
+func (u *apiKeyUsecase) checkActive(key models.APIKey) error {
+	if key.RevokedAt != nil {
+		return apikey.ErrKeyRevoked
+	}
+	if key.ExpiresAt != nil && !u.now().Before(*key.ExpiresAt) {
+		return apikey.ErrKeyExpired
+	}
+	return nil
+}
+
+// parseKey splits "itk_<prefix>.<secret>" into its lookup prefix and secret.
+func parseKey(raw string) (string, string, bool) {
+	rest, ok := strings.CutPrefix(raw, keyPrefix)
+	if !ok {
+		return "", "", false
+	}
+	prefix, secret, ok := strings.Cut(rest, ".")
+	if !ok || prefix == "" || secret == "" {
+		return "", "", false
+	}
+	return prefix, secret, true
+}
+
+func hashSecret(salt, secret string) string {
+	mac := hmac.New(sha256.New, []byte(salt))
+	mac.Write([]byte(secret))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func validateScopes(scopes []string) error {
+	if len(scopes) == 0 {
+		return apikey.ErrInvalidScope
+	}
+	for _, s := range scopes {
+		if !slices.Contains(apikey.ValidScopes, s) {
+			return fmt.Errorf("%w: %s", apikey.ErrInvalidScope, s)
+		}
+	}
+	return nil
+}
+
+func validateAllowedIPs(entries []string) error {
+	for _, entry := range entries {
+		if _, _, err := net.ParseCIDR(entry); err == nil {
+			continue
+		}
+		if net.ParseIP(entry) == nil {
+			return fmt.Errorf("%w: %s", apikey.ErrInvalidIPRule, entry)
+		}
+	}
+	return nil
+}
+
+// ipAllowed reports whether clientIP matches the comma-separated allow-list. An empty list allows every address.
+func ipAllowed(allowList string, clientIP string) bool {
+	entries := splitList(allowList)
+	if len(entries) == 0 {
+		return true
+	}
+	ip := net.ParseIP(clientIP)
+	if ip == nil {
+		return false
+	}
+	for _, entry := range entries {
+		if _, network, err := net.ParseCIDR(entry); err == nil {
+			if network.Contains(ip) {
+				return true
+			}
+			continue
+		}
+		if allowed := net.ParseIP(entry); allowed != nil && allowed.Equal(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+func splitList(s string) []string {
+	if s == "" {
+		return []string{}
+	}
+	return strings.Split(s, ",")
+}
+
+func toResponse(key models.APIKey) dto.APIKeyResponse {
+	return dto.APIKeyResponse{
+		ID:         key.ID,
+		Name:       key.Name,
+		Prefix:     key.Prefix,
+		Scopes:     splitList(key.Scopes),
+		AllowedIPs: splitList(key.AllowedIPs),
+		ExpiresAt:  key.ExpiresAt,
+		RevokedAt:  key.RevokedAt,
+		RotatedTo:  key.RotatedTo,
+		LastUsedAt: key.LastUsedAt,
+		CreatedAt:  key.CreatedAt,
+	}
+}
@@ -0,0 +1,219 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/rohanchauhan02/internal-transfer/domain/apikey"
+	"github.com/rohanchauhan02/internal-transfer/dto"
+	mock_apikey "github.com/rohanchauhan02/internal-transfer/file/mocks/mock_apikey"
+	"github.com/rohanchauhan02/internal-transfer/models"
+	"github.com/rohanchauhan02/internal-transfer/pkg/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func issueKey(t *testing.T, repo *mock_apikey.MockRepository, u apikey.Usecase, req dto.APIKeyCreateRequest) (string, models.APIKey) {
+	var stored models.APIKey
+	repo.EXPECT().Create(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, key *models.APIKey) error {
+		key.ID = 7
+		stored = *key
+		return nil
+	})
+	created, err := u.Issue(context.Background(), req)
+	assert.NoError(t, err)
+	return created.Key, stored
+}
+
+func TestAPIKeyUsecase_IssueStoresOnlyHash(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mock_apikey.NewMockRepository(ctrl)
+	u := NewAPIKeyUsecase(repo, config.Auth{})
+	defer u.Close()
+
+	raw, stored := issueKey(t, repo, u, dto.APIKeyCreateRequest{Name: "ops", Scopes: []string{apikey.ScopeAccountsRead}})
+
+	assert.Contains(t, raw, keyPrefix+stored.Prefix+".")
+	assert.NotContains(t, stored.KeyHash, raw[len(keyPrefix)+len(stored.Prefix)+1:])
+	assert.NotEmpty(t, stored.Salt)
+}
+
+func TestAPIKeyUsecase_Authenticate(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	past := time.Now().Add(-time.Minute)
+
+	tests := []struct {
+		name          string
+		allowedIPs    []string
+		mutate        func(key *models.APIKey)
+		presented     func(raw string) string
+		clientIP      string
+		expectedError error
+	}{
+		{
+			name:     "Valid Key",
+			clientIP: "10.0.0.1",
+		},
+		{
+			name:          "Wrong Secret",
+			presented:     func(raw string) string { return raw + "x" },
+			clientIP:      "10.0.0.1",
+			expectedError: apikey.ErrInvalidKey,
+		},
+		{
+			name:          "Revoked Key",
+			mutate:        func(key *models.APIKey) { key.RevokedAt = &past },
+			clientIP:      "10.0.0.1",
+			expectedError: apikey.ErrKeyRevoked,
+		},
+		{
+			name:          "Expired Key",
+			mutate:        func(key *models.APIKey) { key.ExpiresAt = &past },
+			clientIP:      "10.0.0.1",
+			expectedError: apikey.ErrKeyExpired,
+		},
+		{
+			name:       "IP In Allow-List",
+			allowedIPs: []string{"10.0.0.0/24"},
+			clientIP:   "10.0.0.42",
+		},
+		{
+			name:          "IP Outside Allow-List",
+			allowedIPs:    []string{"10.0.0.0/24", "192.168.1.5"},
+			clientIP:      "10.0.1.1",
+			expectedError: apikey.ErrIPNotAllowed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := mock_apikey.NewMockRepository(ctrl)
+			repo.EXPECT().TouchLastUsed(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+			u := NewAPIKeyUsecase(repo, config.Auth{})
+			defer u.Close()
+
+			raw, stored := issueKey(t, repo, u, dto.APIKeyCreateRequest{
+				Name:       "svc",
+				Scopes:     []string{apikey.ScopeTransfersWrite},
+				AllowedIPs: tt.allowedIPs,
+			})
+			if tt.mutate != nil {
+				tt.mutate(&stored)
+			}
+			if tt.presented != nil {
+				raw = tt.presented(raw)
+			}
+			repo.EXPECT().GetByPrefix(gomock.Any(), stored.Prefix).Return(stored, nil)
+
+			principal, err := u.Authenticate(context.Background(), raw, tt.clientIP)
+			if tt.expectedError != nil {
+				assert.ErrorIs(t, err, tt.expectedError)
+				return
+			}
+			assert.NoError(t, err)
+			assert.True(t, principal.HasScope(apikey.ScopeTransfersWrite))
+			assert.False(t, principal.HasScope(apikey.ScopeAdmin))
+		})
+	}
+}
+
+func TestAPIKeyUsecase_RotateKeepsOldKeyDuringGrace(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mock_apikey.NewMockRepository(ctrl)
+	u := NewAPIKeyUsecase(repo, config.Auth{})
+	defer u.Close()
+
+	old := models.APIKey{ID: 3, Name: "svc", Prefix: "aaaa", Scopes: apikey.ScopeAdmin}
+	repo.EXPECT().GetByID(gomock.Any(), uint(3)).Return(old, nil)
+	repo.EXPECT().Rotate(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, key models.APIKey, replacement *models.APIKey) error {
+		assert.NotNil(t, key.ExpiresAt)
+		assert.WithinDuration(t, time.Now().Add(time.Hour), *key.ExpiresAt, time.Minute)
+		assert.Nil(t, key.RevokedAt)
+		assert.Equal(t, old.Scopes, replacement.Scopes)
+		assert.NotEmpty(t, replacement.KeyHash)
+		replacement.ID = 4
+		return nil
+	})
+
+	created, err := u.Rotate(context.Background(), 3, time.Hour)
+	assert.NoError(t, err)
+	assert.Equal(t, uint(4), created.ID)
+	assert.Equal(t, []string{apikey.ScopeAdmin}, created.Scopes)
+}
+
+func TestAPIKeyUsecase_RotateFailureIssuesNothing(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mock_apikey.NewMockRepository(ctrl)
+	u := NewAPIKeyUsecase(repo, config.Auth{})
+	defer u.Close()
+
+	repo.EXPECT().GetByID(gomock.Any(), uint(3)).Return(models.APIKey{ID: 3, Name: "svc", Scopes: apikey.ScopeAdmin}, nil)
+	repo.EXPECT().Rotate(gomock.Any(), gomock.Any(), gomock.Any()).Return(errors.New("connection reset"))
+
+	// Create and Update are never called on their own, so no replacement can outlive the failure.
+	created, err := u.Rotate(context.Background(), 3, time.Hour)
+	assert.Error(t, err)
+	assert.Empty(t, created.Key)
+}
+
+func TestAPIKeyUsecase_IssueValidation(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	past := time.Now().Add(-time.Minute)
+	tests := []struct {
+		name          string
+		req           dto.APIKeyCreateRequest
+		expectedError error
+	}{
+		{
+			name:          "Empty Name",
+			req:           dto.APIKeyCreateRequest{Name: "  ", Scopes: []string{apikey.ScopeAdmin}},
+			expectedError: apikey.ErrInvalidName,
+		},
+		{
+			name:          "Past Expiry",
+			req:           dto.APIKeyCreateRequest{Name: "ops", Scopes: []string{apikey.ScopeAdmin}, ExpiresAt: &past},
+			expectedError: apikey.ErrInvalidExpiry,
+		},
+		{
+			name:          "Unknown Scope",
+			req:           dto.APIKeyCreateRequest{Name: "ops", Scopes: []string{"everything"}},
+			expectedError: apikey.ErrInvalidScope,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := mock_apikey.NewMockRepository(ctrl)
+			u := NewAPIKeyUsecase(repo, config.Auth{})
+			defer u.Close()
+
+			_, err := u.Issue(context.Background(), tt.req)
+			assert.ErrorIs(t, err, tt.expectedError)
+		})
+	}
+}
+
+func TestAPIKeyUsecase_BootstrapKey(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mock_apikey.NewMockRepository(ctrl)
+	u := NewAPIKeyUsecase(repo, config.Auth{BootstrapKey: "let-me-in"})
+	defer u.Close()
+
+	principal, err := u.Authenticate(context.Background(), "let-me-in", "127.0.0.1")
+	assert.NoError(t, err)
+	assert.True(t, principal.HasScope(apikey.ScopeAdmin))
+}
@@ -0,0 +1,95 @@
+package audit
+
+import (
+	"context"
+	"io"
+	"time"
+
+	"github.com/rohanchauhan02/internal-transfer/dto"
+	"github.com/rohanchauhan02/internal-transfer/models"
+)
+
+// Outcomes stored on audit records.
+const (
+	OutcomeSuccess = "success"
+	OutcomeFailure = "failure"
+)
+
+// Entry collects the domain details of a state-changing request while it is being handled.
+// The audit middleware creates one per request and writes it once the handler returns;
+// handlers and usecases fill it in through EntryFromContext. All methods are nil-safe so
+// code paths without an audit entry (tests, background jobs) need no special casing.
+type Entry struct {
+	TargetAccountID *int
+	Before          any
+	After           any
+}
+
+// SetTarget records the account the request acted on.
+func (e *Entry) SetTarget(accountID int) {
+	if e != nil {
+		e.TargetAccountID = &accountID
+	}
+}
+
+// SetBefore records the state prior to the change.
+func (e *Entry) SetBefore(v any) {
+	if e != nil {
+		e.Before = v
+	}
+}
+
+// SetAfter records the state after the change.
+func (e *Entry) SetAfter(v any) {
+	if e != nil {
+		e.After = v
+	}
+}
+
+type entryKey struct{}
+
+// WithEntry returns a copy of ctx carrying entry.
+func WithEntry(ctx context.Context, entry *Entry) context.Context {
+	return context.WithValue(ctx, entryKey{}, entry)
+}
+
+// EntryFromContext returns the audit entry for the current request, or nil.
+func EntryFromContext(ctx context.Context) *Entry {
+	entry, _ := ctx.Value(entryKey{}).(*Entry)
+	return entry
+}
+
+// Event describes a finished request that should be written to the audit trail.
+type Event struct {
+	Actor      string
+	RequestID  string
+	Method     string
+	Route      string
+	StatusCode int
+	Error      string
+	Entry      *Entry
+}
+
+// Filter narrows audit queries and exports. Zero values are ignored.
+type Filter struct {
+	Actor     string
+	RequestID string
+	Route     string
+	Outcome   string
+	AccountID *int
+	From      *time.Time
+	To        *time.Time
+	Limit     int
+	Offset    int
+}
+
+type Usecase interface {
+	Record(context.Context, Event) error
+	Query(context.Context, Filter) ([]dto.AuditRecordResponse, error)
+	Export(context.Context, Filter, io.Writer) error
+}
+type Repository interface {
+	Append(context.Context, *models.AuditRecord) error
+	Query(context.Context, Filter) ([]models.AuditRecord, error)
+	Stream(context.Context, Filter, func(models.AuditRecord) error) error
+}
@@ -0,0 +1,104 @@
+package https
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/rohanchauhan02/internal-transfer/domain/apikey"
+	"github.com/rohanchauhan02/internal-transfer/domain/audit"
+	"github.com/rohanchauhan02/internal-transfer/pkg/ctx"
+	"github.com/rohanchauhan02/internal-transfer/pkg/logger"
+	CustomMiddileware "github.com/rohanchauhan02/internal-transfer/pkg/middleware"
+)
+
+// MIMEApplicationNDJSON is the content type of audit exports.
+const MIMEApplicationNDJSON = "application/x-ndjson"
+
+var log = logger.For("audit")
+
+type auditHandler struct {
+	usecase audit.Usecase
+}
+
+// NewAuditHandler registers the audit query and export endpoints.
+func NewAuditHandler(e *echo.Echo, usecase audit.Usecase) {
+	handler := &auditHandler{
+		usecase: usecase,
+	}
+
+	admin := e.Group("/api/v1/admin", CustomMiddileware.RequireScope(apikey.ScopeAdmin))
+	admin.GET("/audit", handler.Query)
+	admin.GET("/audit/export", handler.Export)
+}
+
+func (h *auditHandler) Query(c echo.Context) error {
+	ac := c.(*ctx.CustomApplicationContext)
+	filter, err := parseFilter(c)
+	if err != nil {
+		return ac.CustomResponse("Bad Request", nil, "", err.Error(), http.StatusBadRequest, nil)
+	}
+	records, err := h.usecase.Query(c.Request().Context(), filter)
+	if err != nil {
+		return ac.CustomResponse("Internal Server Error", nil, "", "Failed to query audit records", http.StatusInternalServerError, nil)
+	}
+	meta := map[string]int{"offset": filter.Offset, "count": len(records)}
+	return ac.CustomResponse("Success", records, "Audit records retrieved successfully", "", http.StatusOK, meta)
+}
+
+func (h *auditHandler) Export(c echo.Context) error {
+	ac := c.(*ctx.CustomApplicationContext)
+	filter, err := parseFilter(c)
+	if err != nil {
+		return ac.CustomResponse("Bad Request", nil, "", err.Error(), http.StatusBadRequest, nil)
+	}
+
+	res := c.Response()
+	res.Header().Set(echo.HeaderContentType, MIMEApplicationNDJSON)
+	res.Header().Set(echo.HeaderContentDisposition, `attachment; filename="audit.ndjson"`)
+	res.WriteHeader(http.StatusOK)
+	if err := h.usecase.Export(c.Request().Context(), filter, res); err != nil {
+		// Headers are already sent, so the error can only be logged; the truncated body tells the client.
+		log.ErrorContext(c.Request().Context(), "audit export aborted", slog.String("error", err.Error()))
+	}
+	res.Flush()
+	return nil
+}
+
+func parseFilter(c echo.Context) (audit.Filter, error) {
+	filter := audit.Filter{
+		Actor:     c.QueryParam("actor"),
+		RequestID: c.QueryParam("request_id"),
+		Route:     c.QueryParam("route"),
+		Outcome:   c.QueryParam("outcome"),
+	}
+	if v := c.QueryParam("account_id"); v != "" {
+		id, err := strconv.Atoi(v)
+		if err != nil {
+			return audit.Filter{}, errors.New("invalid account_id")
+		}
+		filter.AccountID = &id
+	}
+	for param, dst := range map[string]**time.Time{"from": &filter.From, "to": &filter.To} {
+		if v := c.QueryParam(param); v != "" {
+			t, err := time.Parse(time.RFC3339, v)
+			if err != nil {
+				return audit.Filter{}, errors.New("invalid " + param + ": expected RFC3339 timestamp")
+			}
+			*dst = &t
+		}
+	}
+	for param, dst := range map[string]*int{"limit": &filter.Limit, "offset": &filter.Offset} {
+		if v := c.QueryParam(param); v != "" {
+			n, err := strconv.Atoi(v)
+			if err != nil || n < 0 {
+				return audit.Filter{}, errors.New("invalid " + param)
+			}
+			*dst = n
+		}
+	}
+	return filter, nil
+}
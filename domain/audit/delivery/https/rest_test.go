@@ -0,0 +1,106 @@
+package https
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/rohanchauhan02/internal-transfer/domain/audit"
+	"github.com/rohanchauhan02/internal-transfer/domain/audit/repository"
+	"github.com/rohanchauhan02/internal-transfer/domain/audit/usecase"
+	"github.com/rohanchauhan02/internal-transfer/dto"
+	"github.com/rohanchauhan02/internal-transfer/models"
+	"github.com/rohanchauhan02/internal-transfer/pkg/ctx"
+	CustomMiddileware "github.com/rohanchauhan02/internal-transfer/pkg/middleware"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newAuditServer(t *testing.T) *echo.Echo {
+	repo := repository.NewMemoryAuditRepository()
+	start := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	for i, outcome := range []string{audit.OutcomeSuccess, audit.OutcomeFailure, audit.OutcomeSuccess} {
+		require.NoError(t, repo.Append(context.Background(), &models.AuditRecord{
+			OccurredAt: start.Add(time.Duration(i) * time.Minute),
+			Actor:      "ops",
+			Method:     http.MethodPost,
+			Route:      "/api/v1/transactions",
+			Outcome:    outcome,
+			After:      `{"amount":"10"}`,
+		}))
+	}
+
+	e := echo.New()
+	e.Use(func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			return next(&ctx.CustomApplicationContext{Context: c})
+		}
+	})
+	// Authentication disabled: every request is an admin.
+	e.Use(CustomMiddileware.MiddlewareAPIKey(nil, false))
+	NewAuditHandler(e, usecase.NewAuditUsecase(repo))
+	return e
+}
+
+func TestAuditHandler_Query(t *testing.T) {
+	e := newAuditServer(t)
+
+	tests := []struct {
+		name          string
+		query         string
+		expectedCode  int
+		expectedCount int
+	}{
+		{"All", "", http.StatusOK, 3},
+		{"Outcome", "?outcome=failure", http.StatusOK, 1},
+		{"Page", "?limit=2&offset=2", http.StatusOK, 1},
+		{"Period", "?from=2026-10-01T12:01:00Z&to=2026-10-01T12:02:00Z", http.StatusOK, 1},
+		{"Invalid Account", "?account_id=x", http.StatusBadRequest, 0},
+		{"Invalid From", "?from=yesterday", http.StatusBadRequest, 0},
+		{"Negative Limit", "?limit=-1", http.StatusBadRequest, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/admin/audit"+tt.query, nil))
+			assert.Equal(t, tt.expectedCode, rec.Code)
+
+			var resp struct {
+				Data []dto.AuditRecordResponse `json:"data"`
+			}
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+			assert.Len(t, resp.Data, tt.expectedCount)
+		})
+	}
+}
+
+func TestAuditHandler_Export(t *testing.T) {
+	e := newAuditServer(t)
+
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/admin/audit/export?outcome=success", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, MIMEApplicationNDJSON, rec.Header().Get(echo.HeaderContentType))
+	assert.Contains(t, rec.Header().Get(echo.HeaderContentDisposition), "audit.ndjson")
+
+	var records []dto.AuditRecordResponse
+	scanner := bufio.NewScanner(rec.Body)
+	for scanner.Scan() {
+		var record dto.AuditRecordResponse
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &record))
+		records = append(records, record)
+	}
+	require.Len(t, records, 2)
+	assert.True(t, records[0].OccurredAt.Before(records[1].OccurredAt), "exports are oldest first")
+	assert.JSONEq(t, `{"amount":"10"}`, string(records[0].After))
+
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/admin/audit/export?to=later", nil))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
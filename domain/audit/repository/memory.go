@@ -0,0 +1,87 @@
+package repository
+
+import (
+	"context"
+	"sync"
+
+	"github.com/rohanchauhan02/internal-transfer/domain/audit"
+	"github.com/rohanchauhan02/internal-transfer/models"
+)
+
+type memoryAuditRepository struct {
+	mu      sync.RWMutex
+	records []models.AuditRecord
+}
+
+// NewMemoryAuditRepository creates a Repository that keeps the audit trail in process memory.
+// Records are only ever appended, as with the database table.
+func NewMemoryAuditRepository() audit.Repository {
+	return &memoryAuditRepository{}
+}
+
+// Append inserts a new audit record
+func (r *memoryAuditRepository) Append(_ context.Context, record *models.AuditRecord) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	record.ID = uint(len(r.records) + 1)
+	r.records = append(r.records, *record)
+	return nil
+}
+
+// Query returns audit records matching the filter, newest first
+func (r *memoryAuditRepository) Query(_ context.Context, filter audit.Filter) ([]models.AuditRecord, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	var records []models.AuditRecord
+	skipped := 0
+	for i := len(r.records) - 1; i >= 0; i-- {
+		if !matches(r.records[i], filter) {
+			continue
+		}
+		if skipped < filter.Offset {
+			skipped++
+			continue
+		}
+		records = append(records, r.records[i])
+		if filter.Limit > 0 && len(records) == filter.Limit {
+			break
+		}
+	}
+	return records, nil
+}
+
+// Stream calls fn for every matching audit record in insertion order
+func (r *memoryAuditRepository) Stream(ctx context.Context, filter audit.Filter, fn func(models.AuditRecord) error) error {
+	r.mu.RLock()
+	records := make([]models.AuditRecord, len(r.records))
+	copy(records, r.records)
+	r.mu.RUnlock()
+
+	for _, record := range records {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if !matches(record, filter) {
+			continue
+		}
+		if err := fn(record); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// matches mirrors applyFilter for records held in memory.
+func matches(record models.AuditRecord, filter audit.Filter) bool {
+	switch {
+	case filter.Actor != "" && record.Actor != filter.Actor,
+		filter.RequestID != "" && record.RequestID != filter.RequestID,
+		filter.Route != "" && record.Route != filter.Route,
+		filter.Outcome != "" && record.Outcome != filter.Outcome,
+		filter.AccountID != nil && (record.TargetAccountID == nil || *record.TargetAccountID != *filter.AccountID),
+		filter.From != nil && record.OccurredAt.Before(*filter.From),
+		filter.To != nil && !record.OccurredAt.Before(*filter.To):
+		return false
+	}
+	return true
+}
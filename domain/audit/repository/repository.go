@@ -0,0 +1,89 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/rohanchauhan02/internal-transfer/domain/audit"
+	"github.com/rohanchauhan02/internal-transfer/models"
+	"gorm.io/gorm"
+)
+
+// streamBatchSize bounds how many audit rows are held in memory during an export.
+const streamBatchSize = 500
+
+type auditRepository struct {
+	db *gorm.DB
+}
+
+// NewAuditRepository creates a new Repository instance
+func NewAuditRepository(db *gorm.DB) audit.Repository {
+	return &auditRepository{
+		db: db,
+	}
+}
+
+// Append inserts a new audit record
+func (r *auditRepository) Append(ctx context.Context, record *models.AuditRecord) error {
+	return r.db.WithContext(ctx).Create(record).Error
+}
+
+// Query returns audit records matching the filter, newest first
+func (r *auditRepository) Query(ctx context.Context, filter audit.Filter) ([]models.AuditRecord, error) {
+	var records []models.AuditRecord
+	query := applyFilter(r.db.WithContext(ctx), filter).Order("id DESC")
+	if filter.Limit > 0 {
+		query = query.Limit(filter.Limit)
+	}
+	if filter.Offset > 0 {
+		query = query.Offset(filter.Offset)
+	}
+	if err := query.Find(&records).Error; err != nil {
+		return nil, err
+	}
+	return records, nil
+}
+
+// Stream calls fn for every matching audit record in insertion order, loading rows in batches
+func (r *auditRepository) Stream(ctx context.Context, filter audit.Filter, fn func(models.AuditRecord) error) error {
+	var batch []models.AuditRecord
+	var fnErr error
+	result := applyFilter(r.db.WithContext(ctx), filter).
+		FindInBatches(&batch, streamBatchSize, func(tx *gorm.DB, _ int) error {
+			for _, record := range batch {
+				if fnErr = fn(record); fnErr != nil {
+					return fnErr
+				}
+			}
+			return nil
+		})
+	if fnErr != nil {
+		return fnErr
+	}
+	return result.Error
+}
+
+func applyFilter(query *gorm.DB, filter audit.Filter) *gorm.DB {
+	query = query.Model(&models.AuditRecord{})
+	if filter.Actor != "" {
+		query = query.Where("actor = ?", filter.Actor)
+	}
+	if filter.RequestID != "" {
+		query = query.Where("request_id = ?", filter.RequestID)
+	}
+	if filter.Route != "" {
+		query = query.Where("route = ?", filter.Route)
+	}
+	if filter.Outcome != "" {
+		query = query.Where("outcome = ?", filter.Outcome)
+	}
+	if filter.AccountID != nil {
+		query = query.Where("target_account_id = ?", *filter.AccountID)
+	}
+	if filter.From != nil {
+		query = query.Where("occurred_at >= ?", *filter.From)
+	}
+	if filter.To != nil {
+		query = query.Where("occurred_at < ?", *filter.To)
+	}
+	return query
+}
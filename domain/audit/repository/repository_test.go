@@ -0,0 +1,130 @@
+package repository
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/rohanchauhan02/internal-transfer/domain/audit"
+	"github.com/rohanchauhan02/internal-transfer/models"
+	"github.com/rohanchauhan02/internal-transfer/pkg/config"
+	"github.com/rohanchauhan02/internal-transfer/pkg/database"
+	"github.com/rohanchauhan02/internal-transfer/pkg/migrations"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var start = time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+
+// auditBackends are the repositories that must answer queries identically.
+var auditBackends = []struct {
+	name string
+	open func(t *testing.T) audit.Repository
+}{
+	{"memory", func(t *testing.T) audit.Repository { return NewMemoryAuditRepository() }},
+	{"sqlite", func(t *testing.T) audit.Repository {
+		db, err := database.NewSQLite(config.DB{Path: filepath.Join(t.TempDir(), "audit.db")}).InitClient(context.Background())
+		require.NoError(t, err)
+		sqlDB, err := db.DB()
+		require.NoError(t, err)
+		t.Cleanup(func() { sqlDB.Close() })
+		migrator, err := migrations.New(sqlDB, migrations.SQLite)
+		require.NoError(t, err)
+		_, err = migrator.Up(context.Background())
+		require.NoError(t, err)
+		return NewAuditRepository(db)
+	}},
+}
+
+// seed appends five records an hour apart: ops and billing alternating, every third one failed,
+// and the even ones targeting account 1.
+func seed(t *testing.T, repo audit.Repository) {
+	for i := 0; i < 5; i++ {
+		record := models.AuditRecord{
+			OccurredAt: start.Add(time.Duration(i) * time.Hour),
+			Actor:      []string{"ops", "billing"}[i%2],
+			RequestID:  "req-" + string(rune('a'+i)),
+			Method:     "POST",
+			Route:      "/api/v1/transactions",
+			Outcome:    audit.OutcomeSuccess,
+			StatusCode: 200,
+		}
+		if i%3 == 0 {
+			record.Outcome, record.StatusCode = audit.OutcomeFailure, 422
+		}
+		if i%2 == 0 {
+			account := 1
+			record.TargetAccountID = &account
+		}
+		require.NoError(t, repo.Append(context.Background(), &record))
+		assert.NotZero(t, record.ID)
+	}
+}
+
+func requestIDs(records []models.AuditRecord) []string {
+	ids := make([]string, 0, len(records))
+	for _, r := range records {
+		ids = append(ids, r.RequestID)
+	}
+	return ids
+}
+
+func TestAuditRepository_Query(t *testing.T) {
+	account := 1
+	from, to := start.Add(time.Hour), start.Add(3*time.Hour)
+	tests := []struct {
+		name     string
+		filter   audit.Filter
+		expected []string
+	}{
+		{"All Newest First", audit.Filter{}, []string{"req-e", "req-d", "req-c", "req-b", "req-a"}},
+		{"Actor", audit.Filter{Actor: "billing"}, []string{"req-d", "req-b"}},
+		{"Request ID", audit.Filter{RequestID: "req-c"}, []string{"req-c"}},
+		{"Outcome", audit.Filter{Outcome: audit.OutcomeFailure}, []string{"req-d", "req-a"}},
+		{"Account", audit.Filter{AccountID: &account}, []string{"req-e", "req-c", "req-a"}},
+		{"Period", audit.Filter{From: &from, To: &to}, []string{"req-c", "req-b"}},
+		{"Route", audit.Filter{Route: "/api/v1/accounts"}, []string{}},
+		{"Page", audit.Filter{Limit: 2, Offset: 1}, []string{"req-d", "req-c"}},
+		{"Page Past End", audit.Filter{Limit: 2, Offset: 5}, []string{}},
+	}
+	for _, backend := range auditBackends {
+		t.Run(backend.name, func(t *testing.T) {
+			repo := backend.open(t)
+			seed(t, repo)
+			for _, tt := range tests {
+				t.Run(tt.name, func(t *testing.T) {
+					records, err := repo.Query(context.Background(), tt.filter)
+					require.NoError(t, err)
+					assert.Equal(t, tt.expected, requestIDs(records))
+				})
+			}
+		})
+	}
+}
+
+func TestAuditRepository_Stream(t *testing.T) {
+	for _, backend := range auditBackends {
+		t.Run(backend.name, func(t *testing.T) {
+			repo := backend.open(t)
+			seed(t, repo)
+
+			var streamed []models.AuditRecord
+			err := repo.Stream(context.Background(), audit.Filter{Actor: "ops"}, func(r models.AuditRecord) error {
+				streamed = append(streamed, r)
+				return nil
+			})
+			require.NoError(t, err)
+			assert.Equal(t, []string{"req-a", "req-c", "req-e"}, requestIDs(streamed), "oldest first")
+
+			errStop := assert.AnError
+			calls := 0
+			err = repo.Stream(context.Background(), audit.Filter{}, func(models.AuditRecord) error {
+				calls++
+				return errStop
+			})
+			assert.ErrorIs(t, err, errStop)
+			assert.Equal(t, 1, calls, "an error from fn stops the stream")
+		})
+	}
+}
@@ -0,0 +1,121 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/rohanchauhan02/internal-transfer/domain/audit"
+	"github.com/rohanchauhan02/internal-transfer/dto"
+	"github.com/rohanchauhan02/internal-transfer/models"
+	"github.com/rohanchauhan02/internal-transfer/pkg/metrics"
+)
+
+// maxQueryLimit caps the page size of audit queries; larger extracts should use Export.
+const maxQueryLimit = 1000
+
+type auditUsecase struct {
+	repo audit.Repository
+	now  func() time.Time
+}
+
+// NewAuditUsecase creates a new audit usecase instance
+func NewAuditUsecase(repo audit.Repository) audit.Usecase {
+	return &auditUsecase{
+		repo: repo,
+		now:  time.Now,
+	}
+}
+
+// Record appends a finished request to the audit trail. It runs after the request's changes
+// have committed, so a failure is counted in metrics.AuditWriteFailuresTotal for alerting.
+func (u *auditUsecase) Record(ctx context.Context, event audit.Event) (err error) {
+	defer func() {
+		if err != nil {
+			metrics.AuditWriteFailuresTotal.Inc()
+		}
+	}()
+	record := models.AuditRecord{
+		OccurredAt:   u.now().UTC(),
+		Actor:        event.Actor,
+		RequestID:    event.RequestID,
+		Method:       event.Method,
+		Route:        event.Route,
+		Outcome:      audit.OutcomeSuccess,
+		StatusCode:   event.StatusCode,
+		ErrorMessage: event.Error,
+	}
+	if event.StatusCode >= http.StatusBadRequest || event.Error != "" {
+		record.Outcome = audit.OutcomeFailure
+	}
+	if entry := event.Entry; entry != nil {
+		record.TargetAccountID = entry.TargetAccountID
+		if record.Before, err = snapshot(entry.Before); err != nil {
+			return err
+		}
+		if record.After, err = snapshot(entry.After); err != nil {
+			return err
+		}
+	}
+	return u.repo.Append(ctx, &record)
+}
+
+// Query returns a page of audit records matching the filter
+func (u *auditUsecase) Query(ctx context.Context, filter audit.Filter) ([]dto.AuditRecordResponse, error) {
+	if filter.Limit <= 0 || filter.Limit > maxQueryLimit {
+		filter.Limit = maxQueryLimit
+	}
+	records, err := u.repo.Query(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	resp := make([]dto.AuditRecordResponse, 0, len(records))
+	for _, record := range records {
+		resp = append(resp, toResponse(record))
+	}
+	return resp, nil
+}
+
+// Export writes every matching audit record to w as newline-delimited JSON
+func (u *auditUsecase) Export(ctx context.Context, filter audit.Filter, w io.Writer) error {
+	enc := json.NewEncoder(w)
+	return u.repo.Stream(ctx, filter, func(record models.AuditRecord) error {
+		return enc.Encode(toResponse(record))
+	})
+}
+
+func snapshot(v any) (string, error) {
+	if v == nil {
+		return "", nil
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return "", errors.New("failed to encode audit snapshot: " + err.Error())
+	}
+	return string(b), nil
+}
+
+func toResponse(record models.AuditRecord) dto.AuditRecordResponse {
+	resp := dto.AuditRecordResponse{
+		ID:              record.ID,
+		OccurredAt:      record.OccurredAt,
+		Actor:           record.Actor,
+		RequestID:       record.RequestID,
+		Method:          record.Method,
+		Route:           record.Route,
+		TargetAccountID: record.TargetAccountID,
+		Outcome:         record.Outcome,
+		StatusCode:      record.StatusCode,
+		ErrorMessage:    record.ErrorMessage,
+	}
+	if record.Before != "" {
+		resp.Before = json.RawMessage(record.Before)
+	}
+	if record.After != "" {
+		resp.After = json.RawMessage(record.After)
+	}
+	return resp
+}
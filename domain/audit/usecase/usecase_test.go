@@ -0,0 +1,149 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/rohanchauhan02/internal-transfer/domain/audit"
+	"github.com/rohanchauhan02/internal-transfer/dto"
+	mock_audit "github.com/rohanchauhan02/internal-transfer/file/mocks/mock_audit"
+	"github.com/rohanchauhan02/internal-transfer/models"
+	"github.com/rohanchauhan02/internal-transfer/pkg/metrics"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuditUsecase_Record(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	account := 3
+	tests := []struct {
+		name            string
+		event           audit.Event
+		expectedOutcome string
+		expectedBefore  string
+		expectedAfter   string
+	}{
+		{
+			name:            "Success With Snapshots",
+			event:           audit.Event{StatusCode: 201, Entry: &audit.Entry{TargetAccountID: &account, Before: map[string]string{"balance": "10"}, After: map[string]string{"balance": "5"}}},
+			expectedOutcome: audit.OutcomeSuccess,
+			expectedBefore:  `{"balance":"10"}`,
+			expectedAfter:   `{"balance":"5"}`,
+		},
+		{
+			name:            "Client Error",
+			event:           audit.Event{StatusCode: 422},
+			expectedOutcome: audit.OutcomeFailure,
+		},
+		{
+			name:            "Error Message With Success Status",
+			event:           audit.Event{StatusCode: 200, Error: "partially applied"},
+			expectedOutcome: audit.OutcomeFailure,
+		},
+		{
+			name:            "Empty Entry",
+			event:           audit.Event{StatusCode: 200, Entry: &audit.Entry{}},
+			expectedOutcome: audit.OutcomeSuccess,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := mock_audit.NewMockRepository(ctrl)
+			u := &auditUsecase{repo: repo, now: func() time.Time { return time.Date(2026, 10, 1, 14, 0, 0, 0, time.FixedZone("CEST", 7200)) }}
+
+			var stored models.AuditRecord
+			repo.EXPECT().Append(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, record *models.AuditRecord) error {
+				stored = *record
+				return nil
+			})
+
+			assert.NoError(t, u.Record(context.Background(), tt.event))
+			assert.Equal(t, tt.expectedOutcome, stored.Outcome)
+			assert.Equal(t, tt.event.StatusCode, stored.StatusCode)
+			assert.Equal(t, tt.expectedBefore, stored.Before)
+			assert.Equal(t, tt.expectedAfter, stored.After)
+			assert.Equal(t, time.UTC, stored.OccurredAt.Location())
+			if tt.event.Entry != nil {
+				assert.Equal(t, tt.event.Entry.TargetAccountID, stored.TargetAccountID)
+			}
+		})
+	}
+}
+
+func TestAuditUsecase_RecordCountsFailures(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mock_audit.NewMockRepository(ctrl)
+	u := NewAuditUsecase(repo)
+	before := testutil.ToFloat64(metrics.AuditWriteFailuresTotal)
+
+	repo.EXPECT().Append(gomock.Any(), gomock.Any()).Return(errors.New("disk full"))
+	assert.Error(t, u.Record(context.Background(), audit.Event{StatusCode: 200}))
+	assert.Error(t, u.Record(context.Background(), audit.Event{StatusCode: 200, Entry: &audit.Entry{After: make(chan int)}}), "an unencodable snapshot is never appended")
+
+	assert.Equal(t, before+2, testutil.ToFloat64(metrics.AuditWriteFailuresTotal))
+}
+
+func TestAuditUsecase_QueryCapsLimit(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	tests := []struct {
+		limit         int
+		expectedLimit int
+	}{
+		{0, maxQueryLimit},
+		{50, 50},
+		{maxQueryLimit + 1, maxQueryLimit},
+	}
+	for _, tt := range tests {
+		repo := mock_audit.NewMockRepository(ctrl)
+		u := NewAuditUsecase(repo)
+		repo.EXPECT().Query(gomock.Any(), audit.Filter{Actor: "ops", Limit: tt.expectedLimit}).Return([]models.AuditRecord{{ID: 1, After: `{"id":1}`}}, nil)
+
+		records, err := u.Query(context.Background(), audit.Filter{Actor: "ops", Limit: tt.limit})
+		require.NoError(t, err)
+		require.Len(t, records, 1)
+		assert.JSONEq(t, `{"id":1}`, string(records[0].After))
+		assert.Nil(t, records[0].Before)
+	}
+}
+
+func TestAuditUsecase_Export(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mock_audit.NewMockRepository(ctrl)
+	u := NewAuditUsecase(repo)
+	repo.EXPECT().Stream(gomock.Any(), audit.Filter{Outcome: audit.OutcomeFailure}, gomock.Any()).DoAndReturn(
+		func(_ context.Context, _ audit.Filter, fn func(models.AuditRecord) error) error {
+			for _, id := range []uint{1, 2} {
+				if err := fn(models.AuditRecord{ID: id, Outcome: audit.OutcomeFailure}); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+
+	var buf bytes.Buffer
+	require.NoError(t, u.Export(context.Background(), audit.Filter{Outcome: audit.OutcomeFailure}, &buf))
+
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	require.Len(t, lines, 2)
+	for i, line := range lines {
+		var record dto.AuditRecordResponse
+		require.NoError(t, json.Unmarshal([]byte(line), &record))
+		assert.Equal(t, uint(i+1), record.ID)
+	}
+}
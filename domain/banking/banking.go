@@ -1,21 +1,130 @@
 package banking
 
 import (
-	"github.com/labstack/echo/v4"
+	"context"
+	"errors"
+	"time"
+
 	"github.com/rohanchauhan02/internal-transfer/dto"
 	"github.com/rohanchauhan02/internal-transfer/models"
-	"gorm.io/gorm"
 )
 
+var (
+	// ErrAccountExists is returned when an account with the same account ID already exists.
+	ErrAccountExists = errors.New("account already exists")
+	// ErrSameAccount is returned for a transfer whose source and destination are the same account.
+	ErrSameAccount = errors.New("source and destination accounts must differ")
+	// ErrInvalidAmount is returned when a transfer amount is not a positive decimal number.
+	ErrInvalidAmount = errors.New("invalid transfer amount")
+	// ErrInsufficientBalance is returned when the source account cannot cover a transfer.
+	ErrInsufficientBalance = errors.New("insufficient balance")
+	// ErrAccountNotFound is returned when an operation targets an account that does not exist.
+	ErrAccountNotFound = errors.New("account not found")
+	// ErrAccountFrozen is returned for a transfer from or to a frozen account.
+	ErrAccountFrozen = errors.New("account is frozen")
+	// ErrCurrencyMismatch is returned for a transfer between accounts held in different currencies.
+	ErrCurrencyMismatch = errors.New("accounts are held in different currencies")
+	// ErrImportInvalid is returned when an account import is committed with invalid rows.
+	ErrImportInvalid = errors.New("import has invalid rows")
+	// ErrInvalidPeriod is returned for a statement period that does not end after it starts.
+	ErrInvalidPeriod = errors.New("statement period must end after it starts")
+	// ErrInvalidTransferDetails is returned when the reference, description or metadata of a
+	// transfer is too long or malformed.
+	ErrInvalidTransferDetails = errors.New("invalid transfer details")
+	// ErrDuplicateReference is returned for a transfer that asks for a unique reference when the
+	// source account already made a transfer with it.
+	ErrDuplicateReference = errors.New("reference already used by the source account")
+	// ErrCustomerNotFound is returned when an operation targets a customer that does not exist.
+	ErrCustomerNotFound = errors.New("customer not found")
+	// ErrInvalidCustomer is returned for customer details that are missing, too long or not one
+	// of the allowed values.
+	ErrInvalidCustomer = errors.New("invalid customer")
+	// ErrKYCNotVerified is returned, when KYC is enforced, for a transfer from or to an account
+	// whose customer is not KYC verified or that has no customer.
+	ErrKYCNotVerified = errors.New("customer KYC is not verified")
+)
+
+// Page sizes of transaction listings: the default when no limit is given, and the largest
+// limit a caller may ask for.
+const (
+	DefaultTransactionLimit = 100
+	MaxTransactionLimit     = 1000
+)
+
+// DefaultImportChunkSize is the number of accounts an import creates per unit of work when
+// AccountImportOptions.ChunkSize is not set.
+const DefaultImportChunkSize = 500
+
 type Usecase interface {
-	CreateAccount(echo.Context, int, string) error
-	GetAccount(int) (dto.AccountResponse, error)
-	Transaction(echo.Context, int, int, string) error
+	CreateAccount(context.Context, int, string) error
+	// GetAccount returns an account, or ErrAccountNotFound if it does not exist.
+	GetAccount(context.Context, int) (dto.AccountResponse, error)
+	// Transaction makes the transfer described by the request.
+	Transaction(context.Context, dto.TransactionRequest) error
+	ListTransactions(context.Context, TransactionFilter) ([]dto.TransactionResponse, error)
+	// SetFrozen freezes or unfreezes an account and returns its new state.
+	SetFrozen(context.Context, int, bool) (dto.AccountResponse, error)
+	// ImportAccounts validates rows and, unless it is a dry run, creates their accounts.
+	ImportAccounts(context.Context, []dto.AccountImportRow, dto.AccountImportOptions) (dto.AccountImportReport, error)
+	// Statement returns an account's transactions from the first time up to the second.
+	Statement(context.Context, int, time.Time, time.Time) (dto.Statement, error)
+	// CreateCustomer creates a customer with a pending KYC status.
+	CreateCustomer(context.Context, dto.CustomerRequest) (dto.CustomerResponse, error)
+	// CreateCustomerAccount creates an account owned by the customer.
+	CreateCustomerAccount(context.Context, uint, int, string) error
+	// CustomerAccounts returns a customer with its accounts and their total balance.
+	CustomerAccounts(context.Context, uint) (dto.CustomerAccounts, error)
+	// SetKYCStatus changes a customer's KYC status and returns the customer.
+	SetKYCStatus(context.Context, uint, string) (dto.CustomerResponse, error)
 }
+
+// Repository methods take part in the unit of work carried by the context, if any; outside
+// of one each call commits on its own. GetAccountTx locks the account until the unit of work ends.
 type Repository interface {
-	CreateAccount(*gorm.DB, models.Account) error
-	GetAccount(int) (models.Account, error)
-	GetAccountTx(*gorm.DB, int) (models.Account, error)
-	UpdateAccount(*gorm.DB, models.Account) error
-	Transaction(*gorm.DB, models.Transaction) error
+	CreateAccount(context.Context, models.Account) error
+	GetAccount(context.Context, int) (models.Account, error)
+	// GetAccounts returns the accounts among the IDs that exist, ordered by account ID.
+	GetAccounts(context.Context, []int) ([]models.Account, error)
+	GetAccountTx(context.Context, int) (models.Account, error)
+	UpdateAccount(context.Context, models.Account) error
+	Transaction(context.Context, models.Transaction) error
+	ListTransactions(context.Context, TransactionFilter) ([]models.Transaction, error)
+	// ListAccounts returns every account ordered by account ID.
+	ListAccounts(context.Context) ([]models.Account, error)
+	// CreateCustomer stores a new customer and returns it with its assigned ID.
+	CreateCustomer(context.Context, models.Customer) (models.Customer, error)
+	// GetCustomer returns a customer, or the zero value if it does not exist.
+	GetCustomer(context.Context, uint) (models.Customer, error)
+	UpdateCustomer(context.Context, models.Customer) error
+	// ListCustomerAccounts returns the accounts of a customer ordered by account ID.
+	ListCustomerAccounts(context.Context, uint) ([]models.Account, error)
+}
+
+// TransactionFilter narrows ListTransactions; zero fields match every transaction.
+type TransactionFilter struct {
+	// AccountID matches transactions where the account is the source or the destination.
+	AccountID *int
+	// SourceAccountID matches transactions sent by the account.
+	SourceAccountID *int
+	// From and To bound the creation time; From is inclusive and To exclusive.
+	From *time.Time
+	To   *time.Time
+	// Reference matches the client reference exactly.
+	Reference *string
+	// MetadataKey matches transactions whose metadata has the key, with MetadataValue as its
+	// value when that is set too.
+	MetadataKey   string
+	MetadataValue *string
+	Limit         int
+}
+
+// UnitOfWork runs fn in a storage transaction. Repository calls made with the context passed
+// to fn are committed together when fn returns nil, and rolled back when it returns an error
+// or panics. Nested calls join the outer unit of work.
+type UnitOfWork interface {
+	Do(ctx context.Context, fn func(ctx context.Context) error) error
+	// Snapshot runs fn in a read-only unit of work whose reads all see the same committed
+	// state without taking locks, so it never waits for transfers nor holds them up. Nested
+	// calls join the outer unit of work.
+	Snapshot(ctx context.Context, fn func(ctx context.Context) error) error
 }
@@ -0,0 +1,129 @@
+package grpc
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/rohanchauhan02/internal-transfer/domain/apikey"
+	"github.com/rohanchauhan02/internal-transfer/domain/banking"
+	"github.com/rohanchauhan02/internal-transfer/dto"
+	bankingv1 "github.com/rohanchauhan02/internal-transfer/proto/banking/v1"
+	gogrpc "google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// MethodScopes lists the api key scope each BankingService method requires, matching the REST routes.
+var MethodScopes = map[string]string{
+	bankingv1.BankingService_CreateAccount_FullMethodName:    apikey.ScopeAccountsWrite,
+	bankingv1.BankingService_GetAccount_FullMethodName:       apikey.ScopeAccountsRead,
+	bankingv1.BankingService_Transfer_FullMethodName:         apikey.ScopeTransfersWrite,
+	bankingv1.BankingService_ListTransactions_FullMethodName: apikey.ScopeAccountsRead,
+}
+
+// MethodRoutes lists the REST route of each BankingService method, so the rate limit rules of
+// a route apply to its gRPC method too, sharing the same buckets.
+var MethodRoutes = map[string]string{
+	bankingv1.BankingService_CreateAccount_FullMethodName:    "POST /api/v1/accounts",
+	bankingv1.BankingService_GetAccount_FullMethodName:       "GET /api/v1/accounts/:id",
+	bankingv1.BankingService_Transfer_FullMethodName:         "POST /api/v1/transactions",
+	bankingv1.BankingService_ListTransactions_FullMethodName: "GET /api/v1/transactions",
+}
+
+// AuditedMethods lists the state-changing BankingService methods written to the audit trail.
+var AuditedMethods = map[string]bool{
+	bankingv1.BankingService_CreateAccount_FullMethodName: true,
+	bankingv1.BankingService_Transfer_FullMethodName:      true,
+}
+
+type bankingServer struct {
+	bankingv1.UnimplementedBankingServiceServer
+	usecase banking.Usecase
+}
+
+// NewBankingServer registers the gRPC banking service backed by usecase.
+// Usecase errors are returned as is and mapped to status codes by the error interceptor.
+func NewBankingServer(s gogrpc.ServiceRegistrar, usecase banking.Usecase) {
+	bankingv1.RegisterBankingServiceServer(s, &bankingServer{
+		usecase: usecase,
+	})
+}
+
+func (s *bankingServer) CreateAccount(ctx context.Context, req *bankingv1.CreateAccountRequest) (*bankingv1.CreateAccountResponse, error) {
+	if req.GetAccountId() <= 0 || req.GetInitialBalance() == "" {
+		return nil, status.Error(codes.InvalidArgument, "account_id and initial_balance are required")
+	}
+	if err := s.usecase.CreateAccount(ctx, int(req.GetAccountId()), req.GetInitialBalance()); err != nil {
+		return nil, err
+	}
+	return &bankingv1.CreateAccountResponse{
+		Account: &bankingv1.Account{AccountId: req.GetAccountId(), Balance: req.GetInitialBalance()},
+	}, nil
+}
+
+func (s *bankingServer) GetAccount(ctx context.Context, req *bankingv1.GetAccountRequest) (*bankingv1.GetAccountResponse, error) {
+	if req.GetAccountId() <= 0 {
+		return nil, status.Error(codes.InvalidArgument, "account_id is required")
+	}
+	account, err := s.usecase.GetAccount(ctx, int(req.GetAccountId()))
+	if err != nil {
+		return nil, err
+	}
+	return &bankingv1.GetAccountResponse{
+		Account: &bankingv1.Account{AccountId: int64(account.AccountID), Balance: account.Balance},
+	}, nil
+}
+
+func (s *bankingServer) Transfer(ctx context.Context, req *bankingv1.TransferRequest) (*bankingv1.TransferResponse, error) {
+	if req.GetSourceAccountId() <= 0 || req.GetDestinationAccountId() <= 0 || req.GetAmount() == "" {
+		return nil, status.Error(codes.InvalidArgument, "source_account_id, destination_account_id and amount are required")
+	}
+	transfer := dto.TransactionRequest{
+		SourceAccountID:      int(req.GetSourceAccountId()),
+		DestinationAccountID: int(req.GetDestinationAccountId()),
+		Amount:               req.GetAmount(),
+		Reference:            req.GetReference(),
+		Description:          req.GetDescription(),
+		UniqueReference:      req.GetUniqueReference(),
+	}
+	if req.GetMetadata() != "" {
+		transfer.Metadata = json.RawMessage(req.GetMetadata())
+	}
+	if err := s.usecase.Transaction(ctx, transfer); err != nil {
+		return nil, err
+	}
+	return &bankingv1.TransferResponse{}, nil
+}
+
+func (s *bankingServer) ListTransactions(req *bankingv1.ListTransactionsRequest, stream gogrpc.ServerStreamingServer[bankingv1.Transaction]) error {
+	if req.GetAccountId() < 0 || req.GetLimit() < 0 || req.GetLimit() > banking.MaxTransactionLimit {
+		return status.Errorf(codes.InvalidArgument, "account_id must not be negative and limit must be 0 to %d",
+			banking.MaxTransactionLimit)
+	}
+	// Like REST, a missing limit means the default page rather than every transaction.
+	filter := banking.TransactionFilter{Limit: banking.DefaultTransactionLimit}
+	if req.GetLimit() > 0 {
+		filter.Limit = int(req.GetLimit())
+	}
+	if req.GetAccountId() != 0 {
+		accountID := int(req.GetAccountId())
+		filter.AccountID = &accountID
+	}
+	transactions, err := s.usecase.ListTransactions(stream.Context(), filter)
+	if err != nil {
+		return err
+	}
+	for _, t := range transactions {
+		if err := stream.Send(&bankingv1.Transaction{
+			Id:                   uint64(t.ID),
+			SourceAccountId:      int64(t.SourceAccountID),
+			DestinationAccountId: int64(t.DestinationAccountID),
+			Amount:               t.Amount,
+			CreatedAt:            timestamppb.New(t.CreatedAt),
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,207 @@
+package grpc
+
+import (
+	"context"
+	"io"
+	"net"
+	"testing"
+
+	"github.com/rohanchauhan02/internal-transfer/domain/banking"
+	"github.com/rohanchauhan02/internal-transfer/domain/banking/repository"
+	"github.com/rohanchauhan02/internal-transfer/domain/banking/usecase"
+	"github.com/rohanchauhan02/internal-transfer/pkg/config"
+	"github.com/rohanchauhan02/internal-transfer/pkg/interceptor"
+	bankingv1 "github.com/rohanchauhan02/internal-transfer/proto/banking/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	gogrpc "google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// newTestClient serves the banking service over an in-memory connection, backed by the
+// in-memory store.
+func newTestClient(t *testing.T) bankingv1.BankingServiceClient {
+	t.Helper()
+	client, _ := newTestServer(t)
+	return client
+}
+
+// newTestServer is newTestClient that also returns the store, to check what was written.
+func newTestServer(t *testing.T) (bankingv1.BankingServiceClient, *repository.MemoryStore) {
+	t.Helper()
+	store := repository.NewMemoryStore()
+	s := gogrpc.NewServer(
+		gogrpc.ChainUnaryInterceptor(interceptor.UnaryRequestID(), interceptor.UnaryErrors()),
+		gogrpc.ChainStreamInterceptor(interceptor.StreamRequestID(), interceptor.StreamErrors()),
+	)
+	NewBankingServer(s, usecase.NewBankingUsecase(store, store, config.Banking{}))
+
+	lis := bufconn.Listen(1 << 20)
+	go s.Serve(lis)
+	t.Cleanup(s.Stop)
+
+	conn, err := gogrpc.NewClient("passthrough:///bufnet",
+		gogrpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		gogrpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+	return bankingv1.NewBankingServiceClient(conn), store
+}
+
+func TestBankingServer_TransferFlow(t *testing.T) {
+	client := newTestClient(t)
+	ctx := context.Background()
+
+	_, err := client.CreateAccount(ctx, &bankingv1.CreateAccountRequest{AccountId: 1, InitialBalance: "100"})
+	require.NoError(t, err)
+	_, err = client.CreateAccount(ctx, &bankingv1.CreateAccountRequest{AccountId: 2, InitialBalance: "0"})
+	require.NoError(t, err)
+
+	var header metadata.MD
+	_, err = client.Transfer(metadata.AppendToOutgoingContext(ctx, interceptor.MetadataRequestID, "req-1"),
+		&bankingv1.TransferRequest{SourceAccountId: 1, DestinationAccountId: 2, Amount: "30"}, gogrpc.Header(&header))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"req-1"}, header.Get(interceptor.MetadataRequestID))
+
+	resp, err := client.GetAccount(ctx, &bankingv1.GetAccountRequest{AccountId: 2})
+	require.NoError(t, err)
+	assert.Equal(t, "30", resp.GetAccount().GetBalance())
+
+	_, err = client.Transfer(ctx, &bankingv1.TransferRequest{SourceAccountId: 2, DestinationAccountId: 1, Amount: "5"})
+	require.NoError(t, err)
+
+	stream, err := client.ListTransactions(ctx, &bankingv1.ListTransactionsRequest{AccountId: 1})
+	require.NoError(t, err)
+	var amounts []string
+	for {
+		tr, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		assert.NotZero(t, tr.GetId())
+		assert.True(t, tr.GetCreatedAt().IsValid())
+		amounts = append(amounts, tr.GetAmount())
+	}
+	assert.Equal(t, []string{"5", "30"}, amounts, "newest first")
+
+	stream, err = client.ListTransactions(ctx, &bankingv1.ListTransactionsRequest{Limit: 1})
+	require.NoError(t, err)
+	_, err = stream.Recv()
+	require.NoError(t, err)
+	_, err = stream.Recv()
+	assert.Equal(t, io.EOF, err)
+
+	stream, err = client.ListTransactions(ctx, &bankingv1.ListTransactionsRequest{Limit: 1001})
+	require.NoError(t, err)
+	_, err = stream.Recv()
+	assert.Equal(t, codes.InvalidArgument, status.Code(err), "limits above the REST maximum are rejected")
+}
+
+func TestBankingServer_TransferDetails(t *testing.T) {
+	client, store := newTestServer(t)
+	ctx := context.Background()
+	_, err := client.CreateAccount(ctx, &bankingv1.CreateAccountRequest{AccountId: 1, InitialBalance: "100"})
+	require.NoError(t, err)
+	_, err = client.CreateAccount(ctx, &bankingv1.CreateAccountRequest{AccountId: 2, InitialBalance: "0"})
+	require.NoError(t, err)
+
+	req := &bankingv1.TransferRequest{
+		SourceAccountId:      1,
+		DestinationAccountId: 2,
+		Amount:               "10",
+		Reference:            "INV-1",
+		Description:          "October invoice",
+		Metadata:             `{"invoice": "INV-1"}`,
+		UniqueReference:      true,
+	}
+	_, err = client.Transfer(ctx, req)
+	require.NoError(t, err)
+
+	transactions, err := store.ListTransactions(ctx, banking.TransactionFilter{})
+	require.NoError(t, err)
+	require.Len(t, transactions, 1)
+	assert.Equal(t, "INV-1", transactions[0].Reference)
+	assert.Equal(t, "October invoice", transactions[0].Description)
+	assert.JSONEq(t, `{"invoice":"INV-1"}`, transactions[0].Metadata)
+
+	_, err = client.Transfer(ctx, req)
+	assert.Equal(t, codes.AlreadyExists, status.Code(err), "the reference is already used by account 1")
+
+	req.Reference, req.Metadata = "INV-2", `["not", "an", "object"]`
+	_, err = client.Transfer(ctx, req)
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestBankingServer_ErrorCodes(t *testing.T) {
+	client := newTestClient(t)
+	ctx := context.Background()
+	_, err := client.CreateAccount(ctx, &bankingv1.CreateAccountRequest{AccountId: 1, InitialBalance: "10"})
+	require.NoError(t, err)
+	_, err = client.CreateAccount(ctx, &bankingv1.CreateAccountRequest{AccountId: 2, InitialBalance: "10"})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name string
+		call func() error
+		want codes.Code
+	}{
+		{
+			name: "duplicate account",
+			call: func() error {
+				_, err := client.CreateAccount(ctx, &bankingv1.CreateAccountRequest{AccountId: 1, InitialBalance: "10"})
+				return err
+			},
+			want: codes.AlreadyExists,
+		},
+		{
+			name: "missing account",
+			call: func() error {
+				_, err := client.GetAccount(ctx, &bankingv1.GetAccountRequest{AccountId: 99})
+				return err
+			},
+			want: codes.NotFound,
+		},
+		{
+			name: "insufficient balance",
+			call: func() error {
+				_, err := client.Transfer(ctx, &bankingv1.TransferRequest{SourceAccountId: 1, DestinationAccountId: 2, Amount: "11"})
+				return err
+			},
+			want: codes.FailedPrecondition,
+		},
+		{
+			name: "same account",
+			call: func() error {
+				_, err := client.Transfer(ctx, &bankingv1.TransferRequest{SourceAccountId: 1, DestinationAccountId: 1, Amount: "1"})
+				return err
+			},
+			want: codes.InvalidArgument,
+		},
+		{
+			name: "invalid amount",
+			call: func() error {
+				_, err := client.Transfer(ctx, &bankingv1.TransferRequest{SourceAccountId: 1, DestinationAccountId: 2, Amount: "ten"})
+				return err
+			},
+			want: codes.InvalidArgument,
+		},
+		{
+			name: "missing fields",
+			call: func() error {
+				_, err := client.Transfer(ctx, &bankingv1.TransferRequest{SourceAccountId: 1})
+				return err
+			},
+			want: codes.InvalidArgument,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, status.Code(tt.call()))
+		})
+	}
+}
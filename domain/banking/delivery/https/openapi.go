@@ -0,0 +1,300 @@
+package https
+
+import (
+	"net/http"
+
+	"github.com/rohanchauhan02/internal-transfer/domain/apikey"
+	"github.com/rohanchauhan02/internal-transfer/dto"
+	CustomMiddileware "github.com/rohanchauhan02/internal-transfer/pkg/middleware"
+	"github.com/rohanchauhan02/internal-transfer/pkg/openapi"
+	"github.com/rohanchauhan02/internal-transfer/pkg/signing"
+)
+
+// idempotencyKey is accepted by every state-changing route.
+var idempotencyKey = openapi.Parameter{
+	Name:        CustomMiddileware.HeaderIdempotencyKey,
+	In:          "header",
+	Description: "Client generated key; a retry with the same key and body replays the first response",
+	Example:     "",
+}
+
+// signatureHeaders carry the optional HMAC signature of the transfer routes.
+var signatureHeaders = []openapi.Parameter{
+	{Name: signing.HeaderClientID, In: "header", Description: "Signing client ID", Example: ""},
+	{Name: signing.HeaderTimestamp, In: "header", Description: "Unix timestamp of the signature", Example: ""},
+	{Name: signing.HeaderNonce, In: "header", Description: "Single use random nonce", Example: ""},
+	{Name: signing.HeaderSignature, In: "header", Description: "Hex encoded HMAC-SHA256 signature", Example: ""},
+}
+
+// idempotencyResponses are the rejections of a reused Idempotency-Key.
+var idempotencyResponses = []openapi.Response{
+	{Status: http.StatusConflict, Description: "A request with this idempotency key is in progress"},
+	{Status: http.StatusUnprocessableEntity, Description: "Idempotency key was used for a different request"},
+}
+
+// Operations describes the routes registered by NewBankingHandler for the OpenAPI document.
+func Operations() []openapi.Operation {
+	return []openapi.Operation{
+		{
+			Method:     http.MethodPost,
+			Path:       "/api/v1/accounts",
+			ID:         "createAccount",
+			Summary:    "Create an account",
+			Tags:       []string{"accounts"},
+			Scope:      apikey.ScopeAccountsWrite,
+			Parameters: []openapi.Parameter{idempotencyKey},
+			Request:    dto.AccountCreationRequest{},
+			Responses: append([]openapi.Response{
+				{Status: http.StatusCreated, Description: "Account created"},
+				{Status: http.StatusBadRequest, Description: "Invalid request"},
+				{Status: http.StatusConflict, Description: "Account already exists, or the idempotency key is in progress"},
+				{Status: http.StatusUnprocessableEntity, Description: "Idempotency key was used for a different request"},
+				{Status: http.StatusInternalServerError, Description: "Failed to create account"},
+			}, append(openapi.AuthResponses(), openapi.TimeoutResponses()...)...),
+		},
+		{
+			Method:  http.MethodPost,
+			Path:    "/api/v1/accounts/import",
+			ID:      "importAccounts",
+			Summary: "Import accounts from CSV",
+			Description: "The CSV header names the columns account_id, initial_balance, currency and, optionally, " +
+				"metadata, a JSON object. A dry run validates every row and writes nothing. Otherwise nothing is " +
+				"written unless every row is valid, and accounts are created in chunks. Rows whose account already " +
+				"exists with the same values are skipped, so a stopped import can be resumed by sending the file " +
+				"again, or from the report's next_row with start_row.",
+			Tags:  []string{"accounts"},
+			Scope: apikey.ScopeAccountsWrite,
+			Parameters: []openapi.Parameter{
+				idempotencyKey,
+				{Name: "dry_run", In: "query", Description: "Validate only", Example: false},
+				{Name: "start_row", In: "query", Description: "First row to import, counting from 1 after the header", Example: 0},
+				{Name: "chunk_size", In: "query", Description: "Accounts created per transaction, 1 to 5000, default 500", Example: 0},
+			},
+			RequestMediaType: "text/csv",
+			Responses: append([]openapi.Response{
+				{Status: http.StatusOK, Description: "Import validated or committed", Data: dto.AccountImportReport{}},
+				{Status: http.StatusBadRequest, Description: "Invalid parameters, or a file that is not a valid import CSV"},
+				{Status: http.StatusConflict, Description: "An account was created concurrently, or the idempotency key is in progress",
+					Data: dto.AccountImportReport{}},
+				{Status: http.StatusRequestEntityTooLarge, Description: "Import file exceeds 32 MiB"},
+				{Status: http.StatusUnprocessableEntity, Description: "Import has invalid rows, or the idempotency key was used for a different request",
+					Data: dto.AccountImportReport{}},
+				{Status: http.StatusInternalServerError, Description: "Import stopped; the report's next_row is where to resume",
+					Data: dto.AccountImportReport{}},
+			}, append(openapi.AuthResponses(), openapi.TimeoutResponses()...)...),
+		},
+		{
+			Method:  http.MethodGet,
+			Path:    "/api/v1/accounts/:id",
+			ID:      "getAccount",
+			Summary: "Get an account balance",
+			Tags:    []string{"accounts"},
+			Scope:   apikey.ScopeAccountsRead,
+			Parameters: []openapi.Parameter{
+				{Name: "id", In: "path", Description: "Account ID", Example: 0},
+			},
+			Responses: append([]openapi.Response{
+				{Status: http.StatusOK, Description: "Account retrieved", Data: dto.AccountResponse{}},
+				{Status: http.StatusBadRequest, Description: "Invalid account ID format"},
+				{Status: http.StatusNotFound, Description: "Account not found"},
+				{Status: http.StatusInternalServerError, Description: "Failed to retrieve account"},
+			}, append(openapi.AuthResponses(), openapi.TimeoutResponses()...)...),
+		},
+		{
+			Method:  http.MethodGet,
+			Path:    "/api/v1/accounts/:id/statement",
+			ID:      "getStatement",
+			Summary: "Get an account statement",
+			Description: "Lists the account's transactions in the period, oldest first, with the running balance after " +
+				"each and the opening and closing balances. Dates are whole UTC days and to includes its day; RFC 3339 " +
+				"times are used as given, from inclusive and to exclusive. The period defaults to the last 30 days and " +
+				"spans at most 366. CSV, PDF, ISO 20022 camt.053 and SWIFT MT940 statements are sent as attachments.",
+			Tags:  []string{"accounts"},
+			Scope: apikey.ScopeAccountsRead,
+			Parameters: []openapi.Parameter{
+				{Name: "id", In: "path", Description: "Account ID", Example: 0},
+				{Name: "from", In: "query", Description: "Start of the period, a date or an RFC 3339 time", Example: ""},
+				{Name: "to", In: "query", Description: "End of the period, a date or an RFC 3339 time; defaults to now", Example: ""},
+				{Name: "format", In: "query", Description: "json (default), csv, pdf, camt053 or mt940", Example: ""},
+			},
+			Responses: append([]openapi.Response{
+				{Status: http.StatusOK, Description: "Statement created", Data: dto.Statement{},
+					MediaTypes: []string{"text/csv", "application/pdf", "application/xml", "text/plain"}},
+				{Status: http.StatusBadRequest, Description: "Invalid account ID, period or format"},
+				{Status: http.StatusNotFound, Description: "Account not found"},
+				{Status: http.StatusInternalServerError, Description: "Failed to create statement"},
+			}, append(openapi.AuthResponses(), openapi.TimeoutResponses()...)...),
+		},
+		freezeOperation(true),
+		freezeOperation(false),
+		{
+			Method:  http.MethodPost,
+			Path:    "/api/v1/customers",
+			ID:      "createCustomer",
+			Summary: "Create a customer",
+			Description: "A customer is an individual or a business that owns accounts. Its KYC status starts pending; " +
+				"when BANKING.REQUIRE_KYC is set, transfers are only made between accounts of verified customers.",
+			Tags:       []string{"customers"},
+			Scope:      apikey.ScopeAccountsWrite,
+			Parameters: []openapi.Parameter{idempotencyKey},
+			Request:    dto.CustomerRequest{},
+			Responses: append([]openapi.Response{
+				{Status: http.StatusCreated, Description: "Customer created", Data: dto.CustomerResponse{}},
+				{Status: http.StatusBadRequest, Description: "Invalid request or customer details"},
+				{Status: http.StatusInternalServerError, Description: "Failed to create customer"},
+			}, append(append(idempotencyResponses, openapi.AuthResponses()...), openapi.TimeoutResponses()...)...),
+		},
+		{
+			Method:      http.MethodGet,
+			Path:        "/api/v1/customers/:id/accounts",
+			ID:          "getCustomerAccounts",
+			Summary:     "List a customer's accounts",
+			Description: "Returns the customer, its accounts and the sum of their balances.",
+			Tags:        []string{"customers"},
+			Scope:       apikey.ScopeAccountsRead,
+			Parameters: []openapi.Parameter{
+				{Name: "id", In: "path", Description: "Customer ID", Example: 0},
+			},
+			Responses: append([]openapi.Response{
+				{Status: http.StatusOK, Description: "Customer accounts retrieved", Data: dto.CustomerAccounts{}},
+				{Status: http.StatusBadRequest, Description: "Invalid customer ID format"},
+				{Status: http.StatusNotFound, Description: "Customer not found"},
+				{Status: http.StatusInternalServerError, Description: "Failed to retrieve customer accounts"},
+			}, append(openapi.AuthResponses(), openapi.TimeoutResponses()...)...),
+		},
+		{
+			Method:  http.MethodPost,
+			Path:    "/api/v1/customers/:id/accounts",
+			ID:      "createCustomerAccount",
+			Summary: "Create an account owned by a customer",
+			Tags:    []string{"customers"},
+			Scope:   apikey.ScopeAccountsWrite,
+			Parameters: []openapi.Parameter{
+				{Name: "id", In: "path", Description: "Customer ID", Example: 0},
+				idempotencyKey,
+			},
+			Request: dto.AccountCreationRequest{},
+			Responses: append([]openapi.Response{
+				{Status: http.StatusCreated, Description: "Account created"},
+				{Status: http.StatusBadRequest, Description: "Invalid customer ID format or request"},
+				{Status: http.StatusNotFound, Description: "Customer not found"},
+				{Status: http.StatusConflict, Description: "Account already exists, or the idempotency key is in progress"},
+				{Status: http.StatusUnprocessableEntity, Description: "Idempotency key was used for a different request"},
+				{Status: http.StatusInternalServerError, Description: "Failed to create account"},
+			}, append(openapi.AuthResponses(), openapi.TimeoutResponses()...)...),
+		},
+		{
+			Method:      http.MethodPut,
+			Path:        "/api/v1/customers/:id/kyc",
+			ID:          "setCustomerKYCStatus",
+			Summary:     "Set a customer's KYC status",
+			Description: "The status is pending, verified or rejected.",
+			Tags:        []string{"customers"},
+			Scope:       apikey.ScopeAdmin,
+			Parameters: []openapi.Parameter{
+				{Name: "id", In: "path", Description: "Customer ID", Example: 0},
+			},
+			Request: dto.KYCStatusRequest{},
+			Responses: append([]openapi.Response{
+				{Status: http.StatusOK, Description: "Customer updated", Data: dto.CustomerResponse{}},
+				{Status: http.StatusBadRequest, Description: "Invalid customer ID format or KYC status"},
+				{Status: http.StatusNotFound, Description: "Customer not found"},
+				{Status: http.StatusInternalServerError, Description: "Failed to update customer"},
+			}, append(openapi.AuthResponses(), openapi.TimeoutResponses()...)...),
+		},
+		{
+			Method:  http.MethodGet,
+			Path:    "/api/v1/transactions",
+			ID:      "listTransactions",
+			Summary: "List transactions, newest first",
+			Tags:    []string{"transactions"},
+			Scope:   apikey.ScopeAccountsRead,
+			Parameters: []openapi.Parameter{
+				{Name: "account_id", In: "query", Description: "Only transactions from or to this account", Example: 0},
+				{Name: "reference", In: "query", Description: "Only transactions with this client reference", Example: ""},
+				{Name: "metadata_key", In: "query", Description: "Only transactions whose metadata has this top-level key", Example: ""},
+				{Name: "metadata_value", In: "query", Description: "With metadata_key, only transactions where the key has this value; " +
+					"string values match as written, other values as compact JSON", Example: ""},
+				{Name: "limit", In: "query", Description: "Page size, 1 to 1000, default 100", Example: 0},
+			},
+			Responses: append([]openapi.Response{
+				{Status: http.StatusOK, Description: "Transactions retrieved", Data: []dto.TransactionResponse{}},
+				{Status: http.StatusBadRequest, Description: "Invalid account ID or limit, or metadata_value without metadata_key"},
+				{Status: http.StatusInternalServerError, Description: "Failed to list transactions"},
+			}, append(openapi.AuthResponses(), openapi.TimeoutResponses()...)...),
+		},
+		{
+			Method:  http.MethodPost,
+			Path:    "/api/v1/transactions",
+			ID:      "createTransaction",
+			Summary: "Transfer funds between accounts",
+			Description: "A transfer may carry a client reference of up to 35 characters, a description of up to 140 " +
+				"characters and a metadata object of up to 4096 bytes and 32 keys, each key up to 64 characters and each " +
+				"value up to 512 bytes. With unique_reference the transfer is rejected when the source account already " +
+				"made one with the same reference. Requests may be signed with HMAC-SHA256 over the method, path, " +
+				"timestamp, nonce and body digest; unsigned requests are rejected when signing is required. Transfers " +
+				"between accounts of the same customer are marked internal.",
+			Tags:       []string{"transactions"},
+			Scope:      apikey.ScopeTransfersWrite,
+			Parameters: append([]openapi.Parameter{idempotencyKey}, signatureHeaders...),
+			Request:    dto.TransactionRequest{},
+			Responses: append([]openapi.Response{
+				{Status: http.StatusOK, Description: "Transaction completed"},
+				{Status: http.StatusBadRequest, Description: "Invalid request body, amount, reference, description or metadata, " +
+					"or the same source and destination account"},
+				{Status: http.StatusNotFound, Description: "The source or destination account does not exist"},
+				{Status: http.StatusConflict, Description: "The reference is already used by the source account, " +
+					"or a request with this idempotency key is in progress"},
+				{Status: http.StatusUnprocessableEntity, Description: "Insufficient balance, a frozen account, accounts held in " +
+					"different currencies, an account whose customer is not KYC verified when KYC is required, or an " +
+					"idempotency key used for a different request"},
+				{Status: http.StatusInternalServerError, Description: "Transaction failed"},
+			}, append(openapi.AuthResponses(), openapi.TimeoutResponses()...)...),
+		},
+		{
+			Method:  http.MethodPost,
+			Path:    "/api/v1/transactions/pain001",
+			ID:      "createPain001Transactions",
+			Summary: "Transfer funds with an ISO 20022 pain.001 message",
+			Description: "Executes each credit transfer of a pain.001.001.09 customer credit transfer initiation as an " +
+				"internal transfer, in order and each on its own, and responds with a pain.002.001.10 status report. " +
+				"Accounts are identified by their account ID in Id/Othr/Id. Transfers are reported ACSC when made and " +
+				"RJCT with a reason code otherwise; the whole message is rejected, and nothing transferred, when NbOfTxs " +
+				"or CtrlSum do not match its transactions. An EndToEndId already used by the debtor account is rejected " +
+				"with AM05, so a message sent twice does not pay twice. Requests are signed like single transfers.",
+			Tags:             []string{"transactions"},
+			Scope:            apikey.ScopeTransfersWrite,
+			Parameters:       append([]openapi.Parameter{idempotencyKey}, signatureHeaders...),
+			RequestMediaType: "application/xml",
+			Responses: append([]openapi.Response{
+				{Status: http.StatusOK, Description: "pain.002 status report", MediaTypes: []string{"application/xml"}},
+				{Status: http.StatusBadRequest, Description: "Not a valid pain.001.001.09 message"},
+				{Status: http.StatusRequestEntityTooLarge, Description: "Message exceeds 32 MiB"},
+				{Status: http.StatusInternalServerError, Description: "Failed to write status report"},
+			}, append(append(idempotencyResponses, openapi.AuthResponses()...), openapi.TimeoutResponses()...)...),
+		},
+	}
+}
+
+func freezeOperation(frozen bool) openapi.Operation {
+	op := openapi.Operation{
+		Method:      http.MethodPost,
+		Path:        "/api/v1/accounts/:id/freeze",
+		ID:          "freezeAccount",
+		Summary:     "Freeze an account",
+		Description: "A frozen account can neither send nor receive transfers.",
+		Tags:        []string{"accounts"},
+		Scope:       apikey.ScopeAdmin,
+	}
+	if !frozen {
+		op.Path, op.ID, op.Summary, op.Description = "/api/v1/accounts/:id/unfreeze", "unfreezeAccount", "Unfreeze an account", ""
+	}
+	op.Parameters = []openapi.Parameter{{Name: "id", In: "path", Description: "Account ID", Example: 0}}
+	op.Responses = append([]openapi.Response{
+		{Status: http.StatusOK, Description: "Account updated", Data: dto.AccountResponse{}},
+		{Status: http.StatusBadRequest, Description: "Invalid account ID format"},
+		{Status: http.StatusNotFound, Description: "Account not found"},
+		{Status: http.StatusInternalServerError, Description: "Failed to update account"},
+	}, append(openapi.AuthResponses(), openapi.TimeoutResponses()...)...)
+	return op
+}
@@ -1,29 +1,54 @@
 package https
 
 import (
+	"bytes"
+	"context"
+	"errors"
 	"net/http"
+	"slices"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/labstack/echo/v4"
+	"github.com/rohanchauhan02/internal-transfer/domain/apikey"
 	"github.com/rohanchauhan02/internal-transfer/domain/banking"
 	"github.com/rohanchauhan02/internal-transfer/dto"
 	"github.com/rohanchauhan02/internal-transfer/pkg/ctx"
+	"github.com/rohanchauhan02/internal-transfer/pkg/iso20022"
+	CustomMiddileware "github.com/rohanchauhan02/internal-transfer/pkg/middleware"
+	"github.com/rohanchauhan02/internal-transfer/pkg/statement"
 )
 
+// maxImportChunkSize is the largest chunk_size an import may ask for.
+const maxImportChunkSize = 5000
+
 type bankingHandler struct {
 	usecase banking.Usecase
 }
 
 // NewBankingHandler creates a new banking handler with the provided usecase.
-func NewBankingHandler(e *echo.Echo, usecase banking.Usecase) {
+// transferMiddleware is applied to the transfer routes only, e.g. request signature checks.
+func NewBankingHandler(e *echo.Echo, usecase banking.Usecase, transferMiddleware ...echo.MiddlewareFunc) {
 	handler := &bankingHandler{
 		usecase: usecase,
 	}
 
 	api := e.Group("/api/v1")
-	api.POST("/accounts", handler.CreateAccount)
-	api.GET("/accounts/:id", handler.GetAccount)
-	api.POST("/transactions", handler.Transaction)
+	api.POST("/accounts", handler.CreateAccount, CustomMiddileware.RequireScope(apikey.ScopeAccountsWrite))
+	api.POST("/accounts/import", handler.ImportAccounts, CustomMiddileware.RequireScope(apikey.ScopeAccountsWrite))
+	api.GET("/accounts/:id", handler.GetAccount, CustomMiddileware.RequireScope(apikey.ScopeAccountsRead))
+	api.GET("/accounts/:id/statement", handler.Statement, CustomMiddileware.RequireScope(apikey.ScopeAccountsRead))
+	api.POST("/accounts/:id/freeze", handler.FreezeAccount, CustomMiddileware.RequireScope(apikey.ScopeAdmin))
+	api.POST("/accounts/:id/unfreeze", handler.UnfreezeAccount, CustomMiddileware.RequireScope(apikey.ScopeAdmin))
+	api.POST("/customers", handler.CreateCustomer, CustomMiddileware.RequireScope(apikey.ScopeAccountsWrite))
+	api.GET("/customers/:id/accounts", handler.CustomerAccounts, CustomMiddileware.RequireScope(apikey.ScopeAccountsRead))
+	api.POST("/customers/:id/accounts", handler.CreateCustomerAccount, CustomMiddileware.RequireScope(apikey.ScopeAccountsWrite))
+	api.PUT("/customers/:id/kyc", handler.SetKYCStatus, CustomMiddileware.RequireScope(apikey.ScopeAdmin))
+	api.GET("/transactions", handler.ListTransactions, CustomMiddileware.RequireScope(apikey.ScopeAccountsRead))
+	transfers := append([]echo.MiddlewareFunc{CustomMiddileware.RequireScope(apikey.ScopeTransfersWrite)}, transferMiddleware...)
+	api.POST("/transactions", handler.Transaction, transfers...)
+	api.POST("/transactions/pain001", handler.Pain001, transfers...)
 }
 
 func (h *bankingHandler) CreateAccount(c echo.Context) error {
@@ -32,12 +57,74 @@ func (h *bankingHandler) CreateAccount(c echo.Context) error {
 	if err := ac.CustomBind(&account); err != nil {
 		return ac.CustomResponse("Bad Request", nil, "", "Invalid request", http.StatusBadRequest, nil)
 	}
-	if err := h.usecase.CreateAccount(c, account.AccountID, account.InitialBalance); err != nil {
+	if err := h.usecase.CreateAccount(c.Request().Context(), account.AccountID, account.InitialBalance); err != nil {
+		if isContextError(err) {
+			return contextErrorResponse(ac, err, nil)
+		}
+		if errors.Is(err, banking.ErrAccountExists) {
+			return ac.CustomResponse("Conflict", nil, "", "Account already exists", http.StatusConflict, nil)
+		}
 		return ac.CustomResponse("Internal Server Error", nil, "", "Failed to create account", http.StatusInternalServerError, nil)
 	}
 	return ac.CustomResponse("Success", nil, "Account created successfully", "", http.StatusCreated, nil)
 }
 
+// ImportAccounts creates the accounts of a CSV file. With dry_run=true it only validates the
+// rows and reports every invalid one; otherwise it imports nothing unless all rows are valid.
+func (h *bankingHandler) ImportAccounts(c echo.Context) error {
+	ac := c.(*ctx.CustomApplicationContext)
+	var opts dto.AccountImportOptions
+	if raw := c.QueryParam("dry_run"); raw != "" {
+		dryRun, err := strconv.ParseBool(raw)
+		if err != nil {
+			return ac.CustomResponse("Bad Request", nil, "", "dry_run must be true or false", http.StatusBadRequest, nil)
+		}
+		opts.DryRun = dryRun
+	}
+	if raw := c.QueryParam("start_row"); raw != "" {
+		startRow, err := strconv.Atoi(raw)
+		if err != nil || startRow < 1 {
+			return ac.CustomResponse("Bad Request", nil, "", "start_row must be a positive integer", http.StatusBadRequest, nil)
+		}
+		opts.StartRow = startRow
+	}
+	if raw := c.QueryParam("chunk_size"); raw != "" {
+		chunkSize, err := strconv.Atoi(raw)
+		if err != nil || chunkSize < 1 || chunkSize > maxImportChunkSize {
+			return ac.CustomResponse("Bad Request", nil, "",
+				"chunk_size must be between 1 and "+strconv.Itoa(maxImportChunkSize), http.StatusBadRequest, nil)
+		}
+		opts.ChunkSize = chunkSize
+	}
+
+	rows, err := banking.ReadImportCSV(http.MaxBytesReader(c.Response(), c.Request().Body, banking.MaxImportBytes))
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			return ac.CustomResponse("Request Entity Too Large", nil, "",
+				"Import file exceeds "+strconv.Itoa(banking.MaxImportBytes>>20)+" MiB", http.StatusRequestEntityTooLarge, nil)
+		}
+		return ac.CustomResponse("Bad Request", nil, "", "Invalid import file: "+err.Error(), http.StatusBadRequest, nil)
+	}
+
+	report, err := h.usecase.ImportAccounts(c.Request().Context(), rows, opts)
+	if err != nil {
+		switch {
+		case isContextError(err):
+			return contextErrorResponse(ac, err, report)
+		case errors.Is(err, banking.ErrImportInvalid):
+			return ac.CustomResponse("Unprocessable Entity", report, "", "Import has invalid rows", http.StatusUnprocessableEntity, nil)
+		case errors.Is(err, banking.ErrAccountExists):
+			return ac.CustomResponse("Conflict", report, "", "Account already exists", http.StatusConflict, nil)
+		}
+		return ac.CustomResponse("Internal Server Error", report, "", "Failed to import accounts", http.StatusInternalServerError, nil)
+	}
+	if opts.DryRun {
+		return ac.CustomResponse("Success", report, "Import validated", "", http.StatusOK, nil)
+	}
+	return ac.CustomResponse("Success", report, "Accounts imported successfully", "", http.StatusOK, nil)
+}
+
 func (h *bankingHandler) GetAccount(c echo.Context) error {
 	ac := c.(*ctx.CustomApplicationContext)
 	accountID := c.Param("id")
@@ -48,8 +135,14 @@ func (h *bankingHandler) GetAccount(c echo.Context) error {
 	if err != nil {
 		return ac.CustomResponse("Bad Request", nil, "", "Invalid account ID format", http.StatusBadRequest, nil)
 	}
-	account, err := h.usecase.GetAccount(id)
+	account, err := h.usecase.GetAccount(c.Request().Context(), id)
 	if err != nil {
+		if isContextError(err) {
+			return contextErrorResponse(ac, err, nil)
+		}
+		if errors.Is(err, banking.ErrAccountNotFound) {
+			return ac.CustomResponse("Not Found", nil, "", err.Error(), http.StatusNotFound, nil)
+		}
 		return ac.CustomResponse("Internal Server Error", nil, "", "Failed to retrieve account", http.StatusInternalServerError, nil)
 	}
 	return ac.CustomResponse("Success", account, "Account retrieved successfully", "", http.StatusOK, nil)
@@ -61,9 +154,283 @@ func (h *bankingHandler) Transaction(c echo.Context) error {
 	if err := ac.CustomBind(&transaction); err != nil {
 		return ac.CustomResponse("Bad Request", nil, "", "Invalid request body", http.StatusBadRequest, nil)
 	}
-	if err := h.usecase.Transaction(c, transaction.SourceAccountID, transaction.DestinationAccountID,
-		transaction.Amount); err != nil {
+	if err := h.usecase.Transaction(c.Request().Context(), transaction); err != nil {
+		switch {
+		case isContextError(err):
+			return contextErrorResponse(ac, err, nil)
+		case errors.Is(err, banking.ErrInvalidTransferDetails), errors.Is(err, banking.ErrSameAccount),
+			errors.Is(err, banking.ErrInvalidAmount):
+			return ac.CustomResponse("Bad Request", nil, "", err.Error(), http.StatusBadRequest, nil)
+		case errors.Is(err, banking.ErrAccountNotFound):
+			return ac.CustomResponse("Not Found", nil, "", "Transaction failed: "+err.Error(), http.StatusNotFound, nil)
+		case errors.Is(err, banking.ErrDuplicateReference):
+			return ac.CustomResponse("Conflict", nil, "", "Transaction failed: "+err.Error(), http.StatusConflict, nil)
+		case errors.Is(err, banking.ErrInsufficientBalance), errors.Is(err, banking.ErrAccountFrozen),
+			errors.Is(err, banking.ErrKYCNotVerified), errors.Is(err, banking.ErrCurrencyMismatch):
+			return ac.CustomResponse("Unprocessable Entity", nil, "", "Transaction failed: "+err.Error(),
+				http.StatusUnprocessableEntity, nil)
+		}
 		return ac.CustomResponse("Internal Server Error", nil, "", "Transaction failed: "+err.Error(), http.StatusInternalServerError, nil)
 	}
 	return ac.CustomResponse("Success", nil, "Transaction completed successfully", "", http.StatusOK, nil)
 }
+
+// Statement returns an account statement as JSON, or as a CSV, PDF, camt.053 or MT940 download.
+func (h *bankingHandler) Statement(c echo.Context) error {
+	ac := c.(*ctx.CustomApplicationContext)
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return ac.CustomResponse("Bad Request", nil, "", "Invalid account ID format", http.StatusBadRequest, nil)
+	}
+	format := c.QueryParam("format")
+	if format == "" {
+		format = statement.FormatJSON
+	}
+	if !slices.Contains(statement.Formats, format) {
+		return ac.CustomResponse("Bad Request", nil, "", "Format must be one of "+strings.Join(statement.Formats, ", "),
+			http.StatusBadRequest, nil)
+	}
+	to := time.Now().UTC()
+	if raw := c.QueryParam("to"); raw != "" {
+		if to, err = statement.ParseTime(raw, true); err != nil {
+			return ac.CustomResponse("Bad Request", nil, "", "Invalid to: "+err.Error(), http.StatusBadRequest, nil)
+		}
+	}
+	from := to.AddDate(0, 0, -statement.DefaultDays)
+	if raw := c.QueryParam("from"); raw != "" {
+		if from, err = statement.ParseTime(raw, false); err != nil {
+			return ac.CustomResponse("Bad Request", nil, "", "Invalid from: "+err.Error(), http.StatusBadRequest, nil)
+		}
+	}
+	if to.Sub(from) > statement.MaxDays*24*time.Hour {
+		return ac.CustomResponse("Bad Request", nil, "",
+			"Statement period is limited to "+strconv.Itoa(statement.MaxDays)+" days", http.StatusBadRequest, nil)
+	}
+
+	s, err := h.usecase.Statement(c.Request().Context(), id, from, to)
+	if err != nil {
+		switch {
+		case isContextError(err):
+			return contextErrorResponse(ac, err, nil)
+		case errors.Is(err, banking.ErrAccountNotFound):
+			return ac.CustomResponse("Not Found", nil, "", "Account not found", http.StatusNotFound, nil)
+		case errors.Is(err, banking.ErrInvalidPeriod):
+			return ac.CustomResponse("Bad Request", nil, "", "Statement period must end after it starts", http.StatusBadRequest, nil)
+		}
+		return ac.CustomResponse("Internal Server Error", nil, "", "Failed to create statement", http.StatusInternalServerError, nil)
+	}
+
+	if format == statement.FormatJSON {
+		return ac.CustomResponse("Success", s, "Statement created successfully", "", http.StatusOK, nil)
+	}
+	var buf bytes.Buffer
+	if err := statement.Write(&buf, format, s); err != nil {
+		return ac.CustomResponse("Internal Server Error", nil, "", "Failed to render statement", http.StatusInternalServerError, nil)
+	}
+	c.Response().Header().Set(echo.HeaderContentDisposition, `attachment; filename="`+statement.Filename(s, format)+`"`)
+	return c.Blob(http.StatusOK, statement.ContentType(format), buf.Bytes())
+}
+
+// Pain001 executes the credit transfers of an ISO 20022 pain.001 message and responds with a
+// pain.002 status report. Each instruction is a separate transfer; the report says which were
+// made, so it is returned even when some or all of them were rejected.
+func (h *bankingHandler) Pain001(c echo.Context) error {
+	ac := c.(*ctx.CustomApplicationContext)
+	p, err := iso20022.ReadPain001(http.MaxBytesReader(c.Response(), c.Request().Body, iso20022.MaxPain001Bytes))
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			return ac.CustomResponse("Request Entity Too Large", nil, "",
+				"Message exceeds "+strconv.Itoa(iso20022.MaxPain001Bytes>>20)+" MiB", http.StatusRequestEntityTooLarge, nil)
+		}
+		return ac.CustomResponse("Bad Request", nil, "", err.Error(), http.StatusBadRequest, nil)
+	}
+
+	report := iso20022.Execute(c.Request().Context(), h.usecase, p)
+	now := time.Now().UTC()
+	var buf bytes.Buffer
+	if err := iso20022.WritePain002(&buf, report, "STS"+now.Format("20060102150405.000000"), now); err != nil {
+		return ac.CustomResponse("Internal Server Error", nil, "", "Failed to write status report", http.StatusInternalServerError, nil)
+	}
+	return c.Blob(http.StatusOK, echo.MIMEApplicationXMLCharsetUTF8, buf.Bytes())
+}
+
+func (h *bankingHandler) FreezeAccount(c echo.Context) error {
+	return h.setFrozen(c, true)
+}
+
+func (h *bankingHandler) UnfreezeAccount(c echo.Context) error {
+	return h.setFrozen(c, false)
+}
+
+func (h *bankingHandler) setFrozen(c echo.Context, frozen bool) error {
+	ac := c.(*ctx.CustomApplicationContext)
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return ac.CustomResponse("Bad Request", nil, "", "Invalid account ID format", http.StatusBadRequest, nil)
+	}
+	account, err := h.usecase.SetFrozen(c.Request().Context(), id, frozen)
+	if err != nil {
+		switch {
+		case isContextError(err):
+			return contextErrorResponse(ac, err, nil)
+		case errors.Is(err, banking.ErrAccountNotFound):
+			return ac.CustomResponse("Not Found", nil, "", "Account not found", http.StatusNotFound, nil)
+		}
+		return ac.CustomResponse("Internal Server Error", nil, "", "Failed to update account", http.StatusInternalServerError, nil)
+	}
+	message := "Account unfrozen successfully"
+	if frozen {
+		message = "Account frozen successfully"
+	}
+	return ac.CustomResponse("Success", account, message, "", http.StatusOK, nil)
+}
+
+// ListTransactions returns the newest transactions, optionally only those of one account, with
+// a client reference or with a metadata key and value.
+func (h *bankingHandler) ListTransactions(c echo.Context) error {
+	ac := c.(*ctx.CustomApplicationContext)
+	filter := banking.TransactionFilter{Limit: banking.DefaultTransactionLimit}
+	if raw := c.QueryParam("account_id"); raw != "" {
+		id, err := strconv.Atoi(raw)
+		if err != nil {
+			return ac.CustomResponse("Bad Request", nil, "", "Invalid account ID format", http.StatusBadRequest, nil)
+		}
+		filter.AccountID = &id
+	}
+	params := c.QueryParams()
+	if params.Has("reference") {
+		reference := params.Get("reference")
+		filter.Reference = &reference
+	}
+	filter.MetadataKey = c.QueryParam("metadata_key")
+	if params.Has("metadata_value") {
+		if filter.MetadataKey == "" {
+			return ac.CustomResponse("Bad Request", nil, "", "metadata_value needs metadata_key", http.StatusBadRequest, nil)
+		}
+		value := params.Get("metadata_value")
+		filter.MetadataValue = &value
+	}
+	if raw := c.QueryParam("limit"); raw != "" {
+		limit, err := strconv.Atoi(raw)
+		if err != nil || limit < 1 || limit > banking.MaxTransactionLimit {
+			return ac.CustomResponse("Bad Request", nil, "",
+				"Limit must be between 1 and "+strconv.Itoa(banking.MaxTransactionLimit), http.StatusBadRequest, nil)
+		}
+		filter.Limit = limit
+	}
+	transactions, err := h.usecase.ListTransactions(c.Request().Context(), filter)
+	if err != nil {
+		if isContextError(err) {
+			return contextErrorResponse(ac, err, nil)
+		}
+		return ac.CustomResponse("Internal Server Error", nil, "", "Failed to list transactions", http.StatusInternalServerError, nil)
+	}
+	return ac.CustomResponse("Success", transactions, "Transactions retrieved successfully", "", http.StatusOK, nil)
+}
+
+func isContextError(err error) bool {
+	return errors.Is(err, context.DeadlineExceeded) || errors.Is(err, context.Canceled)
+}
+
+// contextErrorResponse reports work abandoned because the request deadline passed or the client went away.
+// data describes the work done before that, if any.
+func contextErrorResponse(ac *ctx.CustomApplicationContext, err error, data any) error {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return ac.CustomResponse("Gateway Timeout", data, "", "Request timed out", http.StatusGatewayTimeout, nil)
+	}
+	return ac.CustomResponse("Service Unavailable", data, "", "Request was cancelled", http.StatusServiceUnavailable, nil)
+}
+
+// CreateCustomer creates a customer. KYC starts pending and is changed with SetKYCStatus.
+func (h *bankingHandler) CreateCustomer(c echo.Context) error {
+	ac := c.(*ctx.CustomApplicationContext)
+	var req dto.CustomerRequest
+	if err := ac.CustomBind(&req); err != nil {
+		return ac.CustomResponse("Bad Request", nil, "", "Invalid request", http.StatusBadRequest, nil)
+	}
+	customer, err := h.usecase.CreateCustomer(c.Request().Context(), req)
+	if err != nil {
+		switch {
+		case isContextError(err):
+			return contextErrorResponse(ac, err, nil)
+		case errors.Is(err, banking.ErrInvalidCustomer):
+			return ac.CustomResponse("Bad Request", nil, "", err.Error(), http.StatusBadRequest, nil)
+		}
+		return ac.CustomResponse("Internal Server Error", nil, "", "Failed to create customer", http.StatusInternalServerError, nil)
+	}
+	return ac.CustomResponse("Success", customer, "Customer created successfully", "", http.StatusCreated, nil)
+}
+
+// CreateCustomerAccount creates an account owned by the customer.
+func (h *bankingHandler) CreateCustomerAccount(c echo.Context) error {
+	ac := c.(*ctx.CustomApplicationContext)
+	customerID, err := strconv.ParseUint(c.Param("id"), 10, 0)
+	if err != nil {
+		return ac.CustomResponse("Bad Request", nil, "", "Invalid customer ID format", http.StatusBadRequest, nil)
+	}
+	var account dto.AccountCreationRequest
+	if err := ac.CustomBind(&account); err != nil {
+		return ac.CustomResponse("Bad Request", nil, "", "Invalid request", http.StatusBadRequest, nil)
+	}
+	if err := h.usecase.CreateCustomerAccount(c.Request().Context(), uint(customerID), account.AccountID,
+		account.InitialBalance); err != nil {
+		switch {
+		case isContextError(err):
+			return contextErrorResponse(ac, err, nil)
+		case errors.Is(err, banking.ErrCustomerNotFound):
+			return ac.CustomResponse("Not Found", nil, "", "Customer not found", http.StatusNotFound, nil)
+		case errors.Is(err, banking.ErrAccountExists):
+			return ac.CustomResponse("Conflict", nil, "", "Account already exists", http.StatusConflict, nil)
+		}
+		return ac.CustomResponse("Internal Server Error", nil, "", "Failed to create account", http.StatusInternalServerError, nil)
+	}
+	return ac.CustomResponse("Success", nil, "Account created successfully", "", http.StatusCreated, nil)
+}
+
+// CustomerAccounts returns a customer with its accounts and their total balance.
+func (h *bankingHandler) CustomerAccounts(c echo.Context) error {
+	ac := c.(*ctx.CustomApplicationContext)
+	customerID, err := strconv.ParseUint(c.Param("id"), 10, 0)
+	if err != nil {
+		return ac.CustomResponse("Bad Request", nil, "", "Invalid customer ID format", http.StatusBadRequest, nil)
+	}
+	accounts, err := h.usecase.CustomerAccounts(c.Request().Context(), uint(customerID))
+	if err != nil {
+		switch {
+		case isContextError(err):
+			return contextErrorResponse(ac, err, nil)
+		case errors.Is(err, banking.ErrCustomerNotFound):
+			return ac.CustomResponse("Not Found", nil, "", "Customer not found", http.StatusNotFound, nil)
+		}
+		return ac.CustomResponse("Internal Server Error", nil, "", "Failed to retrieve customer accounts",
+			http.StatusInternalServerError, nil)
+	}
+	return ac.CustomResponse("Success", accounts, "Customer accounts retrieved successfully", "", http.StatusOK, nil)
+}
+
+// SetKYCStatus changes a customer's KYC status.
+func (h *bankingHandler) SetKYCStatus(c echo.Context) error {
+	ac := c.(*ctx.CustomApplicationContext)
+	customerID, err := strconv.ParseUint(c.Param("id"), 10, 0)
+	if err != nil {
+		return ac.CustomResponse("Bad Request", nil, "", "Invalid customer ID format", http.StatusBadRequest, nil)
+	}
+	var req dto.KYCStatusRequest
+	if err := ac.CustomBind(&req); err != nil {
+		return ac.CustomResponse("Bad Request", nil, "", "Invalid request", http.StatusBadRequest, nil)
+	}
+	customer, err := h.usecase.SetKYCStatus(c.Request().Context(), uint(customerID), req.KYCStatus)
+	if err != nil {
+		switch {
+		case isContextError(err):
+			return contextErrorResponse(ac, err, nil)
+		case errors.Is(err, banking.ErrInvalidCustomer):
+			return ac.CustomResponse("Bad Request", nil, "", err.Error(), http.StatusBadRequest, nil)
+		case errors.Is(err, banking.ErrCustomerNotFound):
+			return ac.CustomResponse("Not Found", nil, "", "Customer not found", http.StatusNotFound, nil)
+		}
+		return ac.CustomResponse("Internal Server Error", nil, "", "Failed to update customer", http.StatusInternalServerError, nil)
+	}
+	return ac.CustomResponse("Success", customer, "KYC status updated successfully", "", http.StatusOK, nil)
+}
@@ -0,0 +1,83 @@
+package banking
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	"github.com/rohanchauhan02/internal-transfer/dto"
+)
+
+// Limits of one account import file: its rows and its size in bytes.
+const (
+	MaxImportRows  = 100000
+	MaxImportBytes = 32 << 20
+)
+
+// ImportColumns are the columns of an account import file.
+var ImportColumns = []string{"account_id", "initial_balance", "currency", "metadata"}
+
+// ReadImportCSV reads the rows of an account import file. The header names the columns, in any
+// order; metadata may be left out. Only the file's structure is checked here, the values are
+// validated by Usecase.ImportAccounts.
+func ReadImportCSV(r io.Reader) ([]dto.AccountImportRow, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil, errors.New("file is empty")
+	}
+	if err != nil {
+		return nil, err
+	}
+	index := make(map[string]int, len(header))
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))
+		if !slices.Contains(ImportColumns, name) {
+			return nil, fmt.Errorf("unknown column %q", name)
+		}
+		if _, ok := index[name]; ok {
+			return nil, fmt.Errorf("duplicate column %q", name)
+		}
+		index[name] = i
+	}
+	for _, name := range ImportColumns[:3] {
+		if _, ok := index[name]; !ok {
+			return nil, fmt.Errorf("missing column %q", name)
+		}
+	}
+
+	var rows []dto.AccountImportRow
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			return rows, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		if len(rows) == MaxImportRows {
+			return nil, fmt.Errorf("more than %d rows", MaxImportRows)
+		}
+		row := len(rows) + 1
+		if len(record) != len(header) {
+			return nil, fmt.Errorf("row %d has %d fields, the header has %d", row, len(record), len(header))
+		}
+		field := func(name string) string {
+			if i, ok := index[name]; ok {
+				return strings.TrimSpace(record[i])
+			}
+			return ""
+		}
+		rows = append(rows, dto.AccountImportRow{
+			Row:            row,
+			AccountID:      field("account_id"),
+			InitialBalance: field("initial_balance"),
+			Currency:       field("currency"),
+			Metadata:       field("metadata"),
+		})
+	}
+}
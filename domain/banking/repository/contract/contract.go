@@ -0,0 +1,596 @@
+// Package contract is a conformance test suite for banking.Repository implementations.
+// Every storage backend runs the same cases, so the usecase can rely on identical behaviour
+// whichever one it is wired to.
+package contract
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/rohanchauhan02/internal-transfer/domain/banking"
+	"github.com/rohanchauhan02/internal-transfer/models"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Backend is a storage backend under test: a repository and the unit of work its writes join.
+type Backend struct {
+	Repo banking.Repository
+	UoW  banking.UnitOfWork
+}
+
+// Factory returns a fresh, empty backend. It is called once per case.
+type Factory func(t *testing.T) Backend
+
+// Run runs every contract case against the backends returned by newBackend
+func Run(t *testing.T, newBackend Factory) {
+	cases := []struct {
+		name string
+		run  func(*testing.T, Backend)
+	}{
+		{"CreateAndGet", testCreateAndGet},
+		{"DuplicateAccount", testDuplicateAccount},
+		{"ConcurrentDuplicateAccount", testConcurrentDuplicateAccount},
+		{"RollbackDiscardsWrites", testRollbackDiscardsWrites},
+		{"UncommittedWritesAreInvisible", testUncommittedWritesAreInvisible},
+		{"PanicRollsBack", testPanicRollsBack},
+		{"Snapshot", testSnapshot},
+		{"ConcurrentTransfers", testConcurrentTransfers},
+		{"History", testHistory},
+		{"HistoryTimeRange", testHistoryTimeRange},
+		{"ListAccounts", testListAccounts},
+		{"GetAccounts", testGetAccounts},
+		{"FrozenAtRoundTrip", testFrozenAtRoundTrip},
+		{"CurrencyAndMetadataRoundTrip", testCurrencyAndMetadataRoundTrip},
+		{"TransactionDetailsLookup", testTransactionDetailsLookup},
+		{"Customers", testCustomers},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			c.run(t, newBackend(t))
+		})
+	}
+}
+
+func testCreateAndGet(t *testing.T, b Backend) {
+	ctx := context.Background()
+	require.NoError(t, b.Repo.CreateAccount(ctx, models.Account{AccountID: 1, Balance: "100.5"}))
+
+	account, err := b.Repo.GetAccount(ctx, 1)
+	require.NoError(t, err)
+	assert.Equal(t, 1, account.AccountID)
+	assertBalance(t, "100.5", account.Balance)
+	assert.NotZero(t, account.ID)
+
+	missing, err := b.Repo.GetAccount(ctx, 2)
+	require.NoError(t, err)
+	assert.Zero(t, missing.AccountID, "a missing account reads as the zero value")
+}
+
+func testDuplicateAccount(t *testing.T, b Backend) {
+	ctx := context.Background()
+	require.NoError(t, b.Repo.CreateAccount(ctx, models.Account{AccountID: 1, Balance: "10"}))
+	assert.ErrorIs(t, b.Repo.CreateAccount(ctx, models.Account{AccountID: 1, Balance: "20"}), banking.ErrAccountExists)
+
+	account, err := b.Repo.GetAccount(ctx, 1)
+	require.NoError(t, err)
+	assertBalance(t, "10", account.Balance)
+}
+
+func testConcurrentDuplicateAccount(t *testing.T, b Backend) {
+	const workers = 8
+	errs := make(chan error, workers)
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs <- b.UoW.Do(context.Background(), func(ctx context.Context) error {
+				return b.Repo.CreateAccount(ctx, models.Account{AccountID: 1, Balance: "10"})
+			})
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	created := 0
+	for err := range errs {
+		if err == nil {
+			created++
+			continue
+		}
+		assert.ErrorIs(t, err, banking.ErrAccountExists)
+	}
+	assert.Equal(t, 1, created)
+}
+
+func testRollbackDiscardsWrites(t *testing.T, b Backend) {
+	ctx := context.Background()
+	require.NoError(t, b.Repo.CreateAccount(ctx, models.Account{AccountID: 1, Balance: "10"}))
+	require.NoError(t, b.Repo.CreateAccount(ctx, models.Account{AccountID: 2, Balance: "10"}))
+
+	errAbort := errors.New("abort")
+	err := b.UoW.Do(ctx, func(ctx context.Context) error {
+		if err := transfer(ctx, b, 1, 2, "5"); err != nil {
+			return err
+		}
+		if err := b.Repo.CreateAccount(ctx, models.Account{AccountID: 3, Balance: "1"}); err != nil {
+			return err
+		}
+		return errAbort
+	})
+	require.ErrorIs(t, err, errAbort)
+
+	assertBalances(t, b, map[int]string{1: "10", 2: "10"})
+	missing, err := b.Repo.GetAccount(ctx, 3)
+	require.NoError(t, err)
+	assert.Zero(t, missing.AccountID)
+	history, err := b.Repo.ListTransactions(ctx, banking.TransactionFilter{})
+	require.NoError(t, err)
+	assert.Empty(t, history)
+}
+
+func testUncommittedWritesAreInvisible(t *testing.T, b Backend) {
+	ctx := context.Background()
+	require.NoError(t, b.Repo.CreateAccount(ctx, models.Account{AccountID: 1, Balance: "10"}))
+	require.NoError(t, b.Repo.CreateAccount(ctx, models.Account{AccountID: 2, Balance: "10"}))
+
+	err := b.UoW.Do(ctx, func(txCtx context.Context) error {
+		if err := transfer(txCtx, b, 1, 2, "5"); err != nil {
+			return err
+		}
+
+		// The unit of work reads its own writes, everyone else still sees the committed state.
+		inside, err := b.Repo.GetAccount(txCtx, 1)
+		require.NoError(t, err)
+		assertBalance(t, "5", inside.Balance)
+		accountID := 2
+		pending, err := b.Repo.ListTransactions(txCtx, banking.TransactionFilter{AccountID: &accountID})
+		require.NoError(t, err)
+		require.Len(t, pending, 1)
+		assert.NotZero(t, pending[0].ID)
+		assertBalance(t, "5", pending[0].Amount)
+
+		assertBalances(t, b, map[int]string{1: "10", 2: "10"})
+		history, err := b.Repo.ListTransactions(ctx, banking.TransactionFilter{})
+		require.NoError(t, err)
+		assert.Empty(t, history)
+		return nil
+	})
+	require.NoError(t, err)
+	assertBalances(t, b, map[int]string{1: "5", 2: "15"})
+}
+
+func testSnapshot(t *testing.T, b Backend) {
+	ctx := context.Background()
+	require.NoError(t, b.Repo.CreateAccount(ctx, models.Account{AccountID: 1, Balance: "10"}))
+	require.NoError(t, b.Repo.CreateAccount(ctx, models.Account{AccountID: 2, Balance: "10"}))
+
+	// A snapshot reads an account locked by a transfer in progress without waiting for it.
+	err := b.UoW.Do(ctx, func(txCtx context.Context) error {
+		if err := transfer(txCtx, b, 1, 2, "5"); err != nil {
+			return err
+		}
+		readCtx, cancel := context.WithTimeout(ctx, 2*time.Second)
+		defer cancel()
+		return b.UoW.Snapshot(readCtx, func(snapCtx context.Context) error {
+			account, err := b.Repo.GetAccount(snapCtx, 1)
+			require.NoError(t, err)
+			assertBalance(t, "10", account.Balance)
+			return nil
+		})
+	})
+	require.NoError(t, err)
+
+	// Transfers committed after the snapshot's first read are not seen by it.
+	err = b.UoW.Snapshot(ctx, func(snapCtx context.Context) error {
+		before, err := b.Repo.GetAccount(snapCtx, 1)
+		require.NoError(t, err)
+		require.NoError(t, b.UoW.Do(ctx, func(ctx context.Context) error {
+			return transfer(ctx, b, 1, 2, "1")
+		}))
+		after, err := b.Repo.GetAccount(snapCtx, 1)
+		require.NoError(t, err)
+		assertBalance(t, before.Balance, after.Balance)
+		history, err := b.Repo.ListTransactions(snapCtx, banking.TransactionFilter{})
+		require.NoError(t, err)
+		assert.Len(t, history, 1)
+		return nil
+	})
+	require.NoError(t, err)
+	assertBalances(t, b, map[int]string{1: "4", 2: "16"})
+}
+
+func testPanicRollsBack(t *testing.T, b Backend) {
+	ctx := context.Background()
+	require.NoError(t, b.Repo.CreateAccount(ctx, models.Account{AccountID: 1, Balance: "10"}))
+	require.NoError(t, b.Repo.CreateAccount(ctx, models.Account{AccountID: 2, Balance: "10"}))
+
+	assert.Panics(t, func() {
+		_ = b.UoW.Do(ctx, func(ctx context.Context) error {
+			if err := transfer(ctx, b, 1, 2, "5"); err != nil {
+				return err
+			}
+			panic("boom")
+		})
+	})
+	assertBalances(t, b, map[int]string{1: "10", 2: "10"})
+
+	// The locks taken before the panic are released.
+	require.NoError(t, b.UoW.Do(ctx, func(ctx context.Context) error {
+		return transfer(ctx, b, 1, 2, "1")
+	}))
+	assertBalances(t, b, map[int]string{1: "9", 2: "11"})
+}
+
+func testConcurrentTransfers(t *testing.T, b Backend) {
+	ctx := context.Background()
+	const (
+		accounts = 4
+		rounds   = 10
+	)
+	for id := 1; id <= accounts; id++ {
+		require.NoError(t, b.Repo.CreateAccount(ctx, models.Account{AccountID: id, Balance: "1000"}))
+	}
+
+	// Every ordered pair moves one unit per round, so each account ends where it started. A lost
+	// update on any account shows up as a changed balance.
+	var wg sync.WaitGroup
+	errs := make(chan error, accounts*(accounts-1)*rounds)
+	for from := 1; from <= accounts; from++ {
+		for to := 1; to <= accounts; to++ {
+			if from == to {
+				continue
+			}
+			wg.Add(1)
+			go func(from, to int) {
+				defer wg.Done()
+				for i := 0; i < rounds; i++ {
+					errs <- b.UoW.Do(ctx, func(ctx context.Context) error {
+						return transfer(ctx, b, from, to, "1")
+					})
+				}
+			}(from, to)
+		}
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		require.NoError(t, err)
+	}
+
+	want := make(map[int]string, accounts)
+	for id := 1; id <= accounts; id++ {
+		want[id] = "1000"
+	}
+	assertBalances(t, b, want)
+	history, err := b.Repo.ListTransactions(ctx, banking.TransactionFilter{})
+	require.NoError(t, err)
+	assert.Len(t, history, accounts*(accounts-1)*rounds)
+}
+
+func testHistory(t *testing.T, b Backend) {
+	ctx := context.Background()
+	for id := 1; id <= 3; id++ {
+		require.NoError(t, b.Repo.CreateAccount(ctx, models.Account{AccountID: id, Balance: "100"}))
+	}
+	transfers := []struct {
+		from, to int
+		amount   string
+	}{
+		{1, 2, "1"},
+		{2, 3, "2"},
+		{3, 1, "3"},
+		{2, 1, "4"},
+	}
+	for _, tr := range transfers {
+		require.NoError(t, b.UoW.Do(ctx, func(ctx context.Context) error {
+			return transfer(ctx, b, tr.from, tr.to, tr.amount)
+		}))
+	}
+
+	all, err := b.Repo.ListTransactions(ctx, banking.TransactionFilter{})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"4", "3", "2", "1"}, amounts(all), "newest first")
+	for _, tr := range all {
+		assert.NotZero(t, tr.ID)
+		assert.False(t, tr.CreatedAt.IsZero())
+	}
+
+	account := 1
+	involving, err := b.Repo.ListTransactions(ctx, banking.TransactionFilter{AccountID: &account})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"4", "3", "1"}, amounts(involving), "as source or destination")
+
+	limited, err := b.Repo.ListTransactions(ctx, banking.TransactionFilter{AccountID: &account, Limit: 2})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"4", "3"}, amounts(limited))
+
+	unknown := 99
+	none, err := b.Repo.ListTransactions(ctx, banking.TransactionFilter{AccountID: &unknown})
+	require.NoError(t, err)
+	assert.Empty(t, none)
+}
+
+func testHistoryTimeRange(t *testing.T, b Backend) {
+	ctx := context.Background()
+	for id := 1; id <= 2; id++ {
+		require.NoError(t, b.Repo.CreateAccount(ctx, models.Account{AccountID: id, Balance: "100"}))
+	}
+	start := time.Now().Truncate(time.Second)
+	for i, amount := range []string{"1", "2", "3"} {
+		require.NoError(t, b.Repo.Transaction(ctx, models.Transaction{
+			SourceAccountID:      1,
+			DestinationAccountID: 2,
+			Amount:               amount,
+			CreatedAt:            start.Add(time.Duration(i) * time.Hour),
+		}))
+	}
+
+	from, to := start.Add(time.Hour), start.Add(2*time.Hour)
+	ranged, err := b.Repo.ListTransactions(ctx, banking.TransactionFilter{From: &from, To: &to})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"2"}, amounts(ranged), "from is inclusive and to exclusive")
+
+	utc := from.UTC()
+	since, err := b.Repo.ListTransactions(ctx, banking.TransactionFilter{From: &utc})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"3", "2"}, amounts(since), "bounds in any time zone")
+}
+
+func testListAccounts(t *testing.T, b Backend) {
+	ctx := context.Background()
+	none, err := b.Repo.ListAccounts(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, none)
+
+	for _, id := range []int{3, 1, 2} {
+		require.NoError(t, b.Repo.CreateAccount(ctx, models.Account{AccountID: id, Balance: "1"}))
+	}
+	accounts, err := b.Repo.ListAccounts(ctx)
+	require.NoError(t, err)
+	ids := make([]int, len(accounts))
+	for i, account := range accounts {
+		ids[i] = account.AccountID
+	}
+	assert.Equal(t, []int{1, 2, 3}, ids, "ordered by account ID")
+}
+
+func testGetAccounts(t *testing.T, b Backend) {
+	ctx := context.Background()
+	none, err := b.Repo.GetAccounts(ctx, nil)
+	require.NoError(t, err)
+	assert.Empty(t, none)
+
+	// More IDs than fit in one query, most of them missing.
+	ids := make([]int, 2500)
+	for i := range ids {
+		ids[i] = len(ids) - i
+	}
+	for _, id := range []int{2400, 7, 1200} {
+		require.NoError(t, b.Repo.CreateAccount(ctx, models.Account{AccountID: id, Balance: "1"}))
+	}
+	accounts, err := b.Repo.GetAccounts(ctx, ids)
+	require.NoError(t, err)
+	found := make([]int, len(accounts))
+	for i, account := range accounts {
+		found[i] = account.AccountID
+	}
+	assert.Equal(t, []int{7, 1200, 2400}, found, "only existing accounts, ordered by account ID")
+
+	// Inside a unit of work the lookup sees its own uncommitted accounts.
+	err = b.UoW.Do(ctx, func(ctx context.Context) error {
+		require.NoError(t, b.Repo.CreateAccount(ctx, models.Account{AccountID: 8, Balance: "1"}))
+		accounts, err := b.Repo.GetAccounts(ctx, []int{8, 9})
+		require.NoError(t, err)
+		require.Len(t, accounts, 1)
+		assert.Equal(t, 8, accounts[0].AccountID)
+		return nil
+	})
+	require.NoError(t, err)
+}
+
+func testFrozenAtRoundTrip(t *testing.T, b Backend) {
+	ctx := context.Background()
+	require.NoError(t, b.Repo.CreateAccount(ctx, models.Account{AccountID: 1, Balance: "1"}))
+	frozenAt := time.Now().UTC().Truncate(time.Second)
+
+	require.NoError(t, b.UoW.Do(ctx, func(ctx context.Context) error {
+		account, err := b.Repo.GetAccountTx(ctx, 1)
+		if err != nil {
+			return err
+		}
+		account.FrozenAt = &frozenAt
+		return b.Repo.UpdateAccount(ctx, account)
+	}))
+	account, err := b.Repo.GetAccount(ctx, 1)
+	require.NoError(t, err)
+	require.NotNil(t, account.FrozenAt)
+	assert.True(t, frozenAt.Equal(*account.FrozenAt))
+
+	require.NoError(t, b.UoW.Do(ctx, func(ctx context.Context) error {
+		account.FrozenAt = nil
+		return b.Repo.UpdateAccount(ctx, account)
+	}))
+	account, err = b.Repo.GetAccount(ctx, 1)
+	require.NoError(t, err)
+	assert.Nil(t, account.FrozenAt)
+}
+
+func testCurrencyAndMetadataRoundTrip(t *testing.T, b Backend) {
+	ctx := context.Background()
+	require.NoError(t, b.Repo.CreateAccount(ctx, models.Account{
+		AccountID: 1, Balance: "1", Currency: "EUR", Metadata: `{"unit":"emea"}`,
+	}))
+	require.NoError(t, b.Repo.CreateAccount(ctx, models.Account{AccountID: 2, Balance: "1"}))
+
+	account, err := b.Repo.GetAccount(ctx, 1)
+	require.NoError(t, err)
+	assert.Equal(t, "EUR", account.Currency)
+	assert.Equal(t, `{"unit":"emea"}`, account.Metadata)
+	account, err = b.Repo.GetAccount(ctx, 2)
+	require.NoError(t, err)
+	assert.Empty(t, account.Currency)
+	assert.Empty(t, account.Metadata)
+}
+
+func testCustomers(t *testing.T, b Backend) {
+	ctx := context.Background()
+	alice, err := b.Repo.CreateCustomer(ctx, models.Customer{Name: "Alice", Type: models.CustomerIndividual,
+		KYCStatus: models.KYCPending, Email: "alice@example.com"})
+	require.NoError(t, err)
+	require.NotZero(t, alice.ID)
+	acme, err := b.Repo.CreateCustomer(ctx, models.Customer{Name: "Acme", Type: models.CustomerBusiness,
+		KYCStatus: models.KYCPending})
+	require.NoError(t, err)
+	assert.NotEqual(t, alice.ID, acme.ID)
+
+	require.NoError(t, b.Repo.CreateAccount(ctx, models.Account{AccountID: 2, Balance: "5", CustomerID: &alice.ID}))
+	require.NoError(t, b.Repo.CreateAccount(ctx, models.Account{AccountID: 1, Balance: "10", CustomerID: &alice.ID}))
+	require.NoError(t, b.Repo.CreateAccount(ctx, models.Account{AccountID: 3, Balance: "1", CustomerID: &acme.ID}))
+	require.NoError(t, b.Repo.CreateAccount(ctx, models.Account{AccountID: 4, Balance: "1"}))
+
+	accounts, err := b.Repo.ListCustomerAccounts(ctx, alice.ID)
+	require.NoError(t, err)
+	require.Len(t, accounts, 2)
+	assert.Equal(t, 1, accounts[0].AccountID, "accounts are listed by account ID")
+	assert.Equal(t, 2, accounts[1].AccountID)
+	account, err := b.Repo.GetAccount(ctx, 3)
+	require.NoError(t, err)
+	require.NotNil(t, account.CustomerID)
+	assert.Equal(t, acme.ID, *account.CustomerID)
+	account, err = b.Repo.GetAccount(ctx, 4)
+	require.NoError(t, err)
+	assert.Nil(t, account.CustomerID)
+
+	alice.KYCStatus = models.KYCVerified
+	require.NoError(t, b.Repo.UpdateCustomer(ctx, alice))
+	got, err := b.Repo.GetCustomer(ctx, alice.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.KYCVerified, got.KYCStatus)
+	assert.Equal(t, "alice@example.com", got.Email)
+	got, err = b.Repo.GetCustomer(ctx, acme.ID+100)
+	require.NoError(t, err)
+	assert.Zero(t, got.ID, "an unknown customer is the zero value")
+
+	require.NoError(t, b.Repo.Transaction(ctx, models.Transaction{SourceAccountID: 1, DestinationAccountID: 2,
+		Amount: "1", Internal: true}))
+	source := 1
+	transactions, err := b.Repo.ListTransactions(ctx, banking.TransactionFilter{SourceAccountID: &source})
+	require.NoError(t, err)
+	require.Len(t, transactions, 1)
+	assert.True(t, transactions[0].Internal)
+}
+
+// transfer moves amount between accounts the way the usecase does: lock both in ascending
+// order, update the balances and record the transaction. ctx must carry a unit of work.
+func testTransactionDetailsLookup(t *testing.T, b Backend) {
+	ctx := context.Background()
+	for id := 1; id <= 3; id++ {
+		require.NoError(t, b.Repo.CreateAccount(ctx, models.Account{AccountID: id, Balance: "100"}))
+	}
+	for _, tr := range []models.Transaction{
+		{SourceAccountID: 1, DestinationAccountID: 2, Amount: "1", Reference: "INV-1", Description: "first",
+			Metadata: `{"order":"A-1","batch":7}`},
+		{SourceAccountID: 3, DestinationAccountID: 2, Amount: "2", Reference: "INV-1", Metadata: `{"order":"A-2"}`},
+		{SourceAccountID: 1, DestinationAccountID: 3, Amount: "3", Reference: "INV-2"},
+		{SourceAccountID: 2, DestinationAccountID: 1, Amount: "4"},
+	} {
+		require.NoError(t, b.Repo.Transaction(ctx, tr))
+	}
+	// A rolled back transfer leaves no metadata behind.
+	err := b.UoW.Do(ctx, func(ctx context.Context) error {
+		if err := b.Repo.Transaction(ctx, models.Transaction{SourceAccountID: 1, DestinationAccountID: 2,
+			Amount: "5", Metadata: `{"order":"A-3"}`}); err != nil {
+			return err
+		}
+		return errors.New("rollback")
+	})
+	require.Error(t, err)
+
+	list := func(filter banking.TransactionFilter) []string {
+		t.Helper()
+		transactions, err := b.Repo.ListTransactions(ctx, filter)
+		require.NoError(t, err)
+		return amounts(transactions)
+	}
+	reference, source := "INV-1", 1
+	assert.Equal(t, []string{"2", "1"}, list(banking.TransactionFilter{Reference: &reference}))
+	assert.Equal(t, []string{"1"}, list(banking.TransactionFilter{Reference: &reference, SourceAccountID: &source}))
+	empty := ""
+	assert.Equal(t, []string{"4"}, list(banking.TransactionFilter{Reference: &empty}))
+
+	assert.Equal(t, []string{"2", "1"}, list(banking.TransactionFilter{MetadataKey: "order"}))
+	value := "A-2"
+	assert.Equal(t, []string{"2"}, list(banking.TransactionFilter{MetadataKey: "order", MetadataValue: &value}))
+	number := "7"
+	assert.Equal(t, []string{"1"}, list(banking.TransactionFilter{MetadataKey: "batch", MetadataValue: &number}),
+		"non-string values match as JSON")
+	assert.Empty(t, list(banking.TransactionFilter{MetadataKey: "A-2"}))
+
+	transactions, err := b.Repo.ListTransactions(ctx, banking.TransactionFilter{Reference: &reference, SourceAccountID: &source})
+	require.NoError(t, err)
+	require.Len(t, transactions, 1)
+	assert.Equal(t, "first", transactions[0].Description)
+	assert.JSONEq(t, `{"order":"A-1","batch":7}`, transactions[0].Metadata)
+}
+
+func transfer(ctx context.Context, b Backend, from, to int, amount string) error {
+	first, second := from, to
+	if second < first {
+		first, second = second, first
+	}
+	locked := make(map[int]models.Account, 2)
+	for _, id := range []int{first, second} {
+		account, err := b.Repo.GetAccountTx(ctx, id)
+		if err != nil {
+			return err
+		}
+		locked[id] = account
+	}
+
+	value := decimal.RequireFromString(amount)
+	source, destination := locked[from], locked[to]
+	source.Balance = decimal.RequireFromString(source.Balance).Sub(value).String()
+	destination.Balance = decimal.RequireFromString(destination.Balance).Add(value).String()
+	if err := b.Repo.UpdateAccount(ctx, source); err != nil {
+		return err
+	}
+	if err := b.Repo.UpdateAccount(ctx, destination); err != nil {
+		return err
+	}
+	return b.Repo.Transaction(ctx, models.Transaction{
+		SourceAccountID:      from,
+		DestinationAccountID: to,
+		Amount:               amount,
+	})
+}
+
+func assertBalances(t *testing.T, b Backend, want map[int]string) {
+	t.Helper()
+	for id, balance := range want {
+		account, err := b.Repo.GetAccount(context.Background(), id)
+		require.NoError(t, err)
+		assertBalance(t, balance, account.Balance)
+	}
+}
+
+// assertBalance compares balances as decimals, since backends may normalise the text.
+func assertBalance(t *testing.T, want, got string) {
+	t.Helper()
+	g, err := decimal.NewFromString(got)
+	require.NoError(t, err, "balance %q", got)
+	assert.True(t, decimal.RequireFromString(want).Equal(g), "balance: want %s, got %s", want, got)
+}
+
+func amounts(transactions []models.Transaction) []string {
+	out := make([]string, len(transactions))
+	for i, tr := range transactions {
+		out[i] = decimal.RequireFromString(tr.Amount).String()
+	}
+	return out
+}
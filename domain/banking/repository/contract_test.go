@@ -0,0 +1,83 @@
+package repository
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/rohanchauhan02/internal-transfer/domain/banking/repository/contract"
+	"github.com/rohanchauhan02/internal-transfer/pkg/config"
+	"github.com/rohanchauhan02/internal-transfer/pkg/database"
+	"github.com/rohanchauhan02/internal-transfer/pkg/migrations"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+// bankingTables are the tables the banking repository writes. The Postgres contract runner
+// empties them before every case.
+var bankingTables = []string{"accounts", "transactions", "transaction_metadata", "customers"}
+
+// otherTables are created by the migrations for other domains and left alone by the runner.
+var otherTables = []string{"api_keys", "audit_records", "rate_limit_buckets", "schema_migrations"}
+
+func TestMemoryStore_Contract(t *testing.T) {
+	contract.Run(t, func(t *testing.T) contract.Backend {
+		store := NewMemoryStore()
+		return contract.Backend{Repo: store, UoW: store}
+	})
+}
+
+func TestBankingRepository_SQLiteContract(t *testing.T) {
+	contract.Run(t, func(t *testing.T) contract.Backend {
+		// A file database, so readers outside a unit of work get their own connection.
+		db, err := database.NewSQLite(config.DB{Path: filepath.Join(t.TempDir(), "contract.db")}).InitClient(context.Background())
+		require.NoError(t, err)
+		return migratedBackend(t, db, migrations.SQLite)
+	})
+}
+
+// TestBankingTablesCoverMigrations fails when a migration adds a table that is in neither list,
+// so the Postgres runner cannot silently leave rows behind between cases.
+func TestBankingTablesCoverMigrations(t *testing.T) {
+	db, err := database.NewSQLite(config.DB{Path: filepath.Join(t.TempDir(), "tables.db")}).InitClient(context.Background())
+	require.NoError(t, err)
+	migratedBackend(t, db, migrations.SQLite)
+
+	var tables []string
+	require.NoError(t, db.Raw("SELECT name FROM sqlite_master WHERE type = 'table' AND name NOT LIKE 'sqlite_%'").Scan(&tables).Error)
+	assert.ElementsMatch(t, append(slices.Clone(bankingTables), otherTables...), tables)
+}
+
+// TestBankingRepository_PostgresContract runs against the database in CONTRACT_POSTGRES_DSN.
+// Its banking tables are truncated before every case.
+func TestBankingRepository_PostgresContract(t *testing.T) {
+	dsn := os.Getenv("CONTRACT_POSTGRES_DSN")
+	if dsn == "" {
+		t.Skip("CONTRACT_POSTGRES_DSN not set")
+	}
+	contract.Run(t, func(t *testing.T) contract.Backend {
+		db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{TranslateError: true})
+		require.NoError(t, err)
+		backend := migratedBackend(t, db, migrations.Postgres)
+		require.NoError(t, db.Exec("TRUNCATE "+strings.Join(bankingTables, ", ")+" RESTART IDENTITY CASCADE").Error)
+		return backend
+	})
+}
+
+func migratedBackend(t *testing.T, db *gorm.DB, dialect migrations.Dialect) contract.Backend {
+	t.Helper()
+	sqlDB, err := db.DB()
+	require.NoError(t, err)
+	t.Cleanup(func() { sqlDB.Close() })
+
+	migrator, err := migrations.New(sqlDB, dialect)
+	require.NoError(t, err)
+	_, err = migrator.Up(context.Background())
+	require.NoError(t, err)
+	return contract.Backend{Repo: NewBankingRepository(db), UoW: NewUnitOfWork(db)}
+}
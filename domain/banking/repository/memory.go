@@ -0,0 +1,454 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"maps"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/rohanchauhan02/internal-transfer/domain/banking"
+	"github.com/rohanchauhan02/internal-transfer/models"
+)
+
+var (
+	errTxDone   = errors.New("unit of work already committed or rolled back")
+	errReadOnly = errors.New("unit of work is read-only")
+)
+
+// MemoryStore keeps accounts and transactions in process memory. It implements both
+// banking.Repository and banking.UnitOfWork with the same guarantees the database gives:
+// writes in a unit of work are invisible to others until commit, and locked accounts stay
+// locked until commit or rollback.
+type MemoryStore struct {
+	mu           sync.RWMutex
+	accounts     map[int]models.Account
+	transactions []models.Transaction
+	customers    map[uint]models.Customer
+	// Sequences for the primary keys, as the database would assign them.
+	nextAccountID     uint
+	nextTransactionID uint
+	nextCustomerID    uint
+
+	lockMu sync.Mutex
+	// locks holds only the account locks some unit of work holds or waits for.
+	locks map[int]*accountLock
+}
+
+// accountLock is the lock of one account ID.
+type accountLock struct {
+	ch chan struct{}
+	// refs counts the units of work holding or waiting for the lock; at zero it is dropped.
+	refs int
+}
+
+// memoryTxKey is the context key of the unit of work opened by MemoryStore.Do.
+type memoryTxKey struct{}
+
+// memoryTx buffers the writes of one unit of work and the account locks it holds.
+type memoryTx struct {
+	held         map[int]bool
+	accounts     map[int]models.Account
+	created      map[int]bool
+	transactions []models.Transaction
+	customers    map[uint]models.Customer
+	done         bool
+	// snapshot is set for units of work opened by Snapshot, which read from it and cannot write.
+	snapshot *memorySnapshot
+}
+
+// memorySnapshot is the committed state as it was when a snapshot unit of work began.
+type memorySnapshot struct {
+	accounts     map[int]models.Account
+	transactions []models.Transaction
+	customers    map[uint]models.Customer
+}
+
+// NewMemoryStore creates an empty in-memory store
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		accounts:  make(map[int]models.Account),
+		customers: make(map[uint]models.Customer),
+		locks:     make(map[int]*accountLock),
+	}
+}
+
+var (
+	_ banking.Repository = (*MemoryStore)(nil)
+	_ banking.UnitOfWork = (*MemoryStore)(nil)
+)
+
+// Do runs fn in a unit of work, joining the one already in ctx if there is one
+func (s *MemoryStore) Do(ctx context.Context, fn func(ctx context.Context) error) error {
+	if _, ok := ctx.Value(memoryTxKey{}).(*memoryTx); ok {
+		return fn(ctx)
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	tx := &memoryTx{
+		held:      make(map[int]bool),
+		accounts:  make(map[int]models.Account),
+		created:   make(map[int]bool),
+		customers: make(map[uint]models.Customer),
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			s.rollback(tx)
+			panic(r)
+		}
+	}()
+	if err := fn(context.WithValue(ctx, memoryTxKey{}, tx)); err != nil {
+		s.rollback(tx)
+		return err
+	}
+	return s.commit(ctx, tx)
+}
+
+// Snapshot runs fn against a copy of the committed state, joining the unit of work already in
+// ctx if there is one. Transactions are only ever appended, so the copy shares them.
+func (s *MemoryStore) Snapshot(ctx context.Context, fn func(ctx context.Context) error) error {
+	if _, ok := ctx.Value(memoryTxKey{}).(*memoryTx); ok {
+		return fn(ctx)
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	s.mu.RLock()
+	snapshot := &memorySnapshot{
+		accounts:     maps.Clone(s.accounts),
+		transactions: slices.Clip(s.transactions),
+		customers:    maps.Clone(s.customers),
+	}
+	s.mu.RUnlock()
+	return fn(context.WithValue(ctx, memoryTxKey{}, &memoryTx{snapshot: snapshot}))
+}
+
+// CreateAccount adds an account. The account ID stays locked until the unit of work ends, so a
+// concurrent creation of the same ID waits and then fails with banking.ErrAccountExists.
+func (s *MemoryStore) CreateAccount(ctx context.Context, account models.Account) error {
+	return s.inTx(ctx, func(ctx context.Context, tx *memoryTx) error {
+		if err := s.lock(ctx, tx, account.AccountID); err != nil {
+			return err
+		}
+		if _, ok := s.read(tx, account.AccountID); ok {
+			return banking.ErrAccountExists
+		}
+		tx.accounts[account.AccountID] = account
+		tx.created[account.AccountID] = true
+		return nil
+	})
+}
+
+// GetAccount returns the committed state of an account, or the zero value if it does not exist
+func (s *MemoryStore) GetAccount(ctx context.Context, accountID int) (models.Account, error) {
+	if tx, ok := ctx.Value(memoryTxKey{}).(*memoryTx); ok {
+		account, _ := s.read(tx, accountID)
+		return account, nil
+	}
+	state, release := s.committed(ctx)
+	defer release()
+	return state.accounts[accountID], nil
+}
+
+// GetAccounts returns the existing accounts among accountIDs ordered by account ID
+func (s *MemoryStore) GetAccounts(ctx context.Context, accountIDs []int) ([]models.Account, error) {
+	accounts := make([]models.Account, 0, len(accountIDs))
+	if tx, ok := ctx.Value(memoryTxKey{}).(*memoryTx); ok {
+		for _, id := range accountIDs {
+			if account, ok := s.read(tx, id); ok {
+				accounts = append(accounts, account)
+			}
+		}
+	} else {
+		state, release := s.committed(ctx)
+		for _, id := range accountIDs {
+			if account, ok := state.accounts[id]; ok {
+				accounts = append(accounts, account)
+			}
+		}
+		release()
+	}
+	sort.Slice(accounts, func(i, j int) bool { return accounts[i].AccountID < accounts[j].AccountID })
+	return accounts, nil
+}
+
+// GetAccountTx locks an account until the unit of work ends and returns its latest state
+func (s *MemoryStore) GetAccountTx(ctx context.Context, accountID int) (models.Account, error) {
+	var account models.Account
+	err := s.inTx(ctx, func(ctx context.Context, tx *memoryTx) error {
+		if err := s.lock(ctx, tx, accountID); err != nil {
+			return err
+		}
+		account, _ = s.read(tx, accountID)
+		return nil
+	})
+	return account, err
+}
+
+// UpdateAccount stores the new state of an existing account
+func (s *MemoryStore) UpdateAccount(ctx context.Context, account models.Account) error {
+	return s.inTx(ctx, func(ctx context.Context, tx *memoryTx) error {
+		if err := s.lock(ctx, tx, account.AccountID); err != nil {
+			return err
+		}
+		account.UpdatedAt = time.Now()
+		tx.accounts[account.AccountID] = account
+		return nil
+	})
+}
+
+// Transaction records a transfer between accounts. Like a database sequence, the ID is taken at
+// once and not given back if the unit of work rolls back.
+func (s *MemoryStore) Transaction(ctx context.Context, transaction models.Transaction) error {
+	return s.inTx(ctx, func(_ context.Context, tx *memoryTx) error {
+		if _, err := metadataEntries(transaction.Metadata); err != nil {
+			return err
+		}
+		if transaction.CreatedAt.IsZero() {
+			transaction.CreatedAt = time.Now()
+		}
+		s.mu.Lock()
+		s.nextTransactionID++
+		transaction.ID = s.nextTransactionID
+		s.mu.Unlock()
+		tx.transactions = append(tx.transactions, transaction)
+		return nil
+	})
+}
+
+// ListTransactions returns the transactions matching the filter, newest first: the committed
+// ones and those recorded earlier in the unit of work from ctx
+func (s *MemoryStore) ListTransactions(ctx context.Context, filter banking.TransactionFilter) ([]models.Transaction, error) {
+	state, release := s.committed(ctx)
+	defer release()
+	visible := state.transactions
+	if tx, ok := ctx.Value(memoryTxKey{}).(*memoryTx); ok && len(tx.transactions) > 0 {
+		visible = append(slices.Clip(visible), tx.transactions...)
+	}
+	var transactions []models.Transaction
+	for _, transaction := range visible {
+		if filter.AccountID != nil && transaction.SourceAccountID != *filter.AccountID &&
+			transaction.DestinationAccountID != *filter.AccountID {
+			continue
+		}
+		if filter.SourceAccountID != nil && transaction.SourceAccountID != *filter.SourceAccountID ||
+			filter.Reference != nil && transaction.Reference != *filter.Reference ||
+			filter.MetadataKey != "" && !hasMetadata(transaction, filter.MetadataKey, filter.MetadataValue) {
+			continue
+		}
+		if filter.From != nil && transaction.CreatedAt.Before(*filter.From) ||
+			filter.To != nil && !transaction.CreatedAt.Before(*filter.To) {
+			continue
+		}
+		transactions = append(transactions, transaction)
+	}
+	sort.Slice(transactions, func(i, j int) bool {
+		if !transactions[i].CreatedAt.Equal(transactions[j].CreatedAt) {
+			return transactions[i].CreatedAt.After(transactions[j].CreatedAt)
+		}
+		return transactions[i].ID > transactions[j].ID
+	})
+	if filter.Limit > 0 && len(transactions) > filter.Limit {
+		transactions = transactions[:filter.Limit]
+	}
+	return transactions, nil
+}
+
+// hasMetadata reports whether the transaction's metadata has the key, with the value if one is given.
+func hasMetadata(transaction models.Transaction, key string, value *string) bool {
+	entries, _ := metadataEntries(transaction.Metadata)
+	for _, e := range entries {
+		if e.Key == key && (value == nil || e.Value == *value) {
+			return true
+		}
+	}
+	return false
+}
+
+// ListAccounts returns the committed state of every account ordered by account ID
+func (s *MemoryStore) ListAccounts(ctx context.Context) ([]models.Account, error) {
+	state, release := s.committed(ctx)
+	defer release()
+	accounts := make([]models.Account, 0, len(state.accounts))
+	for _, account := range state.accounts {
+		accounts = append(accounts, account)
+	}
+	sort.Slice(accounts, func(i, j int) bool { return accounts[i].AccountID < accounts[j].AccountID })
+	return accounts, nil
+}
+
+// CreateCustomer adds a customer. Like a database sequence, the ID is taken at once and not
+// given back if the unit of work rolls back.
+func (s *MemoryStore) CreateCustomer(ctx context.Context, customer models.Customer) (models.Customer, error) {
+	err := s.inTx(ctx, func(_ context.Context, tx *memoryTx) error {
+		s.mu.Lock()
+		s.nextCustomerID++
+		customer.ID = s.nextCustomerID
+		s.mu.Unlock()
+		customer.CreatedAt = time.Now()
+		customer.UpdatedAt = customer.CreatedAt
+		tx.customers[customer.ID] = customer
+		return nil
+	})
+	if err != nil {
+		return models.Customer{}, err
+	}
+	return customer, nil
+}
+
+// GetCustomer returns a customer as seen by the unit of work in ctx, or the zero value if it does not exist
+func (s *MemoryStore) GetCustomer(ctx context.Context, customerID uint) (models.Customer, error) {
+	if tx, ok := ctx.Value(memoryTxKey{}).(*memoryTx); ok {
+		if customer, ok := tx.customers[customerID]; ok {
+			return customer, nil
+		}
+	}
+	state, release := s.committed(ctx)
+	defer release()
+	return state.customers[customerID], nil
+}
+
+// UpdateCustomer stores the new state of an existing customer
+func (s *MemoryStore) UpdateCustomer(ctx context.Context, customer models.Customer) error {
+	return s.inTx(ctx, func(_ context.Context, tx *memoryTx) error {
+		customer.UpdatedAt = time.Now()
+		tx.customers[customer.ID] = customer
+		return nil
+	})
+}
+
+// ListCustomerAccounts returns the committed accounts of a customer ordered by account ID
+func (s *MemoryStore) ListCustomerAccounts(ctx context.Context, customerID uint) ([]models.Account, error) {
+	state, release := s.committed(ctx)
+	defer release()
+	var accounts []models.Account
+	for _, account := range state.accounts {
+		if account.CustomerID != nil && *account.CustomerID == customerID {
+			accounts = append(accounts, account)
+		}
+	}
+	sort.Slice(accounts, func(i, j int) bool { return accounts[i].AccountID < accounts[j].AccountID })
+	return accounts, nil
+}
+
+// inTx runs op in the unit of work from ctx, or in one of its own that commits immediately.
+func (s *MemoryStore) inTx(ctx context.Context, op func(context.Context, *memoryTx) error) error {
+	if tx, ok := ctx.Value(memoryTxKey{}).(*memoryTx); ok {
+		switch {
+		case tx.done:
+			return errTxDone
+		case tx.snapshot != nil:
+			return errReadOnly
+		}
+		return op(ctx, tx)
+	}
+	return s.Do(ctx, func(ctx context.Context) error {
+		return op(ctx, ctx.Value(memoryTxKey{}).(*memoryTx))
+	})
+}
+
+// read returns an account as seen by tx: its own writes first, then the committed state.
+func (s *MemoryStore) read(tx *memoryTx, accountID int) (models.Account, bool) {
+	if account, ok := tx.accounts[accountID]; ok {
+		return account, true
+	}
+	if tx.snapshot != nil {
+		account, ok := tx.snapshot.accounts[accountID]
+		return account, ok
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	account, ok := s.accounts[accountID]
+	return account, ok
+}
+
+// committed returns the committed state read in ctx and the function that releases it: the
+// snapshot of a Snapshot unit of work, or else the store itself under its read lock.
+func (s *MemoryStore) committed(ctx context.Context) (*memorySnapshot, func()) {
+	if tx, ok := ctx.Value(memoryTxKey{}).(*memoryTx); ok && tx.snapshot != nil {
+		return tx.snapshot, func() {}
+	}
+	s.mu.RLock()
+	return &memorySnapshot{accounts: s.accounts, transactions: s.transactions, customers: s.customers}, s.mu.RUnlock
+}
+
+// lock acquires the account lock for tx, waiting until the holder finishes or ctx is done.
+// Locks are re-entrant within a unit of work.
+func (s *MemoryStore) lock(ctx context.Context, tx *memoryTx, accountID int) error {
+	if tx.held[accountID] {
+		return nil
+	}
+	s.lockMu.Lock()
+	l, ok := s.locks[accountID]
+	if !ok {
+		l = &accountLock{ch: make(chan struct{}, 1)}
+		s.locks[accountID] = l
+	}
+	l.refs++
+	s.lockMu.Unlock()
+
+	select {
+	case l.ch <- struct{}{}:
+		tx.held[accountID] = true
+		return nil
+	case <-ctx.Done():
+		s.lockMu.Lock()
+		s.unref(accountID, l)
+		s.lockMu.Unlock()
+		return ctx.Err()
+	}
+}
+
+func (s *MemoryStore) unlockAll(tx *memoryTx) {
+	s.lockMu.Lock()
+	defer s.lockMu.Unlock()
+	for accountID := range tx.held {
+		l := s.locks[accountID]
+		<-l.ch
+		s.unref(accountID, l)
+	}
+	tx.held = nil
+	tx.done = true
+}
+
+// unref drops a unit of work's interest in a lock, and the lock itself once nobody holds or
+// waits for it, so IDs of missing accounts do not pile up. lockMu must be held.
+func (s *MemoryStore) unref(accountID int, l *accountLock) {
+	l.refs--
+	if l.refs == 0 {
+		delete(s.locks, accountID)
+	}
+}
+
+func (s *MemoryStore) commit(ctx context.Context, tx *memoryTx) error {
+	defer s.unlockAll(tx)
+	// Like a database, a unit of work whose context has ended is not committed.
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := time.Now()
+	for accountID, account := range tx.accounts {
+		if tx.created[accountID] {
+			s.nextAccountID++
+			account.ID = s.nextAccountID
+			account.CreatedAt = now
+			account.UpdatedAt = now
+		}
+		s.accounts[accountID] = account
+	}
+	for customerID, customer := range tx.customers {
+		s.customers[customerID] = customer
+	}
+	s.transactions = append(s.transactions, tx.transactions...)
+	return nil
+}
+
+func (s *MemoryStore) rollback(tx *memoryTx) {
+	s.unlockAll(tx)
+}
@@ -0,0 +1,115 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/rohanchauhan02/internal-transfer/domain/banking"
+	"github.com/rohanchauhan02/internal-transfer/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryStore_UncommittedWritesAreIsolated(t *testing.T) {
+	store := NewMemoryStore()
+	ctx := context.Background()
+	require.NoError(t, store.CreateAccount(ctx, models.Account{AccountID: 1, Balance: "10"}))
+
+	errAbort := errors.New("abort")
+	err := store.Do(ctx, func(ctx context.Context) error {
+		account, err := store.GetAccountTx(ctx, 1)
+		require.NoError(t, err)
+		account.Balance = "0"
+		require.NoError(t, store.UpdateAccount(ctx, account))
+
+		// The unit of work reads its own write, everyone else still sees the committed value.
+		inside, _ := store.GetAccount(ctx, 1)
+		outside, _ := store.GetAccount(context.Background(), 1)
+		assert.Equal(t, "0", inside.Balance)
+		assert.Equal(t, "10", outside.Balance)
+		return errAbort
+	})
+	assert.ErrorIs(t, err, errAbort)
+
+	account, err := store.GetAccount(ctx, 1)
+	require.NoError(t, err)
+	assert.Equal(t, "10", account.Balance)
+}
+
+func TestMemoryStore_LockedAccountBlocksUntilCommit(t *testing.T) {
+	store := NewMemoryStore()
+	ctx := context.Background()
+	require.NoError(t, store.CreateAccount(ctx, models.Account{AccountID: 1, Balance: "10"}))
+
+	locked := make(chan struct{})
+	release := make(chan struct{})
+	done := make(chan error)
+	go func() {
+		done <- store.Do(ctx, func(ctx context.Context) error {
+			account, err := store.GetAccountTx(ctx, 1)
+			if err != nil {
+				return err
+			}
+			close(locked)
+			<-release
+			account.Balance = "20"
+			return store.UpdateAccount(ctx, account)
+		})
+	}()
+	<-locked
+
+	waitCtx, cancel := context.WithTimeout(ctx, 20*time.Millisecond)
+	defer cancel()
+	_, err := store.GetAccountTx(waitCtx, 1)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+	close(release)
+	require.NoError(t, <-done)
+	account, err := store.GetAccountTx(ctx, 1)
+	require.NoError(t, err)
+	assert.Equal(t, "20", account.Balance)
+}
+
+func TestMemoryStore_DropsIdleLocks(t *testing.T) {
+	store := NewMemoryStore()
+	ctx := context.Background()
+	require.NoError(t, store.CreateAccount(ctx, models.Account{AccountID: 1, Balance: "10"}))
+
+	// Locking IDs nobody created, as transfers to missing accounts do, leaves nothing behind.
+	for id := 100; id < 200; id++ {
+		_, err := store.GetAccountTx(ctx, id)
+		require.NoError(t, err)
+	}
+	locked := make(chan struct{})
+	release := make(chan struct{})
+	done := make(chan error)
+	go func() {
+		done <- store.Do(ctx, func(ctx context.Context) error {
+			if _, err := store.GetAccountTx(ctx, 1); err != nil {
+				return err
+			}
+			close(locked)
+			<-release
+			return nil
+		})
+	}()
+	<-locked
+	waitCtx, cancel := context.WithTimeout(ctx, 20*time.Millisecond)
+	defer cancel()
+	_, err := store.GetAccountTx(waitCtx, 1)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Len(t, store.locks, 1, "the held lock stays")
+
+	close(release)
+	require.NoError(t, <-done)
+	assert.Empty(t, store.locks)
+}
+
+func TestMemoryStore_DuplicateAccount(t *testing.T) {
+	store := NewMemoryStore()
+	ctx := context.Background()
+	require.NoError(t, store.CreateAccount(ctx, models.Account{AccountID: 7, Balance: "1"}))
+	assert.ErrorIs(t, store.CreateAccount(ctx, models.Account{AccountID: 7, Balance: "2"}), banking.ErrAccountExists)
+}
@@ -1,11 +1,25 @@
 package repository
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+
 	"github.com/rohanchauhan02/internal-transfer/domain/banking"
 	"github.com/rohanchauhan02/internal-transfer/models"
+	"github.com/rohanchauhan02/internal-transfer/pkg/tracing"
+	"go.opentelemetry.io/otel/attribute"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
+var tracer = tracing.Tracer("banking/repository")
+
+// getAccountsChunkSize bounds the IDs bound into one GetAccounts query.
+const getAccountsChunkSize = 1000
+
 type bankingRepository struct {
 	db *gorm.DB
 }
@@ -17,38 +31,189 @@ func NewBankingRepository(db *gorm.DB) banking.Repository {
 	}
 }
 
-// CreateAccount creates a new account in the database
-func (r *bankingRepository) CreateAccount(tx *gorm.DB, account models.Account) error {
-	return tx.Create(&account).Error
+// CreateAccount creates a new account in the database. The unique constraint on account_id
+// decides between concurrent creations; the loser gets banking.ErrAccountExists.
+func (r *bankingRepository) CreateAccount(ctx context.Context, account models.Account) error {
+	// account_id is the only unique column the insert sets, so a duplicate key means a taken account ID.
+	err := r.conn(ctx).Create(&account).Error
+	if errors.Is(err, gorm.ErrDuplicatedKey) {
+		return banking.ErrAccountExists
+	}
+	return err
 }
 
 // GetAccount retrieves an account by its ID
-func (r *bankingRepository) GetAccount(accountID int) (models.Account, error) {
+func (r *bankingRepository) GetAccount(ctx context.Context, accountID int) (models.Account, error) {
 	var account models.Account
-	if err := r.db.Where("account_id", accountID).Find(&account).Error; err != nil {
+	if err := r.conn(ctx).Where("account_id", accountID).Find(&account).Error; err != nil {
 		return models.Account{}, err
 	}
 	return account, nil
 }
 
-// GetAccountTx retrieves an account by its ID within a transaction
-func (r *bankingRepository) GetAccountTx(tx *gorm.DB, accountID int) (models.Account, error) {
-	var account models.Account
-	if err := tx.Raw("SELECT * FROM accounts WHERE account_id = ? FOR UPDATE", accountID).Scan(&account).Error; err != nil {
-		return models.Account{}, err
+// GetAccounts retrieves the existing accounts among accountIDs ordered by account ID, querying
+// at most getAccountsChunkSize IDs at a time to stay under the databases' bind parameter limits
+func (r *bankingRepository) GetAccounts(ctx context.Context, accountIDs []int) ([]models.Account, error) {
+	accounts := make([]models.Account, 0, len(accountIDs))
+	for start := 0; start < len(accountIDs); start += getAccountsChunkSize {
+		var chunk []models.Account
+		ids := accountIDs[start:min(start+getAccountsChunkSize, len(accountIDs))]
+		if err := r.conn(ctx).Where("account_id IN ?", ids).Find(&chunk).Error; err != nil {
+			return nil, err
+		}
+		accounts = append(accounts, chunk...)
 	}
-	if err := tx.Where("account_id = ?", accountID).Find(&account).Error; err != nil {
+	sort.Slice(accounts, func(i, j int) bool { return accounts[i].AccountID < accounts[j].AccountID })
+	return accounts, nil
+}
+
+// GetAccountTx retrieves an account by its ID and locks its row until the unit of work ends.
+// SQLite drops the FOR UPDATE clause; there the unit of work already holds the database write
+// lock, taken by BEGIN IMMEDIATE.
+func (r *bankingRepository) GetAccountTx(ctx context.Context, accountID int) (models.Account, error) {
+	ctx, span := tracer.Start(ctx, "banking.GetAccountTx")
+	span.SetAttributes(attribute.Int("account.id", accountID))
+	defer span.End()
+
+	var account models.Account
+	if err := r.conn(ctx).Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("account_id = ?", accountID).Find(&account).Error; err != nil {
+		span.RecordError(err)
 		return models.Account{}, err
 	}
 	return account, nil
 }
 
 // UpdateAccount updates an existing account in the database
-func (r *bankingRepository) UpdateAccount(tx *gorm.DB, account models.Account) error {
-	return tx.Save(&account).Error
+func (r *bankingRepository) UpdateAccount(ctx context.Context, account models.Account) error {
+	return r.conn(ctx).Save(&account).Error
 }
 
 // Transaction processes a transaction between accounts
-func (r *bankingRepository) Transaction(tx *gorm.DB, transaction models.Transaction) error {
-	return tx.Create(&transaction).Error
+func (r *bankingRepository) Transaction(ctx context.Context, transaction models.Transaction) error {
+	entries, err := metadataEntries(transaction.Metadata)
+	if err != nil {
+		return err
+	}
+	// Inside a unit of work this nests as a savepoint; outside one it keeps the rows together.
+	return r.conn(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&transaction).Error; err != nil {
+			return err
+		}
+		if len(entries) == 0 {
+			return nil
+		}
+		for i := range entries {
+			entries[i].TransactionID = transaction.ID
+		}
+		return tx.Create(&entries).Error
+	})
+}
+
+// ListTransactions returns transactions matching the filter, newest first
+func (r *bankingRepository) ListTransactions(ctx context.Context, filter banking.TransactionFilter) ([]models.Transaction, error) {
+	var transactions []models.Transaction
+	query := r.conn(ctx).Order("created_at DESC, id DESC")
+	if filter.AccountID != nil {
+		query = query.Where("source_account_id = ? OR destination_account_id = ?", *filter.AccountID, *filter.AccountID)
+	}
+	if filter.SourceAccountID != nil {
+		query = query.Where("source_account_id = ?", *filter.SourceAccountID)
+	}
+	if filter.Reference != nil {
+		query = query.Where("reference = ?", *filter.Reference)
+	}
+	if filter.MetadataKey != "" {
+		entries := r.conn(ctx).Model(&models.TransactionMetadata{}).Select("transaction_id").
+			Where("key = ?", filter.MetadataKey)
+		if filter.MetadataValue != nil {
+			entries = entries.Where("value = ?", *filter.MetadataValue)
+		}
+		query = query.Where("id IN (?)", entries)
+	}
+	// Created times are written in local time; SQLite compares them as text, so bounds must be too.
+	if filter.From != nil {
+		query = query.Where("created_at >= ?", filter.From.Local())
+	}
+	if filter.To != nil {
+		query = query.Where("created_at < ?", filter.To.Local())
+	}
+	if filter.Limit > 0 {
+		query = query.Limit(filter.Limit)
+	}
+	if err := query.Find(&transactions).Error; err != nil {
+		return nil, err
+	}
+	return transactions, nil
+}
+
+// ListAccounts returns every account ordered by account ID
+func (r *bankingRepository) ListAccounts(ctx context.Context) ([]models.Account, error) {
+	var accounts []models.Account
+	if err := r.conn(ctx).Order("account_id").Find(&accounts).Error; err != nil {
+		return nil, err
+	}
+	return accounts, nil
+}
+
+// CreateCustomer stores a new customer; the database assigns its ID
+func (r *bankingRepository) CreateCustomer(ctx context.Context, customer models.Customer) (models.Customer, error) {
+	if err := r.conn(ctx).Create(&customer).Error; err != nil {
+		return models.Customer{}, err
+	}
+	return customer, nil
+}
+
+// GetCustomer retrieves a customer by its ID
+func (r *bankingRepository) GetCustomer(ctx context.Context, customerID uint) (models.Customer, error) {
+	var customer models.Customer
+	if err := r.conn(ctx).Where("id = ?", customerID).Find(&customer).Error; err != nil {
+		return models.Customer{}, err
+	}
+	return customer, nil
+}
+
+// UpdateCustomer updates an existing customer in the database
+func (r *bankingRepository) UpdateCustomer(ctx context.Context, customer models.Customer) error {
+	return r.conn(ctx).Save(&customer).Error
+}
+
+// ListCustomerAccounts returns the accounts of a customer ordered by account ID
+func (r *bankingRepository) ListCustomerAccounts(ctx context.Context, customerID uint) ([]models.Account, error) {
+	var accounts []models.Account
+	if err := r.conn(ctx).Where("customer_id = ?", customerID).Order("account_id").Find(&accounts).Error; err != nil {
+		return nil, err
+	}
+	return accounts, nil
+}
+
+// metadataEntries splits a transaction's metadata object into one row per top-level key,
+// ordered by key. String values are stored unquoted, so they can be matched as written.
+func metadataEntries(metadata string) ([]models.TransactionMetadata, error) {
+	if metadata == "" {
+		return nil, nil
+	}
+	var object map[string]json.RawMessage
+	if err := json.Unmarshal([]byte(metadata), &object); err != nil {
+		return nil, fmt.Errorf("transaction metadata: %w", err)
+	}
+	entries := make([]models.TransactionMetadata, 0, len(object))
+	for key, raw := range object {
+		value := string(raw)
+		var text string
+		if err := json.Unmarshal(raw, &text); err == nil {
+			value = text
+		}
+		entries = append(entries, models.TransactionMetadata{Key: key, Value: value})
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Key < entries[j].Key })
+	return entries, nil
+}
+
+// conn returns the transaction of the unit of work in ctx, or the shared handle outside of one.
+func (r *bankingRepository) conn(ctx context.Context) *gorm.DB {
+	if tx, ok := ctx.Value(txKey{}).(*gorm.DB); ok {
+		return tx.WithContext(ctx)
+	}
+	return r.db.WithContext(ctx)
 }
@@ -0,0 +1,45 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/rohanchauhan02/internal-transfer/domain/banking"
+	"gorm.io/gorm"
+)
+
+// txKey is the context key of the transaction opened by unitOfWork.Do.
+type txKey struct{}
+
+type unitOfWork struct {
+	db *gorm.DB
+}
+
+// NewUnitOfWork creates a UnitOfWork whose transactions are picked up by the GORM repository
+func NewUnitOfWork(db *gorm.DB) banking.UnitOfWork {
+	return &unitOfWork{
+		db: db,
+	}
+}
+
+// Do runs fn in a database transaction, joining the transaction already in ctx if there is one
+func (u *unitOfWork) Do(ctx context.Context, fn func(ctx context.Context) error) error {
+	if _, ok := ctx.Value(txKey{}).(*gorm.DB); ok {
+		return fn(ctx)
+	}
+	return u.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return fn(context.WithValue(ctx, txKey{}, tx))
+	})
+}
+
+// Snapshot runs fn in a read-only repeatable read transaction, joining the transaction already
+// in ctx if there is one. SQLite begins read-only transactions without IMMEDIATE, so they do not
+// take the write lock, and in WAL mode they read from a snapshot too.
+func (u *unitOfWork) Snapshot(ctx context.Context, fn func(ctx context.Context) error) error {
+	if _, ok := ctx.Value(txKey{}).(*gorm.DB); ok {
+		return fn(ctx)
+	}
+	return u.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return fn(context.WithValue(ctx, txKey{}, tx))
+	}, &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true})
+}
@@ -0,0 +1,210 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/mail"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/rohanchauhan02/internal-transfer/domain/audit"
+	"github.com/rohanchauhan02/internal-transfer/domain/banking"
+	"github.com/rohanchauhan02/internal-transfer/dto"
+	"github.com/rohanchauhan02/internal-transfer/models"
+	"github.com/rohanchauhan02/internal-transfer/pkg/logger"
+	"github.com/shopspring/decimal"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// Limits on customer details.
+const (
+	maxCustomerNameLength = 200
+	maxEmailLength        = 254
+	maxAddressLength      = 500
+)
+
+var phonePattern = regexp.MustCompile(`^\+?[0-9][0-9 ()-]{2,31}$`)
+
+// CreateCustomer creates a customer. Every customer starts with a pending KYC status, which
+// only SetKYCStatus changes.
+func (u *bankingUsecase) CreateCustomer(ctx context.Context, req dto.CustomerRequest) (dto.CustomerResponse, error) {
+	ctx, span := tracer.Start(ctx, "banking.CreateCustomer")
+	defer span.End()
+
+	customer, err := newCustomer(req)
+	if err != nil {
+		return dto.CustomerResponse{}, err
+	}
+	if err := u.uow.Do(ctx, func(ctx context.Context) error {
+		customer, err = u.repo.CreateCustomer(ctx, customer)
+		return err
+	}); err != nil {
+		return dto.CustomerResponse{}, fmt.Errorf("failed to create customer: %w", err)
+	}
+
+	resp := customerResponse(customer)
+	audit.EntryFromContext(ctx).SetAfter(resp)
+	span.SetAttributes(attribute.Int64("customer.id", int64(customer.ID)))
+	log.InfoContext(ctx, "customer created", slog.Uint64("customer_id", uint64(customer.ID)),
+		slog.String("type", customer.Type))
+	return resp, nil
+}
+
+// CreateCustomerAccount creates an account owned by the customer
+func (u *bankingUsecase) CreateCustomerAccount(ctx context.Context, customerID uint, accountID int, balance string) error {
+	return u.createAccount(ctx, models.Account{AccountID: accountID, Balance: balance, CustomerID: &customerID})
+}
+
+// CustomerAccounts returns a customer with its accounts and the sum of their balances
+func (u *bankingUsecase) CustomerAccounts(ctx context.Context, customerID uint) (dto.CustomerAccounts, error) {
+	customer, err := u.repo.GetCustomer(ctx, customerID)
+	if err != nil {
+		return dto.CustomerAccounts{}, err
+	}
+	if customer.ID == 0 {
+		return dto.CustomerAccounts{}, banking.ErrCustomerNotFound
+	}
+	accounts, err := u.repo.ListCustomerAccounts(ctx, customerID)
+	if err != nil {
+		return dto.CustomerAccounts{}, err
+	}
+
+	resp := dto.CustomerAccounts{
+		Customer: customerResponse(customer),
+		Accounts: make([]dto.AccountResponse, len(accounts)),
+	}
+	var total decimal.Decimal
+	for i, account := range accounts {
+		balance, err := decimal.NewFromString(account.Balance)
+		if err != nil {
+			return dto.CustomerAccounts{}, fmt.Errorf("account %d: invalid balance %q", account.AccountID, account.Balance)
+		}
+		total = total.Add(balance)
+		resp.Accounts[i] = accountResponse(account)
+	}
+	resp.TotalBalance = total.String()
+	return resp, nil
+}
+
+// SetKYCStatus changes a customer's KYC status
+func (u *bankingUsecase) SetKYCStatus(ctx context.Context, customerID uint, status string) (dto.CustomerResponse, error) {
+	ctx, span := tracer.Start(ctx, "banking.SetKYCStatus")
+	span.SetAttributes(attribute.Int64("customer.id", int64(customerID)), attribute.String("customer.kyc_status", status))
+	defer span.End()
+	ctx = logger.WithFields(ctx, slog.Uint64("customer_id", uint64(customerID)))
+
+	switch status {
+	case models.KYCPending, models.KYCVerified, models.KYCRejected:
+	default:
+		return dto.CustomerResponse{}, fmt.Errorf("%w: KYC status must be %s, %s or %s", banking.ErrInvalidCustomer,
+			models.KYCPending, models.KYCVerified, models.KYCRejected)
+	}
+
+	entry := audit.EntryFromContext(ctx)
+	var customer models.Customer
+	err := u.uow.Do(ctx, func(ctx context.Context) error {
+		var err error
+		if customer, err = u.repo.GetCustomer(ctx, customerID); err != nil {
+			return err
+		}
+		if customer.ID == 0 {
+			return banking.ErrCustomerNotFound
+		}
+		entry.SetBefore(customerResponse(customer))
+		if customer.KYCStatus == status {
+			return nil
+		}
+		customer.KYCStatus = status
+		return u.repo.UpdateCustomer(ctx, customer)
+	})
+	if err != nil {
+		return dto.CustomerResponse{}, err
+	}
+
+	resp := customerResponse(customer)
+	entry.SetAfter(resp)
+	log.InfoContext(ctx, "customer KYC status updated", slog.String("kyc_status", status))
+	return resp, nil
+}
+
+// checkKYC returns banking.ErrKYCNotVerified unless both accounts belong to KYC verified customers.
+func (u *bankingUsecase) checkKYC(ctx context.Context, accounts ...models.Account) error {
+	verified := map[uint]bool{}
+	for _, account := range accounts {
+		if account.CustomerID == nil {
+			return fmt.Errorf("%w: account %d has no customer", banking.ErrKYCNotVerified, account.AccountID)
+		}
+		if verified[*account.CustomerID] {
+			continue
+		}
+		customer, err := u.repo.GetCustomer(ctx, *account.CustomerID)
+		if err != nil {
+			return err
+		}
+		if customer.KYCStatus != models.KYCVerified {
+			return fmt.Errorf("%w: the customer of account %d is %s", banking.ErrKYCNotVerified, account.AccountID,
+				customer.KYCStatus)
+		}
+		verified[customer.ID] = true
+	}
+	return nil
+}
+
+// sameCustomer reports whether both accounts belong to the same customer.
+func sameCustomer(a, b models.Account) bool {
+	return a.CustomerID != nil && b.CustomerID != nil && *a.CustomerID == *b.CustomerID
+}
+
+// newCustomer checks the details of a new customer and returns it with a pending KYC status.
+func newCustomer(req dto.CustomerRequest) (models.Customer, error) {
+	invalid := func(reason string) error {
+		return fmt.Errorf("%w: %s", banking.ErrInvalidCustomer, reason)
+	}
+	customer := models.Customer{
+		Name:      strings.TrimSpace(req.Name),
+		Type:      req.Type,
+		KYCStatus: models.KYCPending,
+		Email:     strings.TrimSpace(req.Email),
+		Phone:     strings.TrimSpace(req.Phone),
+		Address:   strings.TrimSpace(req.Address),
+	}
+	switch {
+	case customer.Name == "":
+		return models.Customer{}, invalid("name is required")
+	case utf8.RuneCountInString(customer.Name) > maxCustomerNameLength:
+		return models.Customer{}, invalid(fmt.Sprintf("name must be at most %d characters", maxCustomerNameLength))
+	case customer.Type != models.CustomerIndividual && customer.Type != models.CustomerBusiness:
+		return models.Customer{}, invalid(fmt.Sprintf("type must be %s or %s", models.CustomerIndividual, models.CustomerBusiness))
+	case customer.Email != "" && !validEmail(customer.Email):
+		return models.Customer{}, invalid("email is not a valid address")
+	case customer.Phone != "" && !phonePattern.MatchString(customer.Phone):
+		return models.Customer{}, invalid("phone must be 3 to 32 digits, spaces, parentheses or dashes, optionally after a +")
+	case utf8.RuneCountInString(customer.Address) > maxAddressLength:
+		return models.Customer{}, invalid(fmt.Sprintf("address must be at most %d characters", maxAddressLength))
+	}
+	return customer, nil
+}
+
+// validEmail accepts a bare address, without a display name.
+func validEmail(email string) bool {
+	if len(email) > maxEmailLength {
+		return false
+	}
+	addr, err := mail.ParseAddress(email)
+	return err == nil && addr.Address == email
+}
+
+func customerResponse(customer models.Customer) dto.CustomerResponse {
+	return dto.CustomerResponse{
+		ID:        customer.ID,
+		Name:      customer.Name,
+		Type:      customer.Type,
+		KYCStatus: customer.KYCStatus,
+		Email:     customer.Email,
+		Phone:     customer.Phone,
+		Address:   customer.Address,
+		CreatedAt: customer.CreatedAt,
+	}
+}
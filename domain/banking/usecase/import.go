@@ -0,0 +1,214 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"regexp"
+	"sort"
+	"strconv"
+
+	"github.com/rohanchauhan02/internal-transfer/domain/audit"
+	"github.com/rohanchauhan02/internal-transfer/domain/banking"
+	"github.com/rohanchauhan02/internal-transfer/dto"
+	"github.com/rohanchauhan02/internal-transfer/models"
+	"github.com/shopspring/decimal"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// maxImportMetadataBytes bounds the metadata object of one imported account.
+const maxImportMetadataBytes = 4096
+
+var currencyCode = regexp.MustCompile(`^[A-Z]{3}$`)
+
+// importRow is a validated row waiting to be created.
+type importRow struct {
+	row     int
+	account models.Account
+}
+
+// ImportAccounts validates rows from opts.StartRow on and, unless opts.DryRun is set, creates
+// their accounts in chunks of opts.ChunkSize, each chunk in its own unit of work. Nothing is
+// written when any row is invalid. A row whose account already exists with the same balance,
+// currency and metadata is counted as existing and skipped, so an import that stopped part way
+// can be resumed by sending the file again, or by starting from the report's NextRow.
+func (u *bankingUsecase) ImportAccounts(ctx context.Context, rows []dto.AccountImportRow,
+	opts dto.AccountImportOptions) (dto.AccountImportReport, error) {
+	ctx, span := tracer.Start(ctx, "banking.ImportAccounts")
+	span.SetAttributes(attribute.Int("import.rows", len(rows)), attribute.Bool("import.dry_run", opts.DryRun))
+	defer span.End()
+
+	report := dto.AccountImportReport{DryRun: opts.DryRun, Errors: []dto.AccountImportError{}}
+	pending, err := u.validateImport(ctx, rows, opts.StartRow, &report)
+	if err != nil {
+		return report, err
+	}
+	if opts.DryRun {
+		return report, nil
+	}
+	if report.InvalidRows > 0 {
+		return report, banking.ErrImportInvalid
+	}
+
+	chunkSize := opts.ChunkSize
+	if chunkSize < 1 {
+		chunkSize = banking.DefaultImportChunkSize
+	}
+	for start := 0; start < len(pending); start += chunkSize {
+		chunk := pending[start:min(start+chunkSize, len(pending))]
+		err := u.uow.Do(ctx, func(ctx context.Context) error {
+			for _, r := range chunk {
+				if err := u.repo.CreateAccount(ctx, r.account); err != nil {
+					if errors.Is(err, banking.ErrAccountExists) {
+						// Created by someone else since the rows were validated.
+						report.Errors = append(report.Errors, dto.AccountImportError{
+							Row:       r.row,
+							AccountID: strconv.Itoa(r.account.AccountID),
+							Field:     "account_id",
+							Message:   "account already exists",
+						})
+					}
+					return err
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			report.NextRow = chunk[0].row
+			log.WarnContext(ctx, "account import stopped",
+				slog.Int("created", report.CreatedRows),
+				slog.Int("next_row", report.NextRow),
+				slog.String("error", err.Error()))
+			return report, fmt.Errorf("failed to import accounts from row %d: %w", report.NextRow, err)
+		}
+		report.CreatedRows += len(chunk)
+	}
+
+	audit.EntryFromContext(ctx).SetAfter(report)
+	log.InfoContext(ctx, "accounts imported",
+		slog.Int("created", report.CreatedRows),
+		slog.Int("existing", report.ExistingRows))
+	return report, nil
+}
+
+// validateImport checks the rows from startRow on, records their errors in report and returns
+// the rows whose accounts still have to be created.
+func (u *bankingUsecase) validateImport(ctx context.Context, rows []dto.AccountImportRow, startRow int,
+	report *dto.AccountImportReport) ([]importRow, error) {
+	var candidates []importRow
+	// firstRow maps each account ID to the first row that uses it.
+	firstRow := make(map[int]int)
+	for _, r := range rows {
+		if r.Row < startRow {
+			continue
+		}
+		report.TotalRows++
+		errorCount := len(report.Errors)
+		fail := func(field, message string) {
+			report.Errors = append(report.Errors, dto.AccountImportError{
+				Row: r.Row, AccountID: r.AccountID, Field: field, Message: message,
+			})
+		}
+
+		accountID, err := strconv.Atoi(r.AccountID)
+		switch {
+		case err != nil || accountID <= 0:
+			fail("account_id", "must be a positive integer")
+		case firstRow[accountID] != 0:
+			fail("account_id", fmt.Sprintf("duplicates row %d", firstRow[accountID]))
+		default:
+			firstRow[accountID] = r.Row
+		}
+		if balance, err := decimal.NewFromString(r.InitialBalance); err != nil {
+			fail("initial_balance", "must be a decimal number")
+		} else if balance.IsNegative() {
+			fail("initial_balance", "must not be negative")
+		}
+		if !currencyCode.MatchString(r.Currency) {
+			fail("currency", "must be a three letter ISO 4217 code, e.g. USD")
+		}
+		metadata, err := importMetadata(r.Metadata)
+		if err != nil {
+			fail("metadata", err.Error())
+		}
+		if len(report.Errors) > errorCount {
+			report.InvalidRows++
+			continue
+		}
+
+		candidates = append(candidates, importRow{row: r.Row, account: models.Account{
+			AccountID: accountID,
+			Balance:   r.InitialBalance,
+			Currency:  r.Currency,
+			Metadata:  metadata,
+		}})
+	}
+
+	// Look the valid rows' accounts up in one batch rather than one query per row.
+	ids := make([]int, len(candidates))
+	for i, c := range candidates {
+		ids[i] = c.account.AccountID
+	}
+	existingAccounts, err := u.repo.GetAccounts(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	existing := make(map[int]models.Account, len(existingAccounts))
+	for _, account := range existingAccounts {
+		existing[account.AccountID] = account
+	}
+
+	var pending []importRow
+	for _, c := range candidates {
+		if account, ok := existing[c.account.AccountID]; ok {
+			if !sameImportedAccount(account, c.account) {
+				report.Errors = append(report.Errors, dto.AccountImportError{
+					Row:       c.row,
+					AccountID: strconv.Itoa(c.account.AccountID),
+					Field:     "account_id",
+					Message:   "account already exists with different values",
+				})
+				report.InvalidRows++
+				continue
+			}
+			report.ExistingRows++
+		} else {
+			pending = append(pending, c)
+		}
+		report.ValidRows++
+	}
+	// Keep the errors in row order now that the lookup errors come after the field errors.
+	sort.SliceStable(report.Errors, func(i, j int) bool { return report.Errors[i].Row < report.Errors[j].Row })
+	return pending, nil
+}
+
+// importMetadata returns the compacted metadata object, or "" when raw is empty.
+func importMetadata(raw string) (string, error) {
+	if raw == "" {
+		return "", nil
+	}
+	if len(raw) > maxImportMetadataBytes {
+		return "", fmt.Errorf("must be at most %d bytes", maxImportMetadataBytes)
+	}
+	var object map[string]any
+	if err := json.Unmarshal([]byte(raw), &object); err != nil || object == nil {
+		return "", errors.New("must be a JSON object")
+	}
+	var buf bytes.Buffer
+	if err := json.Compact(&buf, []byte(raw)); err != nil {
+		return "", errors.New("must be a JSON object")
+	}
+	return buf.String(), nil
+}
+
+func sameImportedAccount(existing, imported models.Account) bool {
+	existingBalance, err := decimal.NewFromString(existing.Balance)
+	if err != nil {
+		return false
+	}
+	return existingBalance.Equal(decimal.RequireFromString(imported.Balance)) &&
+		existing.Currency == imported.Currency && existing.Metadata == imported.Metadata
+}
@@ -0,0 +1,285 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"runtime/pprof"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/rohanchauhan02/internal-transfer/domain/banking"
+	"github.com/rohanchauhan02/internal-transfer/domain/banking/repository"
+	"github.com/rohanchauhan02/internal-transfer/dto"
+	"github.com/rohanchauhan02/internal-transfer/pkg/config"
+	"github.com/rohanchauhan02/internal-transfer/pkg/database"
+	"github.com/rohanchauhan02/internal-transfer/pkg/logger"
+	"github.com/rohanchauhan02/internal-transfer/pkg/migrations"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+	gormlogger "gorm.io/gorm/logger"
+)
+
+// Stress parameters. A failing run logs its seed; rerun it with e.g.
+//
+//	go test ./domain/banking/usecase -run 'TestBankingUsecase_Invariants/memory' -stress.seed=1234
+//
+// The seed fixes the accounts, the transfer plan and which transfers are cancelled. Goroutine
+// scheduling is left to the runtime, so a seed reproduces the workload rather than one interleaving.
+var (
+	stressSeed      = flag.Int64("stress.seed", 0, "seed for the invariant tests, 0 picks one from the clock")
+	stressTransfers = flag.Int("stress.transfers", 5000, "transfers per invariant run")
+	stressAccounts  = flag.Int("stress.accounts", 8, "accounts per invariant run")
+	stressWorkers   = flag.Int("stress.workers", 32, "concurrent workers per invariant run")
+)
+
+// stressDeadline bounds a whole run. Transfers lock accounts in a fixed order, so a run that
+// does not finish in time is reported as a deadlock together with a goroutine dump.
+const stressDeadline = 2 * time.Minute
+
+type stressBackend struct {
+	name string
+	// scale shrinks the number of transfers for backends that serialise every write.
+	scale int
+	open  func(t *testing.T) (banking.UnitOfWork, banking.Repository)
+}
+
+var stressBackends = []stressBackend{
+	{
+		name:  "memory",
+		scale: 1,
+		open: func(t *testing.T) (banking.UnitOfWork, banking.Repository) {
+			store := repository.NewMemoryStore()
+			return store, store
+		},
+	},
+	{
+		name:  "sqlite",
+		scale: 5,
+		open: func(t *testing.T) (banking.UnitOfWork, banking.Repository) {
+			db, err := database.NewSQLite(config.DB{Path: filepath.Join(t.TempDir(), "stress.db")}).InitClient(context.Background())
+			require.NoError(t, err)
+			// Cancelled transfers would otherwise log every interrupted query.
+			db = db.Session(&gorm.Session{Logger: gormlogger.Discard})
+			sqlDB, err := db.DB()
+			require.NoError(t, err)
+			t.Cleanup(func() { sqlDB.Close() })
+			migrator, err := migrations.New(sqlDB, migrations.SQLite)
+			require.NoError(t, err)
+			_, err = migrator.Up(context.Background())
+			require.NoError(t, err)
+			return repository.NewUnitOfWork(db), repository.NewBankingRepository(db)
+		},
+	},
+}
+
+// plannedTransfer is one step of a seeded workload.
+type plannedTransfer struct {
+	from, to int
+	amount   string
+	// invalid marks amounts the usecase must reject with ErrInvalidAmount.
+	invalid bool
+	// cancelAfter, when non-zero, cancels the transfer's context after that delay.
+	cancelAfter time.Duration
+}
+
+type stressPlan struct {
+	balances  map[int]decimal.Decimal
+	transfers []plannedTransfer
+}
+
+// invalidStressAmounts are non-positive or unparseable amounts mixed into the workload.
+var invalidStressAmounts = []string{"0", "0.00", "-0.01", "-250.00", "abc", "12.3.4", "1,00"}
+
+// newStressPlan derives the whole workload from seed. Amounts are mostly small, with some
+// larger than a typical balance so insufficient funds and drained accounts are exercised too,
+// and a few that are not positive amounts at all.
+func newStressPlan(seed int64, accounts, transfers int) stressPlan {
+	rng := rand.New(rand.NewSource(seed))
+	plan := stressPlan{balances: make(map[int]decimal.Decimal, accounts)}
+	for id := 1; id <= accounts; id++ {
+		plan.balances[id] = decimal.New(rng.Int63n(100_000), -2)
+	}
+	for i := 0; i < transfers; i++ {
+		from := rng.Intn(accounts) + 1
+		to := rng.Intn(accounts-1) + 1
+		if to >= from {
+			to++
+		}
+		cents := rng.Int63n(5_000) + 1
+		if rng.Intn(10) == 0 {
+			cents = rng.Int63n(200_000) + 1
+		}
+		tr := plannedTransfer{from: from, to: to, amount: decimal.New(cents, -2).StringFixed(2)}
+		if rng.Intn(25) == 0 {
+			tr.amount, tr.invalid = invalidStressAmounts[rng.Intn(len(invalidStressAmounts))], true
+		}
+		if rng.Intn(20) == 0 {
+			tr.cancelAfter = time.Duration(rng.Intn(500)+1) * time.Microsecond
+		}
+		plan.transfers = append(plan.transfers, tr)
+	}
+	return plan
+}
+
+func TestBankingUsecase_Invariants(t *testing.T) {
+	seed := *stressSeed
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+	// Per-transfer logs would drown the output.
+	logger.Init(config.Logging{Level: "error"})
+	t.Cleanup(func() { logger.Init(config.Logging{}) })
+
+	for _, backend := range stressBackends {
+		t.Run(backend.name, func(t *testing.T) {
+			t.Cleanup(func() {
+				if t.Failed() {
+					t.Logf("reproduce with: go test ./domain/banking/usecase -run 'TestBankingUsecase_Invariants/%s' -stress.seed=%d", backend.name, seed)
+				}
+			})
+			transfers := *stressTransfers / backend.scale
+			if testing.Short() {
+				transfers /= 10
+			}
+			plan := newStressPlan(seed, *stressAccounts, transfers)
+			uow, repo := backend.open(t)
+			runStress(t, NewBankingUsecase(uow, repo, config.Banking{}), repo, plan)
+		})
+	}
+}
+
+func runStress(t *testing.T, usecase banking.Usecase, repo banking.Repository, plan stressPlan) {
+	ctx := context.Background()
+	initial := decimal.Zero
+	for id, balance := range plan.balances {
+		require.NoError(t, usecase.CreateAccount(ctx, id, balance.StringFixed(2)))
+		initial = initial.Add(balance)
+	}
+
+	// Watch committed balances while transfers run: none may ever be observed below zero.
+	stop := make(chan struct{})
+	negative := make(chan string, 1)
+	var watcher sync.WaitGroup
+	watcher.Add(1)
+	go func() {
+		defer watcher.Done()
+		for {
+			select {
+			case <-stop:
+				return
+			default:
+			}
+			for id := range plan.balances {
+				account, err := usecase.GetAccount(ctx, id)
+				if err == nil && decimal.RequireFromString(account.Balance).IsNegative() {
+					select {
+					case negative <- fmt.Sprintf("account %d observed at %s", id, account.Balance):
+					default:
+					}
+				}
+			}
+		}
+	}()
+
+	jobs := make(chan plannedTransfer)
+	var (
+		mu        sync.Mutex
+		succeeded int
+		failures  = make(map[string]int)
+		workers   sync.WaitGroup
+	)
+	for w := 0; w < *stressWorkers; w++ {
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			for tr := range jobs {
+				trCtx, cancel := context.WithCancel(ctx)
+				if tr.cancelAfter > 0 {
+					time.AfterFunc(tr.cancelAfter, cancel)
+				}
+				err := usecase.Transaction(trCtx, dto.TransactionRequest{SourceAccountID: tr.from, DestinationAccountID: tr.to, Amount: tr.amount})
+				cancelled := trCtx.Err() != nil
+				cancel()
+
+				mu.Lock()
+				switch {
+				case tr.invalid && errors.Is(err, banking.ErrInvalidAmount):
+					failures["invalid amount"]++
+				case tr.invalid:
+					failures[fmt.Sprintf("unexpected: amount %q gave %v", tr.amount, err)]++
+				case err == nil:
+					succeeded++
+				case cancelled:
+					failures["cancelled"]++
+				case errors.Is(err, banking.ErrInsufficientBalance):
+					failures["insufficient balance"]++
+				case strings.Contains(err.Error(), "database is locked"):
+					// SQLite gave up waiting for the write lock: the transfer was rolled back, which
+					// resolves the wait rather than leaving it hanging.
+					failures["lock timeout"]++
+				default:
+					failures["unexpected: "+err.Error()]++
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+
+	done := make(chan struct{})
+	go func() {
+		for _, tr := range plan.transfers {
+			jobs <- tr
+		}
+		close(jobs)
+		workers.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(stressDeadline):
+		pprof.Lookup("goroutine").WriteTo(os.Stderr, 2)
+		t.Fatalf("transfers did not finish within %s: unresolved deadlock", stressDeadline)
+	}
+	close(stop)
+	watcher.Wait()
+
+	select {
+	case msg := <-negative:
+		t.Errorf("negative balance: %s", msg)
+	default:
+	}
+	for reason, n := range failures {
+		assert.NotContains(t, reason, "unexpected", "%d transfers", n)
+	}
+
+	// Money is conserved, no account is negative, and the ledger explains every final balance.
+	final := decimal.Zero
+	net := make(map[int]decimal.Decimal, len(plan.balances))
+	history, err := repo.ListTransactions(ctx, banking.TransactionFilter{})
+	require.NoError(t, err)
+	assert.Len(t, history, succeeded, "one ledger entry per successful transfer")
+	for _, tr := range history {
+		amount := decimal.RequireFromString(tr.Amount)
+		net[tr.SourceAccountID] = net[tr.SourceAccountID].Sub(amount)
+		net[tr.DestinationAccountID] = net[tr.DestinationAccountID].Add(amount)
+	}
+	for id, start := range plan.balances {
+		account, err := usecase.GetAccount(ctx, id)
+		require.NoError(t, err)
+		balance := decimal.RequireFromString(account.Balance)
+		assert.False(t, balance.IsNegative(), "account %d ended at %s", id, balance)
+		assert.True(t, start.Add(net[id]).Equal(balance), "account %d: started at %s, ledger net %s, ended at %s", id, start, net[id], balance)
+		final = final.Add(balance)
+	}
+	assert.True(t, initial.Equal(final), "total balance changed from %s to %s", initial, final)
+	t.Logf("%d transfers: %d succeeded, other outcomes %v", len(plan.transfers), succeeded, failures)
+}
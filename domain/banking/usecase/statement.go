@@ -0,0 +1,104 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/rohanchauhan02/internal-transfer/domain/banking"
+	"github.com/rohanchauhan02/internal-transfer/dto"
+	"github.com/rohanchauhan02/internal-transfer/models"
+	"github.com/shopspring/decimal"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// Statement returns the account's transactions created in [from, to) with the balance after
+// each one. Initial balances are not in the transactions table, so the balances are worked
+// back from the current balance: the closing balance undoes every transaction from to on, and
+// the opening balance also undoes those in the period.
+func (u *bankingUsecase) Statement(ctx context.Context, accountID int, from, to time.Time) (dto.Statement, error) {
+	ctx, span := tracer.Start(ctx, "banking.Statement")
+	span.SetAttributes(attribute.Int("account.id", accountID))
+	defer span.End()
+
+	if !from.Before(to) {
+		return dto.Statement{}, banking.ErrInvalidPeriod
+	}
+	var account models.Account
+	var transactions []models.Transaction
+	// Both reads see the same snapshot, so a transfer committed in between cannot make the
+	// balance disagree with the transactions, and the statement does not hold transfers up.
+	err := u.uow.Snapshot(ctx, func(ctx context.Context) error {
+		var err error
+		if account, err = u.repo.GetAccount(ctx, accountID); err != nil {
+			return err
+		}
+		if account.AccountID == 0 {
+			return banking.ErrAccountNotFound
+		}
+		transactions, err = u.repo.ListTransactions(ctx, banking.TransactionFilter{AccountID: &accountID, From: &from})
+		return err
+	})
+	if err != nil {
+		return dto.Statement{}, err
+	}
+
+	balance, err := decimal.NewFromString(account.Balance)
+	if err != nil {
+		return dto.Statement{}, fmt.Errorf("invalid balance in account %d: %w", accountID, err)
+	}
+	credits, debits := decimal.Zero, decimal.Zero
+	var entries []dto.StatementEntry
+	// Transactions come newest first, so balance is the balance after t while walking back.
+	for _, t := range transactions {
+		amount, err := decimal.NewFromString(t.Amount)
+		if err != nil {
+			return dto.Statement{}, fmt.Errorf("invalid amount in transaction %d: %w", t.ID, err)
+		}
+		entry := dto.StatementEntry{
+			TransactionID:         t.ID,
+			Date:                  t.CreatedAt,
+			CounterpartyAccountID: t.SourceAccountID,
+			Direction:             dto.StatementCredit,
+			Amount:                amount.String(),
+			Balance:               balance.String(),
+			Reference:             t.Reference,
+			Description:           t.Description,
+		}
+		signed := amount
+		if t.SourceAccountID == accountID {
+			entry.CounterpartyAccountID = t.DestinationAccountID
+			entry.Direction = dto.StatementDebit
+			signed = amount.Neg()
+		}
+		balance = balance.Sub(signed)
+		if !t.CreatedAt.Before(to) {
+			continue
+		}
+		if entry.Direction == dto.StatementCredit {
+			credits = credits.Add(amount)
+		} else {
+			debits = debits.Add(amount)
+		}
+		entries = append(entries, entry)
+	}
+	slices.Reverse(entries)
+
+	statement := dto.Statement{
+		AccountID:      accountID,
+		Currency:       account.Currency,
+		From:           from,
+		To:             to,
+		OpeningBalance: balance.String(),
+		ClosingBalance: balance.Add(credits).Sub(debits).String(),
+		TotalCredits:   credits.String(),
+		TotalDebits:    debits.String(),
+		Entries:        entries,
+		GeneratedAt:    time.Now(),
+	}
+	if statement.Entries == nil {
+		statement.Entries = []dto.StatementEntry{}
+	}
+	return statement, nil
+}
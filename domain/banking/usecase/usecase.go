@@ -1,144 +1,422 @@
 package usecase
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
 	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+	"unicode/utf8"
 
-	"github.com/labstack/echo/v4"
+	"github.com/rohanchauhan02/internal-transfer/domain/audit"
 	"github.com/rohanchauhan02/internal-transfer/domain/banking"
 	"github.com/rohanchauhan02/internal-transfer/dto"
 	"github.com/rohanchauhan02/internal-transfer/models"
-	"github.com/rohanchauhan02/internal-transfer/pkg/ctx"
+	"github.com/rohanchauhan02/internal-transfer/pkg/config"
+	"github.com/rohanchauhan02/internal-transfer/pkg/logger"
+	"github.com/rohanchauhan02/internal-transfer/pkg/metrics"
+	"github.com/rohanchauhan02/internal-transfer/pkg/tracing"
 	"github.com/shopspring/decimal"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+)
+
+var (
+	tracer = tracing.Tracer("banking/usecase")
+	log    = logger.For("banking")
 )
 
 type bankingUsecase struct {
+	uow  banking.UnitOfWork
 	repo banking.Repository
+	conf config.Banking
 }
 
 // NewBankingUsecase creates a new banking usecase instance
-func NewBankingUsecase(repo banking.Repository) banking.Usecase {
+func NewBankingUsecase(uow banking.UnitOfWork, repo banking.Repository, conf config.Banking) banking.Usecase {
 	return &bankingUsecase{
+		uow:  uow,
 		repo: repo,
+		conf: conf,
 	}
 }
 
 // CreateAccount creates a new account
-func (u *bankingUsecase) CreateAccount(c echo.Context, accountID int, balance string) error {
-	ac := c.(*ctx.CustomApplicationContext)
+func (u *bankingUsecase) CreateAccount(ctx context.Context, accountID int, balance string) error {
+	return u.createAccount(ctx, models.Account{AccountID: accountID, Balance: balance})
+}
 
-	// Check if account already exists
-	existingAccount, err := u.repo.GetAccount(accountID)
-	if err == nil && existingAccount.AccountID == accountID {
-		return errors.New("account already exists with this user ID")
-	}
+// createAccount creates an account, after checking that its customer exists if it has one.
+func (u *bankingUsecase) createAccount(ctx context.Context, account models.Account) error {
+	ctx, span := tracer.Start(ctx, "banking.CreateAccount")
+	span.SetAttributes(attribute.Int("account.id", account.AccountID))
+	defer span.End()
+	ctx = logger.WithFields(ctx, slog.Int("account_id", account.AccountID))
 
-	account := models.Account{
-		AccountID: accountID,
-		Balance:   balance,
-	}
-	tx := ac.PostgresDB.Begin()
-	defer func() {
-		if r := recover(); r != nil {
-			tx.Rollback()
+	if err := u.uow.Do(ctx, func(ctx context.Context) error {
+		if account.CustomerID != nil {
+			customer, err := u.repo.GetCustomer(ctx, *account.CustomerID)
+			if err != nil {
+				return err
+			}
+			if customer.ID == 0 {
+				return banking.ErrCustomerNotFound
+			}
 		}
-	}()
-	if err := tx.Error; err != nil {
-		return errors.New("failed to start transaction")
-	}
-	if err := u.repo.CreateAccount(tx, account); err != nil {
-		tx.Rollback()
-		return errors.New("failed to create account: " + err.Error())
-	}
-	if err := tx.Commit().Error; err != nil {
-		return errors.New("failed to commit transaction: " + err.Error())
+		return u.repo.CreateAccount(ctx, account)
+	}); err != nil {
+		return fmt.Errorf("failed to create account: %w", err)
 	}
+
+	entry := audit.EntryFromContext(ctx)
+	entry.SetTarget(account.AccountID)
+	entry.SetAfter(accountResponse(account))
+	log.InfoContext(ctx, "account created", slog.String("initial_balance", account.Balance))
 	return nil
 }
 
 // GetAccount retrieves account details by account ID
-func (u *bankingUsecase) GetAccount(accountID int) (dto.AccountResponse, error) {
-	account, err := u.repo.GetAccount(accountID)
+func (u *bankingUsecase) GetAccount(ctx context.Context, accountID int) (dto.AccountResponse, error) {
+	account, err := u.repo.GetAccount(ctx, accountID)
 	if err != nil {
 		return dto.AccountResponse{}, err
 	}
-	return dto.AccountResponse{
-		AccountID: account.AccountID,
-		Balance:   account.Balance,
-	}, nil
+	if account.AccountID == 0 {
+		return dto.AccountResponse{}, banking.ErrAccountNotFound
+	}
+	return accountResponse(account), nil
 }
 
+// SetFrozen freezes or unfreezes an account. Freezing an already frozen account keeps the
+// original freeze time.
+func (u *bankingUsecase) SetFrozen(ctx context.Context, accountID int, frozen bool) (dto.AccountResponse, error) {
+	ctx, span := tracer.Start(ctx, "banking.SetFrozen")
+	span.SetAttributes(attribute.Int("account.id", accountID), attribute.Bool("account.frozen", frozen))
+	defer span.End()
+	ctx = logger.WithFields(ctx, slog.Int("account_id", accountID))
+
+	entry := audit.EntryFromContext(ctx)
+	entry.SetTarget(accountID)
+
+	var account models.Account
+	err := u.uow.Do(ctx, func(ctx context.Context) error {
+		var err error
+		if account, err = u.repo.GetAccountTx(ctx, accountID); err != nil {
+			return err
+		}
+		if account.AccountID == 0 {
+			return banking.ErrAccountNotFound
+		}
+		entry.SetBefore(accountResponse(account))
+		if (account.FrozenAt != nil) == frozen {
+			return nil
+		}
+		account.FrozenAt = nil
+		if frozen {
+			now := time.Now()
+			account.FrozenAt = &now
+		}
+		return u.repo.UpdateAccount(ctx, account)
+	})
+	if err != nil {
+		return dto.AccountResponse{}, err
+	}
+
+	resp := accountResponse(account)
+	entry.SetAfter(resp)
+	log.InfoContext(ctx, "account freeze updated", slog.Bool("frozen", frozen))
+	return resp, nil
+}
+
+func accountResponse(account models.Account) dto.AccountResponse {
+	resp := dto.AccountResponse{
+		AccountID:  account.AccountID,
+		Balance:    account.Balance,
+		Frozen:     account.FrozenAt != nil,
+		CustomerID: account.CustomerID,
+		Currency:   account.Currency,
+	}
+	if account.Metadata != "" {
+		resp.Metadata = json.RawMessage(account.Metadata)
+	}
+	return resp
+}
+
+// Limits on the details a transfer is made with. References and descriptions are as long as
+// an ISO 20022 end-to-end ID and unstructured remittance line, so transfers can be exchanged
+// in those formats.
+const (
+	maxReferenceLength     = 35
+	maxDescriptionLength   = 140
+	maxMetadataBytes       = 4096
+	maxMetadataKeys        = 32
+	maxMetadataKeyLength   = 64
+	maxMetadataValueLength = 512
+)
+
 // Transaction transfers funds between accounts
-func (u *bankingUsecase) Transaction(c echo.Context, fromAccountID int, toAccountID int, amount string) error {
-	ac := c.(*ctx.CustomApplicationContext)
-	tx := ac.PostgresDB.Begin()
+func (u *bankingUsecase) Transaction(ctx context.Context, req dto.TransactionRequest) error {
+	fromAccountID, toAccountID, amount := req.SourceAccountID, req.DestinationAccountID, req.Amount
+	ctx, span := tracer.Start(ctx, "banking.Transaction")
+	span.SetAttributes(
+		attribute.Int("transfer.source_account_id", fromAccountID),
+		attribute.Int("transfer.destination_account_id", toAccountID),
+	)
+	ctx = logger.WithFields(ctx,
+		slog.Int("source_account_id", fromAccountID),
+		slog.Int("destination_account_id", toAccountID),
+	)
+	start := time.Now()
+	outcome := metrics.OutcomeError
 	defer func() {
-		if r := recover(); r != nil {
-			tx.Rollback()
+		observeTransfer(outcome, amount, time.Since(start))
+		level := slog.LevelInfo
+		if outcome != metrics.OutcomeSuccess {
+			level = slog.LevelWarn
+		}
+		log.LogAttrs(ctx, level, "transfer finished",
+			slog.String("outcome", outcome),
+			slog.String("amount", amount),
+			slog.Duration("duration", time.Since(start)))
+		span.SetAttributes(attribute.String("transfer.outcome", outcome))
+		if outcome != metrics.OutcomeSuccess {
+			span.SetStatus(codes.Error, outcome)
 		}
+		span.End()
 	}()
-	if err := tx.Error; err != nil {
-		return errors.New("failed to start transaction")
-	}
 
-	fromAccount, err := u.repo.GetAccountTx(tx, fromAccountID)
+	if fromAccountID == toAccountID {
+		outcome = metrics.OutcomeInvalid
+		return banking.ErrSameAccount
+	}
+	// A negative amount would move funds from the destination to the source.
+	transferAmount, err := decimal.NewFromString(amount)
+	if err != nil || !transferAmount.IsPositive() {
+		outcome = metrics.OutcomeInvalid
+		return banking.ErrInvalidAmount
+	}
+	metadata, err := transferMetadata(req.Metadata)
+	if err == nil {
+		err = checkTransferDetails(req)
+	}
 	if err != nil {
-		tx.Rollback()
+		outcome = metrics.OutcomeInvalid
 		return err
 	}
 
-	toAccount, err := u.repo.GetAccountTx(tx, toAccountID)
+	entry := audit.EntryFromContext(ctx)
+	entry.SetTarget(fromAccountID)
+
+	var fromAccount, toAccount models.Account
+	err = u.uow.Do(ctx, func(ctx context.Context) error {
+		// Lock in ascending account ID order, so opposite transfers between the same pair of
+		// accounts cannot deadlock.
+		first, second := &fromAccount, &toAccount
+		firstID, secondID := fromAccountID, toAccountID
+		if firstID > secondID {
+			first, second = second, first
+			firstID, secondID = secondID, firstID
+		}
+		var err error
+		lockStart := time.Now()
+		*first, err = u.repo.GetAccountTx(ctx, firstID)
+		metrics.LockWaitDuration.Observe(time.Since(lockStart).Seconds())
+		if err != nil {
+			return err
+		}
+		lockStart = time.Now()
+		*second, err = u.repo.GetAccountTx(ctx, secondID)
+		metrics.LockWaitDuration.Observe(time.Since(lockStart).Seconds())
+		if err != nil {
+			return err
+		}
+
+		// Missing accounts read as the zero value.
+		for _, account := range []struct {
+			id    int
+			found bool
+		}{{fromAccountID, fromAccount.AccountID != 0}, {toAccountID, toAccount.AccountID != 0}} {
+			if !account.found {
+				outcome = metrics.OutcomeRejected
+				return fmt.Errorf("account %d: %w", account.id, banking.ErrAccountNotFound)
+			}
+		}
+
+		// Both rows are locked, so these balances are exactly what the transfer starts from.
+		entry.SetBefore([]dto.AccountResponse{
+			{AccountID: fromAccount.AccountID, Balance: fromAccount.Balance},
+			{AccountID: toAccount.AccountID, Balance: toAccount.Balance},
+		})
+
+		if fromAccount.FrozenAt != nil || toAccount.FrozenAt != nil {
+			outcome = metrics.OutcomeRejected
+			return banking.ErrAccountFrozen
+		}
+		// Transfers move the amount as is, so both accounts must be in the same currency. Accounts
+		// created without one take part in any transfer.
+		if fromAccount.Currency != "" && toAccount.Currency != "" && fromAccount.Currency != toAccount.Currency {
+			outcome = metrics.OutcomeRejected
+			return fmt.Errorf("%w: account %d is held in %s and account %d in %s", banking.ErrCurrencyMismatch,
+				fromAccount.AccountID, fromAccount.Currency, toAccount.AccountID, toAccount.Currency)
+		}
+		if u.conf.RequireKYC {
+			if err := u.checkKYC(ctx, fromAccount, toAccount); err != nil {
+				outcome = metrics.OutcomeRejected
+				return err
+			}
+		}
+
+		fromBalance, err := decimal.NewFromString(fromAccount.Balance)
+		if err != nil {
+			return errors.New("invalid balance in sender's account")
+		}
+		toBalance, err := decimal.NewFromString(toAccount.Balance)
+		if err != nil {
+			return errors.New("invalid balance in receiver's account")
+		}
+
+		// The source account is locked, so no other transfer from it can take the reference
+		// between this check and the insert.
+		if req.UniqueReference {
+			used, err := u.repo.ListTransactions(ctx, banking.TransactionFilter{
+				SourceAccountID: &fromAccountID,
+				Reference:       &req.Reference,
+				Limit:           1,
+			})
+			if err != nil {
+				return err
+			}
+			if len(used) > 0 {
+				outcome = metrics.OutcomeRejected
+				return banking.ErrDuplicateReference
+			}
+		}
+
+		if fromBalance.LessThan(transferAmount) {
+			outcome = metrics.OutcomeInsufficientFunds
+			return banking.ErrInsufficientBalance
+		}
+
+		fromAccount.Balance = fromBalance.Sub(transferAmount).String()
+		if err := u.repo.UpdateAccount(ctx, fromAccount); err != nil {
+			return err
+		}
+
+		toAccount.Balance = toBalance.Add(transferAmount).String()
+		if err := u.repo.UpdateAccount(ctx, toAccount); err != nil {
+			return err
+		}
+
+		return u.repo.Transaction(ctx, models.Transaction{
+			SourceAccountID:      fromAccountID,
+			DestinationAccountID: toAccountID,
+			Amount:               amount,
+			Reference:            req.Reference,
+			Description:          req.Description,
+			Metadata:             metadata,
+			Internal:             sameCustomer(fromAccount, toAccount),
+		})
+	})
 	if err != nil {
-		tx.Rollback()
 		return err
 	}
 
-	fromBalance, err := decimal.NewFromString(fromAccount.Balance)
-	if err != nil {
-		tx.Rollback()
-		return errors.New("invalid balance in sender's account")
-	}
-	toBalance, err := decimal.NewFromString(toAccount.Balance)
+	outcome = metrics.OutcomeSuccess
+	entry.SetAfter([]dto.AccountResponse{
+		{AccountID: fromAccount.AccountID, Balance: fromAccount.Balance},
+		{AccountID: toAccount.AccountID, Balance: toAccount.Balance},
+	})
+	return nil
+}
+
+// ListTransactions returns transactions matching the filter, newest first
+func (u *bankingUsecase) ListTransactions(ctx context.Context, filter banking.TransactionFilter) ([]dto.TransactionResponse, error) {
+	transactions, err := u.repo.ListTransactions(ctx, filter)
 	if err != nil {
-		tx.Rollback()
-		return errors.New("invalid balance in receiver's account")
+		return nil, err
 	}
-	transferAmount, err := decimal.NewFromString(amount)
-	if err != nil {
-		tx.Rollback()
-		return errors.New("invalid transfer amount")
+	resp := make([]dto.TransactionResponse, len(transactions))
+	for i, t := range transactions {
+		resp[i] = dto.TransactionResponse{
+			ID:                   t.ID,
+			SourceAccountID:      t.SourceAccountID,
+			DestinationAccountID: t.DestinationAccountID,
+			Amount:               t.Amount,
+			Reference:            t.Reference,
+			Description:          t.Description,
+			Internal:             t.Internal,
+			CreatedAt:            t.CreatedAt,
+		}
+		if t.Metadata != "" {
+			resp[i].Metadata = json.RawMessage(t.Metadata)
+		}
 	}
+	return resp, nil
+}
 
-	if fromBalance.LessThan(transferAmount) {
-		tx.Rollback()
-		return errors.New("insufficient balance")
+// checkTransferDetails checks the reference and description of a transfer against their limits.
+func checkTransferDetails(req dto.TransactionRequest) error {
+	switch {
+	case utf8.RuneCountInString(req.Reference) > maxReferenceLength:
+		return fmt.Errorf("%w: reference must be at most %d characters", banking.ErrInvalidTransferDetails, maxReferenceLength)
+	case strings.TrimSpace(req.Reference) != req.Reference:
+		return fmt.Errorf("%w: reference must not start or end with spaces", banking.ErrInvalidTransferDetails)
+	case req.UniqueReference && req.Reference == "":
+		return fmt.Errorf("%w: a unique reference needs a reference", banking.ErrInvalidTransferDetails)
+	case utf8.RuneCountInString(req.Description) > maxDescriptionLength:
+		return fmt.Errorf("%w: description must be at most %d characters", banking.ErrInvalidTransferDetails, maxDescriptionLength)
 	}
+	return nil
+}
 
-	fromAccount.Balance = fromBalance.Sub(transferAmount).String()
-	if err := u.repo.UpdateAccount(tx, fromAccount); err != nil {
-		tx.Rollback()
-		return err
+// transferMetadata checks a transfer's metadata object against the limits and returns it
+// compacted, or "" when there is none. Each value is indexed for lookup, so values are
+// limited as well as the whole object.
+func transferMetadata(raw json.RawMessage) (string, error) {
+	if len(raw) == 0 || string(raw) == "null" {
+		return "", nil
 	}
-
-	toAccount.Balance = toBalance.Add(transferAmount).String()
-	if err := u.repo.UpdateAccount(tx, toAccount); err != nil {
-		tx.Rollback()
-		return err
+	invalid := func(format string, args ...any) error {
+		return fmt.Errorf("%w: metadata "+format, append([]any{banking.ErrInvalidTransferDetails}, args...)...)
 	}
-
-	transaction := models.Transaction{
-		SourceAccountID:      fromAccountID,
-		DestinationAccountID: toAccountID,
-		Amount:               amount,
+	if len(raw) > maxMetadataBytes {
+		return "", invalid("must be at most %d bytes", maxMetadataBytes)
 	}
-
-	if err := u.repo.Transaction(tx, transaction); err != nil {
-		tx.Rollback()
-		return err
+	var buf bytes.Buffer
+	if err := json.Compact(&buf, raw); err != nil {
+		return "", invalid("must be a JSON object")
 	}
-
-	if err := tx.Commit().Error; err != nil {
-		return errors.New("failed to commit transaction: " + err.Error())
+	var object map[string]json.RawMessage
+	if err := json.Unmarshal(buf.Bytes(), &object); err != nil || object == nil {
+		return "", invalid("must be a JSON object")
+	}
+	if len(object) > maxMetadataKeys {
+		return "", invalid("must have at most %d keys", maxMetadataKeys)
+	}
+	for key, value := range object {
+		if key == "" || utf8.RuneCountInString(key) > maxMetadataKeyLength {
+			return "", invalid("keys must be 1 to %d characters", maxMetadataKeyLength)
+		}
+		if len(value) > maxMetadataValueLength {
+			return "", invalid("value of %q must be at most %d bytes", key, maxMetadataValueLength)
+		}
 	}
+	return buf.String(), nil
+}
 
-	return nil
+// observeTransfer records the outcome, amount and duration of a transfer attempt.
+func observeTransfer(outcome string, amount string, elapsed time.Duration) {
+	metrics.TransfersTotal.WithLabelValues(outcome).Inc()
+	metrics.TransactionDuration.WithLabelValues(outcome).Observe(elapsed.Seconds())
+	if outcome == metrics.OutcomeInsufficientFunds {
+		metrics.InsufficientFundsTotal.Inc()
+	}
+	// Counters cannot decrease, so rejected negative amounts are not added.
+	if value, err := decimal.NewFromString(amount); err == nil && !value.IsNegative() {
+		f, _ := value.Float64()
+		metrics.TransferAmountTotal.WithLabelValues(outcome).Add(f)
+	}
 }
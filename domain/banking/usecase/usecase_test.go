@@ -1,34 +1,24 @@
 package usecase
 
 import (
+	"context"
+	"encoding/json"
 	"errors"
+	"strings"
 	"testing"
+	"time"
 
-	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/golang/mock/gomock"
+	"github.com/rohanchauhan02/internal-transfer/domain/banking"
+	"github.com/rohanchauhan02/internal-transfer/domain/banking/repository"
 	"github.com/rohanchauhan02/internal-transfer/dto"
 	mock_banking "github.com/rohanchauhan02/internal-transfer/file/mocks/mock_banking"
 	"github.com/rohanchauhan02/internal-transfer/models"
-	"github.com/rohanchauhan02/internal-transfer/pkg/ctx"
+	"github.com/rohanchauhan02/internal-transfer/pkg/config"
 	"github.com/stretchr/testify/assert"
-	"gorm.io/driver/postgres"
-	"gorm.io/gorm"
 )
 
 func TestBankingUsecase_CreateAccount(t *testing.T) {
-	db, sqlmock, err := sqlmock.New()
-	if err != nil {
-		t.Fatalf("failed to open sqlmock database: %v", err)
-	}
-	defer db.Close()
-
-	gormDB, err := gorm.Open(postgres.New(postgres.Config{
-		Conn: db,
-	}), &gorm.Config{})
-	if err != nil {
-		t.Fatalf("failed to open gorm database: %v", err)
-	}
-
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
@@ -57,24 +47,30 @@ func TestBankingUsecase_CreateAccount(t *testing.T) {
 			},
 			expectedError: errors.New("failed to create account"),
 		},
+		{
+			name:      "Create Account Duplicate",
+			accountID: 3,
+			balance:   "10.00",
+			mockSetup: func(repo *mock_banking.MockRepository) {
+				repo.EXPECT().CreateAccount(gomock.Any(), gomock.Any()).Return(banking.ErrAccountExists)
+			},
+			expectedError: banking.ErrAccountExists,
+		},
 	}
 
 	for _, tt := range tests {
-		sqlmock.ExpectBegin()
-		sqlmock.ExpectCommit()
-
 		t.Run(tt.name, func(t *testing.T) {
 			mockRepo := mock_banking.NewMockRepository(ctrl)
 			tt.mockSetup(mockRepo)
+			mockUoW := mock_banking.NewMockUnitOfWork(ctrl)
+			expectUnitOfWork(mockUoW, nil)
 
-			usecase := NewBankingUsecase(mockRepo)
+			usecase := NewBankingUsecase(mockUoW, mockRepo, config.Banking{})
 
-			c := &ctx.CustomApplicationContext{
-				PostgresDB: gormDB,
-			}
-
-			err := usecase.CreateAccount(c, tt.accountID, tt.balance)
-			if tt.expectedError != nil {
+			err := usecase.CreateAccount(context.Background(), tt.accountID, tt.balance)
+			if tt.expectedError == banking.ErrAccountExists {
+				assert.ErrorIs(t, err, banking.ErrAccountExists)
+			} else if tt.expectedError != nil {
 				assert.ErrorContains(t, err, tt.expectedError.Error())
 			} else {
 				assert.NoError(t, err)
@@ -88,7 +84,7 @@ func TestBankingUsecase_GetAccount(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockRepo := mock_banking.NewMockRepository(ctrl)
-	usecase := NewBankingUsecase(mockRepo)
+	usecase := NewBankingUsecase(nil, mockRepo, config.Banking{})
 
 	tests := []struct {
 		name          string
@@ -102,7 +98,7 @@ func TestBankingUsecase_GetAccount(t *testing.T) {
 			accountID: 1,
 			mockSetup: func() {
 				mockRepo.EXPECT().
-					GetAccount(1).
+					GetAccount(gomock.Any(), 1).
 					Return(models.Account{AccountID: 1, Balance: "1000.00"}, nil)
 			},
 			expectedResp: dto.AccountResponse{
@@ -116,18 +112,29 @@ func TestBankingUsecase_GetAccount(t *testing.T) {
 			accountID: 2,
 			mockSetup: func() {
 				mockRepo.EXPECT().
-					GetAccount(2).
+					GetAccount(gomock.Any(), 2).
 					Return(models.Account{}, errors.New("account not found"))
 			},
 			expectedResp:  dto.AccountResponse{},
 			expectedError: errors.New("account not found"),
 		},
+		{
+			name:      "Get Account Missing",
+			accountID: 3,
+			mockSetup: func() {
+				mockRepo.EXPECT().
+					GetAccount(gomock.Any(), 3).
+					Return(models.Account{}, nil)
+			},
+			expectedResp:  dto.AccountResponse{},
+			expectedError: banking.ErrAccountNotFound,
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			tt.mockSetup()
-			resp, err := usecase.GetAccount(tt.accountID)
+			resp, err := usecase.GetAccount(context.Background(), tt.accountID)
 			if tt.expectedError != nil {
 				assert.ErrorContains(t, err, tt.expectedError.Error())
 				assert.Equal(t, tt.expectedResp, resp)
@@ -139,19 +146,6 @@ func TestBankingUsecase_GetAccount(t *testing.T) {
 	}
 }
 func TestBankingUsecase_Transaction(t *testing.T) {
-	db, sqlmock, err := sqlmock.New()
-	if err != nil {
-		t.Fatalf("failed to open sqlmock database: %v", err)
-	}
-	defer db.Close()
-
-	gormDB, err := gorm.Open(postgres.New(postgres.Config{
-		Conn: db,
-	}), &gorm.Config{})
-	if err != nil {
-		t.Fatalf("failed to open gorm database: %v", err)
-	}
-
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
@@ -164,7 +158,7 @@ func TestBankingUsecase_Transaction(t *testing.T) {
 		name          string
 		args          args
 		mockSetup     func(repo *mock_banking.MockRepository)
-		sqlSetup      func()
+		commitErr     error
 		expectedError string
 	}{
 		{
@@ -176,10 +170,6 @@ func TestBankingUsecase_Transaction(t *testing.T) {
 				repo.EXPECT().UpdateAccount(gomock.Any(), gomock.AssignableToTypeOf(models.Account{})).Return(nil).Times(2)
 				repo.EXPECT().Transaction(gomock.Any(), gomock.Any()).Return(nil)
 			},
-			sqlSetup: func() {
-				sqlmock.ExpectBegin()
-				sqlmock.ExpectCommit()
-			},
 			expectedError: "",
 		},
 		{
@@ -189,10 +179,6 @@ func TestBankingUsecase_Transaction(t *testing.T) {
 				repo.EXPECT().GetAccountTx(gomock.Any(), 1).Return(models.Account{AccountID: 1, Balance: "500.00"}, nil)
 				repo.EXPECT().GetAccountTx(gomock.Any(), 2).Return(models.Account{AccountID: 2, Balance: "200.00"}, nil)
 			},
-			sqlSetup: func() {
-				sqlmock.ExpectBegin()
-				sqlmock.ExpectRollback()
-			},
 			expectedError: "insufficient balance",
 		},
 		{
@@ -202,10 +188,6 @@ func TestBankingUsecase_Transaction(t *testing.T) {
 				repo.EXPECT().GetAccountTx(gomock.Any(), 1).Return(models.Account{AccountID: 1, Balance: "invalid"}, nil)
 				repo.EXPECT().GetAccountTx(gomock.Any(), 2).Return(models.Account{AccountID: 2, Balance: "200.00"}, nil)
 			},
-			sqlSetup: func() {
-				sqlmock.ExpectBegin()
-				sqlmock.ExpectRollback()
-			},
 			expectedError: "invalid balance in sender's account",
 		},
 		{
@@ -215,35 +197,14 @@ func TestBankingUsecase_Transaction(t *testing.T) {
 				repo.EXPECT().GetAccountTx(gomock.Any(), 1).Return(models.Account{AccountID: 1, Balance: "500.00"}, nil)
 				repo.EXPECT().GetAccountTx(gomock.Any(), 2).Return(models.Account{AccountID: 2, Balance: "invalid"}, nil)
 			},
-			sqlSetup: func() {
-				sqlmock.ExpectBegin()
-				sqlmock.ExpectRollback()
-			},
 			expectedError: "invalid balance in receiver's account",
 		},
-		{
-			name: "Invalid Transfer Amount",
-			args: args{fromAccountID: 1, toAccountID: 2, amount: "invalid"},
-			mockSetup: func(repo *mock_banking.MockRepository) {
-				repo.EXPECT().GetAccountTx(gomock.Any(), 1).Return(models.Account{AccountID: 1, Balance: "500.00"}, nil)
-				repo.EXPECT().GetAccountTx(gomock.Any(), 2).Return(models.Account{AccountID: 2, Balance: "200.00"}, nil)
-			},
-			sqlSetup: func() {
-				sqlmock.ExpectBegin()
-				sqlmock.ExpectRollback()
-			},
-			expectedError: "invalid transfer amount",
-		},
 		{
 			name: "GetAccountTx Sender Error",
 			args: args{fromAccountID: 1, toAccountID: 2, amount: "100.00"},
 			mockSetup: func(repo *mock_banking.MockRepository) {
 				repo.EXPECT().GetAccountTx(gomock.Any(), 1).Return(models.Account{}, errors.New("sender not found"))
 			},
-			sqlSetup: func() {
-				sqlmock.ExpectBegin()
-				sqlmock.ExpectRollback()
-			},
 			expectedError: "sender not found",
 		},
 		{
@@ -253,10 +214,6 @@ func TestBankingUsecase_Transaction(t *testing.T) {
 				repo.EXPECT().GetAccountTx(gomock.Any(), 1).Return(models.Account{AccountID: 1, Balance: "500.00"}, nil)
 				repo.EXPECT().GetAccountTx(gomock.Any(), 2).Return(models.Account{}, errors.New("receiver not found"))
 			},
-			sqlSetup: func() {
-				sqlmock.ExpectBegin()
-				sqlmock.ExpectRollback()
-			},
 			expectedError: "receiver not found",
 		},
 		{
@@ -267,10 +224,6 @@ func TestBankingUsecase_Transaction(t *testing.T) {
 				repo.EXPECT().GetAccountTx(gomock.Any(), 2).Return(models.Account{AccountID: 2, Balance: "200.00"}, nil)
 				repo.EXPECT().UpdateAccount(gomock.Any(), gomock.AssignableToTypeOf(models.Account{})).Return(errors.New("update sender error"))
 			},
-			sqlSetup: func() {
-				sqlmock.ExpectBegin()
-				sqlmock.ExpectRollback()
-			},
 			expectedError: "update sender error",
 		},
 		{
@@ -282,10 +235,6 @@ func TestBankingUsecase_Transaction(t *testing.T) {
 				repo.EXPECT().UpdateAccount(gomock.Any(), gomock.AssignableToTypeOf(models.Account{})).Return(nil)
 				repo.EXPECT().UpdateAccount(gomock.Any(), gomock.AssignableToTypeOf(models.Account{})).Return(errors.New("update receiver error"))
 			},
-			sqlSetup: func() {
-				sqlmock.ExpectBegin()
-				sqlmock.ExpectRollback()
-			},
 			expectedError: "update receiver error",
 		},
 		{
@@ -297,10 +246,6 @@ func TestBankingUsecase_Transaction(t *testing.T) {
 				repo.EXPECT().UpdateAccount(gomock.Any(), gomock.AssignableToTypeOf(models.Account{})).Return(nil).Times(2)
 				repo.EXPECT().Transaction(gomock.Any(), gomock.Any()).Return(errors.New("insert transaction error"))
 			},
-			sqlSetup: func() {
-				sqlmock.ExpectBegin()
-				sqlmock.ExpectRollback()
-			},
 			expectedError: "insert transaction error",
 		},
 		{
@@ -312,11 +257,8 @@ func TestBankingUsecase_Transaction(t *testing.T) {
 				repo.EXPECT().UpdateAccount(gomock.Any(), gomock.AssignableToTypeOf(models.Account{})).Return(nil).Times(2)
 				repo.EXPECT().Transaction(gomock.Any(), gomock.Any()).Return(nil)
 			},
-			sqlSetup: func() {
-				sqlmock.ExpectBegin()
-				sqlmock.ExpectCommit().WillReturnError(errors.New("commit error"))
-			},
-			expectedError: "failed to commit transaction: commit error",
+			commitErr:     errors.New("commit error"),
+			expectedError: "commit error",
 		},
 	}
 
@@ -324,14 +266,12 @@ func TestBankingUsecase_Transaction(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			mockRepo := mock_banking.NewMockRepository(ctrl)
 			tt.mockSetup(mockRepo)
-			tt.sqlSetup()
+			mockUoW := mock_banking.NewMockUnitOfWork(ctrl)
+			expectUnitOfWork(mockUoW, tt.commitErr)
 
-			usecase := NewBankingUsecase(mockRepo)
-			c := &ctx.CustomApplicationContext{
-				PostgresDB: gormDB,
-			}
+			usecase := NewBankingUsecase(mockUoW, mockRepo, config.Banking{})
 
-			err := usecase.Transaction(c, tt.args.fromAccountID, tt.args.toAccountID, tt.args.amount)
+			err := usecase.Transaction(context.Background(), dto.TransactionRequest{SourceAccountID: tt.args.fromAccountID, DestinationAccountID: tt.args.toAccountID, Amount: tt.args.amount})
 			if tt.expectedError != "" {
 				assert.Error(t, err)
 				assert.Contains(t, err.Error(), tt.expectedError)
@@ -341,3 +281,430 @@ func TestBankingUsecase_Transaction(t *testing.T) {
 		})
 	}
 }
+
+func TestBankingUsecase_TransactionHonoursCancelledContext(t *testing.T) {
+	store := repository.NewMemoryStore()
+	usecase := NewBankingUsecase(store, store, config.Banking{})
+	assert.NoError(t, usecase.CreateAccount(context.Background(), 1, "100.00"))
+	assert.NoError(t, usecase.CreateAccount(context.Background(), 2, "0.00"))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err := usecase.Transaction(ctx, dto.TransactionRequest{SourceAccountID: 1, DestinationAccountID: 2, Amount: "10.00"})
+	assert.ErrorIs(t, err, context.Canceled)
+
+	account, err := usecase.GetAccount(context.Background(), 1)
+	assert.NoError(t, err)
+	assert.Equal(t, "100.00", account.Balance)
+}
+
+func TestBankingUsecase_TransactionRejectsInvalidAmounts(t *testing.T) {
+	store := repository.NewMemoryStore()
+	usecase := NewBankingUsecase(store, store, config.Banking{})
+	assert.NoError(t, usecase.CreateAccount(context.Background(), 1, "100.00"))
+	assert.NoError(t, usecase.CreateAccount(context.Background(), 2, "100.00"))
+
+	for _, amount := range []string{"invalid", "", "0", "0.00", "-50", "-0.01"} {
+		err := usecase.Transaction(context.Background(), dto.TransactionRequest{SourceAccountID: 1, DestinationAccountID: 2, Amount: amount})
+		assert.ErrorIs(t, err, banking.ErrInvalidAmount, "amount %q", amount)
+	}
+	for _, id := range []int{1, 2} {
+		account, err := usecase.GetAccount(context.Background(), id)
+		assert.NoError(t, err)
+		assert.Equal(t, "100.00", account.Balance, "rejected amounts move nothing")
+	}
+}
+
+func TestBankingUsecase_TransactionRejectsCurrencyMismatch(t *testing.T) {
+	ctx := context.Background()
+	store := repository.NewMemoryStore()
+	usecase := NewBankingUsecase(store, store, config.Banking{})
+	for _, account := range []models.Account{
+		{AccountID: 1, Balance: "100", Currency: "USD"},
+		{AccountID: 2, Balance: "0", Currency: "EUR"},
+		{AccountID: 3, Balance: "0", Currency: "USD"},
+		{AccountID: 4, Balance: "0"},
+	} {
+		assert.NoError(t, store.CreateAccount(ctx, account))
+	}
+	transfer := func(to int) error {
+		return usecase.Transaction(ctx, dto.TransactionRequest{SourceAccountID: 1, DestinationAccountID: to, Amount: "10"})
+	}
+
+	err := transfer(2)
+	assert.ErrorIs(t, err, banking.ErrCurrencyMismatch)
+	assert.ErrorContains(t, err, "account 1 is held in USD and account 2 in EUR")
+	assert.NoError(t, transfer(3))
+	assert.NoError(t, transfer(4), "accounts without a currency take part in any transfer")
+
+	account, err := usecase.GetAccount(ctx, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, "0", account.Balance)
+}
+
+func TestBankingUsecase_TransactionRejectsSameAccount(t *testing.T) {
+	store := repository.NewMemoryStore()
+	usecase := NewBankingUsecase(store, store, config.Banking{})
+	assert.NoError(t, usecase.CreateAccount(context.Background(), 1, "100.00"))
+
+	err := usecase.Transaction(context.Background(), dto.TransactionRequest{SourceAccountID: 1, DestinationAccountID: 1, Amount: "10.00"})
+	assert.ErrorContains(t, err, "must differ")
+}
+
+func TestBankingUsecase_TransactionDetails(t *testing.T) {
+	ctx := context.Background()
+	store := repository.NewMemoryStore()
+	usecase := NewBankingUsecase(store, store, config.Banking{})
+	assert.NoError(t, usecase.CreateAccount(ctx, 1, "100.00"))
+	assert.NoError(t, usecase.CreateAccount(ctx, 2, "0.00"))
+
+	assert.NoError(t, usecase.Transaction(ctx, dto.TransactionRequest{
+		SourceAccountID: 1, DestinationAccountID: 2, Amount: "10.00",
+		Reference: "INV-1", Description: "March invoice", Metadata: json.RawMessage(`{ "order": "A-1", "lines": [1, 2] }`),
+	}))
+	assert.NoError(t, usecase.Transaction(ctx, dto.TransactionRequest{SourceAccountID: 1, DestinationAccountID: 2, Amount: "1.00"}))
+
+	reference := "INV-1"
+	transactions, err := usecase.ListTransactions(ctx, banking.TransactionFilter{Reference: &reference})
+	assert.NoError(t, err)
+	if assert.Len(t, transactions, 1) {
+		assert.Equal(t, "March invoice", transactions[0].Description)
+		assert.Equal(t, json.RawMessage(`{"order":"A-1","lines":[1,2]}`), transactions[0].Metadata, "metadata is stored compacted")
+	}
+	value := "A-1"
+	transactions, err = usecase.ListTransactions(ctx, banking.TransactionFilter{MetadataKey: "order", MetadataValue: &value})
+	assert.NoError(t, err)
+	assert.Len(t, transactions, 1)
+}
+
+func TestBankingUsecase_TransactionRejectsInvalidDetails(t *testing.T) {
+	tests := []struct {
+		name string
+		req  dto.TransactionRequest
+		want string
+	}{
+		{"long reference", dto.TransactionRequest{Reference: strings.Repeat("r", 36)}, "reference must be at most 35 characters"},
+		{"padded reference", dto.TransactionRequest{Reference: " INV-1"}, "must not start or end with spaces"},
+		{"unique without reference", dto.TransactionRequest{UniqueReference: true}, "a unique reference needs a reference"},
+		{"long description", dto.TransactionRequest{Description: strings.Repeat("d", 141)}, "description must be at most 140 characters"},
+		{"metadata array", dto.TransactionRequest{Metadata: json.RawMessage(`[1]`)}, "metadata must be a JSON object"},
+		{"metadata too large", dto.TransactionRequest{Metadata: json.RawMessage(`{"a":"` + strings.Repeat("x", 4096) + `"}`)},
+			"metadata must be at most 4096 bytes"},
+		{"empty metadata key", dto.TransactionRequest{Metadata: json.RawMessage(`{"":1}`)}, "keys must be 1 to 64 characters"},
+		{"long metadata value", dto.TransactionRequest{Metadata: json.RawMessage(`{"a":"` + strings.Repeat("x", 600) + `"}`)},
+			`value of "a" must be at most 512 bytes`},
+	}
+	store := repository.NewMemoryStore()
+	usecase := NewBankingUsecase(store, store, config.Banking{})
+	assert.NoError(t, usecase.CreateAccount(context.Background(), 1, "100.00"))
+	assert.NoError(t, usecase.CreateAccount(context.Background(), 2, "0.00"))
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.req.SourceAccountID, tt.req.DestinationAccountID, tt.req.Amount = 1, 2, "1.00"
+			err := usecase.Transaction(context.Background(), tt.req)
+			assert.ErrorIs(t, err, banking.ErrInvalidTransferDetails)
+			assert.ErrorContains(t, err, tt.want)
+		})
+	}
+	account, err := usecase.GetAccount(context.Background(), 1)
+	assert.NoError(t, err)
+	assert.Equal(t, "100.00", account.Balance)
+}
+
+func TestBankingUsecase_TransactionUniqueReference(t *testing.T) {
+	ctx := context.Background()
+	store := repository.NewMemoryStore()
+	usecase := NewBankingUsecase(store, store, config.Banking{})
+	for id := 1; id <= 3; id++ {
+		assert.NoError(t, usecase.CreateAccount(ctx, id, "100"))
+	}
+	transfer := func(from, to int, unique bool) error {
+		return usecase.Transaction(ctx, dto.TransactionRequest{
+			SourceAccountID: from, DestinationAccountID: to, Amount: "1", Reference: "INV-1", UniqueReference: unique,
+		})
+	}
+
+	assert.NoError(t, transfer(1, 2, true))
+	assert.ErrorIs(t, transfer(1, 3, true), banking.ErrDuplicateReference)
+	assert.NoError(t, transfer(3, 1, true), "references are unique per source account")
+	assert.NoError(t, transfer(1, 3, false), "the guard is optional")
+
+	account, err := usecase.GetAccount(ctx, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, "99", account.Balance, "a rejected duplicate moves nothing")
+}
+
+func TestBankingUsecase_SetFrozen(t *testing.T) {
+	ctx := context.Background()
+	store := repository.NewMemoryStore()
+	usecase := NewBankingUsecase(store, store, config.Banking{})
+	assert.NoError(t, usecase.CreateAccount(ctx, 1, "100.00"))
+	assert.NoError(t, usecase.CreateAccount(ctx, 2, "0.00"))
+
+	account, err := usecase.SetFrozen(ctx, 2, true)
+	assert.NoError(t, err)
+	assert.True(t, account.Frozen)
+
+	assert.ErrorIs(t, usecase.Transaction(ctx, dto.TransactionRequest{SourceAccountID: 1, DestinationAccountID: 2, Amount: "10.00"}), banking.ErrAccountFrozen, "frozen accounts cannot receive")
+	assert.ErrorIs(t, usecase.Transaction(ctx, dto.TransactionRequest{SourceAccountID: 2, DestinationAccountID: 1, Amount: "1.00"}), banking.ErrAccountFrozen, "frozen accounts cannot send")
+
+	account, err = usecase.SetFrozen(ctx, 2, false)
+	assert.NoError(t, err)
+	assert.False(t, account.Frozen)
+	assert.NoError(t, usecase.Transaction(ctx, dto.TransactionRequest{SourceAccountID: 1, DestinationAccountID: 2, Amount: "10.00"}))
+
+	_, err = usecase.SetFrozen(ctx, 99, true)
+	assert.ErrorIs(t, err, banking.ErrAccountNotFound)
+}
+
+func TestBankingUsecase_CreateCustomer(t *testing.T) {
+	tests := []struct {
+		name string
+		req  dto.CustomerRequest
+		want string
+	}{
+		{"missing name", dto.CustomerRequest{Name: "  ", Type: models.CustomerIndividual}, "name is required"},
+		{"unknown type", dto.CustomerRequest{Name: "Alice", Type: "trust"}, "type must be individual or business"},
+		{"invalid email", dto.CustomerRequest{Name: "Alice", Type: models.CustomerIndividual, Email: "Alice <a@example.com>"},
+			"email is not a valid address"},
+		{"invalid phone", dto.CustomerRequest{Name: "Alice", Type: models.CustomerIndividual, Phone: "call me"}, "phone must be"},
+		{"long address", dto.CustomerRequest{Name: "Alice", Type: models.CustomerIndividual, Address: strings.Repeat("a", 501)},
+			"address must be at most 500 characters"},
+	}
+	store := repository.NewMemoryStore()
+	usecase := NewBankingUsecase(store, store, config.Banking{})
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := usecase.CreateCustomer(context.Background(), tt.req)
+			assert.ErrorIs(t, err, banking.ErrInvalidCustomer)
+			assert.ErrorContains(t, err, tt.want)
+		})
+	}
+
+	customer, err := usecase.CreateCustomer(context.Background(), dto.CustomerRequest{
+		Name: " Acme Ltd ", Type: models.CustomerBusiness, Email: "ops@acme.example", Phone: "+44 20 7946 0000",
+	})
+	assert.NoError(t, err)
+	assert.NotZero(t, customer.ID)
+	assert.Equal(t, "Acme Ltd", customer.Name)
+	assert.Equal(t, models.KYCPending, customer.KYCStatus, "customers start pending")
+}
+
+func TestBankingUsecase_CustomerAccounts(t *testing.T) {
+	ctx := context.Background()
+	store := repository.NewMemoryStore()
+	usecase := NewBankingUsecase(store, store, config.Banking{})
+	customer, err := usecase.CreateCustomer(ctx, dto.CustomerRequest{Name: "Alice", Type: models.CustomerIndividual})
+	assert.NoError(t, err)
+	assert.NoError(t, usecase.CreateCustomerAccount(ctx, customer.ID, 1, "100.25"))
+	assert.NoError(t, usecase.CreateCustomerAccount(ctx, customer.ID, 2, "0.75"))
+	assert.NoError(t, usecase.CreateAccount(ctx, 3, "50"))
+	assert.ErrorIs(t, usecase.CreateCustomerAccount(ctx, customer.ID+1, 4, "1"), banking.ErrCustomerNotFound)
+
+	// Transfers between a customer's own accounts are internal.
+	assert.NoError(t, usecase.Transaction(ctx, dto.TransactionRequest{SourceAccountID: 1, DestinationAccountID: 2, Amount: "10"}))
+	assert.NoError(t, usecase.Transaction(ctx, dto.TransactionRequest{SourceAccountID: 1, DestinationAccountID: 3, Amount: "5"}))
+
+	accounts, err := usecase.CustomerAccounts(ctx, customer.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, customer.ID, accounts.Customer.ID)
+	if assert.Len(t, accounts.Accounts, 2) {
+		assert.Equal(t, "85.25", accounts.Accounts[0].Balance)
+		assert.Equal(t, &customer.ID, accounts.Accounts[0].CustomerID)
+	}
+	assert.Equal(t, "96", accounts.TotalBalance)
+
+	transactions, err := usecase.ListTransactions(ctx, banking.TransactionFilter{})
+	assert.NoError(t, err)
+	if assert.Len(t, transactions, 2) {
+		assert.False(t, transactions[0].Internal, "transfers to another owner are not internal")
+		assert.True(t, transactions[1].Internal)
+	}
+
+	_, err = usecase.CustomerAccounts(ctx, customer.ID+1)
+	assert.ErrorIs(t, err, banking.ErrCustomerNotFound)
+}
+
+func TestBankingUsecase_TransactionRequiresKYC(t *testing.T) {
+	ctx := context.Background()
+	store := repository.NewMemoryStore()
+	usecase := NewBankingUsecase(store, store, config.Banking{RequireKYC: true})
+	alice, err := usecase.CreateCustomer(ctx, dto.CustomerRequest{Name: "Alice", Type: models.CustomerIndividual})
+	assert.NoError(t, err)
+	bob, err := usecase.CreateCustomer(ctx, dto.CustomerRequest{Name: "Bob", Type: models.CustomerIndividual})
+	assert.NoError(t, err)
+	assert.NoError(t, usecase.CreateCustomerAccount(ctx, alice.ID, 1, "100"))
+	assert.NoError(t, usecase.CreateCustomerAccount(ctx, bob.ID, 2, "0"))
+	assert.NoError(t, usecase.CreateAccount(ctx, 3, "0"))
+	transfer := func(to int) error {
+		return usecase.Transaction(ctx, dto.TransactionRequest{SourceAccountID: 1, DestinationAccountID: to, Amount: "1"})
+	}
+
+	assert.ErrorIs(t, transfer(2), banking.ErrKYCNotVerified, "pending customers cannot transfer")
+	_, err = usecase.SetKYCStatus(ctx, alice.ID, models.KYCVerified)
+	assert.NoError(t, err)
+	assert.ErrorIs(t, transfer(2), banking.ErrKYCNotVerified, "the destination customer must be verified too")
+	bobResp, err := usecase.SetKYCStatus(ctx, bob.ID, models.KYCVerified)
+	assert.NoError(t, err)
+	assert.Equal(t, models.KYCVerified, bobResp.KYCStatus)
+	assert.NoError(t, transfer(2))
+	assert.ErrorIs(t, transfer(3), banking.ErrKYCNotVerified, "accounts without a customer cannot transfer")
+
+	_, err = usecase.SetKYCStatus(ctx, bob.ID, models.KYCRejected)
+	assert.NoError(t, err)
+	assert.ErrorIs(t, transfer(2), banking.ErrKYCNotVerified)
+
+	account, err := usecase.GetAccount(ctx, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, "99", account.Balance, "rejected transfers move nothing")
+
+	_, err = usecase.SetKYCStatus(ctx, bob.ID, "approved")
+	assert.ErrorIs(t, err, banking.ErrInvalidCustomer)
+	_, err = usecase.SetKYCStatus(ctx, bob.ID+10, models.KYCVerified)
+	assert.ErrorIs(t, err, banking.ErrCustomerNotFound)
+}
+
+func TestBankingUsecase_ImportAccounts(t *testing.T) {
+	ctx := context.Background()
+	store := repository.NewMemoryStore()
+	usecase := NewBankingUsecase(store, store, config.Banking{})
+	assert.NoError(t, usecase.CreateAccount(ctx, 9, "5"))
+
+	invalid := []dto.AccountImportRow{
+		{Row: 1, AccountID: "1", InitialBalance: "100.50", Currency: "USD", Metadata: `{"unit": "emea"}`},
+		{Row: 2, AccountID: "x", InitialBalance: "1", Currency: "USD"},
+		{Row: 3, AccountID: "1", InitialBalance: "1", Currency: "USD"},
+		{Row: 4, AccountID: "4", InitialBalance: "-1", Currency: "usd", Metadata: "[1]"},
+		{Row: 5, AccountID: "9", InitialBalance: "1", Currency: "USD"},
+	}
+	report, err := usecase.ImportAccounts(ctx, invalid, dto.AccountImportOptions{DryRun: true})
+	assert.NoError(t, err)
+	assert.Equal(t, 5, report.TotalRows)
+	assert.Equal(t, 1, report.ValidRows)
+	assert.Equal(t, 4, report.InvalidRows)
+	assert.Equal(t, []dto.AccountImportError{
+		{Row: 2, AccountID: "x", Field: "account_id", Message: "must be a positive integer"},
+		{Row: 3, AccountID: "1", Field: "account_id", Message: "duplicates row 1"},
+		{Row: 4, AccountID: "4", Field: "initial_balance", Message: "must not be negative"},
+		{Row: 4, AccountID: "4", Field: "currency", Message: "must be a three letter ISO 4217 code, e.g. USD"},
+		{Row: 4, AccountID: "4", Field: "metadata", Message: "must be a JSON object"},
+		{Row: 5, AccountID: "9", Field: "account_id", Message: "account already exists with different values"},
+	}, report.Errors)
+
+	_, err = usecase.ImportAccounts(ctx, invalid, dto.AccountImportOptions{})
+	assert.ErrorIs(t, err, banking.ErrImportInvalid)
+	_, err = usecase.GetAccount(ctx, 1)
+	assert.ErrorIs(t, err, banking.ErrAccountNotFound, "nothing is written while rows are invalid")
+
+	valid := []dto.AccountImportRow{
+		{Row: 1, AccountID: "1", InitialBalance: "100.50", Currency: "USD", Metadata: `{"unit": "emea"}`},
+		{Row: 2, AccountID: "2", InitialBalance: "0", Currency: "EUR"},
+		{Row: 3, AccountID: "3", InitialBalance: "7", Currency: "GBP"},
+	}
+	report, err = usecase.ImportAccounts(ctx, valid[:2], dto.AccountImportOptions{ChunkSize: 1})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, report.CreatedRows)
+	account, err := usecase.GetAccount(ctx, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, dto.AccountResponse{
+		AccountID: 1, Balance: "100.50", Currency: "USD", Metadata: json.RawMessage(`{"unit":"emea"}`),
+	}, account)
+
+	// Sending the whole file again resumes it: imported rows are skipped.
+	report, err = usecase.ImportAccounts(ctx, valid, dto.AccountImportOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, report.CreatedRows)
+	assert.Equal(t, 2, report.ExistingRows)
+
+	report, err = usecase.ImportAccounts(ctx, valid, dto.AccountImportOptions{DryRun: true, StartRow: 3})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, report.TotalRows, "rows before start_row are not checked")
+}
+
+func TestBankingUsecase_ImportAccountsReportsWhereItStopped(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mock_banking.NewMockRepository(ctrl)
+	// The rows' accounts are looked up in one batch.
+	repo.EXPECT().GetAccounts(gomock.Any(), []int{1, 2, 3}).Return(nil, nil)
+	repo.EXPECT().CreateAccount(gomock.Any(), gomock.Any()).Return(nil).Times(2)
+	repo.EXPECT().CreateAccount(gomock.Any(), gomock.Any()).Return(errors.New("connection reset"))
+	uow := mock_banking.NewMockUnitOfWork(ctrl)
+	expectUnitOfWork(uow, nil)
+	expectUnitOfWork(uow, nil)
+
+	rows := []dto.AccountImportRow{
+		{Row: 1, AccountID: "1", InitialBalance: "1", Currency: "USD"},
+		{Row: 2, AccountID: "2", InitialBalance: "1", Currency: "USD"},
+		{Row: 3, AccountID: "3", InitialBalance: "1", Currency: "USD"},
+	}
+	report, err := NewBankingUsecase(uow, repo, config.Banking{}).ImportAccounts(context.Background(), rows, dto.AccountImportOptions{ChunkSize: 2})
+	assert.ErrorContains(t, err, "connection reset")
+	assert.Equal(t, 2, report.CreatedRows)
+	assert.Equal(t, 3, report.NextRow)
+}
+
+func TestBankingUsecase_Statement(t *testing.T) {
+	ctx := context.Background()
+	store := repository.NewMemoryStore()
+	usecase := NewBankingUsecase(store, store, config.Banking{})
+	// Account 1 started at 100 and account 2 at 50; the balances are those after the transfers.
+	assert.NoError(t, store.CreateAccount(ctx, models.Account{AccountID: 1, Balance: "75", Currency: "USD"}))
+	assert.NoError(t, store.CreateAccount(ctx, models.Account{AccountID: 2, Balance: "75"}))
+	start := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	for i, tr := range []models.Transaction{
+		{SourceAccountID: 1, DestinationAccountID: 2, Amount: "30"},
+		{SourceAccountID: 2, DestinationAccountID: 1, Amount: "10"},
+		{SourceAccountID: 1, DestinationAccountID: 2, Amount: "5"},
+	} {
+		tr.CreatedAt = start.Add(time.Duration(i+1) * time.Hour)
+		assert.NoError(t, store.Transaction(ctx, tr))
+	}
+
+	s, err := usecase.Statement(ctx, 1, start, start.Add(4*time.Hour))
+	assert.NoError(t, err)
+	assert.Equal(t, "USD", s.Currency)
+	assert.Equal(t, "100", s.OpeningBalance)
+	assert.Equal(t, "75", s.ClosingBalance)
+	assert.Equal(t, "10", s.TotalCredits)
+	assert.Equal(t, "35", s.TotalDebits)
+	var balances, directions []string
+	for _, e := range s.Entries {
+		balances = append(balances, e.Balance)
+		directions = append(directions, e.Direction)
+	}
+	assert.Equal(t, []string{"70", "80", "75"}, balances, "oldest first")
+	assert.Equal(t, []string{dto.StatementDebit, dto.StatementCredit, dto.StatementDebit}, directions)
+	assert.Equal(t, 2, s.Entries[0].CounterpartyAccountID)
+
+	// Later transfers are undone to find the balances at the end of the period.
+	s, err = usecase.Statement(ctx, 1, start.Add(90*time.Minute), start.Add(3*time.Hour))
+	assert.NoError(t, err)
+	assert.Equal(t, "70", s.OpeningBalance)
+	assert.Equal(t, "80", s.ClosingBalance)
+	assert.Len(t, s.Entries, 1)
+
+	s, err = usecase.Statement(ctx, 2, start.Add(5*time.Hour), start.Add(6*time.Hour))
+	assert.NoError(t, err)
+	assert.Equal(t, "75", s.OpeningBalance)
+	assert.Equal(t, "75", s.ClosingBalance)
+	assert.Empty(t, s.Entries)
+
+	_, err = usecase.Statement(ctx, 1, start, start)
+	assert.ErrorIs(t, err, banking.ErrInvalidPeriod)
+	_, err = usecase.Statement(ctx, 99, start, start.Add(time.Hour))
+	assert.ErrorIs(t, err, banking.ErrAccountNotFound)
+}
+
+// expectUnitOfWork makes uow run fn inline, then fail with commitErr like a transaction whose
+// commit is rejected.
+func expectUnitOfWork(uow *mock_banking.MockUnitOfWork, commitErr error) {
+	uow.EXPECT().Do(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, fn func(context.Context) error) error {
+		if err := fn(ctx); err != nil {
+			return err
+		}
+		return commitErr
+	})
+}
@@ -0,0 +1,25 @@
+package https
+
+import (
+	"net/http"
+
+	"github.com/rohanchauhan02/internal-transfer/dto"
+	"github.com/rohanchauhan02/internal-transfer/pkg/openapi"
+)
+
+// Operations describes the routes registered by NewHealthHandler for the OpenAPI document.
+func Operations() []openapi.Operation {
+	return []openapi.Operation{
+		{
+			Method:  http.MethodGet,
+			Path:    "/api/v1/healthz",
+			ID:      "checkHealth",
+			Summary: "Check service health",
+			Tags:    []string{"health"},
+			Responses: []openapi.Response{
+				{Status: http.StatusOK, Description: "Service is healthy", Body: dto.HealthResponse{}},
+				{Status: http.StatusInternalServerError, Description: "Service is unhealthy", Body: dto.HealthResponse{}},
+			},
+		},
+	}
+}
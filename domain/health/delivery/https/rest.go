@@ -1,13 +1,17 @@
 package https
 
 import (
-	"log"
+	"log/slog"
 	"net/http"
 
 	"github.com/labstack/echo/v4"
 	"github.com/rohanchauhan02/internal-transfer/domain/health"
+	"github.com/rohanchauhan02/internal-transfer/dto"
+	"github.com/rohanchauhan02/internal-transfer/pkg/logger"
 )
 
+var log = logger.For("health")
+
 type healthHandler struct {
 	usecase health.Usecase
 }
@@ -24,22 +28,20 @@ func NewHealthHandler(e *echo.Echo, usecase health.Usecase) {
 func (h *healthHandler) CheckHealth(c echo.Context) error {
 
 	// Call the health check use case
-	status, err := h.usecase.CheckHealth()
+	status, err := h.usecase.CheckHealth(c.Request().Context())
 	if err != nil {
-		log.Printf("Health check failed: %v", err)
-		return c.JSON(http.StatusInternalServerError, map[string]interface{}{
-			"status":  "error",
-			"data":    nil,
-			"message": "Service is unhealthy",
-			"error":   err.Error(),
+		log.WarnContext(c.Request().Context(), "health check failed", slog.String("error", err.Error()))
+		return c.JSON(http.StatusInternalServerError, dto.HealthResponse{
+			Status:  "error",
+			Message: "Service is unhealthy",
+			Error:   err.Error(),
 		})
 	}
 
-	log.Println("Health check passed")
-	return c.JSON(http.StatusOK, map[string]interface{}{
-		"status":  "success",
-		"data":    status,
-		"message": "Service is healthy",
-		"error":   "",
+	log.DebugContext(c.Request().Context(), "health check passed")
+	return c.JSON(http.StatusOK, dto.HealthResponse{
+		Status:  "success",
+		Data:    status,
+		Message: "Service is healthy",
 	})
 }
@@ -1,8 +1,10 @@
 package health
 
+import "context"
+
 type Usecase interface {
-	CheckHealth() (map[string]string, error)
+	CheckHealth(context.Context) (map[string]string, error)
 }
 type Repository interface {
-	PingDatabase() (string, error)
+	PingDatabase(context.Context) (string, error)
 }
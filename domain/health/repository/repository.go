@@ -19,14 +19,14 @@ func NewHealthRepository(db *gorm.DB) health.Repository {
 	}
 }
 
-// CheckHealth checks the health of the service
-func (r *healthRepository) PingDatabase() (string, error) {
+// PingDatabase checks that the database is reachable within the caller's deadline
+func (r *healthRepository) PingDatabase(ctx context.Context) (string, error) {
 	sqlDB, err := r.db.DB()
 	if err != nil {
 		return "unhealthy", errors.New("failed to get database instance")
 	}
 
-	if err := sqlDB.PingContext(context.Background()); err != nil {
+	if err := sqlDB.PingContext(ctx); err != nil {
 		return "unhealthy", errors.New("database is unreachable")
 	}
 
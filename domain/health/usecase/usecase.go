@@ -1,6 +1,7 @@
 package usecase
 
 import (
+	"context"
 	"errors"
 
 	"github.com/rohanchauhan02/internal-transfer/domain/health"
@@ -18,8 +19,8 @@ func NewHealthUsecase(repo health.Repository) health.Usecase {
 }
 
 // CheckHealth checks the health of the service
-func (u *healthUsecase) CheckHealth() (map[string]string, error) {
-	dbStatus, err := u.repo.PingDatabase()
+func (u *healthUsecase) CheckHealth(ctx context.Context) (map[string]string, error) {
+	dbStatus, err := u.repo.PingDatabase(ctx)
 	if err != nil {
 		return map[string]string{
 			"database": "unhealthy",
@@ -0,0 +1,37 @@
+package dto
+
+import "time"
+
+type APIKeyCreateRequest struct {
+	Name       string     `json:"name" validate:"required"`
+	Scopes     []string   `json:"scopes" validate:"required"`
+	AllowedIPs []string   `json:"allowed_ips"`
+	ExpiresAt  *time.Time `json:"expires_at"`
+}
+
+type APIKeyScopesRequest struct {
+	Scopes []string `json:"scopes" validate:"required"`
+}
+
+type APIKeyRotateRequest struct {
+	GracePeriodSeconds int `json:"grace_period_seconds"`
+}
+
+type APIKeyResponse struct {
+	ID         uint       `json:"id"`
+	Name       string     `json:"name"`
+	Prefix     string     `json:"prefix"`
+	Scopes     []string   `json:"scopes"`
+	AllowedIPs []string   `json:"allowed_ips"`
+	ExpiresAt  *time.Time `json:"expires_at,omitempty"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty"`
+	RotatedTo  *uint      `json:"rotated_to,omitempty"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+// APIKeyCreatedResponse carries the plaintext key. It is only ever returned once, at creation or rotation.
+type APIKeyCreatedResponse struct {
+	APIKeyResponse
+	Key string `json:"key"`
+}
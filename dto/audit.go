@@ -0,0 +1,21 @@
+package dto
+
+import (
+	"encoding/json"
+	"time"
+)
+
+type AuditRecordResponse struct {
+	ID              uint            `json:"id"`
+	OccurredAt      time.Time       `json:"occurred_at"`
+	Actor           string          `json:"actor"`
+	RequestID       string          `json:"request_id"`
+	Method          string          `json:"method"`
+	Route           string          `json:"route"`
+	TargetAccountID *int            `json:"target_account_id,omitempty"`
+	Before          json.RawMessage `json:"before,omitempty"`
+	After           json.RawMessage `json:"after,omitempty"`
+	Outcome         string          `json:"outcome"`
+	StatusCode      int             `json:"status_code"`
+	ErrorMessage    string          `json:"error_message,omitempty"`
+}
@@ -1,5 +1,10 @@
 package dto
 
+import (
+	"encoding/json"
+	"time"
+)
+
 type AccountCreationRequest struct {
 	AccountID      int    `json:"account_id" validate:"required"`
 	InitialBalance string `json:"initial_balance" validate:"required"`
@@ -8,10 +13,145 @@ type AccountCreationRequest struct {
 type AccountResponse struct {
 	AccountID int    `json:"account_id"`
 	Balance   string `json:"balance"`
+	Frozen    bool   `json:"frozen"`
+	// CustomerID is set on accounts owned by a customer.
+	CustomerID *uint `json:"customer_id,omitempty"`
+	// Currency and Metadata are set on imported accounts only.
+	Currency string          `json:"currency,omitempty"`
+	Metadata json.RawMessage `json:"metadata,omitempty"`
 }
 
 type TransactionRequest struct {
 	SourceAccountID      int    `json:"source_account_id" validate:"required"`
 	DestinationAccountID int    `json:"destination_account_id" validate:"required"`
 	Amount               string `json:"amount" validate:"required"`
+	// Reference is the client's own reference for the transfer, searchable afterwards.
+	Reference   string `json:"reference,omitempty"`
+	Description string `json:"description,omitempty"`
+	// Metadata is an arbitrary JSON object stored with the transfer.
+	Metadata json.RawMessage `json:"metadata,omitempty"`
+	// UniqueReference rejects the transfer when the source account already made one with
+	// the same reference.
+	UniqueReference bool `json:"unique_reference,omitempty"`
+}
+
+type TransactionResponse struct {
+	ID                   uint            `json:"id"`
+	SourceAccountID      int             `json:"source_account_id"`
+	DestinationAccountID int             `json:"destination_account_id"`
+	Amount               string          `json:"amount"`
+	Reference            string          `json:"reference,omitempty"`
+	Description          string          `json:"description,omitempty"`
+	Metadata             json.RawMessage `json:"metadata,omitempty"`
+	// Internal is set on transfers between two accounts of the same customer.
+	Internal  bool      `json:"internal"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type CustomerRequest struct {
+	Name string `json:"name" validate:"required"`
+	// Type is "individual" or "business".
+	Type    string `json:"type" validate:"required"`
+	Email   string `json:"email,omitempty"`
+	Phone   string `json:"phone,omitempty"`
+	Address string `json:"address,omitempty"`
+}
+
+type CustomerResponse struct {
+	ID        uint      `json:"id"`
+	Name      string    `json:"name"`
+	Type      string    `json:"type"`
+	KYCStatus string    `json:"kyc_status"`
+	Email     string    `json:"email,omitempty"`
+	Phone     string    `json:"phone,omitempty"`
+	Address   string    `json:"address,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type KYCStatusRequest struct {
+	// KYCStatus is "pending", "verified" or "rejected".
+	KYCStatus string `json:"kyc_status" validate:"required"`
+}
+
+// CustomerAccounts is a customer with its accounts, ordered by account ID. Customer accounts
+// are created without a currency, so TotalBalance is the sum of their balances.
+type CustomerAccounts struct {
+	Customer     CustomerResponse  `json:"customer"`
+	Accounts     []AccountResponse `json:"accounts"`
+	TotalBalance string            `json:"total_balance"`
+}
+
+// AccountImportRow is one data row of an account import file, with its fields as written.
+// Row is the row's position in the file, counting from 1 at the first row after the header.
+type AccountImportRow struct {
+	Row            int
+	AccountID      string
+	InitialBalance string
+	Currency       string
+	Metadata       string
+}
+
+type AccountImportOptions struct {
+	// DryRun validates every row without writing anything.
+	DryRun bool
+	// StartRow skips the rows before it, to resume an import that stopped part way.
+	StartRow int
+	// ChunkSize is the number of accounts created per storage transaction.
+	ChunkSize int
+}
+
+type AccountImportReport struct {
+	DryRun bool `json:"dry_run"`
+	// TotalRows counts the rows checked, which are the rows from StartRow on.
+	TotalRows int `json:"total_rows"`
+	// ValidRows counts the rows that would be or were imported, including existing ones.
+	ValidRows   int `json:"valid_rows"`
+	InvalidRows int `json:"invalid_rows"`
+	CreatedRows int `json:"created_rows"`
+	// ExistingRows counts rows whose account already exists with the same values; they are skipped.
+	ExistingRows int `json:"existing_rows"`
+	// NextRow is where to resume with start_row when the import stopped before the last row.
+	NextRow int                  `json:"next_row,omitempty"`
+	Errors  []AccountImportError `json:"errors"`
+}
+
+type AccountImportError struct {
+	Row       int    `json:"row"`
+	AccountID string `json:"account_id,omitempty"`
+	Field     string `json:"field,omitempty"`
+	Message   string `json:"message"`
+}
+
+// Statement entry directions.
+const (
+	StatementCredit = "credit"
+	StatementDebit  = "debit"
+)
+
+// Statement lists an account's transactions over [From, To), oldest first, with the balance
+// after each one.
+type Statement struct {
+	AccountID      int              `json:"account_id"`
+	Currency       string           `json:"currency,omitempty"`
+	From           time.Time        `json:"from"`
+	To             time.Time        `json:"to"`
+	OpeningBalance string           `json:"opening_balance"`
+	ClosingBalance string           `json:"closing_balance"`
+	TotalCredits   string           `json:"total_credits"`
+	TotalDebits    string           `json:"total_debits"`
+	Entries        []StatementEntry `json:"entries"`
+	GeneratedAt    time.Time        `json:"generated_at"`
+}
+
+type StatementEntry struct {
+	TransactionID         uint      `json:"transaction_id"`
+	Date                  time.Time `json:"date"`
+	CounterpartyAccountID int       `json:"counterparty_account_id"`
+	// Direction is StatementCredit or StatementDebit; Amount is never negative.
+	Direction string `json:"direction"`
+	Amount    string `json:"amount"`
+	Balance   string `json:"balance"`
+	// Reference and Description are those given with the transfer, if any.
+	Reference   string `json:"reference,omitempty"`
+	Description string `json:"description,omitempty"`
 }
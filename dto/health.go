@@ -0,0 +1,10 @@
+package dto
+
+// HealthResponse is the body of the health check. It is not wrapped in ResponsePattern so
+// probes can rely on a fixed shape.
+type HealthResponse struct {
+	Status  string            `json:"status"`
+	Data    map[string]string `json:"data"`
+	Message string            `json:"message"`
+	Error   string            `json:"error"`
+}
@@ -5,13 +5,14 @@
 package mock_banking
 
 import (
+	context "context"
 	reflect "reflect"
+	time "time"
 
 	gomock "github.com/golang/mock/gomock"
-	echo "github.com/labstack/echo/v4"
+	banking "github.com/rohanchauhan02/internal-transfer/domain/banking"
 	dto "github.com/rohanchauhan02/internal-transfer/dto"
 	models "github.com/rohanchauhan02/internal-transfer/models"
-	gorm "gorm.io/gorm"
 )
 
 // MockUsecase is a mock of Usecase interface.
@@ -38,7 +39,7 @@ func (m *MockUsecase) EXPECT() *MockUsecaseMockRecorder {
 }
 
 // CreateAccount mocks base method.
-func (m *MockUsecase) CreateAccount(arg0 echo.Context, arg1 int, arg2 string) error {
+func (m *MockUsecase) CreateAccount(arg0 context.Context, arg1 int, arg2 string) error {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "CreateAccount", arg0, arg1, arg2)
 	ret0, _ := ret[0].(error)
@@ -51,33 +52,152 @@ func (mr *MockUsecaseMockRecorder) CreateAccount(arg0, arg1, arg2 interface{}) *
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAccount", reflect.TypeOf((*MockUsecase)(nil).CreateAccount), arg0, arg1, arg2)
 }
 
+// CreateCustomer mocks base method.
+func (m *MockUsecase) CreateCustomer(arg0 context.Context, arg1 dto.CustomerRequest) (dto.CustomerResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateCustomer", arg0, arg1)
+	ret0, _ := ret[0].(dto.CustomerResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateCustomer indicates an expected call of CreateCustomer.
+func (mr *MockUsecaseMockRecorder) CreateCustomer(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateCustomer", reflect.TypeOf((*MockUsecase)(nil).CreateCustomer), arg0, arg1)
+}
+
+// CreateCustomerAccount mocks base method.
+func (m *MockUsecase) CreateCustomerAccount(arg0 context.Context, arg1 uint, arg2 int, arg3 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateCustomerAccount", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateCustomerAccount indicates an expected call of CreateCustomerAccount.
+func (mr *MockUsecaseMockRecorder) CreateCustomerAccount(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateCustomerAccount", reflect.TypeOf((*MockUsecase)(nil).CreateCustomerAccount), arg0, arg1, arg2, arg3)
+}
+
+// CustomerAccounts mocks base method.
+func (m *MockUsecase) CustomerAccounts(arg0 context.Context, arg1 uint) (dto.CustomerAccounts, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CustomerAccounts", arg0, arg1)
+	ret0, _ := ret[0].(dto.CustomerAccounts)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CustomerAccounts indicates an expected call of CustomerAccounts.
+func (mr *MockUsecaseMockRecorder) CustomerAccounts(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CustomerAccounts", reflect.TypeOf((*MockUsecase)(nil).CustomerAccounts), arg0, arg1)
+}
+
 // GetAccount mocks base method.
-func (m *MockUsecase) GetAccount(arg0 int) (dto.AccountResponse, error) {
+func (m *MockUsecase) GetAccount(arg0 context.Context, arg1 int) (dto.AccountResponse, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetAccount", arg0)
+	ret := m.ctrl.Call(m, "GetAccount", arg0, arg1)
 	ret0, _ := ret[0].(dto.AccountResponse)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetAccount indicates an expected call of GetAccount.
-func (mr *MockUsecaseMockRecorder) GetAccount(arg0 interface{}) *gomock.Call {
+func (mr *MockUsecaseMockRecorder) GetAccount(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAccount", reflect.TypeOf((*MockUsecase)(nil).GetAccount), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAccount", reflect.TypeOf((*MockUsecase)(nil).GetAccount), arg0, arg1)
+}
+
+// ImportAccounts mocks base method.
+func (m *MockUsecase) ImportAccounts(arg0 context.Context, arg1 []dto.AccountImportRow, arg2 dto.AccountImportOptions) (dto.AccountImportReport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ImportAccounts", arg0, arg1, arg2)
+	ret0, _ := ret[0].(dto.AccountImportReport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ImportAccounts indicates an expected call of ImportAccounts.
+func (mr *MockUsecaseMockRecorder) ImportAccounts(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ImportAccounts", reflect.TypeOf((*MockUsecase)(nil).ImportAccounts), arg0, arg1, arg2)
+}
+
+// ListTransactions mocks base method.
+func (m *MockUsecase) ListTransactions(arg0 context.Context, arg1 banking.TransactionFilter) ([]dto.TransactionResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListTransactions", arg0, arg1)
+	ret0, _ := ret[0].([]dto.TransactionResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListTransactions indicates an expected call of ListTransactions.
+func (mr *MockUsecaseMockRecorder) ListTransactions(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTransactions", reflect.TypeOf((*MockUsecase)(nil).ListTransactions), arg0, arg1)
+}
+
+// SetFrozen mocks base method.
+func (m *MockUsecase) SetFrozen(arg0 context.Context, arg1 int, arg2 bool) (dto.AccountResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetFrozen", arg0, arg1, arg2)
+	ret0, _ := ret[0].(dto.AccountResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetFrozen indicates an expected call of SetFrozen.
+func (mr *MockUsecaseMockRecorder) SetFrozen(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetFrozen", reflect.TypeOf((*MockUsecase)(nil).SetFrozen), arg0, arg1, arg2)
+}
+
+// SetKYCStatus mocks base method.
+func (m *MockUsecase) SetKYCStatus(arg0 context.Context, arg1 uint, arg2 string) (dto.CustomerResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetKYCStatus", arg0, arg1, arg2)
+	ret0, _ := ret[0].(dto.CustomerResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetKYCStatus indicates an expected call of SetKYCStatus.
+func (mr *MockUsecaseMockRecorder) SetKYCStatus(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetKYCStatus", reflect.TypeOf((*MockUsecase)(nil).SetKYCStatus), arg0, arg1, arg2)
+}
+
+// Statement mocks base method.
+func (m *MockUsecase) Statement(arg0 context.Context, arg1 int, arg2, arg3 time.Time) (dto.Statement, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Statement", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(dto.Statement)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Statement indicates an expected call of Statement.
+func (mr *MockUsecaseMockRecorder) Statement(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Statement", reflect.TypeOf((*MockUsecase)(nil).Statement), arg0, arg1, arg2, arg3)
 }
 
 // Transaction mocks base method.
-func (m *MockUsecase) Transaction(arg0 echo.Context, arg1, arg2 int, arg3 string) error {
+func (m *MockUsecase) Transaction(arg0 context.Context, arg1 dto.TransactionRequest) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Transaction", arg0, arg1, arg2, arg3)
+	ret := m.ctrl.Call(m, "Transaction", arg0, arg1)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Transaction indicates an expected call of Transaction.
-func (mr *MockUsecaseMockRecorder) Transaction(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+func (mr *MockUsecaseMockRecorder) Transaction(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Transaction", reflect.TypeOf((*MockUsecase)(nil).Transaction), arg0, arg1, arg2, arg3)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Transaction", reflect.TypeOf((*MockUsecase)(nil).Transaction), arg0, arg1)
 }
 
 // MockRepository is a mock of Repository interface.
@@ -104,7 +224,7 @@ func (m *MockRepository) EXPECT() *MockRepositoryMockRecorder {
 }
 
 // CreateAccount mocks base method.
-func (m *MockRepository) CreateAccount(arg0 *gorm.DB, arg1 models.Account) error {
+func (m *MockRepository) CreateAccount(arg0 context.Context, arg1 models.Account) error {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "CreateAccount", arg0, arg1)
 	ret0, _ := ret[0].(error)
@@ -117,23 +237,38 @@ func (mr *MockRepositoryMockRecorder) CreateAccount(arg0, arg1 interface{}) *gom
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAccount", reflect.TypeOf((*MockRepository)(nil).CreateAccount), arg0, arg1)
 }
 
+// CreateCustomer mocks base method.
+func (m *MockRepository) CreateCustomer(arg0 context.Context, arg1 models.Customer) (models.Customer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateCustomer", arg0, arg1)
+	ret0, _ := ret[0].(models.Customer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateCustomer indicates an expected call of CreateCustomer.
+func (mr *MockRepositoryMockRecorder) CreateCustomer(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateCustomer", reflect.TypeOf((*MockRepository)(nil).CreateCustomer), arg0, arg1)
+}
+
 // GetAccount mocks base method.
-func (m *MockRepository) GetAccount(arg0 int) (models.Account, error) {
+func (m *MockRepository) GetAccount(arg0 context.Context, arg1 int) (models.Account, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetAccount", arg0)
+	ret := m.ctrl.Call(m, "GetAccount", arg0, arg1)
 	ret0, _ := ret[0].(models.Account)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetAccount indicates an expected call of GetAccount.
-func (mr *MockRepositoryMockRecorder) GetAccount(arg0 interface{}) *gomock.Call {
+func (mr *MockRepositoryMockRecorder) GetAccount(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAccount", reflect.TypeOf((*MockRepository)(nil).GetAccount), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAccount", reflect.TypeOf((*MockRepository)(nil).GetAccount), arg0, arg1)
 }
 
 // GetAccountTx mocks base method.
-func (m *MockRepository) GetAccountTx(arg0 *gorm.DB, arg1 int) (models.Account, error) {
+func (m *MockRepository) GetAccountTx(arg0 context.Context, arg1 int) (models.Account, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "GetAccountTx", arg0, arg1)
 	ret0, _ := ret[0].(models.Account)
@@ -147,8 +282,83 @@ func (mr *MockRepositoryMockRecorder) GetAccountTx(arg0, arg1 interface{}) *gomo
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAccountTx", reflect.TypeOf((*MockRepository)(nil).GetAccountTx), arg0, arg1)
 }
 
+// GetAccounts mocks base method.
+func (m *MockRepository) GetAccounts(arg0 context.Context, arg1 []int) ([]models.Account, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAccounts", arg0, arg1)
+	ret0, _ := ret[0].([]models.Account)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAccounts indicates an expected call of GetAccounts.
+func (mr *MockRepositoryMockRecorder) GetAccounts(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAccounts", reflect.TypeOf((*MockRepository)(nil).GetAccounts), arg0, arg1)
+}
+
+// GetCustomer mocks base method.
+func (m *MockRepository) GetCustomer(arg0 context.Context, arg1 uint) (models.Customer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCustomer", arg0, arg1)
+	ret0, _ := ret[0].(models.Customer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCustomer indicates an expected call of GetCustomer.
+func (mr *MockRepositoryMockRecorder) GetCustomer(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCustomer", reflect.TypeOf((*MockRepository)(nil).GetCustomer), arg0, arg1)
+}
+
+// ListAccounts mocks base method.
+func (m *MockRepository) ListAccounts(arg0 context.Context) ([]models.Account, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAccounts", arg0)
+	ret0, _ := ret[0].([]models.Account)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAccounts indicates an expected call of ListAccounts.
+func (mr *MockRepositoryMockRecorder) ListAccounts(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAccounts", reflect.TypeOf((*MockRepository)(nil).ListAccounts), arg0)
+}
+
+// ListCustomerAccounts mocks base method.
+func (m *MockRepository) ListCustomerAccounts(arg0 context.Context, arg1 uint) ([]models.Account, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListCustomerAccounts", arg0, arg1)
+	ret0, _ := ret[0].([]models.Account)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListCustomerAccounts indicates an expected call of ListCustomerAccounts.
+func (mr *MockRepositoryMockRecorder) ListCustomerAccounts(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListCustomerAccounts", reflect.TypeOf((*MockRepository)(nil).ListCustomerAccounts), arg0, arg1)
+}
+
+// ListTransactions mocks base method.
+func (m *MockRepository) ListTransactions(arg0 context.Context, arg1 banking.TransactionFilter) ([]models.Transaction, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListTransactions", arg0, arg1)
+	ret0, _ := ret[0].([]models.Transaction)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListTransactions indicates an expected call of ListTransactions.
+func (mr *MockRepositoryMockRecorder) ListTransactions(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTransactions", reflect.TypeOf((*MockRepository)(nil).ListTransactions), arg0, arg1)
+}
+
 // Transaction mocks base method.
-func (m *MockRepository) Transaction(arg0 *gorm.DB, arg1 models.Transaction) error {
+func (m *MockRepository) Transaction(arg0 context.Context, arg1 models.Transaction) error {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "Transaction", arg0, arg1)
 	ret0, _ := ret[0].(error)
@@ -162,7 +372,7 @@ func (mr *MockRepositoryMockRecorder) Transaction(arg0, arg1 interface{}) *gomoc
 }
 
 // UpdateAccount mocks base method.
-func (m *MockRepository) UpdateAccount(arg0 *gorm.DB, arg1 models.Account) error {
+func (m *MockRepository) UpdateAccount(arg0 context.Context, arg1 models.Account) error {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "UpdateAccount", arg0, arg1)
 	ret0, _ := ret[0].(error)
@@ -174,3 +384,68 @@ func (mr *MockRepositoryMockRecorder) UpdateAccount(arg0, arg1 interface{}) *gom
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateAccount", reflect.TypeOf((*MockRepository)(nil).UpdateAccount), arg0, arg1)
 }
+
+// UpdateCustomer mocks base method.
+func (m *MockRepository) UpdateCustomer(arg0 context.Context, arg1 models.Customer) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateCustomer", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateCustomer indicates an expected call of UpdateCustomer.
+func (mr *MockRepositoryMockRecorder) UpdateCustomer(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateCustomer", reflect.TypeOf((*MockRepository)(nil).UpdateCustomer), arg0, arg1)
+}
+
+// MockUnitOfWork is a mock of UnitOfWork interface.
+type MockUnitOfWork struct {
+	ctrl     *gomock.Controller
+	recorder *MockUnitOfWorkMockRecorder
+}
+
+// MockUnitOfWorkMockRecorder is the mock recorder for MockUnitOfWork.
+type MockUnitOfWorkMockRecorder struct {
+	mock *MockUnitOfWork
+}
+
+// NewMockUnitOfWork creates a new mock instance.
+func NewMockUnitOfWork(ctrl *gomock.Controller) *MockUnitOfWork {
+	mock := &MockUnitOfWork{ctrl: ctrl}
+	mock.recorder = &MockUnitOfWorkMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockUnitOfWork) EXPECT() *MockUnitOfWorkMockRecorder {
+	return m.recorder
+}
+
+// Do mocks base method.
+func (m *MockUnitOfWork) Do(ctx context.Context, fn func(context.Context) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Do", ctx, fn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Do indicates an expected call of Do.
+func (mr *MockUnitOfWorkMockRecorder) Do(ctx, fn interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Do", reflect.TypeOf((*MockUnitOfWork)(nil).Do), ctx, fn)
+}
+
+// Snapshot mocks base method.
+func (m *MockUnitOfWork) Snapshot(ctx context.Context, fn func(context.Context) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Snapshot", ctx, fn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Snapshot indicates an expected call of Snapshot.
+func (mr *MockUnitOfWorkMockRecorder) Snapshot(ctx, fn interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Snapshot", reflect.TypeOf((*MockUnitOfWork)(nil).Snapshot), ctx, fn)
+}
@@ -9,14 +9,110 @@ import (
 
 type Account struct {
 	gorm.Model
-	AccountID int    `json:"account_id"`
+	AccountID int    `gorm:"uniqueIndex:uq_accounts_account_id;not null" json:"account_id"`
 	Balance   string `json:"balance"`
+	// FrozenAt is set while the account is frozen; frozen accounts cannot send or receive transfers.
+	FrozenAt *time.Time `json:"frozen_at"`
+	// Currency is the ISO 4217 code of the balance; empty for accounts created without one.
+	Currency string `gorm:"not null;default:''" json:"currency"`
+	// Metadata is the JSON object the account was imported with, or empty.
+	Metadata string `gorm:"type:text;not null;default:''" json:"metadata"`
+	// CustomerID is the customer owning the account, or nil for accounts without an owner.
+	CustomerID *uint `gorm:"index" json:"customer_id"`
+}
+
+// Customer types.
+const (
+	CustomerIndividual = "individual"
+	CustomerBusiness   = "business"
+)
+
+// KYC statuses. Customers start pending; only verified customers pass KYC enforcement.
+const (
+	KYCPending  = "pending"
+	KYCVerified = "verified"
+	KYCRejected = "rejected"
+)
+
+// Customer owns any number of accounts.
+type Customer struct {
+	ID        uint   `gorm:"primarykey" json:"id"`
+	Name      string `gorm:"not null" json:"name"`
+	Type      string `gorm:"not null" json:"type"`
+	KYCStatus string `gorm:"column:kyc_status;not null;default:'pending'" json:"kyc_status"`
+	// Contact data; any of them may be empty.
+	Email     string    `gorm:"not null;default:''" json:"email"`
+	Phone     string    `gorm:"not null;default:''" json:"phone"`
+	Address   string    `gorm:"type:text;not null;default:''" json:"address"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
 }
 
 type Transaction struct {
-	ID uint `gorm:"primarykey"`
-	SourceAccountID      int    `json:"source_account_id"`
+	ID                   uint   `gorm:"primarykey"`
+	SourceAccountID      int    `gorm:"index:idx_transactions_reference_source_account_id,priority:2" json:"source_account_id"`
 	DestinationAccountID int    `json:"destination_account_id"`
 	Amount               string `json:"amount"`
-	CreatedAt            time.Time
+	// Reference is the client's reference for the transfer, or empty.
+	Reference   string `gorm:"not null;default:'';index:idx_transactions_reference_source_account_id,priority:1" json:"reference"`
+	Description string `gorm:"type:text;not null;default:''" json:"description"`
+	// Metadata is the JSON object the transfer was made with, or empty. Its keys are also
+	// stored as TransactionMetadata rows for lookup.
+	Metadata string `gorm:"type:text;not null;default:''" json:"metadata"`
+	// Internal is set on transfers between two accounts of the same customer.
+	Internal  bool `gorm:"not null;default:false" json:"internal"`
+	CreatedAt time.Time
+}
+
+// TransactionMetadata is one top-level key of a transaction's metadata. Value is the string
+// itself for string values and the compact JSON otherwise.
+type TransactionMetadata struct {
+	ID            uint   `gorm:"primarykey"`
+	TransactionID uint   `gorm:"not null;index"`
+	Key           string `gorm:"not null;index:idx_transaction_metadata_key_value,priority:1"`
+	Value         string `gorm:"type:text;not null;index:idx_transaction_metadata_key_value,priority:2"`
+}
+
+// TableName pins the table name rather than relying on GORM pluralising "metadata".
+func (TransactionMetadata) TableName() string {
+	return "transaction_metadata"
+}
+
+type APIKey struct {
+	ID         uint       `gorm:"primarykey" json:"id"`
+	Name       string     `json:"name"`
+	Prefix     string     `gorm:"uniqueIndex" json:"prefix"`
+	Salt       string     `json:"-"`
+	KeyHash    string     `json:"-"`
+	Scopes     string     `json:"scopes"`
+	AllowedIPs string     `json:"allowed_ips"`
+	ExpiresAt  *time.Time `json:"expires_at"`
+	RevokedAt  *time.Time `json:"revoked_at"`
+	RotatedTo  *uint      `json:"rotated_to"`
+	LastUsedAt *time.Time `json:"last_used_at"`
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+}
+
+// AuditRecord is a row in the append-only audit trail. Rows are never updated or deleted.
+type AuditRecord struct {
+	ID              uint      `gorm:"primarykey" json:"id"`
+	OccurredAt      time.Time `gorm:"index" json:"occurred_at"`
+	Actor           string    `gorm:"index" json:"actor"`
+	RequestID       string    `gorm:"index" json:"request_id"`
+	Method          string    `json:"method"`
+	Route           string    `gorm:"index" json:"route"`
+	TargetAccountID *int      `gorm:"index" json:"target_account_id"`
+	Before          string    `gorm:"type:text" json:"before"`
+	After           string    `gorm:"type:text" json:"after"`
+	Outcome         string    `gorm:"index" json:"outcome"`
+	StatusCode      int       `json:"status_code"`
+	ErrorMessage    string    `json:"error_message"`
+}
+
+// RateLimitBucket holds token bucket state for the shared rate limit store.
+type RateLimitBucket struct {
+	Key       string `gorm:"primaryKey"`
+	Tokens    float64
+	UpdatedAt time.Time
 }
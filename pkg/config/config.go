@@ -12,15 +12,34 @@ import (
 type ImmutableConfigs interface {
 	GetPort() int
 	GetDBConf() DB
+	GetAuthConf() Auth
+	GetSigningConf() Signing
+	GetRateLimitConf() RateLimit
+	GetTracingConf() Tracing
+	GetHTTPConf() HTTP
+	GetGRPCConf() GRPC
+	GetLoggingConf() Logging
+	GetBankingConf() Banking
 }
 
 type config struct {
-	Port int `mapstructure:"APP_PORT"`
-	DB   DB  `mapstructure:"DB"`
+	Port      int       `mapstructure:"APP_PORT"`
+	DB        DB        `mapstructure:"DB"`
+	Auth      Auth      `mapstructure:"AUTH"`
+	Signing   Signing   `mapstructure:"SIGNING"`
+	RateLimit RateLimit `mapstructure:"RATE_LIMIT"`
+	Tracing   Tracing   `mapstructure:"TRACING"`
+	HTTP      HTTP      `mapstructure:"HTTP"`
+	GRPC      GRPC      `mapstructure:"GRPC"`
+	Logging   Logging   `mapstructure:"LOGGING"`
+	Banking   Banking   `mapstructure:"BANKING"`
 }
 
 type (
 	DB struct {
+		// Driver is postgres (default) or sqlite; Path is the SQLite database file.
+		Driver           string `mapstructure:"DRIVER"`
+		Path             string `mapstructure:"PATH"`
 		Host             string `mapstructure:"HOST"`
 		Port             string `mapstructure:"PORT"`
 		Name             string `mapstructure:"NAME"`
@@ -31,6 +50,119 @@ type (
 		MaxLifetimeConns int    `mapstructure:"MAX_LIFETIME_CONNS"`
 		SSLMode          string `mapstructure:"SSL_MODE"`
 	}
+
+	Auth struct {
+		Enabled bool `mapstructure:"ENABLED"`
+		// BootstrapKey is accepted as an admin key so the first real keys can be issued.
+		BootstrapKey string `mapstructure:"BOOTSTRAP_KEY"`
+		// LastUsedFlushInterval is the number of seconds between last-used timestamp flushes.
+		LastUsedFlushInterval int `mapstructure:"LAST_USED_FLUSH_INTERVAL"`
+	}
+
+	Signing struct {
+		// Required rejects unsigned requests on signed routes; otherwise only signed requests are verified.
+		Required bool `mapstructure:"REQUIRED"`
+		// MaxSkewSeconds bounds how far a signature timestamp may drift from server time.
+		MaxSkewSeconds int             `mapstructure:"MAX_SKEW_SECONDS"`
+		Clients        []SigningClient `mapstructure:"CLIENTS"`
+		// MaxNonces bounds the nonces remembered for replay detection; 0 means 100000.
+		MaxNonces int `mapstructure:"MAX_NONCES"`
+	}
+
+	SigningClient struct {
+		ID     string `mapstructure:"ID"`
+		Secret string `mapstructure:"SECRET"`
+	}
+
+	RateLimit struct {
+		Enabled bool `mapstructure:"ENABLED"`
+		// Store is "memory" (per instance) or "postgres" (shared by all instances).
+		Store string          `mapstructure:"STORE"`
+		Rules []RateLimitRule `mapstructure:"RULES"`
+	}
+
+	RateLimitRule struct {
+		// Route is the method and echo route path, e.g. "POST /api/v1/transactions".
+		Route string `mapstructure:"ROUTE"`
+		// KeyBy is "api_key", "ip" or "account".
+		KeyBy string  `mapstructure:"KEY_BY"`
+		Rate  float64 `mapstructure:"RATE"`
+		Burst int     `mapstructure:"BURST"`
+	}
+
+	Tracing struct {
+		// Exporter is "otlp", "stdout", "file" or "none".
+		Exporter    string `mapstructure:"EXPORTER"`
+		ServiceName string `mapstructure:"SERVICE_NAME"`
+		// Endpoint is the OTLP/HTTP collector address, e.g. "localhost:4318".
+		Endpoint string `mapstructure:"ENDPOINT"`
+		Insecure bool   `mapstructure:"INSECURE"`
+		// FilePath is where the "file" exporter writes spans as JSON.
+		FilePath    string  `mapstructure:"FILE_PATH"`
+		SampleRatio float64 `mapstructure:"SAMPLE_RATIO"`
+	}
+
+	HTTP struct {
+		// RequestTimeoutMS is the deadline given to every request's context; 0 disables it.
+		RequestTimeoutMS int            `mapstructure:"REQUEST_TIMEOUT_MS"`
+		RouteTimeouts    []RouteTimeout `mapstructure:"ROUTE_TIMEOUTS"`
+		// IdempotencyTTLSeconds is how long responses are kept for replay by Idempotency-Key.
+		IdempotencyTTLSeconds int `mapstructure:"IDEMPOTENCY_TTL_SECONDS"`
+		// TrustedProxies are the CIDR ranges of load balancers whose X-Forwarded-For is believed.
+		// When empty the client address is the address of the connection.
+		TrustedProxies []string `mapstructure:"TRUSTED_PROXIES"`
+		// MaxBodyBytes caps request bodies that middleware reads ahead of the handler, for
+		// idempotency fingerprints and signature digests; 0 means 1 MiB.
+		MaxBodyBytes    int64            `mapstructure:"MAX_BODY_BYTES"`
+		RouteBodyLimits []RouteBodyLimit `mapstructure:"ROUTE_BODY_LIMITS"`
+		// IdempotencyMaxEntries bounds how many idempotency keys are kept; 0 means 100000.
+		IdempotencyMaxEntries int `mapstructure:"IDEMPOTENCY_MAX_ENTRIES"`
+	}
+
+	RouteTimeout struct {
+		// Route is the method and echo route path, e.g. "POST /api/v1/transactions".
+		Route     string `mapstructure:"ROUTE"`
+		TimeoutMS int    `mapstructure:"TIMEOUT_MS"`
+	}
+
+	RouteBodyLimit struct {
+		// Route is the method and echo route path, e.g. "POST /api/v1/accounts/import".
+		Route    string `mapstructure:"ROUTE"`
+		MaxBytes int64  `mapstructure:"MAX_BYTES"`
+	}
+
+	GRPC struct {
+		Enabled bool `mapstructure:"ENABLED"`
+		Port    int  `mapstructure:"PORT"`
+		// RequestTimeoutMS bounds calls whose client sent no deadline, or a later one; 0 disables it.
+		RequestTimeoutMS int `mapstructure:"REQUEST_TIMEOUT_MS"`
+	}
+
+	Banking struct {
+		// RequireKYC rejects transfers unless both accounts belong to customers whose KYC is verified.
+		RequireKYC bool `mapstructure:"REQUIRE_KYC"`
+	}
+
+	Logging struct {
+		// Level is the default level: "debug", "info", "warn" or "error".
+		Level string `mapstructure:"LEVEL"`
+		// Format is "json" or "text".
+		Format        string         `mapstructure:"FORMAT"`
+		PackageLevels []PackageLevel `mapstructure:"PACKAGE_LEVELS"`
+		Redaction     Redaction      `mapstructure:"REDACTION"`
+	}
+
+	PackageLevel struct {
+		Package string `mapstructure:"PACKAGE"`
+		Level   string `mapstructure:"LEVEL"`
+	}
+
+	Redaction struct {
+		// MaskKeys are replaced entirely, e.g. amounts and balances.
+		MaskKeys []string `mapstructure:"MASK_KEYS"`
+		// PartialKeys keep their last two characters, e.g. account identifiers.
+		PartialKeys []string `mapstructure:"PARTIAL_KEYS"`
+	}
 )
 
 func (im *config) GetPort() int {
@@ -41,6 +173,38 @@ func (im *config) GetDBConf() DB {
 	return im.DB
 }
 
+func (im *config) GetAuthConf() Auth {
+	return im.Auth
+}
+
+func (im *config) GetSigningConf() Signing {
+	return im.Signing
+}
+
+func (im *config) GetRateLimitConf() RateLimit {
+	return im.RateLimit
+}
+
+func (im *config) GetTracingConf() Tracing {
+	return im.Tracing
+}
+
+func (im *config) GetHTTPConf() HTTP {
+	return im.HTTP
+}
+
+func (im *config) GetGRPCConf() GRPC {
+	return im.GRPC
+}
+
+func (im *config) GetLoggingConf() Logging {
+	return im.Logging
+}
+
+func (im *config) GetBankingConf() Banking {
+	return im.Banking
+}
+
 var (
 	once sync.Once
 	conf *config
@@ -4,17 +4,18 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"log/slog"
 	"strings"
 
 	"github.com/go-playground/validator"
 	"github.com/labstack/echo/v4"
-	"github.com/labstack/gommon/log"
 	"github.com/rohanchauhan02/internal-transfer/dto"
+	"github.com/rohanchauhan02/internal-transfer/pkg/logger"
 	"github.com/rohanchauhan02/internal-transfer/utils"
-
-	"gorm.io/gorm"
 )
 
+var log = logger.For("http")
+
 // Validator wraps the Go Playground validator
 type Validator struct {
 	Validator *validator.Validate
@@ -25,10 +26,13 @@ func (v *Validator) Validate(i interface{}) error {
 	return v.Validator.Struct(i)
 }
 
-// CustomApplicationContext extends Echo's Context to include additional dependencies.
+// ContextKeyErrorMessage holds the error message of the last CustomResponse so middleware such as
+// the audit trail can report why a request failed.
+const ContextKeyErrorMessage = "error_message"
+
+// CustomApplicationContext extends Echo's Context with the structured response helpers.
 type CustomApplicationContext struct {
 	echo.Context
-	PostgresDB *gorm.DB
 }
 
 // CustomResponse formats and sends a structured JSON response.
@@ -42,12 +46,16 @@ func (c *CustomApplicationContext) CustomResponse(status string, data any, messa
 		Code:         code,
 		Meta:         meta,
 	}
+	if errMsg != "" {
+		c.Set(ContextKeyErrorMessage, errMsg)
+	}
 
-	respBytes, err := json.Marshal(response)
-	if err == nil {
-		log.Infof("%s -- RESPONSE -- %s", utils.GetCallerMethod(), string(respBytes))
-	} else {
-		log.Errorf("Failed to marshal response: %v", err)
+	reqCtx := c.Request().Context()
+	if log.Enabled(reqCtx, slog.LevelDebug) {
+		log.DebugContext(reqCtx, "response sent",
+			slog.String("caller", utils.GetCallerMethod()),
+			slog.Int("code", code),
+			slog.Any("payload", redactedPayload(response)))
 	}
 
 	return c.JSON(code, response)
@@ -55,26 +63,41 @@ func (c *CustomApplicationContext) CustomResponse(status string, data any, messa
 
 // CustomBind binds and validates incoming request data.
 func (c *CustomApplicationContext) CustomBind(i any) error {
+	reqCtx := c.Request().Context()
 	if err := c.Bind(i); err != nil {
-		log.Warnf("%s -- Failed to bind request payload: %v", utils.GetCallerMethod(), err)
+		log.WarnContext(reqCtx, "failed to bind request payload",
+			slog.String("caller", utils.GetCallerMethod()), slog.String("error", err.Error()))
 		return err
 	}
 
 	if err := c.Validate(i); err != nil {
-		log.Warnf("%s -- Validation failed: %v", utils.GetCallerMethod(), err)
+		log.WarnContext(reqCtx, "request validation failed",
+			slog.String("caller", utils.GetCallerMethod()), slog.String("error", err.Error()))
 		return mapValidationErrors(err)
 	}
 
-	reqBytes, err := json.Marshal(i)
-	if err == nil {
-		log.Infof("%s -- Payload -- %s", utils.GetCallerMethod(), string(reqBytes))
-	} else {
-		log.Errorf("Failed to marshal request payload: %v", err)
+	if log.Enabled(reqCtx, slog.LevelDebug) {
+		log.DebugContext(reqCtx, "request payload",
+			slog.String("caller", utils.GetCallerMethod()),
+			slog.Any("payload", redactedPayload(i)))
 	}
 
 	return nil
 }
 
+// redactedPayload round-trips v through JSON so the redaction policy can mask fields by their JSON names.
+func redactedPayload(v any) any {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return "unencodable payload: " + err.Error()
+	}
+	var decoded any
+	if err := json.Unmarshal(b, &decoded); err != nil {
+		return "undecodable payload: " + err.Error()
+	}
+	return logger.Redact(decoded)
+}
+
 // mapValidationErrors converts validation errors into a user-friendly format.
 func mapValidationErrors(err error) error {
 	var validationErrs validator.ValidationErrors
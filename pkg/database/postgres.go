@@ -3,15 +3,36 @@ package database
 import (
 	"context"
 	"fmt"
+	"log/slog"
 	"sync"
 	"time"
 
-	"github.com/labstack/gommon/log"
 	"github.com/rohanchauhan02/internal-transfer/pkg/config"
+	"github.com/rohanchauhan02/internal-transfer/pkg/logger"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 )
 
+var log = logger.For("database")
+
+// Drivers accepted in DB.DRIVER.
+const (
+	DriverPostgres = "postgres"
+	DriverSQLite   = "sqlite"
+)
+
+// Open connects to the database selected by DB.DRIVER, defaulting to Postgres.
+func Open(ctx context.Context, conf config.ImmutableConfigs) (*gorm.DB, error) {
+	switch driver := conf.GetDBConf().Driver; driver {
+	case "", DriverPostgres:
+		return NewPostgres(conf).InitClient(ctx)
+	case DriverSQLite:
+		return NewSQLite(conf.GetDBConf()).InitClient(ctx)
+	default:
+		return nil, fmt.Errorf("unsupported DB.DRIVER %q", driver)
+	}
+}
+
 // Postgres database connection and initialization
 type Postgres interface {
 	InitClient(ctx context.Context) (*gorm.DB, error)
@@ -36,7 +57,7 @@ func NewPostgres(conf config.ImmutableConfigs) Postgres {
 
 func (d *database) InitClient(ctx context.Context) (*gorm.DB, error) {
 	once.Do(func() {
-		log.Info("Initializing PostgreSQL connection...")
+		log.Info("initializing PostgreSQL connection")
 
 		dbConfig := d.conf.GetDBConf()
 
@@ -58,24 +79,25 @@ func (d *database) InitClient(ctx context.Context) (*gorm.DB, error) {
 			}), &gorm.Config{
 				DisableAutomaticPing: false,
 				PrepareStmt:          true,
+				TranslateError:       true,
 			})
 
 			if err == nil {
 				break
 			}
 
-			log.Errorf("Postgres connection attempt %d failed: %v", i+1, err)
+			log.Error("postgres connection attempt failed", slog.Int("attempt", i+1), slog.String("error", err.Error()))
 			time.Sleep(2 * time.Second)
 		}
 
 		if err != nil {
-			log.Errorf("PostgreSQL connection failed after retries: %v", err)
+			log.Error("postgres connection failed after retries", slog.String("error", err.Error()))
 			return
 		}
 
 		sqlDB, sqlErr := db.DB()
 		if sqlErr != nil {
-			log.Errorf("Failed to get underlying sql.DB: %v", sqlErr)
+			log.Error("failed to get underlying sql.DB", slog.String("error", sqlErr.Error()))
 			err = sqlErr
 			return
 		}
@@ -84,7 +106,7 @@ func (d *database) InitClient(ctx context.Context) (*gorm.DB, error) {
 		sqlDB.SetMaxOpenConns(50)
 		sqlDB.SetConnMaxLifetime(30 * time.Minute)
 
-		log.Info("Successfully connected to PostgreSQL!")
+		log.Info("connected to PostgreSQL")
 	})
 
 	return db, err
@@ -0,0 +1,75 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/glebarez/sqlite"
+	"github.com/rohanchauhan02/internal-transfer/pkg/config"
+	"gorm.io/gorm"
+)
+
+// SQLite database connection and initialization
+type SQLite interface {
+	InitClient(ctx context.Context) (*gorm.DB, error)
+}
+
+type sqliteDatabase struct {
+	conf config.DB
+}
+
+// NewSQLite creates a new SQLite instance for the database file at conf.Path.
+func NewSQLite(conf config.DB) SQLite {
+	return &sqliteDatabase{
+		conf: conf,
+	}
+}
+
+// InitClient opens the database file, creating it if needed. Every transaction starts with
+// BEGIN IMMEDIATE, which takes the database write lock up front: SQLite has no SELECT ... FOR
+// UPDATE, so this is what keeps concurrent transfers from reading the same balance.
+func (d *sqliteDatabase) InitClient(ctx context.Context) (*gorm.DB, error) {
+	path := d.conf.Path
+	if path == "" {
+		return nil, fmt.Errorf("DB.PATH is required for the %s driver", DriverSQLite)
+	}
+	log.Info("opening SQLite database")
+
+	query := url.Values{}
+	query.Set("_txlock", "immediate")
+	query.Add("_pragma", "foreign_keys(1)")
+	query.Add("_pragma", "busy_timeout(10000)")
+	inMemory := path == ":memory:" || strings.Contains(path, "mode=memory")
+	if !inMemory {
+		query.Add("_pragma", "journal_mode(WAL)")
+	}
+	separator := "?"
+	if strings.Contains(path, "?") {
+		separator = "&"
+	}
+
+	db, err := gorm.Open(sqlite.Open(path+separator+query.Encode()), &gorm.Config{
+		TranslateError: true,
+	})
+	if err != nil {
+		return nil, err
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		return nil, err
+	}
+	if inMemory {
+		// Every connection to :memory: is a separate database, so keep exactly one.
+		sqlDB.SetMaxOpenConns(1)
+	} else if d.conf.MaxOpenConns > 0 {
+		sqlDB.SetMaxOpenConns(d.conf.MaxOpenConns)
+	}
+	sqlDB.SetConnMaxLifetime(30 * time.Minute)
+	if err := sqlDB.PingContext(ctx); err != nil {
+		return nil, err
+	}
+	return db, nil
+}
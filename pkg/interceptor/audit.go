@@ -0,0 +1,59 @@
+package interceptor
+
+import (
+	"context"
+	"log/slog"
+	"strconv"
+
+	"github.com/rohanchauhan02/internal-transfer/domain/audit"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+)
+
+// auditMethod is stored as the method of audit records written for gRPC calls.
+const auditMethod = "GRPC"
+
+// UnaryAudit writes the state-changing methods listed in audited to the audit trail once they
+// have been handled, like MiddlewareAudit does for REST. The route is the full gRPC method name
+// and the status code the HTTP equivalent of the gRPC code.
+func UnaryAudit(usecase audit.Usecase, audited map[string]bool) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if !audited[info.FullMethod] {
+			return handler(ctx, req)
+		}
+
+		entry := &audit.Entry{}
+		resp, err := handler(audit.WithEntry(ctx, entry), req)
+
+		st := status.Convert(ToStatus(ctx, err))
+		event := audit.Event{
+			Actor:      actor(ctx),
+			RequestID:  RequestIDFromContext(ctx),
+			Method:     auditMethod,
+			Route:      info.FullMethod,
+			StatusCode: httpStatus(st.Code()),
+			Entry:      entry,
+		}
+		if err != nil {
+			event.Error = st.Message()
+		}
+
+		// The record must be written even if the client has already gone away.
+		if recordErr := usecase.Record(context.WithoutCancel(ctx), event); recordErr != nil {
+			grpcLog.ErrorContext(ctx, "failed to write audit record", slog.String("error", recordErr.Error()))
+		}
+		return resp, err
+	}
+}
+
+// actor describes who made the call from the api key principal.
+func actor(ctx context.Context) string {
+	principal, ok := PrincipalFromContext(ctx)
+	if !ok {
+		return "anonymous"
+	}
+	if principal.KeyID != 0 {
+		return "apikey:" + strconv.FormatUint(uint64(principal.KeyID), 10) + ":" + principal.Name
+	}
+	return principal.Name
+}
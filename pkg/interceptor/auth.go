@@ -0,0 +1,105 @@
+package interceptor
+
+import (
+	"context"
+	"errors"
+	"net"
+	"strings"
+
+	"github.com/rohanchauhan02/internal-transfer/domain/apikey"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// metadataAPIKey carries the api key when the authorization bearer scheme is not used.
+const metadataAPIKey = "x-api-key"
+
+// anonymous is attached to calls when authentication is disabled so scope checks still pass.
+var anonymous = apikey.Principal{Name: "anonymous", Scopes: []string{apikey.ScopeAdmin}}
+
+type principalKey struct{}
+
+// PrincipalFromContext returns the principal authenticated by the Auth interceptors.
+func PrincipalFromContext(ctx context.Context) (apikey.Principal, bool) {
+	principal, ok := ctx.Value(principalKey{}).(apikey.Principal)
+	return principal, ok
+}
+
+// UnaryAuth authenticates calls with an api key and checks the scope scopes lists for the
+// method. Methods missing from scopes require the admin scope. When enabled is false every call
+// is treated as an anonymous admin, as MiddlewareAPIKey does for REST.
+func UnaryAuth(usecase apikey.Usecase, enabled bool, scopes map[string]string) grpc.UnaryServerInterceptor {
+	return unary(authenticate(usecase, enabled, scopes))
+}
+
+// StreamAuth is the stream variant of UnaryAuth.
+func StreamAuth(usecase apikey.Usecase, enabled bool, scopes map[string]string) grpc.StreamServerInterceptor {
+	return stream(authenticate(usecase, enabled, scopes))
+}
+
+func authenticate(usecase apikey.Usecase, enabled bool, scopes map[string]string) contextFunc {
+	return func(ctx context.Context, fullMethod string) (context.Context, error) {
+		if !enabled {
+			return context.WithValue(ctx, principalKey{}, anonymous), nil
+		}
+
+		rawKey := extractAPIKey(ctx)
+		if rawKey == "" {
+			return nil, status.Error(codes.Unauthenticated, "API key is required")
+		}
+		principal, err := usecase.Authenticate(ctx, rawKey, peerIP(ctx))
+		if err != nil {
+			switch {
+			case errors.Is(err, apikey.ErrIPNotAllowed):
+				return nil, status.Error(codes.PermissionDenied, err.Error())
+			case errors.Is(err, apikey.ErrInvalidKey),
+				errors.Is(err, apikey.ErrKeyExpired),
+				errors.Is(err, apikey.ErrKeyRevoked):
+				return nil, status.Error(codes.Unauthenticated, err.Error())
+			default:
+				return nil, status.Error(codes.Internal, "Failed to authenticate request")
+			}
+		}
+
+		scope, ok := scopes[fullMethod]
+		if !ok {
+			scope = apikey.ScopeAdmin
+		}
+		if !principal.HasScope(scope) {
+			return nil, status.Error(codes.PermissionDenied, "API key lacks required scope: "+scope)
+		}
+		return context.WithValue(ctx, principalKey{}, principal), nil
+	}
+}
+
+func extractAPIKey(ctx context.Context) string {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ""
+	}
+	if auth := md.Get("authorization"); len(auth) > 0 {
+		if token, ok := strings.CutPrefix(auth[0], "Bearer "); ok {
+			return strings.TrimSpace(token)
+		}
+	}
+	if keys := md.Get(metadataAPIKey); len(keys) > 0 {
+		return strings.TrimSpace(keys[0])
+	}
+	return ""
+}
+
+// peerIP returns the IP address of the caller, used for api key IP allow lists.
+func peerIP(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.Addr == nil {
+		return ""
+	}
+	host, _, err := net.SplitHostPort(p.Addr.String())
+	if err != nil {
+		return p.Addr.String()
+	}
+	return host
+}
@@ -0,0 +1,78 @@
+package interceptor
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/rohanchauhan02/internal-transfer/domain/banking"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ToStatus converts a handler error to a gRPC status error. Errors that already carry a status
+// pass through; unknown errors become Internal with a generic message, and are logged in full.
+func ToStatus(ctx context.Context, err error) error {
+	if err == nil {
+		return nil
+	}
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		return status.Error(codes.DeadlineExceeded, "request timed out")
+	case errors.Is(err, context.Canceled):
+		return status.Error(codes.Canceled, "request was cancelled")
+	case errors.Is(err, banking.ErrAccountExists):
+		return status.Error(codes.AlreadyExists, "account already exists")
+	case errors.Is(err, banking.ErrSameAccount), errors.Is(err, banking.ErrInvalidAmount),
+		errors.Is(err, banking.ErrInvalidTransferDetails):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, banking.ErrDuplicateReference):
+		return status.Error(codes.AlreadyExists, err.Error())
+	case errors.Is(err, banking.ErrInsufficientBalance), errors.Is(err, banking.ErrAccountFrozen),
+		errors.Is(err, banking.ErrKYCNotVerified), errors.Is(err, banking.ErrCurrencyMismatch):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	case errors.Is(err, banking.ErrAccountNotFound):
+		return status.Error(codes.NotFound, err.Error())
+	}
+	grpcLog.ErrorContext(ctx, "unhandled error", slog.String("error", err.Error()))
+	return status.Error(codes.Internal, "internal error")
+}
+
+// httpStatus maps a gRPC code to the HTTP status REST would have answered with, so audit
+// records from both APIs share one set of status codes.
+func httpStatus(code codes.Code) int {
+	switch code {
+	case codes.OK:
+		return http.StatusOK
+	case codes.InvalidArgument, codes.OutOfRange:
+		return http.StatusBadRequest
+	case codes.Unauthenticated:
+		return http.StatusUnauthorized
+	case codes.PermissionDenied:
+		return http.StatusForbidden
+	case codes.NotFound:
+		return http.StatusNotFound
+	case codes.AlreadyExists, codes.Aborted:
+		return http.StatusConflict
+	case codes.FailedPrecondition:
+		return http.StatusUnprocessableEntity
+	case codes.ResourceExhausted:
+		return http.StatusTooManyRequests
+	case codes.DeadlineExceeded:
+		return http.StatusGatewayTimeout
+	case codes.Canceled, codes.Unavailable:
+		return http.StatusServiceUnavailable
+	case codes.Unimplemented:
+		return http.StatusNotImplemented
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+func isServerError(code codes.Code) bool {
+	return httpStatus(code) >= http.StatusInternalServerError && code != codes.Canceled
+}
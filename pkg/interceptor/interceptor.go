@@ -0,0 +1,200 @@
+// Package interceptor holds the gRPC counterparts of the echo middleware in pkg/middleware:
+// panic recovery, request IDs, access logs, error code mapping, api key authentication, rate
+// limits, auditing and deadlines.
+// Each is provided as a unary and a stream interceptor.
+package interceptor
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"runtime/debug"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rohanchauhan02/internal-transfer/pkg/logger"
+	"github.com/rohanchauhan02/internal-transfer/pkg/tracing"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// MetadataRequestID carries the request ID, like the X-Request-ID header does over REST.
+const MetadataRequestID = "x-request-id"
+
+var grpcLog = logger.For("grpc")
+
+// contextFunc prepares the context of a call before the handler runs, or rejects the call.
+type contextFunc func(ctx context.Context, fullMethod string) (context.Context, error)
+
+func unary(f contextFunc) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		ctx, err := f(ctx, info.FullMethod)
+		if err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+func stream(f contextFunc) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, err := f(ss.Context(), info.FullMethod)
+		if err != nil {
+			return err
+		}
+		return handler(srv, &serverStream{ServerStream: ss, ctx: ctx})
+	}
+}
+
+// serverStream overrides the context of a stream.
+type serverStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *serverStream) Context() context.Context {
+	return s.ctx
+}
+
+// UnaryRecovery turns a panic in a handler into an Internal error instead of crashing the
+// process, which also serves REST. Put it first so it covers the other interceptors too.
+func UnaryRecovery() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp any, err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				err = recovered(ctx, info.FullMethod, r)
+			}
+		}()
+		return handler(ctx, req)
+	}
+}
+
+// StreamRecovery is the stream variant of UnaryRecovery.
+func StreamRecovery() grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) (err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				err = recovered(ss.Context(), info.FullMethod, r)
+			}
+		}()
+		return handler(srv, ss)
+	}
+}
+
+func recovered(ctx context.Context, fullMethod string, r any) error {
+	grpcLog.ErrorContext(ctx, "panic in rpc handler", slog.String("rpc", fullMethod),
+		slog.String("panic", fmt.Sprint(r)), slog.String("stack", string(debug.Stack())))
+	return status.Error(codes.Internal, "internal error")
+}
+
+type requestIDKey struct{}
+
+// RequestIDFromContext returns the request ID assigned by the RequestID interceptors.
+func RequestIDFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+// UnaryRequestID makes sure every call carries a request ID, reusing the one sent by the client,
+// then the trace ID, before generating one. It is echoed in the x-request-id response header and
+// added to every log line of the call.
+func UnaryRequestID() grpc.UnaryServerInterceptor {
+	return unary(withRequestID)
+}
+
+// StreamRequestID is the stream variant of UnaryRequestID.
+func StreamRequestID() grpc.StreamServerInterceptor {
+	return stream(withRequestID)
+}
+
+func withRequestID(ctx context.Context, fullMethod string) (context.Context, error) {
+	span := trace.SpanFromContext(ctx)
+	var requestID string
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if ids := md.Get(MetadataRequestID); len(ids) > 0 {
+			requestID = ids[0]
+		}
+	}
+	if requestID == "" {
+		if sc := span.SpanContext(); sc.HasTraceID() {
+			requestID = sc.TraceID().String()
+		} else {
+			requestID = uuid.New().String()
+		}
+	}
+	span.SetAttributes(tracing.AttributeRequestID.String(requestID))
+	if err := grpc.SetHeader(ctx, metadata.Pairs(MetadataRequestID, requestID)); err != nil {
+		grpcLog.WarnContext(ctx, "failed to set request ID header", slog.String("error", err.Error()))
+	}
+	ctx = context.WithValue(ctx, requestIDKey{}, requestID)
+	return logger.WithFields(ctx, slog.String("request_id", requestID), slog.String("rpc", fullMethod)), nil
+}
+
+// UnaryTimeout bounds every call by timeout unless the client asked for an earlier deadline.
+// A zero timeout disables it.
+func UnaryTimeout(timeout time.Duration) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if timeout <= 0 {
+			return handler(ctx, req)
+		}
+		ctx, cancel := context.WithTimeout(ctx, timeout)
+		defer cancel()
+		return handler(ctx, req)
+	}
+}
+
+// StreamTimeout is the stream variant of UnaryTimeout.
+func StreamTimeout(timeout time.Duration) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if timeout <= 0 {
+			return handler(srv, ss)
+		}
+		ctx, cancel := context.WithTimeout(ss.Context(), timeout)
+		defer cancel()
+		return handler(srv, &serverStream{ServerStream: ss, ctx: ctx})
+	}
+}
+
+// UnaryErrors maps errors returned by handlers to gRPC status codes and writes one access log
+// line per call. Put it outside the other interceptors so their rejections are logged too.
+func UnaryErrors() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		start := time.Now()
+		resp, err := handler(ctx, req)
+		err = ToStatus(ctx, err)
+		logCall(ctx, err, time.Since(start))
+		return resp, err
+	}
+}
+
+// StreamErrors is the stream variant of UnaryErrors.
+func StreamErrors() grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		start := time.Now()
+		err := ToStatus(ss.Context(), handler(srv, ss))
+		logCall(ss.Context(), err, time.Since(start))
+		return err
+	}
+}
+
+func logCall(ctx context.Context, err error, latency time.Duration) {
+	st := status.Convert(err)
+	level := slog.LevelInfo
+	if err != nil {
+		level = slog.LevelWarn
+		if isServerError(st.Code()) {
+			level = slog.LevelError
+		}
+	}
+	attrs := []slog.Attr{
+		slog.String("code", st.Code().String()),
+		slog.Duration("latency", latency/time.Microsecond*time.Microsecond),
+	}
+	if err != nil {
+		attrs = append(attrs, slog.String("error", st.Message()))
+	}
+	grpcLog.LogAttrs(ctx, level, "rpc completed", attrs...)
+}
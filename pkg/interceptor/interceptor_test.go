@@ -0,0 +1,166 @@
+package interceptor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/rohanchauhan02/internal-transfer/domain/apikey"
+	"github.com/rohanchauhan02/internal-transfer/domain/audit"
+	"github.com/rohanchauhan02/internal-transfer/domain/banking"
+	mock_apikey "github.com/rohanchauhan02/internal-transfer/file/mocks/mock_apikey"
+	"github.com/rohanchauhan02/internal-transfer/pkg/config"
+	"github.com/rohanchauhan02/internal-transfer/pkg/ratelimit"
+	bankingv1 "github.com/rohanchauhan02/internal-transfer/proto/banking/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+const testMethod = "/banking.v1.BankingService/Transfer"
+
+func TestToStatus(t *testing.T) {
+	tests := []struct {
+		err  error
+		want codes.Code
+	}{
+		{nil, codes.OK},
+		{fmt.Errorf("wrapped: %w", banking.ErrAccountExists), codes.AlreadyExists},
+		{banking.ErrInsufficientBalance, codes.FailedPrecondition},
+		{banking.ErrSameAccount, codes.InvalidArgument},
+		{banking.ErrInvalidAmount, codes.InvalidArgument},
+		{context.DeadlineExceeded, codes.DeadlineExceeded},
+		{context.Canceled, codes.Canceled},
+		{status.Error(codes.NotFound, "gone"), codes.NotFound},
+		{errors.New("database exploded"), codes.Internal},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, status.Code(ToStatus(context.Background(), tt.err)), "%v", tt.err)
+	}
+	assert.Equal(t, "internal error", status.Convert(ToStatus(context.Background(), errors.New("secret detail"))).Message())
+}
+
+func TestUnaryAuth(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	usecase := mock_apikey.NewMockUsecase(ctrl)
+	scopes := map[string]string{testMethod: apikey.ScopeTransfersWrite}
+	info := &grpc.UnaryServerInfo{FullMethod: testMethod}
+	handler := func(ctx context.Context, _ any) (any, error) {
+		principal, ok := PrincipalFromContext(ctx)
+		require.True(t, ok)
+		return principal.Name, nil
+	}
+	withKey := func(key string) context.Context {
+		return metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", "Bearer "+key))
+	}
+
+	t.Run("disabled", func(t *testing.T) {
+		resp, err := UnaryAuth(usecase, false, scopes)(context.Background(), nil, info, handler)
+		require.NoError(t, err)
+		assert.Equal(t, "anonymous", resp)
+	})
+	t.Run("missing key", func(t *testing.T) {
+		_, err := UnaryAuth(usecase, true, scopes)(context.Background(), nil, info, handler)
+		assert.Equal(t, codes.Unauthenticated, status.Code(err))
+	})
+	t.Run("revoked key", func(t *testing.T) {
+		usecase.EXPECT().Authenticate(gomock.Any(), "revoked", gomock.Any()).Return(apikey.Principal{}, apikey.ErrKeyRevoked)
+		_, err := UnaryAuth(usecase, true, scopes)(withKey("revoked"), nil, info, handler)
+		assert.Equal(t, codes.Unauthenticated, status.Code(err))
+	})
+	t.Run("missing scope", func(t *testing.T) {
+		usecase.EXPECT().Authenticate(gomock.Any(), "reader", gomock.Any()).
+			Return(apikey.Principal{Name: "reader", Scopes: []string{apikey.ScopeAccountsRead}}, nil)
+		_, err := UnaryAuth(usecase, true, scopes)(withKey("reader"), nil, info, handler)
+		assert.Equal(t, codes.PermissionDenied, status.Code(err))
+	})
+	t.Run("unlisted method needs admin", func(t *testing.T) {
+		usecase.EXPECT().Authenticate(gomock.Any(), "writer", gomock.Any()).
+			Return(apikey.Principal{Name: "writer", Scopes: []string{apikey.ScopeTransfersWrite}}, nil)
+		_, err := UnaryAuth(usecase, true, scopes)(withKey("writer"), nil, &grpc.UnaryServerInfo{FullMethod: "/other/Method"}, handler)
+		assert.Equal(t, codes.PermissionDenied, status.Code(err))
+	})
+	t.Run("authorised", func(t *testing.T) {
+		ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(metadataAPIKey, "writer"))
+		usecase.EXPECT().Authenticate(gomock.Any(), "writer", gomock.Any()).
+			Return(apikey.Principal{Name: "writer", Scopes: []string{apikey.ScopeTransfersWrite}}, nil)
+		resp, err := UnaryAuth(usecase, true, scopes)(ctx, nil, info, handler)
+		require.NoError(t, err)
+		assert.Equal(t, "writer", resp)
+	})
+}
+
+type recordingAudit struct {
+	audit.Usecase
+	events []audit.Event
+}
+
+func (r *recordingAudit) Record(_ context.Context, event audit.Event) error {
+	r.events = append(r.events, event)
+	return nil
+}
+
+func TestUnaryAudit_RecordsMappedStatus(t *testing.T) {
+	rec := &recordingAudit{}
+	intercept := UnaryAudit(rec, map[string]bool{testMethod: true})
+	ctx := context.WithValue(context.Background(), requestIDKey{}, "req-1")
+	ctx = context.WithValue(ctx, principalKey{}, apikey.Principal{KeyID: 3, Name: "billing"})
+
+	_, err := intercept(ctx, nil, &grpc.UnaryServerInfo{FullMethod: testMethod}, func(ctx context.Context, _ any) (any, error) {
+		audit.EntryFromContext(ctx).SetTarget(7)
+		return nil, banking.ErrInsufficientBalance
+	})
+	assert.ErrorIs(t, err, banking.ErrInsufficientBalance)
+
+	_, err = intercept(ctx, nil, &grpc.UnaryServerInfo{FullMethod: "/banking.v1.BankingService/GetAccount"}, func(context.Context, any) (any, error) {
+		return nil, nil
+	})
+	require.NoError(t, err)
+
+	require.Len(t, rec.events, 1, "only audited methods are recorded")
+	event := rec.events[0]
+	assert.Equal(t, "apikey:3:billing", event.Actor)
+	assert.Equal(t, "req-1", event.RequestID)
+	assert.Equal(t, auditMethod, event.Method)
+	assert.Equal(t, testMethod, event.Route)
+	assert.Equal(t, 422, event.StatusCode)
+	assert.Equal(t, "insufficient balance", event.Error)
+	assert.Equal(t, 7, *event.Entry.TargetAccountID)
+}
+
+func TestRecovery(t *testing.T) {
+	_, err := UnaryRecovery()(context.Background(), nil, &grpc.UnaryServerInfo{FullMethod: testMethod},
+		func(context.Context, any) (any, error) { panic("counter cannot decrease in value") })
+	assert.Equal(t, codes.Internal, status.Code(err))
+	assert.Equal(t, "internal error", status.Convert(err).Message())
+
+	err = StreamRecovery()(nil, &serverStream{ctx: context.Background()}, &grpc.StreamServerInfo{FullMethod: testMethod},
+		func(any, grpc.ServerStream) error { panic("boom") })
+	assert.Equal(t, codes.Internal, status.Code(err))
+}
+
+func TestUnaryRateLimit(t *testing.T) {
+	conf := config.RateLimit{Enabled: true, Rules: []config.RateLimitRule{
+		{Route: "POST /api/v1/transactions", KeyBy: keyByAccount, Rate: 0.001, Burst: 1},
+	}}
+	intercept := UnaryRateLimit(conf, ratelimit.NewMemoryStore(), map[string]string{testMethod: "POST /api/v1/transactions"})
+	info := &grpc.UnaryServerInfo{FullMethod: testMethod}
+	ok := func(context.Context, any) (any, error) { return "ok", nil }
+	transfer := func(from int64) error {
+		_, err := intercept(context.Background(), &bankingv1.TransferRequest{SourceAccountId: from}, info, ok)
+		return err
+	}
+
+	require.NoError(t, transfer(1))
+	assert.Equal(t, codes.ResourceExhausted, status.Code(transfer(1)))
+	assert.NoError(t, transfer(2), "accounts have their own buckets")
+
+	_, err := intercept(context.Background(), nil, &grpc.UnaryServerInfo{FullMethod: "/banking.v1.BankingService/GetAccount"}, ok)
+	assert.NoError(t, err, "methods without rules are not limited")
+}
@@ -0,0 +1,228 @@
+package iso20022
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/rohanchauhan02/internal-transfer/dto"
+	"github.com/shopspring/decimal"
+)
+
+// Balance types, entry status and bank transaction codes used in statements.
+const (
+	balanceOpeningBooked  = "OPBD"
+	balanceClosingBooked  = "CLBD"
+	entryBooked           = "BOOK"
+	domainPayments        = "PMNT"
+	familyIssuedCredit    = "ICDT"
+	familyReceivedCredit  = "RCDT"
+	subFamilyBookTransfer = "BOOK"
+)
+
+type camt053Document struct {
+	XMLName   xml.Name             `xml:"urn:iso:std:iso:20022:tech:xsd:camt.053.001.08 Document"`
+	Statement camt053BankStatement `xml:"BkToCstmrStmt"`
+}
+
+type camt053BankStatement struct {
+	GrpHdr    camt053GroupHeader `xml:"GrpHdr"`
+	Statement camt053Statement   `xml:"Stmt"`
+}
+
+type camt053GroupHeader struct {
+	MsgID   string `xml:"MsgId"`
+	CreDtTm string `xml:"CreDtTm"`
+}
+
+type camt053Statement struct {
+	ID        string           `xml:"Id"`
+	CreDtTm   string           `xml:"CreDtTm"`
+	FrToDt    camt053Period    `xml:"FrToDt"`
+	Acct      *cashAccount     `xml:"Acct"`
+	Bal       []camt053Balance `xml:"Bal"`
+	TxsSummry camt053Summary   `xml:"TxsSummry"`
+	Ntry      []camt053Entry   `xml:"Ntry"`
+}
+
+type camt053Period struct {
+	FrDtTm string `xml:"FrDtTm"`
+	ToDtTm string `xml:"ToDtTm"`
+}
+
+type camt053Balance struct {
+	Tp        camt053BalanceType `xml:"Tp"`
+	Amt       amount             `xml:"Amt"`
+	CdtDbtInd string             `xml:"CdtDbtInd"`
+	Dt        dateAndDateTime    `xml:"Dt"`
+}
+
+type camt053BalanceType struct {
+	CdOrPrtry camt053Code `xml:"CdOrPrtry"`
+}
+
+type camt053Code struct {
+	Cd string `xml:"Cd"`
+}
+
+type camt053Summary struct {
+	TtlNtries    camt053TotalEntries `xml:"TtlNtries"`
+	TtlCdtNtries camt053NumberAndSum `xml:"TtlCdtNtries"`
+	TtlDbtNtries camt053NumberAndSum `xml:"TtlDbtNtries"`
+}
+
+type camt053TotalEntries struct {
+	NbOfNtries string          `xml:"NbOfNtries"`
+	Sum        string          `xml:"Sum"`
+	TtlNetNtry camt053NetEntry `xml:"TtlNetNtry"`
+}
+
+type camt053NetEntry struct {
+	Amt       string `xml:"Amt"`
+	CdtDbtInd string `xml:"CdtDbtInd"`
+}
+
+type camt053NumberAndSum struct {
+	NbOfNtries string `xml:"NbOfNtries"`
+	Sum        string `xml:"Sum"`
+}
+
+type camt053Entry struct {
+	NtryRef     string              `xml:"NtryRef"`
+	Amt         amount              `xml:"Amt"`
+	CdtDbtInd   string              `xml:"CdtDbtInd"`
+	Sts         camt053Code         `xml:"Sts"`
+	BookgDt     dateAndDateTime     `xml:"BookgDt"`
+	ValDt       dateAndDateTime     `xml:"ValDt"`
+	AcctSvcrRef string              `xml:"AcctSvcrRef"`
+	BkTxCd      camt053BankTxCode   `xml:"BkTxCd"`
+	NtryDtls    camt053EntryDetails `xml:"NtryDtls"`
+}
+
+type camt053BankTxCode struct {
+	Domn struct {
+		Cd   string `xml:"Cd"`
+		Fmly struct {
+			Cd        string `xml:"Cd"`
+			SubFmlyCd string `xml:"SubFmlyCd"`
+		} `xml:"Fmly"`
+	} `xml:"Domn"`
+}
+
+type camt053EntryDetails struct {
+	TxDtls camt053TxDetails `xml:"TxDtls"`
+}
+
+type camt053TxDetails struct {
+	Refs struct {
+		AcctSvcrRef string `xml:"AcctSvcrRef"`
+	} `xml:"Refs"`
+	RltdPties struct {
+		DbtrAcct *cashAccount `xml:"DbtrAcct,omitempty"`
+		CdtrAcct *cashAccount `xml:"CdtrAcct,omitempty"`
+	} `xml:"RltdPties"`
+}
+
+// WriteCamt053 writes the statement as a camt.053 bank to customer statement with booked
+// opening and closing balances, a transactions summary, and an entry per transaction.
+// Amounts are in the account's currency, or UnknownCurrency when it has none.
+func WriteCamt053(w io.Writer, s dto.Statement) error {
+	currency := s.Currency
+	if currency == "" {
+		currency = UnknownCurrency
+	}
+	id := fmt.Sprintf("%d-%s", s.AccountID, s.From.UTC().Format("20060102"))
+	stmt := camt053Statement{
+		ID:      id,
+		CreDtTm: dateTime(s.GeneratedAt),
+		FrToDt:  camt053Period{FrDtTm: dateTime(s.From), ToDtTm: dateTime(s.To)},
+		Acct:    internalAccount(s.AccountID),
+	}
+	if s.Currency != "" {
+		stmt.Acct.Ccy = s.Currency
+	}
+
+	for _, b := range []struct {
+		code, value string
+		date        string
+	}{
+		{balanceOpeningBooked, s.OpeningBalance, dateTime(s.From)},
+		{balanceClosingBooked, s.ClosingBalance, dateTime(s.To)},
+	} {
+		value, err := decimal.NewFromString(b.value)
+		if err != nil {
+			return fmt.Errorf("balance %s: %w", b.code, err)
+		}
+		amt, indicator, err := signedAmount(value)
+		if err != nil {
+			return err
+		}
+		stmt.Bal = append(stmt.Bal, camt053Balance{
+			Tp:        camt053BalanceType{CdOrPrtry: camt053Code{Cd: b.code}},
+			Amt:       amount{Ccy: currency, Value: amt},
+			CdtDbtInd: indicator,
+			Dt:        dateAndDateTime{DtTm: b.date},
+		})
+	}
+
+	var credits, debits, sum decimal.Decimal
+	var creditCount, debitCount int
+	for _, e := range s.Entries {
+		value, err := decimal.NewFromString(e.Amount)
+		if err != nil {
+			return fmt.Errorf("transaction %d: %w", e.TransactionID, err)
+		}
+		amt, err := formatAmount(value)
+		if err != nil {
+			return fmt.Errorf("transaction %d: %w", e.TransactionID, err)
+		}
+		ref := strconv.FormatUint(uint64(e.TransactionID), 10)
+		entry := camt053Entry{
+			NtryRef:     ref,
+			Amt:         amount{Ccy: currency, Value: amt},
+			CdtDbtInd:   credit,
+			Sts:         camt053Code{Cd: entryBooked},
+			BookgDt:     dateAndDateTime{DtTm: dateTime(e.Date)},
+			ValDt:       dateAndDateTime{DtTm: dateTime(e.Date)},
+			AcctSvcrRef: ref,
+		}
+		entry.BkTxCd.Domn.Cd = domainPayments
+		entry.BkTxCd.Domn.Fmly.SubFmlyCd = subFamilyBookTransfer
+		entry.NtryDtls.TxDtls.Refs.AcctSvcrRef = ref
+		if e.Direction == dto.StatementDebit {
+			entry.CdtDbtInd = debit
+			entry.BkTxCd.Domn.Fmly.Cd = familyIssuedCredit
+			entry.NtryDtls.TxDtls.RltdPties.CdtrAcct = internalAccount(e.CounterpartyAccountID)
+			debits = debits.Add(value)
+			debitCount++
+		} else {
+			entry.BkTxCd.Domn.Fmly.Cd = familyReceivedCredit
+			entry.NtryDtls.TxDtls.RltdPties.DbtrAcct = internalAccount(e.CounterpartyAccountID)
+			credits = credits.Add(value)
+			creditCount++
+		}
+		sum = sum.Add(value)
+		stmt.Ntry = append(stmt.Ntry, entry)
+	}
+
+	net, indicator, err := signedAmount(credits.Sub(debits))
+	if err != nil {
+		return err
+	}
+	stmt.TxsSummry = camt053Summary{
+		TtlNtries: camt053TotalEntries{
+			NbOfNtries: strconv.Itoa(len(s.Entries)),
+			Sum:        sum.String(),
+			TtlNetNtry: camt053NetEntry{Amt: net, CdtDbtInd: indicator},
+		},
+		TtlCdtNtries: camt053NumberAndSum{NbOfNtries: strconv.Itoa(creditCount), Sum: credits.String()},
+		TtlDbtNtries: camt053NumberAndSum{NbOfNtries: strconv.Itoa(debitCount), Sum: debits.String()},
+	}
+
+	doc := camt053Document{Statement: camt053BankStatement{
+		GrpHdr:    camt053GroupHeader{MsgID: "STMT-" + id + "-" + s.GeneratedAt.UTC().Format("150405"), CreDtTm: dateTime(s.GeneratedAt)},
+		Statement: stmt,
+	}}
+	return writeDocument(w, doc)
+}
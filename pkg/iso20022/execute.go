@@ -0,0 +1,202 @@
+package iso20022
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+
+	"github.com/rohanchauhan02/internal-transfer/domain/banking"
+	"github.com/rohanchauhan02/internal-transfer/dto"
+	"github.com/shopspring/decimal"
+)
+
+// Payment statuses.
+const (
+	StatusAcceptedSettlementCompleted = "ACSC"
+	StatusRejected                    = "RJCT"
+	StatusPartiallyAccepted           = "PART"
+)
+
+// Status reason codes, from the ISO 20022 ExternalStatusReason1Code list.
+const (
+	ReasonInvalidDebtorAccount   = "AC02"
+	ReasonInvalidCreditorAccount = "AC03"
+	ReasonBlockedAccount         = "AC06"
+	ReasonInsufficientFunds      = "AM04"
+	ReasonDuplication            = "AM05"
+	ReasonInvalidCurrency        = "AM03"
+	ReasonControlSumMismatch     = "AM10"
+	ReasonInvalidAmount          = "AM12"
+	ReasonInvalidNumberOfTxs     = "AM18"
+	ReasonRegulatory             = "RR04"
+	ReasonNarrative              = "NARR"
+)
+
+// notProvided is the end-to-end ID of instructions the debtor gave no reference for.
+const notProvided = "NOTPROVIDED"
+
+// maxDescriptionLength is the longest transfer description, the length of one Ustrd line;
+// longer remittance information is cut.
+const maxDescriptionLength = 140
+
+// StatusReport is the outcome of executing a pain.001, written back as a pain.002.
+type StatusReport struct {
+	OriginalMessageID            string
+	OriginalNumberOfTransactions string
+	OriginalControlSum           string
+	// GroupStatus, GroupReason and GroupInfo are set when the whole message was rejected, in
+	// which case Transactions is empty.
+	GroupStatus  string
+	GroupReason  string
+	GroupInfo    string
+	Transactions []TransactionStatus
+}
+
+// TransactionStatus is the outcome of one instruction.
+type TransactionStatus struct {
+	Instruction
+	Status string
+	Reason string
+	Info   string
+}
+
+// Execute runs the instructions of p, in order, as internal transfers through usecase. Each
+// transfer is committed on its own, so a rejected instruction does not affect the others.
+//
+// The message is rejected as a whole, without executing anything, when its group header
+// totals do not match its instructions. Once the context is done the remaining instructions
+// are rejected unexecuted, so the report always says which transfers were made.
+func Execute(ctx context.Context, usecase banking.Usecase, p *Pain001) *StatusReport {
+	report := &StatusReport{
+		OriginalMessageID:            p.MessageID,
+		OriginalNumberOfTransactions: p.NumberOfTransactions,
+		OriginalControlSum:           p.ControlSum,
+	}
+	if reason, info := checkGroupHeader(p); reason != "" {
+		report.GroupStatus, report.GroupReason, report.GroupInfo = StatusRejected, reason, info
+		return report
+	}
+
+	seen := make(map[string]bool, len(p.Instructions))
+	for _, in := range p.Instructions {
+		status := TransactionStatus{Instruction: in, Status: StatusRejected}
+		switch {
+		case seen[in.EndToEndID]:
+			status.Reason, status.Info = ReasonDuplication, "duplicate EndToEndId in message"
+		case ctx.Err() != nil:
+			status.Reason, status.Info = failed(ctx)
+		default:
+			status.Reason, status.Info = execute(ctx, usecase, in)
+			if status.Reason == "" {
+				status.Status = StatusAcceptedSettlementCompleted
+			}
+		}
+		seen[in.EndToEndID] = true
+		report.Transactions = append(report.Transactions, status)
+	}
+	return report
+}
+
+// checkGroupHeader compares the declared number of transactions and control sum with the
+// instructions.
+func checkGroupHeader(p *Pain001) (string, string) {
+	if p.NumberOfTransactions != strconv.Itoa(len(p.Instructions)) {
+		return ReasonInvalidNumberOfTxs, "NbOfTxs " + p.NumberOfTransactions + " does not match " +
+			strconv.Itoa(len(p.Instructions)) + " transactions"
+	}
+	if p.ControlSum == "" {
+		return "", ""
+	}
+	declared, err := decimal.NewFromString(p.ControlSum)
+	if err != nil {
+		return ReasonControlSumMismatch, "CtrlSum is not a decimal number"
+	}
+	var sum decimal.Decimal
+	for _, in := range p.Instructions {
+		amount, err := decimal.NewFromString(in.Amount)
+		if err != nil {
+			// Reported against the instruction itself.
+			continue
+		}
+		sum = sum.Add(amount)
+	}
+	if !sum.Equal(declared) {
+		return ReasonControlSumMismatch, "CtrlSum " + p.ControlSum + " does not match " + sum.String()
+	}
+	return "", ""
+}
+
+// execute validates and runs one instruction and returns the reason it was rejected, if it was.
+func execute(ctx context.Context, usecase banking.Usecase, in Instruction) (string, string) {
+	from, err := strconv.Atoi(in.DebtorAccount)
+	if err != nil || from <= 0 {
+		return ReasonInvalidDebtorAccount, "DbtrAcct/Id/Othr/Id must be an internal account ID"
+	}
+	to, err := strconv.Atoi(in.CreditorAccount)
+	if err != nil || to <= 0 {
+		return ReasonInvalidCreditorAccount, "CdtrAcct/Id/Othr/Id must be an internal account ID"
+	}
+	amount, err := decimal.NewFromString(in.Amount)
+	if err != nil || !amount.IsPositive() || -amount.Exponent() > maxAmountDecimals && !amount.Equal(amount.Round(maxAmountDecimals)) {
+		return ReasonInvalidAmount, "InstdAmt must be a positive amount with at most 5 decimal places"
+	}
+	for _, account := range []struct {
+		id     int
+		reason string
+	}{{from, ReasonInvalidDebtorAccount}, {to, ReasonInvalidCreditorAccount}} {
+		acc, err := usecase.GetAccount(ctx, account.id)
+		if errors.Is(err, banking.ErrAccountNotFound) {
+			return account.reason, "account " + strconv.Itoa(account.id) + " does not exist"
+		}
+		if err != nil {
+			return failed(ctx)
+		}
+		if acc.Currency != "" && !strings.EqualFold(acc.Currency, in.Currency) {
+			return ReasonInvalidCurrency, "account " + strconv.Itoa(account.id) + " is held in " + acc.Currency
+		}
+	}
+
+	// The end-to-end ID is the debtor's reference for the transfer, unless it says there is none.
+	// It must be unique for the debtor account, so a message sent again does not pay twice.
+	req := dto.TransactionRequest{
+		SourceAccountID:      from,
+		DestinationAccountID: to,
+		Amount:               amount.String(),
+		Description:          maxText(in.RemittanceInfo, maxDescriptionLength),
+	}
+	if in.EndToEndID != notProvided {
+		req.Reference = in.EndToEndID
+		req.UniqueReference = true
+	}
+	err = usecase.Transaction(ctx, req)
+	switch {
+	case err == nil:
+		return "", ""
+	case errors.Is(err, banking.ErrInsufficientBalance):
+		return ReasonInsufficientFunds, err.Error()
+	case errors.Is(err, banking.ErrAccountFrozen):
+		return ReasonBlockedAccount, err.Error()
+	case errors.Is(err, banking.ErrKYCNotVerified):
+		return ReasonRegulatory, err.Error()
+	case errors.Is(err, banking.ErrCurrencyMismatch):
+		return ReasonInvalidCurrency, err.Error()
+	case errors.Is(err, banking.ErrDuplicateReference):
+		return ReasonDuplication, "EndToEndId already executed for the debtor account"
+	case errors.Is(err, banking.ErrSameAccount):
+		return ReasonInvalidCreditorAccount, err.Error()
+	case errors.Is(err, banking.ErrInvalidAmount):
+		return ReasonInvalidAmount, err.Error()
+	case errors.Is(err, banking.ErrInvalidTransferDetails):
+		return ReasonNarrative, err.Error()
+	}
+	return failed(ctx)
+}
+
+// failed explains an unexpected error without exposing its details.
+func failed(ctx context.Context) (string, string) {
+	if ctx.Err() != nil {
+		return ReasonNarrative, "not executed: " + ctx.Err().Error()
+	}
+	return ReasonNarrative, "transfer could not be completed"
+}
@@ -0,0 +1,112 @@
+// Package iso20022 converts between the banking domain and ISO 20022 XML messages:
+//
+//   - camt.053.001.08, the bank to customer statement, is written from an account statement.
+//   - pain.001.001.09, the customer credit transfer initiation, is read and its instructions
+//     executed as internal transfers.
+//   - pain.002.001.10, the customer payment status report, is written with the outcome of each
+//     instruction of a pain.001.
+//
+// Accounts are identified by their internal account ID in Id/Othr/Id; IBANs are not used.
+package iso20022
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// Message namespaces.
+const (
+	NamespaceCamt053 = "urn:iso:std:iso:20022:tech:xsd:camt.053.001.08"
+	NamespacePain001 = "urn:iso:std:iso:20022:tech:xsd:pain.001.001.09"
+	NamespacePain002 = "urn:iso:std:iso:20022:tech:xsd:pain.002.001.10"
+)
+
+// MessageNamePain001 is the message name identification of the pain.001 version read.
+const MessageNamePain001 = "pain.001.001.09"
+
+// UnknownCurrency is the ISO 4217 code used for accounts without a currency.
+const UnknownCurrency = "XXX"
+
+// Credit and debit indicators.
+const (
+	credit = "CRDT"
+	debit  = "DBIT"
+)
+
+// maxAmountDecimals is the number of fraction digits ActiveOrHistoricCurrencyAndAmount allows.
+const maxAmountDecimals = 5
+
+// dateTimeLayout formats ISODateTime values.
+const dateTimeLayout = "2006-01-02T15:04:05Z"
+
+// accountIdentification is AccountIdentification4Choice.
+type accountIdentification struct {
+	IBAN string             `xml:"IBAN,omitempty"`
+	Othr *genericIdentifier `xml:"Othr,omitempty"`
+}
+
+type genericIdentifier struct {
+	ID string `xml:"Id"`
+}
+
+// cashAccount is the subset of CashAccount38 and CashAccount39 used here.
+type cashAccount struct {
+	ID  accountIdentification `xml:"Id"`
+	Ccy string                `xml:"Ccy,omitempty"`
+}
+
+// internalAccount identifies an internal account.
+func internalAccount(accountID int) *cashAccount {
+	return &cashAccount{ID: accountIdentification{Othr: &genericIdentifier{ID: fmt.Sprint(accountID)}}}
+}
+
+// amount is ActiveOrHistoricCurrencyAndAmount.
+type amount struct {
+	Ccy   string `xml:"Ccy,attr"`
+	Value string `xml:",chardata"`
+}
+
+// dateAndDateTime is DateAndDateTime2Choice.
+type dateAndDateTime struct {
+	Dt   string `xml:"Dt,omitempty"`
+	DtTm string `xml:"DtTm,omitempty"`
+}
+
+func dateTime(t time.Time) string {
+	return t.UTC().Format(dateTimeLayout)
+}
+
+// signedAmount splits value into a non-negative amount and its credit or debit indicator.
+func signedAmount(value decimal.Decimal) (string, string, error) {
+	indicator := credit
+	if value.IsNegative() {
+		indicator = debit
+	}
+	formatted, err := formatAmount(value.Abs())
+	return formatted, indicator, err
+}
+
+func formatAmount(value decimal.Decimal) (string, error) {
+	if -value.Exponent() > maxAmountDecimals && !value.Equal(value.Round(maxAmountDecimals)) {
+		return "", fmt.Errorf("amount %s has more than %d decimal places", value, maxAmountDecimals)
+	}
+	return value.String(), nil
+}
+
+// writeDocument writes doc as an XML document.
+func writeDocument(w io.Writer, doc any) error {
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(doc); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
@@ -0,0 +1,300 @@
+package iso20022
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/rohanchauhan02/internal-transfer/domain/banking"
+	BankingRepository "github.com/rohanchauhan02/internal-transfer/domain/banking/repository"
+	BankingUsecase "github.com/rohanchauhan02/internal-transfer/domain/banking/usecase"
+	"github.com/rohanchauhan02/internal-transfer/dto"
+	"github.com/rohanchauhan02/internal-transfer/models"
+	"github.com/rohanchauhan02/internal-transfer/pkg/config"
+	"github.com/rohanchauhan02/internal-transfer/pkg/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var start = time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+
+func TestWriteCamt053(t *testing.T) {
+	s := dto.Statement{
+		AccountID:      1,
+		Currency:       "USD",
+		From:           start,
+		To:             start.AddDate(0, 0, 31),
+		OpeningBalance: "100",
+		ClosingBalance: "149.5",
+		TotalCredits:   "60",
+		TotalDebits:    "10.5",
+		GeneratedAt:    start.AddDate(0, 1, 0),
+		Entries: []dto.StatementEntry{
+			{TransactionID: 7, Date: start.Add(time.Hour), CounterpartyAccountID: 2,
+				Direction: dto.StatementCredit, Amount: "60", Balance: "160"},
+			{TransactionID: 9, Date: start.Add(2 * time.Hour), CounterpartyAccountID: 3,
+				Direction: dto.StatementDebit, Amount: "10.5", Balance: "149.5"},
+		},
+	}
+	var buf bytes.Buffer
+	require.NoError(t, WriteCamt053(&buf, s))
+	assertValid(t, loadSchema(t, "testdata/camt.053.001.08.xsd"), buf.Bytes())
+
+	var doc struct {
+		Stmt struct {
+			ID   string `xml:"Id"`
+			Acct struct {
+				ID  string `xml:"Id>Othr>Id"`
+				Ccy string `xml:"Ccy"`
+			} `xml:"Acct"`
+			Bal []struct {
+				Cd        string `xml:"Tp>CdOrPrtry>Cd"`
+				Amt       amount `xml:"Amt"`
+				CdtDbtInd string `xml:"CdtDbtInd"`
+			} `xml:"Bal"`
+			Summary struct {
+				Count     string `xml:"TtlNtries>NbOfNtries"`
+				Net       string `xml:"TtlNtries>TtlNetNtry>Amt"`
+				NetInd    string `xml:"TtlNtries>TtlNetNtry>CdtDbtInd"`
+				Debits    string `xml:"TtlDbtNtries>Sum"`
+				CreditSum string `xml:"TtlCdtNtries>Sum"`
+			} `xml:"TxsSummry"`
+			Ntry []struct {
+				Amt       amount `xml:"Amt"`
+				CdtDbtInd string `xml:"CdtDbtInd"`
+				Ref       string `xml:"AcctSvcrRef"`
+				Family    string `xml:"BkTxCd>Domn>Fmly>Cd"`
+				Debtor    string `xml:"NtryDtls>TxDtls>RltdPties>DbtrAcct>Id>Othr>Id"`
+				Creditor  string `xml:"NtryDtls>TxDtls>RltdPties>CdtrAcct>Id>Othr>Id"`
+			} `xml:"Ntry"`
+		} `xml:"BkToCstmrStmt>Stmt"`
+	}
+	require.NoError(t, xml.Unmarshal(buf.Bytes(), &doc))
+	assert.Equal(t, "1-20261001", doc.Stmt.ID)
+	assert.Equal(t, "1", doc.Stmt.Acct.ID)
+	assert.Equal(t, "USD", doc.Stmt.Acct.Ccy)
+	require.Len(t, doc.Stmt.Bal, 2)
+	assert.Equal(t, "OPBD", doc.Stmt.Bal[0].Cd)
+	assert.Equal(t, amount{Ccy: "USD", Value: "100"}, doc.Stmt.Bal[0].Amt)
+	assert.Equal(t, "CLBD", doc.Stmt.Bal[1].Cd)
+	assert.Equal(t, "149.5", doc.Stmt.Bal[1].Amt.Value)
+	assert.Equal(t, "2", doc.Stmt.Summary.Count)
+	assert.Equal(t, "49.5", doc.Stmt.Summary.Net)
+	assert.Equal(t, "CRDT", doc.Stmt.Summary.NetInd)
+	assert.Equal(t, "60", doc.Stmt.Summary.CreditSum)
+	assert.Equal(t, "10.5", doc.Stmt.Summary.Debits)
+	require.Len(t, doc.Stmt.Ntry, 2)
+	assert.Equal(t, "CRDT", doc.Stmt.Ntry[0].CdtDbtInd)
+	assert.Equal(t, "RCDT", doc.Stmt.Ntry[0].Family)
+	assert.Equal(t, "2", doc.Stmt.Ntry[0].Debtor)
+	assert.Equal(t, "7", doc.Stmt.Ntry[0].Ref)
+	assert.Equal(t, "DBIT", doc.Stmt.Ntry[1].CdtDbtInd)
+	assert.Equal(t, "ICDT", doc.Stmt.Ntry[1].Family)
+	assert.Equal(t, "3", doc.Stmt.Ntry[1].Creditor)
+	assert.Equal(t, "10.5", doc.Stmt.Ntry[1].Amt.Value)
+}
+
+func TestWriteCamt053WithoutCurrencyOrEntries(t *testing.T) {
+	s := dto.Statement{
+		AccountID:      5,
+		From:           start,
+		To:             start.AddDate(0, 0, 1),
+		OpeningBalance: "-3",
+		ClosingBalance: "-3",
+		TotalCredits:   "0",
+		TotalDebits:    "0",
+		GeneratedAt:    start.AddDate(0, 0, 1),
+	}
+	var buf bytes.Buffer
+	require.NoError(t, WriteCamt053(&buf, s))
+	assertValid(t, loadSchema(t, "testdata/camt.053.001.08.xsd"), buf.Bytes())
+	assert.Contains(t, buf.String(), `<Amt Ccy="XXX">3</Amt>`)
+	assert.Contains(t, buf.String(), "<CdtDbtInd>DBIT</CdtDbtInd>")
+	assert.NotContains(t, buf.String(), "<Ntry>")
+
+	s.OpeningBalance = "0.000001"
+	assert.Error(t, WriteCamt053(&bytes.Buffer{}, s))
+}
+
+func TestReadPain001(t *testing.T) {
+	pain001 := loadSchema(t, "testdata/pain.001.001.09.xsd")
+	for _, name := range []string{"pain.001.sample.xml", "pain.001.control-sum.xml"} {
+		data, err := os.ReadFile("testdata/" + name)
+		require.NoError(t, err)
+		assertValid(t, pain001, data)
+	}
+
+	p := readSample(t, "pain.001.sample.xml")
+	assert.Equal(t, "PAYROLL-2026-10", p.MessageID)
+	assert.Equal(t, "6", p.NumberOfTransactions)
+	assert.Equal(t, "1575.25", p.ControlSum)
+	require.Len(t, p.Instructions, 6)
+	assert.Equal(t, Instruction{
+		PaymentInformationID: "PAYROLL-2026-10-A",
+		InstructionID:        "A-1",
+		EndToEndID:           "E2E-0001",
+		DebtorAccount:        "1",
+		CreditorAccount:      "2",
+		Amount:               "250.25",
+		Currency:             "USD",
+		RemittanceInfo:       "October salary",
+	}, p.Instructions[0])
+	assert.Equal(t, "", p.Instructions[3].CreditorAccount, "IBAN creditor")
+	assert.Equal(t, "4", p.Instructions[5].DebtorAccount)
+}
+
+func TestReadPain001RejectsInvalidMessages(t *testing.T) {
+	data, err := os.ReadFile("testdata/pain.001.invalid.xml")
+	require.NoError(t, err)
+	assert.NotEmpty(t, loadSchema(t, "testdata/pain.001.001.09.xsd").validate(bytes.NewReader(data)))
+	_, err = ReadPain001(bytes.NewReader(data))
+	assert.ErrorIs(t, err, ErrInvalidPain001)
+
+	for name, doc := range map[string]string{
+		"not xml":       "payments",
+		"other message": `<Document xmlns="urn:iso:std:iso:20022:tech:xsd:pain.001.001.03"><CstmrCdtTrfInitn/></Document>`,
+		"no payments":   `<Document xmlns="` + NamespacePain001 + `"><CstmrCdtTrfInitn><GrpHdr><MsgId>M</MsgId></GrpHdr></CstmrCdtTrfInitn></Document>`,
+	} {
+		_, err := ReadPain001(bytes.NewReader([]byte(doc)))
+		assert.ErrorIs(t, err, ErrInvalidPain001, name)
+	}
+}
+
+func TestExecutePain001(t *testing.T) {
+	logger.Init(config.Logging{Level: "error"})
+	t.Cleanup(func() { logger.Init(config.Logging{}) })
+
+	ctx := context.Background()
+	store := BankingRepository.NewMemoryStore()
+	for _, a := range []models.Account{
+		{AccountID: 1, Balance: "1000", Currency: "USD"},
+		{AccountID: 2, Balance: "0", Currency: "USD"},
+		{AccountID: 3, Balance: "0"},
+		{AccountID: 4, Balance: "500", Currency: "EUR"},
+	} {
+		require.NoError(t, store.CreateAccount(ctx, a))
+	}
+	usecase := BankingUsecase.NewBankingUsecase(store, store, config.Banking{})
+
+	report := Execute(ctx, usecase, readSample(t, "pain.001.sample.xml"))
+	require.Len(t, report.Transactions, 6)
+	type outcome struct{ EndToEndID, Status, Reason string }
+	var got []outcome
+	for _, tx := range report.Transactions {
+		got = append(got, outcome{tx.EndToEndID, tx.Status, tx.Reason})
+	}
+	assert.Equal(t, []outcome{
+		{"E2E-0001", StatusAcceptedSettlementCompleted, ""},
+		{"E2E-0002", StatusRejected, ReasonInsufficientFunds},
+		{"E2E-0003", StatusRejected, ReasonInvalidCreditorAccount},
+		{"E2E-0004", StatusRejected, ReasonInvalidCreditorAccount},
+		{"E2E-0001", StatusRejected, ReasonDuplication},
+		{"E2E-0101", StatusRejected, ReasonInvalidCurrency},
+	}, got)
+	for id, balance := range map[int]string{1: "749.75", 2: "250.25", 3: "0", 4: "500"} {
+		account, err := usecase.GetAccount(ctx, id)
+		require.NoError(t, err)
+		assert.Equal(t, balance, account.Balance, "account %d", id)
+	}
+	transactions, err := usecase.ListTransactions(ctx, banking.TransactionFilter{})
+	require.NoError(t, err)
+	require.Len(t, transactions, 1)
+	assert.Equal(t, "E2E-0001", transactions[0].Reference, "the end-to-end ID is the transfer's reference")
+	assert.Equal(t, "October salary", transactions[0].Description)
+
+	resent := Execute(ctx, usecase, readSample(t, "pain.001.sample.xml"))
+	require.Len(t, resent.Transactions, 6)
+	assert.Equal(t, StatusRejected, resent.Transactions[0].Status, "a message sent again does not pay twice")
+	assert.Equal(t, ReasonDuplication, resent.Transactions[0].Reason)
+	transactions, err = usecase.ListTransactions(ctx, banking.TransactionFilter{})
+	require.NoError(t, err)
+	assert.Len(t, transactions, 1)
+
+	var buf bytes.Buffer
+	require.NoError(t, WritePain002(&buf, report, "STS-1", start))
+	assertValid(t, loadSchema(t, "testdata/pain.002.001.10.xsd"), buf.Bytes())
+	var doc struct {
+		Group struct {
+			OrgnlMsgID string `xml:"OrgnlMsgId"`
+			GrpSts     string `xml:"GrpSts"`
+			PerStatus  []struct {
+				Count  string `xml:"DtldNbOfTxs"`
+				Status string `xml:"DtldSts"`
+			} `xml:"NbOfTxsPerSts"`
+		} `xml:"CstmrPmtStsRpt>OrgnlGrpInfAndSts"`
+		Payments []struct {
+			ID     string `xml:"OrgnlPmtInfId"`
+			Status string `xml:"PmtInfSts"`
+			Txs    []struct {
+				EndToEndID string `xml:"OrgnlEndToEndId"`
+				Status     string `xml:"TxSts"`
+				Reason     string `xml:"StsRsnInf>Rsn>Cd"`
+			} `xml:"TxInfAndSts"`
+		} `xml:"CstmrPmtStsRpt>OrgnlPmtInfAndSts"`
+	}
+	require.NoError(t, xml.Unmarshal(buf.Bytes(), &doc))
+	assert.Equal(t, "PAYROLL-2026-10", doc.Group.OrgnlMsgID)
+	assert.Equal(t, StatusPartiallyAccepted, doc.Group.GrpSts)
+	require.Len(t, doc.Group.PerStatus, 2)
+	assert.Equal(t, "1", doc.Group.PerStatus[0].Count)
+	assert.Equal(t, "5", doc.Group.PerStatus[1].Count)
+	require.Len(t, doc.Payments, 2)
+	assert.Equal(t, StatusPartiallyAccepted, doc.Payments[0].Status)
+	assert.Len(t, doc.Payments[0].Txs, 5)
+	assert.Equal(t, "AM04", doc.Payments[0].Txs[1].Reason)
+	assert.Equal(t, StatusRejected, doc.Payments[1].Status)
+	assert.Equal(t, "AM03", doc.Payments[1].Txs[0].Reason)
+}
+
+func TestExecutePain001RejectsMismatchedGroupHeader(t *testing.T) {
+	store := BankingRepository.NewMemoryStore()
+	usecase := BankingUsecase.NewBankingUsecase(store, store, config.Banking{})
+
+	report := Execute(context.Background(), usecase, readSample(t, "pain.001.control-sum.xml"))
+	assert.Equal(t, StatusRejected, report.GroupStatus)
+	assert.Equal(t, ReasonControlSumMismatch, report.GroupReason)
+	assert.Empty(t, report.Transactions)
+
+	p := readSample(t, "pain.001.sample.xml")
+	p.NumberOfTransactions = "5"
+	report = Execute(context.Background(), usecase, p)
+	assert.Equal(t, ReasonInvalidNumberOfTxs, report.GroupReason)
+
+	var buf bytes.Buffer
+	require.NoError(t, WritePain002(&buf, report, "STS-2", start))
+	assertValid(t, loadSchema(t, "testdata/pain.002.001.10.xsd"), buf.Bytes())
+	assert.Contains(t, buf.String(), "<GrpSts>RJCT</GrpSts>")
+	assert.NotContains(t, buf.String(), "<OrgnlPmtInfAndSts>")
+}
+
+func TestExecutePain001StopsWhenContextIsDone(t *testing.T) {
+	store := BankingRepository.NewMemoryStore()
+	require.NoError(t, store.CreateAccount(context.Background(), models.Account{AccountID: 1, Balance: "1000"}))
+	require.NoError(t, store.CreateAccount(context.Background(), models.Account{AccountID: 2, Balance: "0"}))
+	usecase := BankingUsecase.NewBankingUsecase(store, store, config.Banking{})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	report := Execute(ctx, usecase, readSample(t, "pain.001.sample.xml"))
+	for _, tx := range report.Transactions[:4] {
+		assert.Equal(t, StatusRejected, tx.Status)
+		assert.Equal(t, ReasonNarrative, tx.Reason, tx.EndToEndID)
+		assert.Contains(t, tx.Info, "not executed")
+	}
+	account, err := usecase.GetAccount(context.Background(), 1)
+	require.NoError(t, err)
+	assert.Equal(t, "1000", account.Balance)
+}
+
+func readSample(t *testing.T, name string) *Pain001 {
+	t.Helper()
+	f, err := os.Open("testdata/" + name)
+	require.NoError(t, err)
+	defer f.Close()
+	p, err := ReadPain001(f)
+	require.NoError(t, err)
+	return p
+}
@@ -0,0 +1,138 @@
+package iso20022
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Limits of one pain.001: its credit transfer instructions and its size in bytes.
+const (
+	MaxPain001Transactions = 10000
+	MaxPain001Bytes        = 32 << 20
+)
+
+// ErrInvalidPain001 is returned for documents that are not well-formed pain.001.001.09
+// messages.
+var ErrInvalidPain001 = errors.New("invalid pain.001 message")
+
+type pain001Document struct {
+	XMLName xml.Name           `xml:"Document"`
+	Initn   *pain001Initiation `xml:"CstmrCdtTrfInitn"`
+}
+
+type pain001Initiation struct {
+	GrpHdr struct {
+		MsgID   string `xml:"MsgId"`
+		NbOfTxs string `xml:"NbOfTxs"`
+		CtrlSum string `xml:"CtrlSum"`
+	} `xml:"GrpHdr"`
+	PmtInf []struct {
+		PmtInfID    string       `xml:"PmtInfId"`
+		PmtMtd      string       `xml:"PmtMtd"`
+		DbtrAcct    *cashAccount `xml:"DbtrAcct"`
+		CdtTrfTxInf []struct {
+			PmtID struct {
+				InstrID    string `xml:"InstrId"`
+				EndToEndID string `xml:"EndToEndId"`
+			} `xml:"PmtId"`
+			Amt struct {
+				InstdAmt *amount `xml:"InstdAmt"`
+			} `xml:"Amt"`
+			CdtrAcct *cashAccount `xml:"CdtrAcct"`
+			RmtInf   struct {
+				Ustrd []string `xml:"Ustrd"`
+			} `xml:"RmtInf"`
+		} `xml:"CdtTrfTxInf"`
+	} `xml:"PmtInf"`
+}
+
+// Pain001 is a customer credit transfer initiation.
+type Pain001 struct {
+	MessageID string
+	// NumberOfTransactions and ControlSum are the group header's declared totals, which are
+	// checked against the instructions when the message is executed.
+	NumberOfTransactions string
+	ControlSum           string
+	Instructions         []Instruction
+}
+
+// Instruction is one credit transfer of a pain.001. The accounts are the Othr/Id account
+// identifications, empty when the account is identified some other way.
+type Instruction struct {
+	PaymentInformationID string
+	InstructionID        string
+	EndToEndID           string
+	DebtorAccount        string
+	CreditorAccount      string
+	Amount               string
+	Currency             string
+	RemittanceInfo       string
+}
+
+// ReadPain001 reads a pain.001.001.09 message. Only the structure is checked here; the
+// contents of the instructions are validated when they are executed.
+func ReadPain001(r io.Reader) (*Pain001, error) {
+	var doc pain001Document
+	if err := xml.NewDecoder(r).Decode(&doc); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidPain001, err)
+	}
+	if doc.XMLName.Space != NamespacePain001 || doc.Initn == nil {
+		return nil, fmt.Errorf("%w: want a CstmrCdtTrfInitn document in namespace %s", ErrInvalidPain001, NamespacePain001)
+	}
+	initn := doc.Initn
+	if initn.GrpHdr.MsgID == "" {
+		return nil, fmt.Errorf("%w: GrpHdr/MsgId is required", ErrInvalidPain001)
+	}
+	if len(initn.PmtInf) == 0 {
+		return nil, fmt.Errorf("%w: at least one PmtInf is required", ErrInvalidPain001)
+	}
+
+	p := &Pain001{
+		MessageID:            initn.GrpHdr.MsgID,
+		NumberOfTransactions: strings.TrimSpace(initn.GrpHdr.NbOfTxs),
+		ControlSum:           strings.TrimSpace(initn.GrpHdr.CtrlSum),
+	}
+	for i, pmt := range initn.PmtInf {
+		if pmt.PmtInfID == "" {
+			return nil, fmt.Errorf("%w: PmtInf %d: PmtInfId is required", ErrInvalidPain001, i+1)
+		}
+		if pmt.PmtMtd != "TRF" {
+			return nil, fmt.Errorf("%w: PmtInf %s: PmtMtd must be TRF", ErrInvalidPain001, pmt.PmtInfID)
+		}
+		if len(pmt.CdtTrfTxInf) == 0 {
+			return nil, fmt.Errorf("%w: PmtInf %s: at least one CdtTrfTxInf is required", ErrInvalidPain001, pmt.PmtInfID)
+		}
+		for _, tx := range pmt.CdtTrfTxInf {
+			if tx.PmtID.EndToEndID == "" {
+				return nil, fmt.Errorf("%w: PmtInf %s: PmtId/EndToEndId is required", ErrInvalidPain001, pmt.PmtInfID)
+			}
+			if tx.Amt.InstdAmt == nil {
+				return nil, fmt.Errorf("%w: transaction %s: Amt/InstdAmt is required", ErrInvalidPain001, tx.PmtID.EndToEndID)
+			}
+			if len(p.Instructions) == MaxPain001Transactions {
+				return nil, fmt.Errorf("%w: more than %d transactions", ErrInvalidPain001, MaxPain001Transactions)
+			}
+			p.Instructions = append(p.Instructions, Instruction{
+				PaymentInformationID: pmt.PmtInfID,
+				InstructionID:        tx.PmtID.InstrID,
+				EndToEndID:           tx.PmtID.EndToEndID,
+				DebtorAccount:        otherID(pmt.DbtrAcct),
+				CreditorAccount:      otherID(tx.CdtrAcct),
+				Amount:               strings.TrimSpace(tx.Amt.InstdAmt.Value),
+				Currency:             tx.Amt.InstdAmt.Ccy,
+				RemittanceInfo:       strings.Join(tx.RmtInf.Ustrd, " "),
+			})
+		}
+	}
+	return p, nil
+}
+
+func otherID(account *cashAccount) string {
+	if account == nil || account.ID.Othr == nil {
+		return ""
+	}
+	return strings.TrimSpace(account.ID.Othr.ID)
+}
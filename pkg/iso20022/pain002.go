@@ -0,0 +1,170 @@
+package iso20022
+
+import (
+	"encoding/xml"
+	"io"
+	"regexp"
+	"strconv"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+type pain002Document struct {
+	XMLName xml.Name      `xml:"urn:iso:std:iso:20022:tech:xsd:pain.002.001.10 Document"`
+	Report  pain002Report `xml:"CstmrPmtStsRpt"`
+}
+
+type pain002Report struct {
+	GrpHdr struct {
+		MsgID   string `xml:"MsgId"`
+		CreDtTm string `xml:"CreDtTm"`
+	} `xml:"GrpHdr"`
+	OrgnlGrpInfAndSts pain002GroupStatus     `xml:"OrgnlGrpInfAndSts"`
+	OrgnlPmtInfAndSts []pain002PaymentStatus `xml:"OrgnlPmtInfAndSts"`
+}
+
+type pain002GroupStatus struct {
+	OrgnlMsgID    string               `xml:"OrgnlMsgId"`
+	OrgnlMsgNmID  string               `xml:"OrgnlMsgNmId"`
+	OrgnlNbOfTxs  string               `xml:"OrgnlNbOfTxs,omitempty"`
+	OrgnlCtrlSum  string               `xml:"OrgnlCtrlSum,omitempty"`
+	GrpSts        string               `xml:"GrpSts"`
+	StsRsnInf     []pain002Reason      `xml:"StsRsnInf"`
+	NbOfTxsPerSts []pain002StatusCount `xml:"NbOfTxsPerSts"`
+}
+
+type pain002PaymentStatus struct {
+	OrgnlPmtInfID string                     `xml:"OrgnlPmtInfId"`
+	OrgnlNbOfTxs  string                     `xml:"OrgnlNbOfTxs"`
+	PmtInfSts     string                     `xml:"PmtInfSts"`
+	TxInfAndSts   []pain002TransactionStatus `xml:"TxInfAndSts"`
+}
+
+type pain002TransactionStatus struct {
+	OrgnlInstrID    string          `xml:"OrgnlInstrId,omitempty"`
+	OrgnlEndToEndID string          `xml:"OrgnlEndToEndId"`
+	TxSts           string          `xml:"TxSts"`
+	StsRsnInf       []pain002Reason `xml:"StsRsnInf"`
+}
+
+type pain002Reason struct {
+	Rsn struct {
+		Cd string `xml:"Cd"`
+	} `xml:"Rsn"`
+	AddtlInf string `xml:"AddtlInf,omitempty"`
+}
+
+type pain002StatusCount struct {
+	DtldNbOfTxs string `xml:"DtldNbOfTxs"`
+	DtldSts     string `xml:"DtldSts"`
+}
+
+var numericText = regexp.MustCompile(`^[0-9]{1,15}$`)
+
+// WritePain002 writes report as a pain.002 customer payment status report with a status per
+// payment information block and per transaction. The group status is ACSC when every transfer
+// was made, RJCT when none was, and PART otherwise.
+func WritePain002(w io.Writer, report *StatusReport, msgID string, createdAt time.Time) error {
+	group := pain002GroupStatus{
+		OrgnlMsgID:   maxText(report.OriginalMessageID, 35),
+		OrgnlMsgNmID: MessageNamePain001,
+		GrpSts:       report.GroupStatus,
+	}
+	if numericText.MatchString(report.OriginalNumberOfTransactions) {
+		group.OrgnlNbOfTxs = report.OriginalNumberOfTransactions
+	}
+	if _, err := decimal.NewFromString(report.OriginalControlSum); err == nil {
+		group.OrgnlCtrlSum = report.OriginalControlSum
+	}
+	if report.GroupReason != "" {
+		group.StsRsnInf = []pain002Reason{reason(report.GroupReason, report.GroupInfo)}
+	}
+
+	var payments []pain002PaymentStatus
+	index := map[string]int{}
+	counts := map[string]int{}
+	var statuses []string
+	for _, tx := range report.Transactions {
+		i, ok := index[tx.PaymentInformationID]
+		if !ok {
+			i = len(payments)
+			index[tx.PaymentInformationID] = i
+			payments = append(payments, pain002PaymentStatus{OrgnlPmtInfID: maxText(tx.PaymentInformationID, 35)})
+		}
+		status := pain002TransactionStatus{
+			OrgnlInstrID:    maxText(tx.InstructionID, 35),
+			OrgnlEndToEndID: maxText(tx.EndToEndID, 35),
+			TxSts:           tx.Status,
+		}
+		if tx.Reason != "" {
+			status.StsRsnInf = []pain002Reason{reason(tx.Reason, tx.Info)}
+		}
+		payments[i].TxInfAndSts = append(payments[i].TxInfAndSts, status)
+		if counts[tx.Status] == 0 {
+			statuses = append(statuses, tx.Status)
+		}
+		counts[tx.Status]++
+	}
+	for i := range payments {
+		var txStatuses []string
+		for _, tx := range payments[i].TxInfAndSts {
+			txStatuses = append(txStatuses, tx.TxSts)
+		}
+		payments[i].OrgnlNbOfTxs = strconv.Itoa(len(txStatuses))
+		payments[i].PmtInfSts = overallStatus(txStatuses)
+	}
+	if group.GrpSts == "" {
+		var all []string
+		for _, tx := range report.Transactions {
+			all = append(all, tx.Status)
+		}
+		group.GrpSts = overallStatus(all)
+	}
+	for _, status := range statuses {
+		group.NbOfTxsPerSts = append(group.NbOfTxsPerSts, pain002StatusCount{
+			DtldNbOfTxs: strconv.Itoa(counts[status]),
+			DtldSts:     status,
+		})
+	}
+
+	doc := pain002Document{Report: pain002Report{
+		OrgnlGrpInfAndSts: group,
+		OrgnlPmtInfAndSts: payments,
+	}}
+	doc.Report.GrpHdr.MsgID = maxText(msgID, 35)
+	doc.Report.GrpHdr.CreDtTm = dateTime(createdAt)
+	return writeDocument(w, doc)
+}
+
+// overallStatus summarises transaction statuses.
+func overallStatus(statuses []string) string {
+	accepted := 0
+	for _, s := range statuses {
+		if s == StatusAcceptedSettlementCompleted {
+			accepted++
+		}
+	}
+	switch accepted {
+	case len(statuses):
+		return StatusAcceptedSettlementCompleted
+	case 0:
+		return StatusRejected
+	}
+	return StatusPartiallyAccepted
+}
+
+func reason(code, info string) pain002Reason {
+	r := pain002Reason{AddtlInf: maxText(info, 105)}
+	r.Rsn.Cd = code
+	return r
+}
+
+// maxText cuts s to the n characters a MaxNText allows.
+func maxText(s string, n int) string {
+	runes := []rune(s)
+	if len(runes) <= n {
+		return s
+	}
+	return string(runes[:n])
+}
@@ -0,0 +1,410 @@
+package iso20022
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"math/big"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// schema validates documents against the XML Schema subsets in testdata. It implements what
+// those schemas use: global complex and simple types, sequences and choices of elements with
+// occurrence bounds, simple content with attributes, and the string, decimal, boolean, date
+// and dateTime facets ISO 20022 relies on.
+type schema struct {
+	namespace string
+	root      xsdElement
+	complex   map[string]xsdComplexType
+	simple    map[string]xsdSimpleType
+}
+
+type xsdFile struct {
+	TargetNamespace string           `xml:"targetNamespace,attr"`
+	Elements        []xsdElement     `xml:"element"`
+	ComplexTypes    []xsdComplexType `xml:"complexType"`
+	SimpleTypes     []xsdSimpleType  `xml:"simpleType"`
+}
+
+type xsdElement struct {
+	Name      string `xml:"name,attr"`
+	Type      string `xml:"type,attr"`
+	MinOccurs string `xml:"minOccurs,attr"`
+	MaxOccurs string `xml:"maxOccurs,attr"`
+}
+
+type xsdComplexType struct {
+	Name     string       `xml:"name,attr"`
+	Sequence []xsdElement `xml:"sequence>element"`
+	Choice   []xsdElement `xml:"choice>element"`
+	Simple   *struct {
+		Base       string `xml:"base,attr"`
+		Attributes []struct {
+			Name string `xml:"name,attr"`
+			Type string `xml:"type,attr"`
+			Use  string `xml:"use,attr"`
+		} `xml:"attribute"`
+	} `xml:"simpleContent>extension"`
+}
+
+type xsdSimpleType struct {
+	Name        string `xml:"name,attr"`
+	Restriction struct {
+		Base   string     `xml:"base,attr"`
+		Facets []xsdFacet `xml:",any"`
+	} `xml:"restriction"`
+}
+
+type xsdFacet struct {
+	XMLName xml.Name
+	Value   string `xml:"value,attr"`
+}
+
+func loadSchema(t *testing.T, path string) *schema {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var f xsdFile
+	if err := xml.Unmarshal(data, &f); err != nil {
+		t.Fatalf("%s: %v", path, err)
+	}
+	if len(f.Elements) != 1 {
+		t.Fatalf("%s: want one global element, got %d", path, len(f.Elements))
+	}
+	s := &schema{
+		namespace: f.TargetNamespace,
+		root:      f.Elements[0],
+		complex:   map[string]xsdComplexType{},
+		simple:    map[string]xsdSimpleType{},
+	}
+	for _, c := range f.ComplexTypes {
+		s.complex[c.Name] = c
+	}
+	for _, st := range f.SimpleTypes {
+		s.simple[st.Name] = st
+	}
+	return s
+}
+
+// node is a parsed instance element.
+type node struct {
+	name     xml.Name
+	attrs    []xml.Attr
+	children []*node
+	text     strings.Builder
+}
+
+func parseInstance(r io.Reader) (*node, error) {
+	dec := xml.NewDecoder(r)
+	var stack []*node
+	var root *node
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		switch tok := tok.(type) {
+		case xml.StartElement:
+			n := &node{name: tok.Name, attrs: tok.Attr}
+			if len(stack) == 0 {
+				root = n
+			} else {
+				parent := stack[len(stack)-1]
+				parent.children = append(parent.children, n)
+			}
+			stack = append(stack, n)
+		case xml.EndElement:
+			stack = stack[:len(stack)-1]
+		case xml.CharData:
+			if len(stack) > 0 {
+				stack[len(stack)-1].text.Write(tok)
+			}
+		}
+	}
+	if root == nil {
+		return nil, fmt.Errorf("no root element")
+	}
+	return root, nil
+}
+
+// validate returns every violation of the schema in the document read from r.
+func (s *schema) validate(r io.Reader) []string {
+	root, err := parseInstance(r)
+	if err != nil {
+		return []string{err.Error()}
+	}
+	var errs []string
+	if root.name.Space != s.namespace || root.name.Local != s.root.Name {
+		return []string{fmt.Sprintf("root element {%s}%s, want {%s}%s", root.name.Space, root.name.Local, s.namespace, s.root.Name)}
+	}
+	s.element(root, s.root.Type, "/"+root.name.Local, &errs)
+	return errs
+}
+
+func (s *schema) element(n *node, typ, path string, errs *[]string) {
+	fail := func(format string, args ...any) {
+		*errs = append(*errs, path+": "+fmt.Sprintf(format, args...))
+	}
+	for _, c := range n.children {
+		if c.name.Space != s.namespace {
+			fail("element %s is not in namespace %s", c.name.Local, s.namespace)
+			return
+		}
+	}
+	ct, ok := s.complex[typ]
+	if !ok {
+		if len(n.children) > 0 {
+			fail("simple element has child elements")
+			return
+		}
+		s.checkAttributes(n, nil, fail)
+		if err := s.value(typ, n.text.String()); err != nil {
+			fail("%v", err)
+		}
+		return
+	}
+
+	if ct.Simple != nil {
+		if len(n.children) > 0 {
+			fail("simple content has child elements")
+			return
+		}
+		declared := map[string]string{}
+		for _, a := range ct.Simple.Attributes {
+			declared[a.Name] = a.Type
+			if a.Use == "required" && attr(n, a.Name) == nil {
+				fail("attribute %s is required", a.Name)
+			}
+		}
+		s.checkAttributes(n, declared, fail)
+		if err := s.value(ct.Simple.Base, n.text.String()); err != nil {
+			fail("%v", err)
+		}
+		return
+	}
+
+	s.checkAttributes(n, nil, fail)
+	if strings.TrimSpace(n.text.String()) != "" {
+		fail("complex element has text")
+	}
+	children := n.children
+	if len(ct.Choice) > 0 {
+		if len(children) != 1 {
+			fail("choice needs exactly one element, got %d", len(children))
+			return
+		}
+		for _, option := range ct.Choice {
+			if option.Name == children[0].name.Local {
+				s.element(children[0], option.Type, path+"/"+option.Name, errs)
+				return
+			}
+		}
+		fail("element %s is not one of the choices", children[0].name.Local)
+		return
+	}
+	for _, particle := range ct.Sequence {
+		count := 0
+		for len(children) > 0 && children[0].name.Local == particle.Name {
+			if count == maxOccurs(particle) {
+				break
+			}
+			count++
+			s.element(children[0], particle.Type, path+"/"+particle.Name, errs)
+			children = children[1:]
+		}
+		if count < minOccurs(particle) {
+			fail("missing element %s", particle.Name)
+		}
+	}
+	for _, c := range children {
+		fail("unexpected element %s", c.name.Local)
+	}
+}
+
+func (s *schema) checkAttributes(n *node, declared map[string]string, fail func(string, ...any)) {
+	for _, a := range n.attrs {
+		if a.Name.Space == "xmlns" || a.Name.Space == "" && a.Name.Local == "xmlns" {
+			continue
+		}
+		typ, ok := declared[a.Name.Local]
+		if !ok || a.Name.Space != "" {
+			fail("undeclared attribute %s", a.Name.Local)
+			continue
+		}
+		if err := s.value(typ, a.Value); err != nil {
+			fail("attribute %s: %v", a.Name.Local, err)
+		}
+	}
+}
+
+func attr(n *node, name string) *xml.Attr {
+	for i := range n.attrs {
+		if n.attrs[i].Name.Space == "" && n.attrs[i].Name.Local == name {
+			return &n.attrs[i]
+		}
+	}
+	return nil
+}
+
+func minOccurs(e xsdElement) int {
+	if e.MinOccurs == "" {
+		return 1
+	}
+	n, _ := strconv.Atoi(e.MinOccurs)
+	return n
+}
+
+func maxOccurs(e xsdElement) int {
+	switch e.MaxOccurs {
+	case "":
+		return 1
+	case "unbounded":
+		return -1
+	}
+	n, _ := strconv.Atoi(e.MaxOccurs)
+	return n
+}
+
+var xsdDecimal = regexp.MustCompile(`^[+-]?([0-9]+(\.[0-9]*)?|\.[0-9]+)$`)
+
+// value checks raw against a simple type, applying the facets of its restriction.
+func (s *schema) value(typ, raw string) error {
+	switch typ {
+	case "xs:string":
+		return nil
+	case "xs:decimal":
+		if !xsdDecimal.MatchString(strings.TrimSpace(raw)) {
+			return fmt.Errorf("%q is not a decimal", raw)
+		}
+		return nil
+	case "xs:boolean":
+		switch strings.TrimSpace(raw) {
+		case "true", "false", "1", "0":
+			return nil
+		}
+		return fmt.Errorf("%q is not a boolean", raw)
+	case "xs:date":
+		if _, err := time.Parse("2006-01-02", strings.TrimSpace(raw)); err != nil {
+			return fmt.Errorf("%q is not a date", raw)
+		}
+		return nil
+	case "xs:dateTime":
+		raw = strings.TrimSpace(raw)
+		for _, layout := range []string{time.RFC3339Nano, "2006-01-02T15:04:05.999999999"} {
+			if _, err := time.Parse(layout, raw); err == nil {
+				return nil
+			}
+		}
+		return fmt.Errorf("%q is not a dateTime", raw)
+	}
+
+	st, ok := s.simple[typ]
+	if !ok {
+		return fmt.Errorf("unknown type %s", typ)
+	}
+	base := st.Restriction.Base
+	if err := s.value(base, raw); err != nil {
+		return err
+	}
+	if base != "xs:string" {
+		raw = strings.TrimSpace(raw)
+	}
+	var enumeration []string
+	for _, f := range st.Restriction.Facets {
+		limit, _ := strconv.Atoi(f.Value)
+		switch f.XMLName.Local {
+		case "enumeration":
+			enumeration = append(enumeration, f.Value)
+		case "pattern":
+			if !regexp.MustCompile(`^(?:` + f.Value + `)$`).MatchString(raw) {
+				return fmt.Errorf("%q does not match %s", raw, f.Value)
+			}
+		case "minLength":
+			if len([]rune(raw)) < limit {
+				return fmt.Errorf("%q is shorter than %d", raw, limit)
+			}
+		case "maxLength":
+			if len([]rune(raw)) > limit {
+				return fmt.Errorf("%q is longer than %d", raw, limit)
+			}
+		case "fractionDigits":
+			if _, fraction, ok := strings.Cut(raw, "."); ok && len(strings.TrimRight(fraction, "0")) > limit {
+				return fmt.Errorf("%q has more than %d fraction digits", raw, limit)
+			}
+		case "totalDigits":
+			whole, fraction, _ := strings.Cut(strings.TrimLeft(raw, "+-"), ".")
+			digits := len(strings.TrimLeft(whole, "0")) + len(strings.TrimRight(fraction, "0"))
+			if digits > limit {
+				return fmt.Errorf("%q has more than %d digits", raw, limit)
+			}
+		case "minInclusive":
+			v, _ := new(big.Rat).SetString(raw)
+			min, _ := new(big.Rat).SetString(f.Value)
+			if v == nil || v.Cmp(min) < 0 {
+				return fmt.Errorf("%q is less than %s", raw, f.Value)
+			}
+		default:
+			return fmt.Errorf("unsupported facet %s", f.XMLName.Local)
+		}
+	}
+	if len(enumeration) > 0 {
+		for _, e := range enumeration {
+			if raw == e {
+				return nil
+			}
+		}
+		return fmt.Errorf("%q is not one of %v", raw, enumeration)
+	}
+	return nil
+}
+
+// assertValid fails the test for every schema violation in doc.
+func assertValid(t *testing.T, s *schema, doc []byte) {
+	t.Helper()
+	for _, err := range s.validate(strings.NewReader(string(doc))) {
+		t.Error(err)
+	}
+}
+
+func TestSchemaValidatorRejectsInvalidDocuments(t *testing.T) {
+	pain001 := loadSchema(t, "testdata/pain.001.001.09.xsd")
+	valid, err := os.ReadFile("testdata/pain.001.sample.xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertValid(t, pain001, valid)
+
+	for name, edit := range map[string][2]string{
+		"missing element": {"<EndToEndId>E2E-0002</EndToEndId>", ""},
+		"unknown element": {"<ChrgBr>SLEV</ChrgBr>", "<ChrgBr>SLEV</ChrgBr><Foo/>"},
+		"out of order":    {"<PmtMtd>TRF</PmtMtd>\n      <BtchBookg>false</BtchBookg>", "<BtchBookg>false</BtchBookg><PmtMtd>TRF</PmtMtd>"},
+		"enumeration":     {"<PmtMtd>TRF</PmtMtd>", "<PmtMtd>WIRE</PmtMtd>"},
+		"pattern":         {`Ccy="USD">250.25`, `Ccy="usd">250.25`},
+		"fraction digits": {">250.25<", ">250.123456<"},
+		"negative amount": {">250.25<", ">-250.25<"},
+		"max length":      {"<MsgId>PAYROLL-2026-10</MsgId>", "<MsgId>" + strings.Repeat("X", 36) + "</MsgId>"},
+		"date time":       {"<CreDtTm>2026-10-19T09:30:00Z</CreDtTm>", "<CreDtTm>19/10/2026</CreDtTm>"},
+		"choice":          {"<Dt>2026-10-19</Dt>", "<Dt>2026-10-19</Dt><DtTm>2026-10-19T00:00:00Z</DtTm>"},
+		"missing attr":    {`<InstdAmt Ccy="USD">250.25`, `<InstdAmt>250.25`},
+		"wrong namespace": {"pain.001.001.09", "pain.001.001.03"},
+		"numeric text":    {"<NbOfTxs>6</NbOfTxs>", "<NbOfTxs>six</NbOfTxs>"},
+	} {
+		doc := strings.Replace(string(valid), edit[0], edit[1], 1)
+		if doc == string(valid) {
+			t.Fatalf("%s: edit did not apply", name)
+		}
+		if errs := pain001.validate(strings.NewReader(doc)); len(errs) == 0 {
+			t.Errorf("%s: document validated", name)
+		}
+	}
+}
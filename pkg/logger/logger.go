@@ -0,0 +1,149 @@
+// Package logger provides the service's structured slog loggers.
+//
+// Every logger writes through one JSON (or text) handler configured by Init. Loggers are obtained
+// per package with For, so levels can be tuned per package, and they add any fields stored on the
+// context with WithFields, such as the request ID, route and account IDs. Values whose keys are
+// listed in the redaction policy are masked before they are written.
+package logger
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/rohanchauhan02/internal-transfer/pkg/config"
+)
+
+// Default redaction policy, used until Init is called or when the config leaves it empty.
+var (
+	defaultMaskKeys    = []string{"amount", "balance", "initial_balance", "key", "secret", "password"}
+	defaultPartialKeys = []string{"account_id", "source_account_id", "destination_account_id", "target_account_id"}
+)
+
+type state struct {
+	handler       slog.Handler
+	level         slog.Level
+	packageLevels map[string]slog.Level
+	policy        policy
+}
+
+var (
+	mu      sync.RWMutex
+	current = newState(config.Logging{}, os.Stdout)
+)
+
+// Init configures the shared handler, levels and redaction policy, and installs the "app"
+// logger as the slog default so stray slog calls follow the same format.
+func Init(conf config.Logging) {
+	InitTo(conf, os.Stdout)
+}
+
+// InitTo is Init writing to w instead of stdout, for commands whose stdout is their output.
+func InitTo(conf config.Logging, w io.Writer) {
+	st := newState(conf, w)
+	mu.Lock()
+	current = st
+	mu.Unlock()
+	slog.SetDefault(For("app"))
+}
+
+func newState(conf config.Logging, w io.Writer) *state {
+	st := &state{
+		level:         parseLevel(conf.Level, slog.LevelInfo),
+		packageLevels: make(map[string]slog.Level, len(conf.PackageLevels)),
+		policy:        newPolicy(conf.Redaction),
+	}
+	for _, pl := range conf.PackageLevels {
+		st.packageLevels[pl.Package] = parseLevel(pl.Level, st.level)
+	}
+
+	// The handler itself accepts everything; packageHandler decides what is enabled.
+	opts := &slog.HandlerOptions{Level: slog.LevelDebug, ReplaceAttr: st.policy.replaceAttr}
+	if strings.EqualFold(conf.Format, "text") {
+		st.handler = slog.NewTextHandler(w, opts)
+	} else {
+		st.handler = slog.NewJSONHandler(w, opts)
+	}
+	return st
+}
+
+func load() *state {
+	mu.RLock()
+	defer mu.RUnlock()
+	return current
+}
+
+// For returns the logger for a package. It may be called before Init, e.g. from package
+// level variables: the configuration is resolved on every call.
+func For(pkg string) *slog.Logger {
+	return slog.New(&packageHandler{pkg: pkg}).With(slog.String("logger", pkg))
+}
+
+// Redact applies the redaction policy to an arbitrary value, such as a decoded request payload.
+func Redact(v any) any {
+	return load().policy.redactValue("", v)
+}
+
+func parseLevel(s string, fallback slog.Level) slog.Level {
+	var level slog.Level
+	if s == "" || level.UnmarshalText([]byte(s)) != nil {
+		return fallback
+	}
+	return level
+}
+
+type fieldsKey struct{}
+
+// WithFields returns a copy of ctx whose log lines carry attrs in addition to any fields already set.
+func WithFields(ctx context.Context, attrs ...slog.Attr) context.Context {
+	existing, _ := ctx.Value(fieldsKey{}).([]slog.Attr)
+	merged := make([]slog.Attr, 0, len(existing)+len(attrs))
+	merged = append(merged, existing...)
+	merged = append(merged, attrs...)
+	return context.WithValue(ctx, fieldsKey{}, merged)
+}
+
+// packageHandler resolves the shared handler and package level at log time and adds context fields.
+type packageHandler struct {
+	pkg string
+	ops []func(slog.Handler) slog.Handler
+}
+
+func (h *packageHandler) Enabled(_ context.Context, level slog.Level) bool {
+	st := load()
+	min, ok := st.packageLevels[h.pkg]
+	if !ok {
+		min = st.level
+	}
+	return level >= min
+}
+
+func (h *packageHandler) Handle(ctx context.Context, r slog.Record) error {
+	if fields, ok := ctx.Value(fieldsKey{}).([]slog.Attr); ok {
+		r = r.Clone()
+		r.AddAttrs(fields...)
+	}
+	handler := load().handler
+	for _, op := range h.ops {
+		handler = op(handler)
+	}
+	return handler.Handle(ctx, r)
+}
+
+func (h *packageHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return h.with(func(next slog.Handler) slog.Handler { return next.WithAttrs(attrs) })
+}
+
+func (h *packageHandler) WithGroup(name string) slog.Handler {
+	return h.with(func(next slog.Handler) slog.Handler { return next.WithGroup(name) })
+}
+
+func (h *packageHandler) with(op func(slog.Handler) slog.Handler) slog.Handler {
+	ops := make([]func(slog.Handler) slog.Handler, 0, len(h.ops)+1)
+	ops = append(ops, h.ops...)
+	ops = append(ops, op)
+	return &packageHandler{pkg: h.pkg, ops: ops}
+}
@@ -0,0 +1,77 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"testing"
+
+	"github.com/rohanchauhan02/internal-transfer/pkg/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func useState(t *testing.T, conf config.Logging) *bytes.Buffer {
+	t.Helper()
+	buf := &bytes.Buffer{}
+	st := newState(conf, buf)
+	mu.Lock()
+	prev := current
+	current = st
+	mu.Unlock()
+	t.Cleanup(func() {
+		mu.Lock()
+		current = prev
+		mu.Unlock()
+	})
+	return buf
+}
+
+func decodeLine(t *testing.T, buf *bytes.Buffer) map[string]any {
+	t.Helper()
+	var line map[string]any
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &line))
+	return line
+}
+
+func TestLogger_RedactsAndAddsContextFields(t *testing.T) {
+	buf := useState(t, config.Logging{})
+
+	ctx := WithFields(context.Background(), slog.String("request_id", "req-1"), slog.Int("account_id", 12345))
+	For("banking").InfoContext(ctx, "transfer finished", slog.String("amount", "100.50"))
+
+	line := decodeLine(t, buf)
+	assert.Equal(t, "banking", line["logger"])
+	assert.Equal(t, "req-1", line["request_id"])
+	assert.Equal(t, "***45", line["account_id"])
+	assert.Equal(t, Masked, line["amount"])
+}
+
+func TestLogger_PackageLevels(t *testing.T) {
+	buf := useState(t, config.Logging{
+		Level:         "warn",
+		PackageLevels: []config.PackageLevel{{Package: "banking", Level: "debug"}},
+	})
+
+	For("http").Info("dropped")
+	assert.Zero(t, buf.Len())
+
+	For("banking").Debug("kept")
+	assert.Equal(t, "kept", decodeLine(t, buf)["msg"])
+}
+
+func TestRedact_NestedPayload(t *testing.T) {
+	useState(t, config.Logging{Redaction: config.Redaction{MaskKeys: []string{"balance"}, PartialKeys: []string{"account_id"}}})
+
+	got := Redact(map[string]any{
+		"account_id": float64(987),
+		"balance":    "10.00",
+		"items":      []any{map[string]any{"balance": "1"}},
+	})
+	assert.Equal(t, map[string]any{
+		"account_id": "*87",
+		"balance":    Masked,
+		"items":      []any{map[string]any{"balance": Masked}},
+	}, got)
+}
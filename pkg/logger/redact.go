@@ -0,0 +1,82 @@
+package logger
+
+import (
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/rohanchauhan02/internal-transfer/pkg/config"
+)
+
+// Masked replaces values whose keys are in the mask list.
+const Masked = "[REDACTED]"
+
+type policy struct {
+	mask    map[string]bool
+	partial map[string]bool
+}
+
+func newPolicy(conf config.Redaction) policy {
+	maskKeys, partialKeys := conf.MaskKeys, conf.PartialKeys
+	if len(maskKeys) == 0 {
+		maskKeys = defaultMaskKeys
+	}
+	if len(partialKeys) == 0 {
+		partialKeys = defaultPartialKeys
+	}
+	p := policy{mask: make(map[string]bool), partial: make(map[string]bool)}
+	for _, k := range maskKeys {
+		p.mask[strings.ToLower(k)] = true
+	}
+	for _, k := range partialKeys {
+		p.partial[strings.ToLower(k)] = true
+	}
+	return p
+}
+
+func (p policy) replaceAttr(_ []string, a slog.Attr) slog.Attr {
+	key := strings.ToLower(a.Key)
+	switch {
+	case p.mask[key]:
+		return slog.String(a.Key, Masked)
+	case p.partial[key]:
+		return slog.String(a.Key, maskPartial(a.Value.Resolve().String()))
+	case a.Value.Kind() == slog.KindAny:
+		return slog.Any(a.Key, p.redactValue(a.Key, a.Value.Any()))
+	}
+	return a
+}
+
+// redactValue walks maps and slices decoded from JSON, masking values by their keys.
+func (p policy) redactValue(key string, v any) any {
+	lower := strings.ToLower(key)
+	switch val := v.(type) {
+	case map[string]any:
+		out := make(map[string]any, len(val))
+		for k, inner := range val {
+			out[k] = p.redactValue(k, inner)
+		}
+		return out
+	case []any:
+		out := make([]any, len(val))
+		for i, inner := range val {
+			out[i] = p.redactValue(key, inner)
+		}
+		return out
+	}
+	switch {
+	case p.mask[lower]:
+		return Masked
+	case p.partial[lower]:
+		return maskPartial(fmt.Sprint(v))
+	}
+	return v
+}
+
+// maskPartial keeps the last two characters so operators can still correlate lines.
+func maskPartial(s string) string {
+	if len(s) <= 2 {
+		return strings.Repeat("*", len(s))
+	}
+	return strings.Repeat("*", len(s)-2) + s[len(s)-2:]
+}
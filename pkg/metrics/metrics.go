@@ -0,0 +1,100 @@
+// Package metrics defines the Prometheus metrics exposed on /metrics.
+package metrics
+
+import (
+	"database/sql"
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "internal_transfer"
+
+// Transfer outcomes used as the "outcome" label.
+const (
+	OutcomeSuccess           = "success"
+	OutcomeInsufficientFunds = "insufficient_funds"
+	OutcomeInvalid           = "invalid"
+	OutcomeRejected          = "rejected"
+	OutcomeError             = "error"
+)
+
+// Registry holds every metric of the service. A dedicated registry keeps tests independent
+// of whatever other packages register on prometheus.DefaultRegisterer.
+var Registry = prometheus.NewRegistry()
+
+var (
+	HTTPRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "http_request_duration_seconds",
+		Help:      "HTTP request latency by method, route and status code.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "route", "status"})
+
+	TransfersTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "transfers_total",
+		Help:      "Transfers attempted, by outcome.",
+	}, []string{"outcome"})
+
+	TransferAmountTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "transfer_amount_total",
+		Help:      "Sum of transfer amounts, by outcome.",
+	}, []string{"outcome"})
+
+	InsufficientFundsTotal = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "insufficient_funds_rejections_total",
+		Help:      "Transfers rejected because the source balance was too low.",
+	})
+
+	LockWaitDuration = prometheus.NewHistogram(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "account_lock_wait_seconds",
+		Help:      "Time spent acquiring an account row lock inside a transfer.",
+		Buckets:   []float64{.0005, .001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5},
+	})
+
+	TransactionDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "transfer_transaction_duration_seconds",
+		Help:      "Duration of the database transaction backing a transfer, by outcome.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"outcome"})
+
+	// AuditWriteFailuresTotal counts requests whose audit record could not be written. Records are
+	// written after the request's own changes have committed, so each one is a change missing
+	// from the audit trail.
+	AuditWriteFailuresTotal = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "audit_write_failures_total",
+		Help:      "Audit records that could not be written.",
+	})
+)
+
+func init() {
+	Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		HTTPRequestDuration,
+		TransfersTotal,
+		TransferAmountTotal,
+		InsufficientFundsTotal,
+		LockWaitDuration,
+		TransactionDuration,
+		AuditWriteFailuresTotal,
+	)
+}
+
+// RegisterDBStats exposes the sql.DBStats of the connection pool under dbName.
+func RegisterDBStats(db *sql.DB, dbName string) error {
+	return Registry.Register(collectors.NewDBStatsCollector(db, dbName))
+}
+
+// Handler serves the registry in the Prometheus text exposition format.
+func Handler() http.Handler {
+	return promhttp.HandlerFor(Registry, promhttp.HandlerOpts{Registry: Registry})
+}
@@ -0,0 +1,53 @@
+package metrics
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/prometheus/common/expfmt"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHandler_ScrapesTextFormat(t *testing.T) {
+	db, _, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to open sqlmock database: %v", err)
+	}
+	defer db.Close()
+	assert.NoError(t, RegisterDBStats(db, "scrape_test"))
+
+	TransfersTotal.WithLabelValues(OutcomeSuccess).Inc()
+	TransferAmountTotal.WithLabelValues(OutcomeSuccess).Add(12.5)
+	InsufficientFundsTotal.Inc()
+	LockWaitDuration.Observe(0.002)
+	HTTPRequestDuration.WithLabelValues(http.MethodPost, "/api/v1/transactions", "200").Observe(0.01)
+
+	srv := httptest.NewServer(Handler())
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL)
+	if err != nil {
+		t.Fatalf("failed to scrape metrics: %v", err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	assert.NoError(t, err)
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Contains(t, resp.Header.Get("Content-Type"), "text/plain")
+	assert.Contains(t, string(body), `internal_transfer_transfers_total{outcome="success"} 1`)
+	assert.Contains(t, string(body), `internal_transfer_transfer_amount_total{outcome="success"} 12.5`)
+	assert.Contains(t, string(body), `internal_transfer_insufficient_funds_rejections_total 1`)
+	assert.Contains(t, string(body), `internal_transfer_account_lock_wait_seconds_count 1`)
+	assert.Contains(t, string(body), `internal_transfer_http_request_duration_seconds_count{method="POST",route="/api/v1/transactions",status="200"} 1`)
+	assert.Contains(t, string(body), `go_sql_open_connections{db_name="scrape_test"}`)
+
+	// The whole exposition must parse as Prometheus text format.
+	var parser expfmt.TextParser
+	_, err = parser.TextToMetricFamilies(bytes.NewReader(body))
+	assert.NoError(t, err)
+}
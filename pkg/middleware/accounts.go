@@ -0,0 +1,57 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+// contextKeyAccountIDs caches the parsed account IDs so the body is only peeked at once per request.
+const contextKeyAccountIDs = "request_account_ids"
+
+// accountBodyRoutes are the routes whose JSON body names the accounts they act on. Bodies of
+// other routes are never read here.
+var accountBodyRoutes = map[string]bool{
+	"POST /api/v1/accounts":     true,
+	"POST /api/v1/transactions": true,
+}
+
+// accountIDs are the accounts a request acts on, taken from the path or the JSON body.
+type accountIDs struct {
+	AccountID            *int `json:"account_id"`
+	SourceAccountID      *int `json:"source_account_id"`
+	DestinationAccountID *int `json:"destination_account_id"`
+}
+
+// requestAccountIDs reads the account IDs of a request from the :id path parameter or, on
+// accountBodyRoutes, the JSON body. At most limit bytes are buffered: a larger body is put
+// back untouched for the handler, or the middleware enforcing the limit, to reject.
+func requestAccountIDs(c echo.Context, limit int64) accountIDs {
+	if ids, ok := c.Get(contextKeyAccountIDs).(accountIDs); ok {
+		return ids
+	}
+
+	var ids accountIDs
+	req := c.Request()
+	if param := c.Param("id"); param != "" {
+		if id, err := strconv.Atoi(param); err == nil {
+			ids.AccountID = &id
+		}
+	} else if req.Body != nil && accountBodyRoutes[req.Method+" "+c.Path()] &&
+		strings.HasPrefix(req.Header.Get(echo.HeaderContentType), echo.MIMEApplicationJSON) {
+		body, err := io.ReadAll(io.LimitReader(req.Body, limit+1))
+		req.Body = struct {
+			io.Reader
+			io.Closer
+		}{io.MultiReader(bytes.NewReader(body), req.Body), req.Body}
+		if err == nil && int64(len(body)) <= limit {
+			_ = json.Unmarshal(body, &ids)
+		}
+	}
+	c.Set(contextKeyAccountIDs, ids)
+	return ids
+}
@@ -0,0 +1,41 @@
+package middleware
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRequestAccountIDs(t *testing.T) {
+	const transfer = `{"source_account_id":1,"destination_account_id":2,"amount":"10"}`
+	e := echo.New()
+	peek := func(path, contentType, body string, limit int64) (accountIDs, string) {
+		req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, contentType)
+		c := e.NewContext(req, httptest.NewRecorder())
+		c.SetPath(path)
+		ids := requestAccountIDs(c, limit)
+		rest, err := io.ReadAll(c.Request().Body)
+		require.NoError(t, err)
+		return ids, string(rest)
+	}
+
+	ids, body := peek("/api/v1/transactions", echo.MIMEApplicationJSON, transfer, 1024)
+	require.NotNil(t, ids.SourceAccountID)
+	assert.Equal(t, 1, *ids.SourceAccountID)
+	assert.Equal(t, transfer, body, "the handler still reads the whole body")
+
+	ids, body = peek("/api/v1/transactions", echo.MIMEApplicationJSON, transfer, 16)
+	assert.Nil(t, ids.SourceAccountID, "bodies over the limit are not parsed")
+	assert.Equal(t, transfer, body)
+
+	ids, body = peek("/api/v1/accounts/import", "text/csv", "account_id\n1\n", 1024)
+	assert.Nil(t, ids.AccountID, "only account and transfer bodies are read")
+	assert.Equal(t, "account_id\n1\n", body)
+}
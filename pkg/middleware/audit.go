@@ -0,0 +1,75 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+	"github.com/rohanchauhan02/internal-transfer/domain/apikey"
+	"github.com/rohanchauhan02/internal-transfer/domain/audit"
+	"github.com/rohanchauhan02/internal-transfer/pkg/ctx"
+)
+
+// MiddlewareAudit writes every state-changing request to the audit trail once it has been handled.
+// Read-only methods are not audited.
+func MiddlewareAudit(usecase audit.Usecase) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			req := c.Request()
+			switch req.Method {
+			case http.MethodGet, http.MethodHead, http.MethodOptions:
+				return next(c)
+			}
+
+			entry := &audit.Entry{}
+			c.SetRequest(req.WithContext(audit.WithEntry(req.Context(), entry)))
+
+			handlerErr := next(c)
+
+			event := audit.Event{
+				Actor:      actor(c),
+				RequestID:  req.Header.Get(echo.HeaderXRequestID),
+				Method:     req.Method,
+				Route:      c.Path(),
+				StatusCode: c.Response().Status,
+				Entry:      entry,
+			}
+			if msg, ok := c.Get(ctx.ContextKeyErrorMessage).(string); ok {
+				event.Error = msg
+			}
+			if handlerErr != nil {
+				event.Error = handlerErr.Error()
+				var httpErr *echo.HTTPError
+				if errors.As(handlerErr, &httpErr) {
+					event.StatusCode = httpErr.Code
+				} else {
+					event.StatusCode = http.StatusInternalServerError
+				}
+			}
+
+			// The record must be written even if the client has already gone away.
+			if err := usecase.Record(context.WithoutCancel(req.Context()), event); err != nil {
+				httpLog.ErrorContext(req.Context(), "failed to write audit record", slog.String("error", err.Error()))
+			}
+			return handlerErr
+		}
+	}
+}
+
+// actor describes who made the request from the api key principal and any verified signing client.
+func actor(c echo.Context) string {
+	name := "anonymous"
+	if principal, ok := c.Get(ContextKeyPrincipal).(apikey.Principal); ok {
+		name = principal.Name
+		if principal.KeyID != 0 {
+			name = "apikey:" + strconv.FormatUint(uint64(principal.KeyID), 10) + ":" + principal.Name
+		}
+	}
+	if client, ok := c.Get(ContextKeySigningClient).(string); ok && client != "" {
+		name += " via client:" + client
+	}
+	return name
+}
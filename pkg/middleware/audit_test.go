@@ -0,0 +1,180 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/rohanchauhan02/internal-transfer/domain/apikey"
+	"github.com/rohanchauhan02/internal-transfer/domain/audit"
+	"github.com/rohanchauhan02/internal-transfer/dto"
+	"github.com/rohanchauhan02/internal-transfer/pkg/ctx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingAuditUsecase keeps the events MiddlewareAudit hands to Record.
+type recordingAuditUsecase struct {
+	events []audit.Event
+	err    error
+}
+
+func (u *recordingAuditUsecase) Record(_ context.Context, event audit.Event) error {
+	u.events = append(u.events, event)
+	return u.err
+}
+
+func (u *recordingAuditUsecase) Query(context.Context, audit.Filter) ([]dto.AuditRecordResponse, error) {
+	return nil, nil
+}
+
+func (u *recordingAuditUsecase) Export(context.Context, audit.Filter, io.Writer) error {
+	return nil
+}
+
+func TestMiddlewareAudit(t *testing.T) {
+	tests := []struct {
+		name            string
+		method          string
+		handler         echo.HandlerFunc
+		recordErr       error
+		expectedCode    int
+		expectedEvent   bool
+		expectedStatus  int
+		expectedError   string
+		expectedAccount int
+	}{
+		{
+			name:   "Read Only",
+			method: http.MethodGet,
+			handler: func(c echo.Context) error {
+				return c.NoContent(http.StatusOK)
+			},
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:   "Success With Target",
+			method: http.MethodPost,
+			handler: func(c echo.Context) error {
+				audit.EntryFromContext(c.Request().Context()).SetTarget(7)
+				return c.NoContent(http.StatusCreated)
+			},
+			expectedCode:    http.StatusCreated,
+			expectedEvent:   true,
+			expectedStatus:  http.StatusCreated,
+			expectedAccount: 7,
+		},
+		{
+			name:   "Custom Response Error",
+			method: http.MethodPost,
+			handler: func(c echo.Context) error {
+				return (&ctx.CustomApplicationContext{Context: c}).CustomResponse("Unprocessable Entity", nil, "", "Insufficient funds", http.StatusUnprocessableEntity, nil)
+			},
+			expectedCode:   http.StatusUnprocessableEntity,
+			expectedEvent:  true,
+			expectedStatus: http.StatusUnprocessableEntity,
+			expectedError:  "Insufficient funds",
+		},
+		{
+			name:   "HTTP Error",
+			method: http.MethodDelete,
+			handler: func(c echo.Context) error {
+				return echo.NewHTTPError(http.StatusNotFound, "not found")
+			},
+			expectedCode:   http.StatusNotFound,
+			expectedEvent:  true,
+			expectedStatus: http.StatusNotFound,
+			expectedError:  "code=404, message=not found",
+		},
+		{
+			name:   "Plain Error",
+			method: http.MethodPut,
+			handler: func(c echo.Context) error {
+				return errors.New("boom")
+			},
+			expectedCode:   http.StatusInternalServerError,
+			expectedEvent:  true,
+			expectedStatus: http.StatusInternalServerError,
+			expectedError:  "boom",
+		},
+		{
+			name:   "Record Failure Keeps Response",
+			method: http.MethodPost,
+			handler: func(c echo.Context) error {
+				return c.NoContent(http.StatusOK)
+			},
+			recordErr:      errors.New("disk full"),
+			expectedCode:   http.StatusOK,
+			expectedEvent:  true,
+			expectedStatus: http.StatusOK,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			usecase := &recordingAuditUsecase{err: tt.recordErr}
+			e := echo.New()
+			e.Use(func(next echo.HandlerFunc) echo.HandlerFunc {
+				return func(c echo.Context) error {
+					c.Set(ContextKeyPrincipal, apikey.Principal{KeyID: 3, Name: "ops"})
+					return next(c)
+				}
+			})
+			e.Use(MiddlewareAudit(usecase))
+			e.Any("/api/v1/accounts/:id", tt.handler)
+
+			req := httptest.NewRequest(tt.method, "/api/v1/accounts/7", nil)
+			req.Header.Set(echo.HeaderXRequestID, "req-1")
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, req)
+			assert.Equal(t, tt.expectedCode, rec.Code)
+
+			if !tt.expectedEvent {
+				assert.Empty(t, usecase.events)
+				return
+			}
+			require.Len(t, usecase.events, 1)
+			event := usecase.events[0]
+			assert.Equal(t, "apikey:3:ops", event.Actor)
+			assert.Equal(t, "req-1", event.RequestID)
+			assert.Equal(t, tt.method, event.Method)
+			assert.Equal(t, "/api/v1/accounts/:id", event.Route)
+			assert.Equal(t, tt.expectedStatus, event.StatusCode)
+			assert.Equal(t, tt.expectedError, event.Error)
+			if tt.expectedAccount != 0 {
+				require.NotNil(t, event.Entry.TargetAccountID)
+				assert.Equal(t, tt.expectedAccount, *event.Entry.TargetAccountID)
+			}
+		})
+	}
+}
+
+func TestActor(t *testing.T) {
+	tests := []struct {
+		name      string
+		principal any
+		client    any
+		expected  string
+	}{
+		{"Anonymous", nil, nil, "anonymous"},
+		{"Static Principal", apikey.Principal{Name: "dev"}, nil, "dev"},
+		{"API Key", apikey.Principal{KeyID: 9, Name: "ops"}, nil, "apikey:9:ops"},
+		{"Signing Client", apikey.Principal{KeyID: 9, Name: "ops"}, "erp", "apikey:9:ops via client:erp"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := echo.New().NewContext(httptest.NewRequest(http.MethodPost, "/", nil), httptest.NewRecorder())
+			if tt.principal != nil {
+				c.Set(ContextKeyPrincipal, tt.principal)
+			}
+			if tt.client != nil {
+				c.Set(ContextKeySigningClient, tt.client)
+			}
+			assert.Equal(t, tt.expected, actor(c))
+		})
+	}
+}
@@ -0,0 +1,61 @@
+package middleware
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"maps"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/rohanchauhan02/internal-transfer/domain/banking"
+	"github.com/rohanchauhan02/internal-transfer/pkg/config"
+	"github.com/rohanchauhan02/internal-transfer/pkg/iso20022"
+)
+
+// defaultMaxBodyBytes applies when the HTTP config does not set MAX_BODY_BYTES.
+const defaultMaxBodyBytes = 1 << 20
+
+// defaultRouteBodyLimits match the caps of the handlers that take uploads, so middleware never
+// rejects a body the handler would accept. ROUTE_BODY_LIMITS entries override them.
+var defaultRouteBodyLimits = map[string]int64{
+	"POST /api/v1/accounts/import":      banking.MaxImportBytes,
+	"POST /api/v1/transactions/pain001": iso20022.MaxPain001Bytes,
+}
+
+// bodyLimits resolves the largest body middleware may read for a route, "METHOD /path".
+func bodyLimits(conf config.HTTP) func(route string) int64 {
+	routeLimits := maps.Clone(defaultRouteBodyLimits)
+	for _, rl := range conf.RouteBodyLimits {
+		routeLimits[rl.Route] = rl.MaxBytes
+	}
+	defaultLimit := conf.MaxBodyBytes
+	if defaultLimit <= 0 {
+		defaultLimit = defaultMaxBodyBytes
+	}
+	return func(route string) int64 {
+		if limit, ok := routeLimits[route]; ok && limit > 0 {
+			return limit
+		}
+		return defaultLimit
+	}
+}
+
+// readBody reads at most limit bytes of the request body and puts them back for the handler.
+// It writes the 400 or 413 response itself and returns ok=false when the body cannot be used.
+func readBody(c echo.Context, limit int64) (body []byte, ok bool, err error) {
+	req := c.Request()
+	if req.Body == nil {
+		return nil, true, nil
+	}
+	body, err = io.ReadAll(http.MaxBytesReader(c.Response(), req.Body, limit))
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			return nil, false, unauthorized(c, http.StatusRequestEntityTooLarge, "Request body is too large")
+		}
+		return nil, false, unauthorized(c, http.StatusBadRequest, "Failed to read request body")
+	}
+	req.Body = io.NopCloser(bytes.NewReader(body))
+	return body, true, nil
+}
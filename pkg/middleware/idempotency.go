@@ -0,0 +1,294 @@
+package middleware
+
+import (
+	"bytes"
+	"container/list"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/rohanchauhan02/internal-transfer/pkg/config"
+)
+
+const (
+	// HeaderIdempotencyKey lets clients retry a state-changing request without applying it twice.
+	HeaderIdempotencyKey = "Idempotency-Key"
+	// HeaderIdempotentReplayed is set on responses served from the idempotency store.
+	HeaderIdempotentReplayed = "Idempotent-Replayed"
+
+	// defaultIdempotencyTTL applies when the HTTP config does not set IDEMPOTENCY_TTL_SECONDS.
+	defaultIdempotencyTTL = 24 * time.Hour
+	// defaultIdempotencyMaxEntries applies when the HTTP config does not set IDEMPOTENCY_MAX_ENTRIES.
+	defaultIdempotencyMaxEntries = 100000
+	// idempotencySweepInterval is how often expired keys are dropped from the memory store.
+	idempotencySweepInterval = time.Minute
+	maxIdempotencyKeyLength  = 255
+)
+
+var (
+	// ErrIdempotencyInFlight is returned while another request with the same key is being handled.
+	ErrIdempotencyInFlight = errors.New("a request with this idempotency key is in progress")
+	// ErrIdempotencyMismatch is returned when a key is reused for a different request body.
+	ErrIdempotencyMismatch = errors.New("idempotency key was used for a different request")
+	// ErrIdempotencyStoreFull is returned when every stored key is still in flight.
+	ErrIdempotencyStoreFull = errors.New("too many idempotent requests in progress")
+)
+
+// StoredResponse is a response kept for replaying to retries.
+type StoredResponse struct {
+	Status      int
+	ContentType string
+	Body        []byte
+}
+
+// IdempotencyStore remembers the outcome of requests by idempotency key.
+type IdempotencyStore interface {
+	// Reserve claims key for a request whose body hashes to fingerprint. It returns the stored
+	// response once the key has completed, ErrIdempotencyInFlight while another request holds it
+	// and ErrIdempotencyMismatch when it was claimed with a different fingerprint.
+	Reserve(key, fingerprint string, ttl time.Duration) (*StoredResponse, error)
+	// Complete stores the response for a reserved key.
+	Complete(key string, resp StoredResponse)
+	// Release forgets a reserved key so the request can be retried.
+	Release(key string)
+	// Close stops background expiry.
+	Close()
+}
+
+type idempotencyEntry struct {
+	key         string
+	fingerprint string
+	response    *StoredResponse
+	expiry      time.Time
+	element     *list.Element
+}
+
+type memoryIdempotencyStore struct {
+	mu         sync.Mutex
+	entries    map[string]*idempotencyEntry
+	order      *list.List // entries by reservation time, oldest first
+	maxEntries int
+	now        func() time.Time
+
+	quit     chan struct{}
+	done     chan struct{}
+	stopOnce sync.Once
+}
+
+// NewMemoryIdempotencyStore creates an in-process IdempotencyStore holding at most maxEntries
+// keys, 100000 when maxEntries is 0. Expired keys are dropped in the background until Close is
+// called. Keys are only honoured per instance, so retries must reach the same instance to be
+// deduplicated.
+func NewMemoryIdempotencyStore(maxEntries int) IdempotencyStore {
+	if maxEntries <= 0 {
+		maxEntries = defaultIdempotencyMaxEntries
+	}
+	s := &memoryIdempotencyStore{
+		entries:    make(map[string]*idempotencyEntry),
+		order:      list.New(),
+		maxEntries: maxEntries,
+		now:        time.Now,
+		quit:       make(chan struct{}),
+		done:       make(chan struct{}),
+	}
+	go s.run()
+	return s
+}
+
+func (s *memoryIdempotencyStore) Reserve(key, fingerprint string, ttl time.Duration) (*StoredResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	if entry, ok := s.entries[key]; ok {
+		switch {
+		case now.After(entry.expiry):
+			s.remove(entry)
+		case entry.fingerprint != fingerprint:
+			return nil, ErrIdempotencyMismatch
+		case entry.response == nil:
+			return nil, ErrIdempotencyInFlight
+		default:
+			return entry.response, nil
+		}
+	}
+	if len(s.entries) >= s.maxEntries && !s.evictOldestCompleted() {
+		return nil, ErrIdempotencyStoreFull
+	}
+	entry := &idempotencyEntry{key: key, fingerprint: fingerprint, expiry: now.Add(ttl)}
+	entry.element = s.order.PushBack(entry)
+	s.entries[key] = entry
+	return nil, nil
+}
+
+func (s *memoryIdempotencyStore) Complete(key string, resp StoredResponse) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if entry, ok := s.entries[key]; ok {
+		entry.response = &resp
+	}
+}
+
+func (s *memoryIdempotencyStore) Release(key string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if entry, ok := s.entries[key]; ok {
+		s.remove(entry)
+	}
+}
+
+func (s *memoryIdempotencyStore) Close() {
+	s.stopOnce.Do(func() {
+		close(s.quit)
+		<-s.done
+	})
+}
+
+func (s *memoryIdempotencyStore) run() {
+	defer close(s.done)
+	ticker := time.NewTicker(idempotencySweepInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			s.sweep()
+		case <-s.quit:
+			return
+		}
+	}
+}
+
+// sweep drops expired keys.
+func (s *memoryIdempotencyStore) sweep() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := s.now()
+	for e := s.order.Front(); e != nil; {
+		next := e.Next()
+		if entry := e.Value.(*idempotencyEntry); now.After(entry.expiry) {
+			s.remove(entry)
+		}
+		e = next
+	}
+}
+
+// evictOldestCompleted makes room for a new key by forgetting the oldest stored response.
+// Keys still in flight are kept, since forgetting them would let a retry run concurrently.
+func (s *memoryIdempotencyStore) evictOldestCompleted() bool {
+	for e := s.order.Front(); e != nil; e = e.Next() {
+		if entry := e.Value.(*idempotencyEntry); entry.response != nil {
+			s.remove(entry)
+			return true
+		}
+	}
+	return false
+}
+
+func (s *memoryIdempotencyStore) remove(entry *idempotencyEntry) {
+	s.order.Remove(entry.element)
+	delete(s.entries, entry.key)
+}
+
+// MiddlewareIdempotency replays the stored response when a state-changing request is retried with
+// the same Idempotency-Key. Keys are scoped to the caller and route. Only handler outcomes are
+// stored: server errors and the rejections of route middleware such as scopes and signatures are
+// not, so a request that failed before taking effect can be retried with the same key. Bodies are read
+// up to the route's limit from the HTTP config to fingerprint them; larger ones are answered with 413.
+func MiddlewareIdempotency(conf config.HTTP, store IdempotencyStore) echo.MiddlewareFunc {
+	ttl := time.Duration(conf.IdempotencyTTLSeconds) * time.Second
+	if ttl <= 0 {
+		ttl = defaultIdempotencyTTL
+	}
+	bodyLimit := bodyLimits(conf)
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			req := c.Request()
+			idempotencyKey := req.Header.Get(HeaderIdempotencyKey)
+			if idempotencyKey == "" || req.Method == http.MethodGet || req.Method == http.MethodHead {
+				return next(c)
+			}
+			if len(idempotencyKey) > maxIdempotencyKeyLength {
+				return unauthorized(c, http.StatusBadRequest, "Idempotency key is too long")
+			}
+
+			route := req.Method + " " + c.Path()
+			body, ok, err := readBody(c, bodyLimit(route))
+			if !ok {
+				return err
+			}
+			sum := sha256.Sum256(body)
+			key := actor(c) + "\x00" + route + "\x00" + idempotencyKey
+
+			stored, err := store.Reserve(key, hex.EncodeToString(sum[:]), ttl)
+			switch {
+			case errors.Is(err, ErrIdempotencyInFlight):
+				return unauthorized(c, http.StatusConflict, err.Error())
+			case errors.Is(err, ErrIdempotencyMismatch):
+				return unauthorized(c, http.StatusUnprocessableEntity, err.Error())
+			case errors.Is(err, ErrIdempotencyStoreFull):
+				return unauthorized(c, http.StatusServiceUnavailable, err.Error())
+			case stored != nil:
+				c.Response().Header().Set(HeaderIdempotentReplayed, "true")
+				return c.Blob(stored.Status, stored.ContentType, stored.Body)
+			}
+
+			recorder := &responseRecorder{ResponseWriter: c.Response().Writer}
+			c.Response().Writer = recorder
+			completed := false
+			defer func() {
+				c.Response().Writer = recorder.ResponseWriter
+				// Also reached when the handler panics, which must not leave the key in flight.
+				if !completed {
+					store.Release(key)
+				}
+			}()
+
+			if err := next(c); err != nil {
+				return err
+			}
+			if status := c.Response().Status; storedStatus(status) {
+				store.Complete(key, StoredResponse{
+					Status:      status,
+					ContentType: c.Response().Header().Get(echo.HeaderContentType),
+					Body:        recorder.body.Bytes(),
+				})
+				completed = true
+			}
+			return nil
+		}
+	}
+}
+
+// storedStatus reports whether a response is the handler's outcome and may be replayed. Server
+// errors, and the 401, 403, 408, 413 and 429 answers of middleware that ran before the handler,
+// say nothing about the request's effect and would otherwise be replayed after the cause is fixed.
+func storedStatus(status int) bool {
+	switch status {
+	case http.StatusUnauthorized, http.StatusForbidden, http.StatusRequestTimeout,
+		http.StatusRequestEntityTooLarge, http.StatusTooManyRequests:
+		return false
+	}
+	return status < http.StatusInternalServerError
+}
+
+// responseRecorder copies the response body while it is written to the client.
+type responseRecorder struct {
+	http.ResponseWriter
+	body bytes.Buffer
+}
+
+func (r *responseRecorder) Write(b []byte) (int, error) {
+	r.body.Write(b)
+	return r.ResponseWriter.Write(b)
+}
+
+func (r *responseRecorder) Flush() {
+	if f, ok := r.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
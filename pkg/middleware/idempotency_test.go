@@ -0,0 +1,150 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/rohanchauhan02/internal-transfer/pkg/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMiddlewareIdempotency(t *testing.T) {
+	calls := 0
+	fail := false
+	store := NewMemoryIdempotencyStore(0)
+	t.Cleanup(store.Close)
+	e := echo.New()
+	e.Use(MiddlewareIdempotency(config.HTTP{MaxBodyBytes: 64}, store))
+	e.POST("/api/v1/transactions", func(c echo.Context) error {
+		calls++
+		if fail {
+			return c.String(http.StatusInternalServerError, "failed")
+		}
+		return c.String(http.StatusOK, fmt.Sprintf("call %d", calls))
+	})
+
+	send := func(key, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/transactions", strings.NewReader(body))
+		if key != "" {
+			req.Header.Set(HeaderIdempotencyKey, key)
+		}
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		return rec
+	}
+
+	first := send("key-1", `{"amount":"10"}`)
+	assert.Equal(t, http.StatusOK, first.Code)
+	assert.Equal(t, "call 1", first.Body.String())
+
+	replay := send("key-1", `{"amount":"10"}`)
+	assert.Equal(t, http.StatusOK, replay.Code)
+	assert.Equal(t, "call 1", replay.Body.String())
+	assert.Equal(t, "true", replay.Header().Get(HeaderIdempotentReplayed))
+	assert.Equal(t, 1, calls, "a replay does not reach the handler")
+
+	assert.Equal(t, http.StatusUnprocessableEntity, send("key-1", `{"amount":"20"}`).Code)
+	assert.Equal(t, "call 2", send("", `{"amount":"10"}`).Body.String(), "requests without a key are not deduplicated")
+
+	fail = true
+	assert.Equal(t, http.StatusInternalServerError, send("key-2", `{}`).Code)
+	fail = false
+	assert.Equal(t, "call 4", send("key-2", `{}`).Body.String(), "server errors are not stored")
+
+	assert.Equal(t, http.StatusBadRequest, send(strings.Repeat("k", 256), `{}`).Code)
+	assert.Equal(t, http.StatusRequestEntityTooLarge, send("key-3", strings.Repeat("x", 65)).Code)
+	assert.Equal(t, 4, calls, "an oversized body does not reach the handler")
+}
+
+func TestMemoryIdempotencyStore_InFlight(t *testing.T) {
+	store := NewMemoryIdempotencyStore(0)
+	t.Cleanup(store.Close)
+	resp, err := store.Reserve("k", "a", time.Minute)
+	assert.NoError(t, err)
+	assert.Nil(t, resp)
+
+	_, err = store.Reserve("k", "a", time.Minute)
+	assert.ErrorIs(t, err, ErrIdempotencyInFlight)
+
+	store.Release("k")
+	_, err = store.Reserve("k", "a", time.Minute)
+	assert.NoError(t, err, "a released key can be reserved again")
+}
+
+func TestMemoryIdempotencyStore_Bounded(t *testing.T) {
+	store := NewMemoryIdempotencyStore(2)
+	t.Cleanup(store.Close)
+
+	_, err := store.Reserve("a", "f", time.Minute)
+	assert.NoError(t, err)
+	_, err = store.Reserve("b", "f", time.Minute)
+	assert.NoError(t, err)
+	_, err = store.Reserve("c", "f", time.Minute)
+	assert.ErrorIs(t, err, ErrIdempotencyStoreFull, "keys in flight are never evicted")
+
+	store.Complete("a", StoredResponse{Status: http.StatusOK})
+	_, err = store.Reserve("c", "f", time.Minute)
+	assert.NoError(t, err, "the oldest completed key makes room")
+	store.Release("b")
+	resp, err := store.Reserve("a", "f", time.Minute)
+	assert.NoError(t, err)
+	assert.Nil(t, resp, "the evicted key was forgotten")
+}
+
+func TestMemoryIdempotencyStore_Expiry(t *testing.T) {
+	store := NewMemoryIdempotencyStore(0).(*memoryIdempotencyStore)
+	t.Cleanup(store.Close)
+	now := time.Now()
+	store.now = func() time.Time { return now }
+
+	_, err := store.Reserve("k", "a", time.Minute)
+	assert.NoError(t, err)
+	store.Complete("k", StoredResponse{Status: http.StatusOK})
+
+	now = now.Add(2 * time.Minute)
+	store.sweep()
+	assert.Empty(t, store.entries)
+	assert.Zero(t, store.order.Len())
+}
+
+func TestMiddlewareIdempotency_StoresOnlyHandlerOutcomes(t *testing.T) {
+	store := NewMemoryIdempotencyStore(0)
+	t.Cleanup(store.Close)
+	signed := false
+	e := echo.New()
+	e.Use(MiddlewareIdempotency(config.HTTP{}, store))
+	e.POST("/api/v1/transactions", func(c echo.Context) error {
+		return c.String(http.StatusCreated, "done")
+	}, func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if !signed {
+				return unauthorized(c, http.StatusUnauthorized, "Invalid request signature")
+			}
+			return next(c)
+		}
+	})
+	e.POST("/api/v1/accounts/import", func(c echo.Context) error {
+		return c.NoContent(http.StatusOK)
+	})
+
+	send := func(path, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+		req.Header.Set(HeaderIdempotencyKey, "key-1")
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		return rec
+	}
+
+	assert.Equal(t, http.StatusUnauthorized, send("/api/v1/transactions", `{}`).Code)
+	signed = true
+	assert.Equal(t, http.StatusCreated, send("/api/v1/transactions", `{}`).Code,
+		"a rejection by route middleware is not replayed once the request is fixed")
+
+	assert.Equal(t, http.StatusOK, send("/api/v1/accounts/import", strings.Repeat("x", 2<<20)).Code,
+		"imports may be as large as the handler allows")
+}
@@ -0,0 +1,28 @@
+package middleware
+
+import (
+	"fmt"
+	"net"
+
+	"github.com/labstack/echo/v4"
+	"github.com/rohanchauhan02/internal-transfer/pkg/config"
+)
+
+// IPExtractor returns how echo determines the client address that api key allow lists and rate
+// limits see. Without trusted proxies it is the address of the connection, since echo's default
+// believes X-Forwarded-For and X-Real-IP from anyone. With them, X-Forwarded-For is read from the
+// right, skipping only addresses in the trusted ranges.
+func IPExtractor(conf config.HTTP) (echo.IPExtractor, error) {
+	if len(conf.TrustedProxies) == 0 {
+		return echo.ExtractIPDirect(), nil
+	}
+	options := []echo.TrustOption{echo.TrustLoopback(false), echo.TrustLinkLocal(false), echo.TrustPrivateNet(false)}
+	for _, cidr := range conf.TrustedProxies {
+		_, ipNet, err := net.ParseCIDR(cidr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid trusted proxy range %q: %w", cidr, err)
+		}
+		options = append(options, echo.TrustIPRange(ipNet))
+	}
+	return echo.ExtractIPFromXFFHeader(options...), nil
+}
@@ -0,0 +1,40 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/rohanchauhan02/internal-transfer/pkg/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIPExtractor(t *testing.T) {
+	request := func(remoteAddr, forwardedFor string) *http.Request {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.RemoteAddr = remoteAddr
+		req.Header.Set(echo.HeaderXForwardedFor, forwardedFor)
+		req.Header.Set(echo.HeaderXRealIP, "203.0.113.9")
+		return req
+	}
+
+	direct, err := IPExtractor(config.HTTP{})
+	require.NoError(t, err)
+	assert.Equal(t, "198.51.100.7", direct(request("198.51.100.7:4000", "203.0.113.9")),
+		"forwarded headers are ignored without trusted proxies")
+	assert.Equal(t, "10.0.0.5", direct(request("10.0.0.5:4000", "203.0.113.9")),
+		"private addresses are not trusted by default")
+
+	proxied, err := IPExtractor(config.HTTP{TrustedProxies: []string{"10.0.0.0/24"}})
+	require.NoError(t, err)
+	assert.Equal(t, "203.0.113.9", proxied(request("10.0.0.5:4000", "203.0.113.9")))
+	assert.Equal(t, "203.0.113.9", proxied(request("10.0.0.5:4000", "192.0.2.1, 203.0.113.9, 10.0.0.4")),
+		"only addresses appended by trusted proxies are skipped")
+	assert.Equal(t, "198.51.100.7", proxied(request("198.51.100.7:4000", "203.0.113.9")),
+		"untrusted peers cannot forward")
+
+	_, err = IPExtractor(config.HTTP{TrustedProxies: []string{"10.0.0.0"}})
+	assert.ErrorContains(t, err, "invalid trusted proxy range")
+}
@@ -0,0 +1,75 @@
+package middleware
+
+import (
+	"log/slog"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	echomiddleware "github.com/labstack/echo/v4/middleware"
+	"github.com/rohanchauhan02/internal-transfer/pkg/config"
+	"github.com/rohanchauhan02/internal-transfer/pkg/logger"
+)
+
+var httpLog = logger.For("http")
+
+// MiddlewareLogContext stores the request ID, route and any account IDs on the request context,
+// so every log line written while handling the request carries them. Bodies are only read up to
+// the route's limit from the HTTP config.
+func MiddlewareLogContext(conf config.HTTP) echo.MiddlewareFunc {
+	bodyLimit := bodyLimits(conf)
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			attrs := []slog.Attr{
+				slog.String("request_id", c.Request().Header.Get(echo.HeaderXRequestID)),
+				slog.String("route", c.Path()),
+			}
+			ids := requestAccountIDs(c, bodyLimit(c.Request().Method+" "+c.Path()))
+			if ids.AccountID != nil {
+				attrs = append(attrs, slog.Int("account_id", *ids.AccountID))
+			}
+			if ids.SourceAccountID != nil {
+				attrs = append(attrs, slog.Int("source_account_id", *ids.SourceAccountID))
+			}
+			if ids.DestinationAccountID != nil {
+				attrs = append(attrs, slog.Int("destination_account_id", *ids.DestinationAccountID))
+			}
+			c.SetRequest(c.Request().WithContext(logger.WithFields(c.Request().Context(), attrs...)))
+			return next(c)
+		}
+	}
+}
+
+// MiddlewareRequestLogger writes one structured access log line per request. The raw path is left
+// out because it can contain account IDs; the route template comes from the request context.
+func MiddlewareRequestLogger() echo.MiddlewareFunc {
+	return echomiddleware.RequestLoggerWithConfig(echomiddleware.RequestLoggerConfig{
+		LogMethod:    true,
+		LogStatus:    true,
+		LogLatency:   true,
+		LogRemoteIP:  true,
+		LogError:     true,
+		HandleError:  true,
+		LogUserAgent: true,
+		LogValuesFunc: func(c echo.Context, v echomiddleware.RequestLoggerValues) error {
+			level := slog.LevelInfo
+			switch {
+			case v.Status >= 500 || v.Error != nil:
+				level = slog.LevelError
+			case v.Status >= 400:
+				level = slog.LevelWarn
+			}
+			attrs := []slog.Attr{
+				slog.String("method", v.Method),
+				slog.Int("status", v.Status),
+				slog.Duration("latency", v.Latency/time.Microsecond*time.Microsecond),
+				slog.String("remote_ip", v.RemoteIP),
+				slog.String("user_agent", v.UserAgent),
+			}
+			if v.Error != nil {
+				attrs = append(attrs, slog.String("error", v.Error.Error()))
+			}
+			httpLog.LogAttrs(c.Request().Context(), level, "request completed", attrs...)
+			return nil
+		},
+	})
+}
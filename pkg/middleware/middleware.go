@@ -3,15 +3,25 @@ package middleware
 import (
 	"github.com/google/uuid"
 	"github.com/labstack/echo/v4"
+	"github.com/rohanchauhan02/internal-transfer/pkg/tracing"
+	"go.opentelemetry.io/otel/trace"
 )
 
+// MiddlewareRequestID makes sure every request carries an X-Request-ID. When the client does not
+// send one and the request is traced, the trace ID is reused so logs and traces share one key.
 func MiddlewareRequestID() echo.MiddlewareFunc {
 	return func(next echo.HandlerFunc) echo.HandlerFunc {
 		return func(c echo.Context) error {
+			span := trace.SpanFromContext(c.Request().Context())
 			requestID := c.Request().Header.Get(echo.HeaderXRequestID)
 			if requestID == "" {
-				requestID = generateRequestID()
+				if sc := span.SpanContext(); sc.HasTraceID() {
+					requestID = sc.TraceID().String()
+				} else {
+					requestID = generateRequestID()
+				}
 			}
+			span.SetAttributes(tracing.AttributeRequestID.String(requestID))
 			c.Request().Header.Set(echo.HeaderXRequestID, requestID)
 			c.Response().Header().Set(echo.HeaderXRequestID, requestID)
 			return next(c)
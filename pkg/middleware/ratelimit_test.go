@@ -0,0 +1,45 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/rohanchauhan02/internal-transfer/pkg/config"
+	"github.com/rohanchauhan02/internal-transfer/pkg/ratelimit"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMiddlewareRateLimit_KeysByConnectionAddress(t *testing.T) {
+	for _, keyBy := range []string{KeyByIP, KeyByAPIKey} {
+		t.Run(keyBy, func(t *testing.T) {
+			e := echo.New()
+			extractor, err := IPExtractor(config.HTTP{})
+			require.NoError(t, err)
+			e.IPExtractor = extractor
+			conf := config.RateLimit{Enabled: true, Rules: []config.RateLimitRule{
+				{Route: "GET /api/v1/accounts/:id", KeyBy: keyBy, Rate: 0.001, Burst: 2},
+			}}
+			e.GET("/api/v1/accounts/:id", func(c echo.Context) error {
+				return c.NoContent(http.StatusOK)
+			}, MiddlewareRateLimit(conf, ratelimit.NewMemoryStore()))
+
+			get := func(remoteAddr string, i int) int {
+				req := httptest.NewRequest(http.MethodGet, "/api/v1/accounts/1", nil)
+				req.RemoteAddr = remoteAddr
+				// A client rotating the forwarded address must not get a new bucket each time.
+				req.Header.Set(echo.HeaderXForwardedFor, "203.0.113."+strconv.Itoa(i))
+				rec := httptest.NewRecorder()
+				e.ServeHTTP(rec, req)
+				return rec.Code
+			}
+			assert.Equal(t, http.StatusOK, get("198.51.100.7:4000", 1))
+			assert.Equal(t, http.StatusOK, get("198.51.100.7:4000", 2))
+			assert.Equal(t, http.StatusTooManyRequests, get("198.51.100.7:4000", 3))
+			assert.Equal(t, http.StatusOK, get("198.51.100.8:4000", 4), "other clients have their own bucket")
+		})
+	}
+}
@@ -0,0 +1,209 @@
+package middleware
+
+import (
+	"container/list"
+	"errors"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/rohanchauhan02/internal-transfer/pkg/config"
+	"github.com/rohanchauhan02/internal-transfer/pkg/signing"
+)
+
+// defaultMaxSkew applies when the signing config does not set MAX_SKEW_SECONDS.
+const defaultMaxSkew = 5 * time.Minute
+
+// nonceSweepInterval is how often expired nonces are dropped from the memory store.
+const nonceSweepInterval = time.Minute
+
+// ContextKeySigningClient is the echo context key holding the verified signing client ID.
+const ContextKeySigningClient = "signing_client"
+
+// defaultMaxNonces applies when the signing config does not set MAX_NONCES.
+const defaultMaxNonces = 100000
+
+// ErrNonceStoreFull is returned when no more nonces can be remembered until some expire.
+var ErrNonceStoreFull = errors.New("too many signed requests, retry later")
+
+// NonceStore remembers nonces for as long as their signatures could still be accepted.
+type NonceStore interface {
+	// Seen records nonce for clientID and reports whether it had already been used. It returns
+	// ErrNonceStoreFull instead of forgetting nonces that could still be replayed.
+	Seen(clientID, nonce string, ttl time.Duration) (bool, error)
+	// Close stops background expiry.
+	Close()
+}
+
+type nonceEntry struct {
+	key     string
+	expiry  time.Time
+	element *list.Element
+}
+
+type memoryNonceStore struct {
+	mu         sync.Mutex
+	entries    map[string]*nonceEntry
+	order      *list.List // entries by the time they were seen, oldest first
+	maxEntries int
+	now        func() time.Time
+
+	quit     chan struct{}
+	done     chan struct{}
+	stopOnce sync.Once
+}
+
+// NewMemoryNonceStore creates an in-process NonceStore holding at most maxEntries nonces,
+// 100000 when maxEntries is 0. Expired nonces are dropped in the background until Close is
+// called. Replays are only detected per instance.
+func NewMemoryNonceStore(maxEntries int) NonceStore {
+	if maxEntries <= 0 {
+		maxEntries = defaultMaxNonces
+	}
+	s := &memoryNonceStore{
+		entries:    make(map[string]*nonceEntry),
+		order:      list.New(),
+		maxEntries: maxEntries,
+		now:        time.Now,
+		quit:       make(chan struct{}),
+		done:       make(chan struct{}),
+	}
+	go s.run()
+	return s
+}
+
+func (s *memoryNonceStore) Seen(clientID, nonce string, ttl time.Duration) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	key := clientID + "\x00" + nonce
+	if entry, ok := s.entries[key]; ok {
+		if !now.After(entry.expiry) {
+			return true, nil
+		}
+		s.remove(entry)
+	}
+	if len(s.entries) >= s.maxEntries {
+		// Nonces share one ttl, so the oldest expire first; drop those before giving up.
+		s.expire(now)
+		if len(s.entries) >= s.maxEntries {
+			return false, ErrNonceStoreFull
+		}
+	}
+	entry := &nonceEntry{key: key, expiry: now.Add(ttl)}
+	entry.element = s.order.PushBack(entry)
+	s.entries[key] = entry
+	return false, nil
+}
+
+func (s *memoryNonceStore) Close() {
+	s.stopOnce.Do(func() {
+		close(s.quit)
+		<-s.done
+	})
+}
+
+func (s *memoryNonceStore) run() {
+	defer close(s.done)
+	ticker := time.NewTicker(nonceSweepInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			s.mu.Lock()
+			s.expire(s.now())
+			s.mu.Unlock()
+		case <-s.quit:
+			return
+		}
+	}
+}
+
+// expire drops the expired nonces at the front of the order.
+func (s *memoryNonceStore) expire(now time.Time) {
+	for e := s.order.Front(); e != nil; e = s.order.Front() {
+		entry := e.Value.(*nonceEntry)
+		if !now.After(entry.expiry) {
+			return
+		}
+		s.remove(entry)
+	}
+}
+
+func (s *memoryNonceStore) remove(entry *nonceEntry) {
+	s.order.Remove(entry.element)
+	delete(s.entries, entry.key)
+}
+
+// MiddlewareSignature verifies HMAC request signatures produced by pkg/signing.
+// Unsigned requests pass through unless conf.Required is set. The body is hashed up to the
+// route's limit from httpConf; larger bodies are answered with 413.
+func MiddlewareSignature(conf config.Signing, httpConf config.HTTP, nonces NonceStore) echo.MiddlewareFunc {
+	secrets := make(map[string]string, len(conf.Clients))
+	for _, client := range conf.Clients {
+		secrets[client.ID] = client.Secret
+	}
+	maxSkew := time.Duration(conf.MaxSkewSeconds) * time.Second
+	if maxSkew <= 0 {
+		maxSkew = defaultMaxSkew
+	}
+	bodyLimit := bodyLimits(httpConf)
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			req := c.Request()
+			signature := req.Header.Get(signing.HeaderSignature)
+			if signature == "" {
+				if conf.Required {
+					return unauthorized(c, http.StatusUnauthorized, "Request signature is required")
+				}
+				return next(c)
+			}
+
+			clientID := req.Header.Get(signing.HeaderClientID)
+			secret, ok := secrets[clientID]
+			if !ok {
+				return unauthorized(c, http.StatusUnauthorized, "Unknown signing client")
+			}
+
+			timestamp := req.Header.Get(signing.HeaderTimestamp)
+			unix, err := strconv.ParseInt(timestamp, 10, 64)
+			if err != nil {
+				return unauthorized(c, http.StatusUnauthorized, "Invalid signature timestamp")
+			}
+			skew := time.Since(time.Unix(unix, 0))
+			if skew > maxSkew || skew < -maxSkew {
+				return unauthorized(c, http.StatusUnauthorized, "Stale signature timestamp")
+			}
+
+			nonce := req.Header.Get(signing.HeaderNonce)
+			if nonce == "" {
+				return unauthorized(c, http.StatusUnauthorized, "Signature nonce is required")
+			}
+
+			body, ok, err := readBody(c, bodyLimit(req.Method+" "+c.Path()))
+			if !ok {
+				return err
+			}
+
+			if !signing.Verify(secret, signature, req.Method, req.URL.Path, timestamp, nonce, signing.BodyDigest(body)) {
+				return unauthorized(c, http.StatusUnauthorized, "Invalid request signature")
+			}
+			// Only record the nonce once the signature is known to be genuine, so forged
+			// requests cannot burn nonces belonging to a real client.
+			replayed, err := nonces.Seen(clientID, nonce, 2*maxSkew)
+			switch {
+			case err != nil:
+				return unauthorized(c, http.StatusServiceUnavailable, err.Error())
+			case replayed:
+				return unauthorized(c, http.StatusUnauthorized, "Replayed request nonce")
+			}
+
+			c.Set(ContextKeySigningClient, clientID)
+			return next(c)
+		}
+	}
+}
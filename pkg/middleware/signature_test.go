@@ -0,0 +1,167 @@
+package middleware
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/rohanchauhan02/internal-transfer/pkg/config"
+	"github.com/rohanchauhan02/internal-transfer/pkg/signing"
+	"github.com/stretchr/testify/assert"
+)
+
+func newSignedServer(t *testing.T, conf config.Signing) *echo.Echo {
+	nonces := NewMemoryNonceStore(0)
+	t.Cleanup(nonces.Close)
+	signature := MiddlewareSignature(conf, config.HTTP{MaxBodyBytes: 256}, nonces)
+	echoBody := func(c echo.Context) error {
+		body, _ := io.ReadAll(c.Request().Body)
+		return c.String(http.StatusOK, string(body))
+	}
+	e := echo.New()
+	e.POST("/api/v1/transactions", echoBody, signature)
+	e.POST("/api/v1/transactions/pain001", echoBody, signature)
+	return e
+}
+
+func TestMiddlewareSignature(t *testing.T) {
+	conf := config.Signing{
+		Required:       true,
+		MaxSkewSeconds: 60,
+		Clients:        []config.SigningClient{{ID: "billing", Secret: "s3cret"}},
+	}
+	const body = `{"source_account_id":1,"destination_account_id":2,"amount":"10"}`
+
+	tests := []struct {
+		name         string
+		prepare      func(req *http.Request)
+		expectedCode int
+	}{
+		{
+			name: "Valid Signature",
+			prepare: func(req *http.Request) {
+				assert.NoError(t, signing.NewSigner("billing", "s3cret").Sign(req))
+			},
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:         "Missing Signature",
+			prepare:      func(req *http.Request) {},
+			expectedCode: http.StatusUnauthorized,
+		},
+		{
+			name: "Wrong Secret",
+			prepare: func(req *http.Request) {
+				assert.NoError(t, signing.NewSigner("billing", "guess").Sign(req))
+			},
+			expectedCode: http.StatusUnauthorized,
+		},
+		{
+			name: "Unknown Client",
+			prepare: func(req *http.Request) {
+				assert.NoError(t, signing.NewSigner("payroll", "s3cret").Sign(req))
+			},
+			expectedCode: http.StatusUnauthorized,
+		},
+		{
+			name: "Stale Timestamp",
+			prepare: func(req *http.Request) {
+				signer := signing.NewSigner("billing", "s3cret")
+				signer.Now = func() time.Time { return time.Now().Add(-2 * time.Minute) }
+				assert.NoError(t, signer.Sign(req))
+			},
+			expectedCode: http.StatusUnauthorized,
+		},
+		{
+			name: "Tampered Body",
+			prepare: func(req *http.Request) {
+				assert.NoError(t, signing.NewSigner("billing", "s3cret").Sign(req))
+				req.Body = io.NopCloser(strings.NewReader(strings.Replace(body, `"10"`, `"1000"`, 1)))
+			},
+			expectedCode: http.StatusUnauthorized,
+		},
+		{
+			name: "Oversized Body",
+			prepare: func(req *http.Request) {
+				assert.NoError(t, signing.NewSigner("billing", "s3cret").Sign(req))
+				req.Body = io.NopCloser(strings.NewReader(strings.Repeat(" ", 257)))
+			},
+			expectedCode: http.StatusRequestEntityTooLarge,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := newSignedServer(t, conf)
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/transactions", strings.NewReader(body))
+			tt.prepare(req)
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, req)
+			assert.Equal(t, tt.expectedCode, rec.Code)
+			if tt.expectedCode == http.StatusOK {
+				assert.Equal(t, body, rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestMiddlewareSignature_RejectsReplayedNonce(t *testing.T) {
+	e := newSignedServer(t, config.Signing{
+		Clients: []config.SigningClient{{ID: "billing", Secret: "s3cret"}},
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/transactions", strings.NewReader("{}"))
+	assert.NoError(t, signing.NewSigner("billing", "s3cret").Sign(req))
+
+	replay := httptest.NewRequest(http.MethodPost, "/api/v1/transactions", strings.NewReader("{}"))
+	replay.Header = req.Header.Clone()
+
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, replay)
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	assert.Contains(t, rec.Body.String(), "Replayed request nonce")
+}
+
+func TestMiddlewareSignature_Pain001BodyLimit(t *testing.T) {
+	e := newSignedServer(t, config.Signing{
+		Clients: []config.SigningClient{{ID: "billing", Secret: "s3cret"}},
+	})
+	message := strings.Repeat("x", 2<<20)
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/transactions/pain001", strings.NewReader(message))
+	assert.NoError(t, signing.NewSigner("billing", "s3cret").Sign(req))
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusOK, rec.Code, "pain.001 messages may be as large as the handler allows")
+}
+
+func TestMemoryNonceStore(t *testing.T) {
+	store := NewMemoryNonceStore(2).(*memoryNonceStore)
+	t.Cleanup(store.Close)
+	now := time.Now()
+	store.now = func() time.Time { return now }
+
+	for _, nonce := range []string{"a", "b"} {
+		seen, err := store.Seen("billing", nonce, time.Minute)
+		assert.NoError(t, err)
+		assert.False(t, seen)
+	}
+	seen, err := store.Seen("billing", "a", time.Minute)
+	assert.NoError(t, err)
+	assert.True(t, seen)
+	_, err = store.Seen("billing", "c", time.Minute)
+	assert.ErrorIs(t, err, ErrNonceStoreFull, "unexpired nonces are never forgotten")
+
+	now = now.Add(2 * time.Minute)
+	seen, err = store.Seen("billing", "c", time.Minute)
+	assert.NoError(t, err, "expired nonces make room")
+	assert.False(t, seen)
+	assert.Equal(t, 1, store.order.Len())
+}
@@ -0,0 +1,36 @@
+package middleware
+
+import (
+	"context"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/rohanchauhan02/internal-transfer/pkg/config"
+)
+
+// MiddlewareTimeout attaches a deadline to the request context, using the route specific timeout
+// when one is configured. Everything downstream that honours the context, including GORM queries
+// run through WithContext, is cancelled once the deadline passes or the client disconnects.
+func MiddlewareTimeout(conf config.HTTP) echo.MiddlewareFunc {
+	routeTimeouts := make(map[string]time.Duration, len(conf.RouteTimeouts))
+	for _, rt := range conf.RouteTimeouts {
+		routeTimeouts[rt.Route] = time.Duration(rt.TimeoutMS) * time.Millisecond
+	}
+	defaultTimeout := time.Duration(conf.RequestTimeoutMS) * time.Millisecond
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			timeout, ok := routeTimeouts[c.Request().Method+" "+c.Path()]
+			if !ok {
+				timeout = defaultTimeout
+			}
+			if timeout <= 0 {
+				return next(c)
+			}
+			ctx, cancel := context.WithTimeout(c.Request().Context(), timeout)
+			defer cancel()
+			c.SetRequest(c.Request().WithContext(ctx))
+			return next(c)
+		}
+	}
+}
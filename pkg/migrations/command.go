@@ -0,0 +1,74 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"text/tabwriter"
+	"time"
+)
+
+// CommandUsage describes the arguments of RunCommand.
+const CommandUsage = "usage: migrate up | down [steps] | status"
+
+// RunCommand implements the `migrate` subcommand of the server and transferctl. `down` rolls
+// back one migration unless a step count is given.
+func RunCommand(ctx context.Context, db *sql.DB, dialect Dialect, args []string, out io.Writer) error {
+	migrator, err := New(db, dialect)
+	if err != nil {
+		return err
+	}
+	if len(args) == 0 {
+		return errors.New(CommandUsage)
+	}
+
+	switch args[0] {
+	case "up":
+		applied, err := migrator.Up(ctx)
+		for _, mig := range applied {
+			fmt.Fprintf(out, "applied %04d_%s\n", mig.Version, mig.Name)
+		}
+		if err == nil && len(applied) == 0 {
+			fmt.Fprintln(out, "schema is up to date")
+		}
+		return err
+	case "down":
+		steps := 1
+		if len(args) > 1 {
+			if steps, err = strconv.Atoi(args[1]); err != nil || steps < 1 {
+				return fmt.Errorf("invalid step count %q", args[1])
+			}
+		}
+		reverted, err := migrator.Down(ctx, steps)
+		for _, mig := range reverted {
+			fmt.Fprintf(out, "rolled back %04d_%s\n", mig.Version, mig.Name)
+		}
+		return err
+	case "status":
+		statuses, err := migrator.Status(ctx)
+		if err != nil {
+			return err
+		}
+		w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "VERSION\tNAME\tAPPLIED AT")
+		for _, s := range statuses {
+			appliedAt := "pending"
+			if s.AppliedAt != nil {
+				appliedAt = s.AppliedAt.Format(time.RFC3339)
+			}
+			fmt.Fprintf(w, "%04d\t%s\t%s\n", s.Version, s.Name, appliedAt)
+		}
+		if err := w.Flush(); err != nil {
+			return err
+		}
+		if err := migrator.Check(ctx); err != nil {
+			fmt.Fprintln(out, err)
+		}
+		return nil
+	default:
+		return errors.New(CommandUsage)
+	}
+}
@@ -0,0 +1,335 @@
+// Package migrations applies the versioned SQL schema migrations embedded in the binary.
+//
+// Migrations live in sql/<dialect>/ as pairs of NNNN_name.up.sql and NNNN_name.down.sql files;
+// every dialect has the same versions. Applied versions are recorded in the schema_migrations
+// table and every change runs in its own transaction. On Postgres the run holds an advisory lock,
+// so replicas starting together cannot race each other; SQLite serialises writers itself.
+package migrations
+
+import (
+	"context"
+	"database/sql"
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"time"
+)
+
+//go:embed sql
+var files embed.FS
+
+// Dialect selects the SQL flavour of the migration files and the bookkeeping statements.
+type Dialect string
+
+const (
+	Postgres Dialect = "postgres"
+	SQLite   Dialect = "sqlite"
+)
+
+// statements are the dialect-specific queries used to track applied migrations.
+type statements struct {
+	// lock and unlock are empty when the database serialises migrations on its own.
+	lock, unlock string
+	tableExists  string
+	createTable  string
+	insert       string
+	delete       string
+}
+
+var dialects = map[Dialect]statements{
+	Postgres: {
+		lock:        `SELECT pg_advisory_lock($1)`,
+		unlock:      `SELECT pg_advisory_unlock($1)`,
+		tableExists: `SELECT to_regclass('schema_migrations') IS NOT NULL`,
+		createTable: `CREATE TABLE IF NOT EXISTS schema_migrations (
+			version    BIGINT PRIMARY KEY,
+			name       TEXT NOT NULL,
+			applied_at TIMESTAMPTZ NOT NULL
+		)`,
+		insert: `INSERT INTO schema_migrations (version, name, applied_at) VALUES ($1, $2, $3)`,
+		delete: `DELETE FROM schema_migrations WHERE version = $1`,
+	},
+	SQLite: {
+		tableExists: `SELECT count(*) > 0 FROM sqlite_master WHERE type = 'table' AND name = 'schema_migrations'`,
+		createTable: `CREATE TABLE IF NOT EXISTS schema_migrations (
+			version    INTEGER PRIMARY KEY,
+			name       TEXT NOT NULL,
+			applied_at DATETIME NOT NULL
+		)`,
+		insert: `INSERT INTO schema_migrations (version, name, applied_at) VALUES (?, ?, ?)`,
+		delete: `DELETE FROM schema_migrations WHERE version = ?`,
+	},
+}
+
+// lockID is the advisory lock key shared by every process that migrates this database.
+const lockID int64 = 0x1A7E5F3C0D17
+
+var (
+	// ErrSchemaBehind means the database is missing migrations this binary expects.
+	ErrSchemaBehind = errors.New("database schema is behind the application")
+	// ErrSchemaAhead means the database has migrations this binary does not know about.
+	ErrSchemaAhead = errors.New("database schema is ahead of the application")
+)
+
+var fileName = regexp.MustCompile(`^(\d+)_([a-z0-9_]+)\.(up|down)\.sql$`)
+
+// Migration is one numbered schema change.
+type Migration struct {
+	Version int
+	Name    string
+	Up      string
+	Down    string
+}
+
+// Status reports whether a migration has been applied, and when.
+type Status struct {
+	Migration
+	AppliedAt *time.Time
+}
+
+// Migrator applies migrations to a database.
+type Migrator struct {
+	db         *sql.DB
+	stmts      statements
+	migrations []Migration
+}
+
+// New returns a Migrator for the migrations of dialect embedded in the binary.
+func New(db *sql.DB, dialect Dialect) (*Migrator, error) {
+	sub, err := fs.Sub(files, path.Join("sql", string(dialect)))
+	if err != nil {
+		return nil, err
+	}
+	return NewWithSource(db, dialect, sub)
+}
+
+// NewWithSource returns a Migrator for the migration files at the root of fsys.
+func NewWithSource(db *sql.DB, dialect Dialect, fsys fs.FS) (*Migrator, error) {
+	stmts, ok := dialects[dialect]
+	if !ok {
+		return nil, fmt.Errorf("unsupported migration dialect %q", dialect)
+	}
+	migrations, err := load(fsys)
+	if err != nil {
+		return nil, err
+	}
+	return &Migrator{db: db, stmts: stmts, migrations: migrations}, nil
+}
+
+// load parses the migration files and checks that versions are contiguous and every up has a down.
+func load(fsys fs.FS) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, fmt.Errorf("read migrations: %w", err)
+	}
+	byVersion := make(map[int]*Migration)
+	for _, entry := range entries {
+		m := fileName.FindStringSubmatch(entry.Name())
+		if entry.IsDir() || m == nil {
+			return nil, fmt.Errorf("unexpected migration file %q", entry.Name())
+		}
+		version, _ := strconv.Atoi(m[1])
+		body, err := fs.ReadFile(fsys, path.Clean(entry.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("read migration %s: %w", entry.Name(), err)
+		}
+		mig, ok := byVersion[version]
+		if !ok {
+			mig = &Migration{Version: version, Name: m[2]}
+			byVersion[version] = mig
+		} else if mig.Name != m[2] {
+			return nil, fmt.Errorf("migration %d has conflicting names %q and %q", version, mig.Name, m[2])
+		}
+		if m[3] == "up" {
+			mig.Up = string(body)
+		} else {
+			mig.Down = string(body)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, mig := range byVersion {
+		if mig.Up == "" || mig.Down == "" {
+			return nil, fmt.Errorf("migration %d_%s needs both an up and a down file", mig.Version, mig.Name)
+		}
+		migrations = append(migrations, *mig)
+	}
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+	for i, mig := range migrations {
+		if mig.Version != i+1 {
+			return nil, fmt.Errorf("migration versions must start at 1 without gaps, found %d at position %d", mig.Version, i+1)
+		}
+	}
+	return migrations, nil
+}
+
+// Latest returns the version the application expects the schema to be at.
+func (m *Migrator) Latest() int {
+	return len(m.migrations)
+}
+
+// Up applies every pending migration and returns the ones it applied.
+func (m *Migrator) Up(ctx context.Context) ([]Migration, error) {
+	var applied []Migration
+	err := m.withLock(ctx, func(conn *sql.Conn) error {
+		current, err := currentVersion(ctx, conn)
+		if err != nil {
+			return err
+		}
+		if current > m.Latest() {
+			return fmt.Errorf("%w: database at version %d, application knows %d", ErrSchemaAhead, current, m.Latest())
+		}
+		for _, mig := range m.migrations[current:] {
+			if err := m.apply(ctx, conn, mig, true); err != nil {
+				return err
+			}
+			applied = append(applied, mig)
+		}
+		return nil
+	})
+	return applied, err
+}
+
+// Down rolls back the last steps applied migrations and returns the ones it rolled back.
+func (m *Migrator) Down(ctx context.Context, steps int) ([]Migration, error) {
+	var reverted []Migration
+	err := m.withLock(ctx, func(conn *sql.Conn) error {
+		current, err := currentVersion(ctx, conn)
+		if err != nil {
+			return err
+		}
+		if current > m.Latest() {
+			return fmt.Errorf("%w: cannot roll back version %d without its migration file", ErrSchemaAhead, current)
+		}
+		for ; steps > 0 && current > 0; steps-- {
+			mig := m.migrations[current-1]
+			if err := m.apply(ctx, conn, mig, false); err != nil {
+				return err
+			}
+			reverted = append(reverted, mig)
+			current--
+		}
+		return nil
+	})
+	return reverted, err
+}
+
+// Status lists every known migration with its applied time.
+func (m *Migrator) Status(ctx context.Context) ([]Status, error) {
+	applied, err := m.appliedAt(ctx)
+	if err != nil {
+		return nil, err
+	}
+	statuses := make([]Status, len(m.migrations))
+	for i, mig := range m.migrations {
+		statuses[i] = Status{Migration: mig}
+		if at, ok := applied[mig.Version]; ok {
+			statuses[i].AppliedAt = &at
+		}
+	}
+	return statuses, nil
+}
+
+// Check returns ErrSchemaBehind or ErrSchemaAhead unless the database is exactly at Latest.
+func (m *Migrator) Check(ctx context.Context) error {
+	applied, err := m.appliedAt(ctx)
+	if err != nil {
+		return err
+	}
+	current := 0
+	for version := range applied {
+		current = max(current, version)
+	}
+	switch {
+	case current < m.Latest():
+		return fmt.Errorf("%w: database at version %d, application expects %d", ErrSchemaBehind, current, m.Latest())
+	case current > m.Latest():
+		return fmt.Errorf("%w: database at version %d, application expects %d", ErrSchemaAhead, current, m.Latest())
+	}
+	return nil
+}
+
+func (m *Migrator) appliedAt(ctx context.Context) (map[int]time.Time, error) {
+	applied := make(map[int]time.Time)
+	var exists bool
+	if err := m.db.QueryRowContext(ctx, m.stmts.tableExists).Scan(&exists); err != nil {
+		return nil, fmt.Errorf("look up schema_migrations: %w", err)
+	}
+	if !exists {
+		return applied, nil
+	}
+	rows, err := m.db.QueryContext(ctx, `SELECT version, applied_at FROM schema_migrations`)
+	if err != nil {
+		return nil, fmt.Errorf("read schema_migrations: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var version int
+		var at time.Time
+		if err := rows.Scan(&version, &at); err != nil {
+			return nil, err
+		}
+		applied[version] = at
+	}
+	return applied, rows.Err()
+}
+
+// withLock runs fn on a single connection holding the migration advisory lock.
+func (m *Migrator) withLock(ctx context.Context, fn func(conn *sql.Conn) error) error {
+	conn, err := m.db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	if m.stmts.lock != "" {
+		if _, err := conn.ExecContext(ctx, m.stmts.lock, lockID); err != nil {
+			return fmt.Errorf("acquire migration lock: %w", err)
+		}
+		// Unlock with a fresh context so a cancelled run still releases the session lock.
+		defer func() {
+			_, _ = conn.ExecContext(context.WithoutCancel(ctx), m.stmts.unlock, lockID)
+		}()
+	}
+
+	if _, err := conn.ExecContext(ctx, m.stmts.createTable); err != nil {
+		return fmt.Errorf("create schema_migrations: %w", err)
+	}
+	return fn(conn)
+}
+
+func currentVersion(ctx context.Context, conn *sql.Conn) (int, error) {
+	var version int
+	if err := conn.QueryRowContext(ctx, `SELECT COALESCE(MAX(version), 0) FROM schema_migrations`).Scan(&version); err != nil {
+		return 0, fmt.Errorf("read schema version: %w", err)
+	}
+	return version, nil
+}
+
+// apply runs one migration and records it in schema_migrations within the same transaction.
+func (m *Migrator) apply(ctx context.Context, conn *sql.Conn, mig Migration, up bool) error {
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	script, record, args := mig.Up, m.stmts.insert, []any{mig.Version, mig.Name, time.Now().UTC()}
+	direction := "up"
+	if !up {
+		script, record, args = mig.Down, m.stmts.delete, []any{mig.Version}
+		direction = "down"
+	}
+	if _, err := tx.ExecContext(ctx, script); err != nil {
+		return fmt.Errorf("migration %d_%s %s: %w", mig.Version, mig.Name, direction, err)
+	}
+	if _, err := tx.ExecContext(ctx, record, args...); err != nil {
+		return fmt.Errorf("record migration %d_%s %s: %w", mig.Version, mig.Name, direction, err)
+	}
+	return tx.Commit()
+}
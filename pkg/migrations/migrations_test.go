@@ -0,0 +1,153 @@
+package migrations
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNew_EmbeddedMigrationsAreValid(t *testing.T) {
+	pg, err := New(nil, Postgres)
+	require.NoError(t, err)
+	lite, err := New(nil, SQLite)
+	require.NoError(t, err)
+
+	// Every dialect must describe the same schema versions.
+	require.Equal(t, pg.Latest(), lite.Latest())
+	for i := range pg.migrations {
+		assert.Equal(t, i+1, pg.migrations[i].Version)
+		assert.Equal(t, pg.migrations[i].Name, lite.migrations[i].Name)
+	}
+}
+
+func TestLoad_RejectsInvalidSources(t *testing.T) {
+	tests := []struct {
+		name string
+		fsys fstest.MapFS
+	}{
+		{
+			name: "missing down",
+			fsys: fstest.MapFS{"0001_init.up.sql": {Data: []byte("SELECT 1")}},
+		},
+		{
+			name: "version gap",
+			fsys: fstest.MapFS{
+				"0001_init.up.sql":   {Data: []byte("SELECT 1")},
+				"0001_init.down.sql": {Data: []byte("SELECT 1")},
+				"0003_next.up.sql":   {Data: []byte("SELECT 1")},
+				"0003_next.down.sql": {Data: []byte("SELECT 1")},
+			},
+		},
+		{
+			name: "unexpected file",
+			fsys: fstest.MapFS{"README.md": {Data: []byte("notes")}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := load(tt.fsys)
+			assert.Error(t, err)
+		})
+	}
+}
+
+var testSource = fstest.MapFS{
+	"0001_init.up.sql":    {Data: []byte("CREATE TABLE a (id INT)")},
+	"0001_init.down.sql":  {Data: []byte("DROP TABLE a")},
+	"0002_index.up.sql":   {Data: []byte("CREATE INDEX idx_a ON a (id)")},
+	"0002_index.down.sql": {Data: []byte("DROP INDEX idx_a")},
+}
+
+func TestMigrator_UpAppliesPendingUnderLock(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	m, err := NewWithSource(db, Postgres, testSource)
+	require.NoError(t, err)
+
+	mock.ExpectExec(`SELECT pg_advisory_lock`).WithArgs(lockID).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(`CREATE TABLE IF NOT EXISTS schema_migrations`).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(`SELECT COALESCE\(MAX\(version\), 0\) FROM schema_migrations`).
+		WillReturnRows(sqlmock.NewRows([]string{"version"}).AddRow(1))
+	mock.ExpectBegin()
+	mock.ExpectExec(`CREATE INDEX idx_a ON a \(id\)`).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(`INSERT INTO schema_migrations`).WithArgs(2, "index", sqlmock.AnyArg()).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	mock.ExpectExec(`SELECT pg_advisory_unlock`).WithArgs(lockID).WillReturnResult(sqlmock.NewResult(0, 0))
+
+	applied, err := m.Up(context.Background())
+	require.NoError(t, err)
+	require.Len(t, applied, 1)
+	assert.Equal(t, 2, applied[0].Version)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMigrator_DownRollsBackAndReleasesLockOnFailure(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	m, err := NewWithSource(db, Postgres, testSource)
+	require.NoError(t, err)
+
+	mock.ExpectExec(`SELECT pg_advisory_lock`).WithArgs(lockID).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(`CREATE TABLE IF NOT EXISTS schema_migrations`).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(`SELECT COALESCE`).WillReturnRows(sqlmock.NewRows([]string{"version"}).AddRow(2))
+	mock.ExpectBegin()
+	mock.ExpectExec(`DROP INDEX idx_a`).WillReturnError(errors.New("boom"))
+	mock.ExpectRollback()
+	mock.ExpectExec(`SELECT pg_advisory_unlock`).WithArgs(lockID).WillReturnResult(sqlmock.NewResult(0, 0))
+
+	reverted, err := m.Down(context.Background(), 1)
+	assert.ErrorContains(t, err, "migration 2_index down")
+	assert.Empty(t, reverted)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMigrator_Check(t *testing.T) {
+	tests := []struct {
+		name     string
+		exists   bool
+		versions []int
+		want     error
+	}{
+		{name: "fresh database", exists: false, want: ErrSchemaBehind},
+		{name: "behind", exists: true, versions: []int{1}, want: ErrSchemaBehind},
+		{name: "current", exists: true, versions: []int{1, 2}},
+		{name: "ahead", exists: true, versions: []int{1, 2, 3}, want: ErrSchemaAhead},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			require.NoError(t, err)
+			defer db.Close()
+
+			m, err := NewWithSource(db, Postgres, testSource)
+			require.NoError(t, err)
+
+			mock.ExpectQuery(`SELECT to_regclass`).WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(tt.exists))
+			if tt.exists {
+				rows := sqlmock.NewRows([]string{"version", "applied_at"})
+				for _, v := range tt.versions {
+					rows.AddRow(v, time.Now())
+				}
+				mock.ExpectQuery(`SELECT version, applied_at FROM schema_migrations`).WillReturnRows(rows)
+			}
+
+			err = m.Check(context.Background())
+			if tt.want == nil {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, tt.want)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS rate_limit_buckets;
+DROP TABLE IF EXISTS audit_records;
+DROP TABLE IF EXISTS api_keys;
+DROP TABLE IF EXISTS transactions;
+DROP TABLE IF EXISTS accounts;
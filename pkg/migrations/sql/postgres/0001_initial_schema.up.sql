@@ -0,0 +1,63 @@
+-- Tables as previously created by GORM AutoMigrate. IF NOT EXISTS lets databases that were
+-- bootstrapped by AutoMigrate adopt versioned migrations without changes.
+CREATE TABLE IF NOT EXISTS accounts (
+    id         BIGSERIAL PRIMARY KEY,
+    created_at TIMESTAMPTZ,
+    updated_at TIMESTAMPTZ,
+    deleted_at TIMESTAMPTZ,
+    account_id BIGINT,
+    balance    TEXT
+);
+CREATE INDEX IF NOT EXISTS idx_accounts_deleted_at ON accounts (deleted_at);
+
+CREATE TABLE IF NOT EXISTS transactions (
+    id                     BIGSERIAL PRIMARY KEY,
+    source_account_id      BIGINT,
+    destination_account_id BIGINT,
+    amount                 TEXT,
+    created_at             TIMESTAMPTZ
+);
+
+CREATE TABLE IF NOT EXISTS api_keys (
+    id           BIGSERIAL PRIMARY KEY,
+    name         TEXT,
+    prefix       TEXT,
+    salt         TEXT,
+    key_hash     TEXT,
+    scopes       TEXT,
+    allowed_ips  TEXT,
+    expires_at   TIMESTAMPTZ,
+    revoked_at   TIMESTAMPTZ,
+    rotated_to   BIGINT,
+    last_used_at TIMESTAMPTZ,
+    created_at   TIMESTAMPTZ,
+    updated_at   TIMESTAMPTZ
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_api_keys_prefix ON api_keys (prefix);
+
+CREATE TABLE IF NOT EXISTS audit_records (
+    id                BIGSERIAL PRIMARY KEY,
+    occurred_at       TIMESTAMPTZ,
+    actor             TEXT,
+    request_id        TEXT,
+    method            TEXT,
+    route             TEXT,
+    target_account_id BIGINT,
+    before            TEXT,
+    after             TEXT,
+    outcome           TEXT,
+    status_code       BIGINT,
+    error_message     TEXT
+);
+CREATE INDEX IF NOT EXISTS idx_audit_records_occurred_at ON audit_records (occurred_at);
+CREATE INDEX IF NOT EXISTS idx_audit_records_actor ON audit_records (actor);
+CREATE INDEX IF NOT EXISTS idx_audit_records_request_id ON audit_records (request_id);
+CREATE INDEX IF NOT EXISTS idx_audit_records_route ON audit_records (route);
+CREATE INDEX IF NOT EXISTS idx_audit_records_target_account_id ON audit_records (target_account_id);
+CREATE INDEX IF NOT EXISTS idx_audit_records_outcome ON audit_records (outcome);
+
+CREATE TABLE IF NOT EXISTS rate_limit_buckets (
+    key        TEXT PRIMARY KEY,
+    tokens     DOUBLE PRECISION,
+    updated_at TIMESTAMPTZ
+);
@@ -0,0 +1,3 @@
+DROP TRIGGER IF EXISTS audit_records_no_truncate ON audit_records;
+DROP TRIGGER IF EXISTS audit_records_no_modify ON audit_records;
+DROP FUNCTION IF EXISTS audit_records_append_only();
@@ -0,0 +1,15 @@
+-- Reject UPDATE, DELETE and TRUNCATE on the audit table, so the trail stays immutable even for
+-- callers that bypass the audit repository.
+CREATE OR REPLACE FUNCTION audit_records_append_only() RETURNS trigger AS $$
+BEGIN
+    RAISE EXCEPTION 'audit_records is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS audit_records_no_modify ON audit_records;
+CREATE TRIGGER audit_records_no_modify BEFORE UPDATE OR DELETE ON audit_records
+    FOR EACH ROW EXECUTE FUNCTION audit_records_append_only();
+
+DROP TRIGGER IF EXISTS audit_records_no_truncate ON audit_records;
+CREATE TRIGGER audit_records_no_truncate BEFORE TRUNCATE ON audit_records
+    FOR EACH STATEMENT EXECUTE FUNCTION audit_records_append_only();
@@ -0,0 +1,11 @@
+DROP INDEX IF EXISTS idx_transactions_created_at;
+DROP INDEX IF EXISTS idx_transactions_destination_account_id_created_at;
+DROP INDEX IF EXISTS idx_transactions_source_account_id_created_at;
+
+ALTER TABLE transactions DROP CONSTRAINT IF EXISTS fk_transactions_destination_account;
+ALTER TABLE transactions DROP CONSTRAINT IF EXISTS fk_transactions_source_account;
+ALTER TABLE transactions ALTER COLUMN destination_account_id DROP NOT NULL;
+ALTER TABLE transactions ALTER COLUMN source_account_id DROP NOT NULL;
+
+ALTER TABLE accounts DROP CONSTRAINT IF EXISTS uq_accounts_account_id;
+ALTER TABLE accounts ALTER COLUMN account_id DROP NOT NULL;
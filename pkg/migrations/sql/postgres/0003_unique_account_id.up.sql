@@ -0,0 +1,52 @@
+-- Report rows that would violate the new constraints instead of failing on the first one, so
+-- operators can clean the data up before rerunning the migration.
+DO $$
+DECLARE
+    problems TEXT;
+BEGIN
+    SELECT string_agg(format('account_id %s has %s rows', account_id, n), '; ' ORDER BY account_id)
+      INTO problems
+      FROM (
+        SELECT account_id, count(*) AS n
+          FROM accounts
+         GROUP BY account_id
+        HAVING count(*) > 1
+      ) duplicates;
+    IF problems IS NOT NULL THEN
+        RAISE EXCEPTION 'accounts has duplicate account_id values: %', problems
+            USING HINT = 'Merge or remove the duplicate accounts, then rerun the migration.';
+    END IF;
+
+    IF EXISTS (SELECT 1 FROM accounts WHERE account_id IS NULL) THEN
+        RAISE EXCEPTION 'accounts has rows without an account_id'
+            USING HINT = 'Assign or remove those accounts, then rerun the migration.';
+    END IF;
+
+    SELECT string_agg(DISTINCT missing::TEXT, ', ')
+      INTO problems
+      FROM (
+        SELECT source_account_id AS missing FROM transactions
+        UNION
+        SELECT destination_account_id FROM transactions
+      ) referenced
+     WHERE NOT EXISTS (SELECT 1 FROM accounts a WHERE a.account_id = referenced.missing);
+    IF problems IS NOT NULL THEN
+        RAISE EXCEPTION 'transactions reference unknown account_id values: %', problems
+            USING HINT = 'Restore the missing accounts, then rerun the migration.';
+    END IF;
+END
+$$;
+
+ALTER TABLE accounts ALTER COLUMN account_id SET NOT NULL;
+ALTER TABLE accounts ADD CONSTRAINT uq_accounts_account_id UNIQUE (account_id);
+
+ALTER TABLE transactions ALTER COLUMN source_account_id SET NOT NULL;
+ALTER TABLE transactions ALTER COLUMN destination_account_id SET NOT NULL;
+ALTER TABLE transactions ADD CONSTRAINT fk_transactions_source_account
+    FOREIGN KEY (source_account_id) REFERENCES accounts (account_id);
+ALTER TABLE transactions ADD CONSTRAINT fk_transactions_destination_account
+    FOREIGN KEY (destination_account_id) REFERENCES accounts (account_id);
+
+CREATE INDEX idx_transactions_source_account_id_created_at ON transactions (source_account_id, created_at);
+CREATE INDEX idx_transactions_destination_account_id_created_at ON transactions (destination_account_id, created_at);
+CREATE INDEX idx_transactions_created_at ON transactions (created_at);
@@ -0,0 +1,3 @@
+-- Accounts created before imports carried a currency or metadata keep empty values.
+ALTER TABLE accounts ADD COLUMN currency TEXT NOT NULL DEFAULT '';
+ALTER TABLE accounts ADD COLUMN metadata TEXT NOT NULL DEFAULT '';
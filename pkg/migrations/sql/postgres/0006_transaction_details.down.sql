@@ -0,0 +1,5 @@
+DROP TABLE transaction_metadata;
+DROP INDEX idx_transactions_reference_source_account_id;
+ALTER TABLE transactions DROP COLUMN metadata;
+ALTER TABLE transactions DROP COLUMN description;
+ALTER TABLE transactions DROP COLUMN reference;
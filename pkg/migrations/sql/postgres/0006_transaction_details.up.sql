@@ -0,0 +1,17 @@
+-- Transfers made before references, descriptions and metadata existed keep empty values.
+ALTER TABLE transactions ADD COLUMN reference TEXT NOT NULL DEFAULT '';
+ALTER TABLE transactions ADD COLUMN description TEXT NOT NULL DEFAULT '';
+ALTER TABLE transactions ADD COLUMN metadata TEXT NOT NULL DEFAULT '';
+-- Serves searches by reference and the per source account uniqueness check.
+CREATE INDEX idx_transactions_reference_source_account_id ON transactions (reference, source_account_id);
+
+-- One row per top-level metadata key, so transactions can be looked up by key and value.
+CREATE TABLE transaction_metadata (
+    id             BIGSERIAL PRIMARY KEY,
+    transaction_id BIGINT NOT NULL
+        CONSTRAINT fk_transaction_metadata_transaction REFERENCES transactions (id) ON DELETE CASCADE,
+    key            TEXT NOT NULL,
+    value          TEXT NOT NULL
+);
+CREATE INDEX idx_transaction_metadata_transaction_id ON transaction_metadata (transaction_id);
+CREATE INDEX idx_transaction_metadata_key_value ON transaction_metadata (key, value);
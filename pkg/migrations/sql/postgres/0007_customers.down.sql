@@ -0,0 +1,4 @@
+ALTER TABLE transactions DROP COLUMN internal;
+DROP INDEX idx_accounts_customer_id;
+ALTER TABLE accounts DROP COLUMN customer_id;
+DROP TABLE customers;
@@ -0,0 +1,61 @@
+CREATE TABLE IF NOT EXISTS accounts (
+    id         INTEGER PRIMARY KEY AUTOINCREMENT,
+    created_at DATETIME,
+    updated_at DATETIME,
+    deleted_at DATETIME,
+    account_id INTEGER,
+    balance    TEXT
+);
+CREATE INDEX IF NOT EXISTS idx_accounts_deleted_at ON accounts (deleted_at);
+
+CREATE TABLE IF NOT EXISTS transactions (
+    id                     INTEGER PRIMARY KEY AUTOINCREMENT,
+    source_account_id      INTEGER,
+    destination_account_id INTEGER,
+    amount                 TEXT,
+    created_at             DATETIME
+);
+
+CREATE TABLE IF NOT EXISTS api_keys (
+    id           INTEGER PRIMARY KEY AUTOINCREMENT,
+    name         TEXT,
+    prefix       TEXT,
+    salt         TEXT,
+    key_hash     TEXT,
+    scopes       TEXT,
+    allowed_ips  TEXT,
+    expires_at   DATETIME,
+    revoked_at   DATETIME,
+    rotated_to   INTEGER,
+    last_used_at DATETIME,
+    created_at   DATETIME,
+    updated_at   DATETIME
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_api_keys_prefix ON api_keys (prefix);
+
+CREATE TABLE IF NOT EXISTS audit_records (
+    id                INTEGER PRIMARY KEY AUTOINCREMENT,
+    occurred_at       DATETIME,
+    actor             TEXT,
+    request_id        TEXT,
+    method            TEXT,
+    route             TEXT,
+    target_account_id INTEGER,
+    before            TEXT,
+    after             TEXT,
+    outcome           TEXT,
+    status_code       INTEGER,
+    error_message     TEXT
+);
+CREATE INDEX IF NOT EXISTS idx_audit_records_occurred_at ON audit_records (occurred_at);
+CREATE INDEX IF NOT EXISTS idx_audit_records_actor ON audit_records (actor);
+CREATE INDEX IF NOT EXISTS idx_audit_records_request_id ON audit_records (request_id);
+CREATE INDEX IF NOT EXISTS idx_audit_records_route ON audit_records (route);
+CREATE INDEX IF NOT EXISTS idx_audit_records_target_account_id ON audit_records (target_account_id);
+CREATE INDEX IF NOT EXISTS idx_audit_records_outcome ON audit_records (outcome);
+
+CREATE TABLE IF NOT EXISTS rate_limit_buckets (
+    key        TEXT PRIMARY KEY,
+    tokens     REAL,
+    updated_at DATETIME
+);
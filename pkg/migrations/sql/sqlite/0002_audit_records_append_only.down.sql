@@ -0,0 +1,2 @@
+DROP TRIGGER IF EXISTS audit_records_no_delete;
+DROP TRIGGER IF EXISTS audit_records_no_update;
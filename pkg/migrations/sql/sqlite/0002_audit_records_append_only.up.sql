@@ -0,0 +1,11 @@
+-- Reject UPDATE and DELETE on the audit table. SQLite has no TRUNCATE; an unqualified DELETE
+-- is caught by the delete trigger.
+CREATE TRIGGER IF NOT EXISTS audit_records_no_update BEFORE UPDATE ON audit_records
+BEGIN
+    SELECT RAISE(ABORT, 'audit_records is append-only');
+END;
+
+CREATE TRIGGER IF NOT EXISTS audit_records_no_delete BEFORE DELETE ON audit_records
+BEGIN
+    SELECT RAISE(ABORT, 'audit_records is append-only');
+END;
@@ -0,0 +1,25 @@
+CREATE TABLE transactions_old (
+    id                     INTEGER PRIMARY KEY AUTOINCREMENT,
+    source_account_id      INTEGER,
+    destination_account_id INTEGER,
+    amount                 TEXT,
+    created_at             DATETIME
+);
+INSERT INTO transactions_old (id, source_account_id, destination_account_id, amount, created_at)
+SELECT id, source_account_id, destination_account_id, amount, created_at FROM transactions;
+DROP TABLE transactions;
+ALTER TABLE transactions_old RENAME TO transactions;
+
+CREATE TABLE accounts_old (
+    id         INTEGER PRIMARY KEY AUTOINCREMENT,
+    created_at DATETIME,
+    updated_at DATETIME,
+    deleted_at DATETIME,
+    account_id INTEGER,
+    balance    TEXT
+);
+INSERT INTO accounts_old (id, created_at, updated_at, deleted_at, account_id, balance)
+SELECT id, created_at, updated_at, deleted_at, account_id, balance FROM accounts;
+DROP TABLE accounts;
+ALTER TABLE accounts_old RENAME TO accounts;
+CREATE INDEX idx_accounts_deleted_at ON accounts (deleted_at);
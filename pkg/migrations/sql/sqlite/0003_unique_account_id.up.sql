@@ -0,0 +1,58 @@
+-- SQLite cannot format values into an error, so each check inserts a marker row whose trigger
+-- aborts the migration with a message naming the query that lists the offending rows.
+CREATE TEMP TABLE migration_0003_check (problem TEXT NOT NULL);
+CREATE TEMP TRIGGER migration_0003_abort BEFORE INSERT ON migration_0003_check
+BEGIN
+    SELECT CASE NEW.problem
+        WHEN 'duplicates' THEN RAISE(ABORT, 'accounts has duplicate account_id values: SELECT account_id, count(*) FROM accounts GROUP BY account_id HAVING count(*) > 1')
+        WHEN 'missing' THEN RAISE(ABORT, 'accounts has rows without an account_id: SELECT id FROM accounts WHERE account_id IS NULL')
+        WHEN 'orphans' THEN RAISE(ABORT, 'transactions reference unknown account_id values: SELECT id FROM transactions WHERE source_account_id NOT IN (SELECT account_id FROM accounts) OR destination_account_id NOT IN (SELECT account_id FROM accounts)')
+    END;
+END;
+
+INSERT INTO migration_0003_check (problem)
+SELECT 'duplicates' WHERE EXISTS (SELECT 1 FROM accounts GROUP BY account_id HAVING count(*) > 1);
+INSERT INTO migration_0003_check (problem)
+SELECT 'missing' WHERE EXISTS (SELECT 1 FROM accounts WHERE account_id IS NULL);
+INSERT INTO migration_0003_check (problem)
+SELECT 'orphans' WHERE EXISTS (
+    SELECT 1 FROM transactions t
+     WHERE NOT EXISTS (SELECT 1 FROM accounts a WHERE a.account_id = t.source_account_id)
+        OR NOT EXISTS (SELECT 1 FROM accounts a WHERE a.account_id = t.destination_account_id)
+);
+
+DROP TRIGGER migration_0003_abort;
+DROP TABLE migration_0003_check;
+
+-- SQLite cannot add constraints to existing tables, so both tables are rebuilt.
+CREATE TABLE accounts_new (
+    id         INTEGER PRIMARY KEY AUTOINCREMENT,
+    created_at DATETIME,
+    updated_at DATETIME,
+    deleted_at DATETIME,
+    account_id INTEGER NOT NULL CONSTRAINT uq_accounts_account_id UNIQUE,
+    balance    TEXT
+);
+INSERT INTO accounts_new (id, created_at, updated_at, deleted_at, account_id, balance)
+SELECT id, created_at, updated_at, deleted_at, account_id, balance FROM accounts;
+DROP TABLE accounts;
+ALTER TABLE accounts_new RENAME TO accounts;
+CREATE INDEX idx_accounts_deleted_at ON accounts (deleted_at);
+
+CREATE TABLE transactions_new (
+    id                     INTEGER PRIMARY KEY AUTOINCREMENT,
+    source_account_id      INTEGER NOT NULL
+        CONSTRAINT fk_transactions_source_account REFERENCES accounts (account_id),
+    destination_account_id INTEGER NOT NULL
+        CONSTRAINT fk_transactions_destination_account REFERENCES accounts (account_id),
+    amount                 TEXT,
+    created_at             DATETIME
+);
+INSERT INTO transactions_new (id, source_account_id, destination_account_id, amount, created_at)
+SELECT id, source_account_id, destination_account_id, amount, created_at FROM transactions;
+DROP TABLE transactions;
+ALTER TABLE transactions_new RENAME TO transactions;
+
+CREATE INDEX idx_transactions_source_account_id_created_at ON transactions (source_account_id, created_at);
+CREATE INDEX idx_transactions_destination_account_id_created_at ON transactions (destination_account_id, created_at);
+CREATE INDEX idx_transactions_created_at ON transactions (created_at);
@@ -0,0 +1,2 @@
+-- Frozen accounts can neither send nor receive transfers until they are unfrozen.
+ALTER TABLE accounts ADD COLUMN frozen_at DATETIME;
@@ -0,0 +1,19 @@
+-- Customers own accounts; accounts created before customers existed have no owner.
+CREATE TABLE customers (
+    id         INTEGER PRIMARY KEY AUTOINCREMENT,
+    name       TEXT NOT NULL,
+    type       TEXT NOT NULL CONSTRAINT ck_customers_type CHECK (type IN ('individual', 'business')),
+    kyc_status TEXT NOT NULL DEFAULT 'pending'
+        CONSTRAINT ck_customers_kyc_status CHECK (kyc_status IN ('pending', 'verified', 'rejected')),
+    email      TEXT NOT NULL DEFAULT '',
+    phone      TEXT NOT NULL DEFAULT '',
+    address    TEXT NOT NULL DEFAULT '',
+    created_at DATETIME,
+    updated_at DATETIME
+);
+
+ALTER TABLE accounts ADD COLUMN customer_id INTEGER CONSTRAINT fk_accounts_customer REFERENCES customers (id);
+CREATE INDEX idx_accounts_customer_id ON accounts (customer_id);
+
+-- Set on transfers between two accounts of the same customer.
+ALTER TABLE transactions ADD COLUMN internal BOOLEAN NOT NULL DEFAULT FALSE;
@@ -0,0 +1,77 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"testing"
+
+	"github.com/rohanchauhan02/internal-transfer/pkg/config"
+	"github.com/rohanchauhan02/internal-transfer/pkg/database"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func openSQLite(t *testing.T) *sql.DB {
+	t.Helper()
+	db, err := database.NewSQLite(config.DB{Path: filepath.Join(t.TempDir(), "migrations.db")}).InitClient(context.Background())
+	require.NoError(t, err)
+	sqlDB, err := db.DB()
+	require.NoError(t, err)
+	t.Cleanup(func() { sqlDB.Close() })
+	return sqlDB
+}
+
+func TestMigrator_SQLiteUpAndDown(t *testing.T) {
+	ctx := context.Background()
+	db := openSQLite(t)
+	m, err := New(db, SQLite)
+	require.NoError(t, err)
+
+	assert.ErrorIs(t, m.Check(ctx), ErrSchemaBehind)
+	applied, err := m.Up(ctx)
+	require.NoError(t, err)
+	assert.Len(t, applied, m.Latest())
+	require.NoError(t, m.Check(ctx))
+
+	statuses, err := m.Status(ctx)
+	require.NoError(t, err)
+	for _, s := range statuses {
+		assert.NotNil(t, s.AppliedAt, "migration %d", s.Version)
+	}
+
+	_, err = db.ExecContext(ctx, `INSERT INTO accounts (account_id, balance) VALUES (1, '10')`)
+	require.NoError(t, err)
+	_, err = db.ExecContext(ctx, `INSERT INTO accounts (account_id, balance) VALUES (1, '20')`)
+	assert.ErrorContains(t, err, "UNIQUE")
+	_, err = db.ExecContext(ctx, `INSERT INTO transactions (source_account_id, destination_account_id, amount) VALUES (1, 99, '1')`)
+	assert.ErrorContains(t, err, "FOREIGN KEY")
+	_, err = db.ExecContext(ctx, `INSERT INTO audit_records (actor) VALUES ('a')`)
+	require.NoError(t, err)
+	_, err = db.ExecContext(ctx, `DELETE FROM audit_records`)
+	assert.ErrorContains(t, err, "append-only")
+
+	reverted, err := m.Down(ctx, m.Latest())
+	require.NoError(t, err)
+	assert.Len(t, reverted, m.Latest())
+	assert.ErrorIs(t, m.Check(ctx), ErrSchemaBehind)
+}
+
+func TestMigrator_SQLiteReportsDuplicateAccounts(t *testing.T) {
+	ctx := context.Background()
+	db := openSQLite(t)
+	m, err := New(db, SQLite)
+	require.NoError(t, err)
+
+	_, err = m.Up(ctx)
+	require.NoError(t, err)
+	// Roll back to before 0003 added the unique constraint
+	_, err = m.Down(ctx, m.Latest()-2)
+	require.NoError(t, err)
+	_, err = db.ExecContext(ctx, `INSERT INTO accounts (account_id, balance) VALUES (5, '1'), (5, '2')`)
+	require.NoError(t, err)
+
+	_, err = m.Up(ctx)
+	assert.ErrorContains(t, err, "duplicate account_id")
+	assert.ErrorIs(t, m.Check(ctx), ErrSchemaBehind)
+}
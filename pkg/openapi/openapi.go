@@ -0,0 +1,320 @@
+// Package openapi generates an OpenAPI 3.1 document from echo routes and the DTO structs they
+// exchange. Delivery packages describe each route with an Operation next to where they register
+// it; Generate fails when a route has no Operation or an Operation has no route, so the document
+// cannot silently drift from the router.
+package openapi
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	"github.com/rohanchauhan02/internal-transfer/dto"
+)
+
+// Version is the OpenAPI version of generated documents.
+const Version = "3.1.0"
+
+// Security scheme names; operations with a Scope accept either.
+const (
+	securityBearer = "bearerAuth"
+	securityAPIKey = "apiKeyAuth"
+)
+
+// Operation describes one route.
+type Operation struct {
+	Method      string
+	Path        string // echo syntax, e.g. /api/v1/accounts/:id
+	ID          string
+	Summary     string
+	Description string
+	Tags        []string
+	// Scope is the api key scope the route requires; empty for public routes.
+	Scope      string
+	Parameters []Parameter
+	// Request is a zero value of the request body DTO, or nil.
+	Request any
+	// RequestMediaType documents a body that is not JSON, e.g. text/csv, as a string of that
+	// media type. Request is ignored when it is set.
+	RequestMediaType string
+	Responses        []Response
+}
+
+// Parameter is a path, query or header parameter.
+type Parameter struct {
+	Name        string
+	In          string // path, query or header
+	Description string
+	Required    bool
+	// Example is a zero value of the parameter type.
+	Example any
+}
+
+// Response is one documented status code.
+type Response struct {
+	Status      int
+	Description string
+	// Data is a zero value of the DTO carried in ResponsePattern.Data, or nil when there is none.
+	Data any
+	// Body, when set, replaces the ResponsePattern envelope with this DTO.
+	Body any
+	// MediaTypes are other media types the response can be sent as, e.g. text/csv; they are
+	// documented as binary strings. A response with MediaTypes but no Data or Body is only
+	// sent as those.
+	MediaTypes []string
+}
+
+// Info is the document's info object.
+type Info struct {
+	Title       string `json:"title"`
+	Version     string `json:"version"`
+	Description string `json:"description,omitempty"`
+}
+
+// Document is the subset of an OpenAPI 3.1 document the generator produces. Field order and
+// sorted map keys keep the encoded document stable.
+type Document struct {
+	OpenAPI    string               `json:"openapi"`
+	Info       Info                 `json:"info"`
+	Paths      map[string]*PathItem `json:"paths"`
+	Components Components           `json:"components"`
+}
+
+type PathItem struct {
+	Get    *OperationObject `json:"get,omitempty"`
+	Post   *OperationObject `json:"post,omitempty"`
+	Put    *OperationObject `json:"put,omitempty"`
+	Patch  *OperationObject `json:"patch,omitempty"`
+	Delete *OperationObject `json:"delete,omitempty"`
+}
+
+type OperationObject struct {
+	OperationID   string                     `json:"operationId"`
+	Summary       string                     `json:"summary,omitempty"`
+	Description   string                     `json:"description,omitempty"`
+	Tags          []string                   `json:"tags,omitempty"`
+	Parameters    []ParameterObject          `json:"parameters,omitempty"`
+	RequestBody   *RequestBody               `json:"requestBody,omitempty"`
+	Responses     map[string]*ResponseObject `json:"responses"`
+	Security      []map[string][]string      `json:"security,omitempty"`
+	RequiredScope string                     `json:"x-required-scope,omitempty"`
+}
+
+type ParameterObject struct {
+	Name        string  `json:"name"`
+	In          string  `json:"in"`
+	Description string  `json:"description,omitempty"`
+	Required    bool    `json:"required,omitempty"`
+	Schema      *Schema `json:"schema"`
+}
+
+type RequestBody struct {
+	Required bool                  `json:"required"`
+	Content  map[string]*MediaType `json:"content"`
+}
+
+type ResponseObject struct {
+	Description string                `json:"description"`
+	Content     map[string]*MediaType `json:"content,omitempty"`
+}
+
+type MediaType struct {
+	Schema *Schema `json:"schema"`
+}
+
+type Components struct {
+	Schemas         map[string]*Schema         `json:"schemas"`
+	SecuritySchemes map[string]*SecurityScheme `json:"securitySchemes"`
+}
+
+type SecurityScheme struct {
+	Type   string `json:"type"`
+	Scheme string `json:"scheme,omitempty"`
+	Name   string `json:"name,omitempty"`
+	In     string `json:"in,omitempty"`
+}
+
+// Generate builds the document for routes, described by ops.
+func Generate(info Info, routes []*echo.Route, ops []Operation) (*Document, error) {
+	doc := &Document{
+		OpenAPI: Version,
+		Info:    info,
+		Paths:   make(map[string]*PathItem),
+		Components: Components{
+			Schemas: make(map[string]*Schema),
+			SecuritySchemes: map[string]*SecurityScheme{
+				securityBearer: {Type: "http", Scheme: "bearer"},
+				securityAPIKey: {Type: "apiKey", Name: "X-API-Key", In: "header"},
+			},
+		},
+	}
+	if err := checkCoverage(routes, ops); err != nil {
+		return nil, err
+	}
+
+	schemas := newSchemaRegistry(doc.Components.Schemas)
+	envelope := schemas.ref(reflect.TypeOf(dto.ResponsePattern{}))
+	for _, op := range ops {
+		obj := &OperationObject{
+			OperationID:   op.ID,
+			Summary:       op.Summary,
+			Description:   op.Description,
+			Tags:          op.Tags,
+			Responses:     make(map[string]*ResponseObject),
+			RequiredScope: op.Scope,
+		}
+		if op.Scope != "" {
+			obj.Security = []map[string][]string{{securityBearer: {}}, {securityAPIKey: {}}}
+		}
+		for _, p := range op.Parameters {
+			obj.Parameters = append(obj.Parameters, ParameterObject{
+				Name:        p.Name,
+				In:          p.In,
+				Description: p.Description,
+				Required:    p.Required || p.In == "path",
+				Schema:      schemas.of(reflect.TypeOf(p.Example)),
+			})
+		}
+		switch {
+		case op.RequestMediaType != "":
+			obj.RequestBody = &RequestBody{
+				Required: true,
+				Content:  map[string]*MediaType{op.RequestMediaType: {Schema: &Schema{Type: "string"}}},
+			}
+		case op.Request != nil:
+			obj.RequestBody = &RequestBody{
+				Required: true,
+				Content:  jsonContent(schemas.ref(reflect.TypeOf(op.Request))),
+			}
+		}
+		for _, r := range op.Responses {
+			desc := r.Description
+			if desc == "" {
+				desc = http.StatusText(r.Status)
+			}
+			var body *Schema
+			switch {
+			case r.Body != nil:
+				body = schemas.ref(reflect.TypeOf(r.Body))
+			case r.Data != nil:
+				body = &Schema{AllOf: []*Schema{envelope, {
+					Type:       "object",
+					Properties: map[string]*Schema{"data": schemas.ref(reflect.TypeOf(r.Data))},
+				}}}
+			default:
+				body = envelope
+			}
+			content := map[string]*MediaType{}
+			if r.Body != nil || r.Data != nil || len(r.MediaTypes) == 0 {
+				content = jsonContent(body)
+			}
+			for _, mediaType := range r.MediaTypes {
+				content[mediaType] = &MediaType{Schema: &Schema{Type: "string", Format: "binary"}}
+			}
+			obj.Responses[strconv.Itoa(r.Status)] = &ResponseObject{Description: desc, Content: content}
+		}
+
+		path := openAPIPath(op.Path)
+		item, ok := doc.Paths[path]
+		if !ok {
+			item = &PathItem{}
+			doc.Paths[path] = item
+		}
+		if err := item.set(op.Method, obj); err != nil {
+			return nil, fmt.Errorf("%s %s: %w", op.Method, op.Path, err)
+		}
+	}
+	return doc, nil
+}
+
+// checkCoverage reports routes without an Operation and Operations without a route.
+func checkCoverage(routes []*echo.Route, ops []Operation) error {
+	documented := make(map[string]bool, len(ops))
+	for _, op := range ops {
+		key := op.Method + " " + op.Path
+		if documented[key] {
+			return fmt.Errorf("operation %s is described twice", key)
+		}
+		documented[key] = true
+	}
+	registered := make(map[string]bool, len(routes))
+	for _, r := range routes {
+		registered[r.Method+" "+r.Path] = true
+	}
+
+	var problems []string
+	for key := range registered {
+		if !documented[key] {
+			problems = append(problems, "route "+key+" has no operation")
+		}
+	}
+	for key := range documented {
+		if !registered[key] {
+			problems = append(problems, "operation "+key+" has no route")
+		}
+	}
+	if len(problems) > 0 {
+		sort.Strings(problems)
+		return fmt.Errorf("openapi: %s", strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+func (p *PathItem) set(method string, op *OperationObject) error {
+	var slot **OperationObject
+	switch method {
+	case http.MethodGet:
+		slot = &p.Get
+	case http.MethodPost:
+		slot = &p.Post
+	case http.MethodPut:
+		slot = &p.Put
+	case http.MethodPatch:
+		slot = &p.Patch
+	case http.MethodDelete:
+		slot = &p.Delete
+	default:
+		return fmt.Errorf("unsupported method")
+	}
+	if *slot != nil {
+		return fmt.Errorf("duplicate operation")
+	}
+	*slot = op
+	return nil
+}
+
+// openAPIPath converts echo path parameters (:id) to OpenAPI templates ({id}).
+func openAPIPath(path string) string {
+	parts := strings.Split(path, "/")
+	for i, part := range parts {
+		if name, ok := strings.CutPrefix(part, ":"); ok {
+			parts[i] = "{" + name + "}"
+		}
+	}
+	return strings.Join(parts, "/")
+}
+
+func jsonContent(schema *Schema) map[string]*MediaType {
+	return map[string]*MediaType{echo.MIMEApplicationJSON: {Schema: schema}}
+}
+
+// AuthResponses are the rejections every route behind api key authentication and rate limiting can return.
+func AuthResponses() []Response {
+	return []Response{
+		{Status: http.StatusUnauthorized, Description: "Missing, invalid, expired or revoked api key"},
+		{Status: http.StatusForbidden, Description: "Api key lacks the required scope or is used from a disallowed IP"},
+		{Status: http.StatusTooManyRequests, Description: "Rate limit exceeded; see Retry-After"},
+	}
+}
+
+// TimeoutResponses are returned when the request deadline passes or the client goes away.
+func TimeoutResponses() []Response {
+	return []Response{
+		{Status: http.StatusServiceUnavailable, Description: "Request was cancelled"},
+		{Status: http.StatusGatewayTimeout, Description: "Request timed out"},
+	}
+}
@@ -0,0 +1,104 @@
+package openapi
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type createRequest struct {
+	ID     int      `json:"id" validate:"required,gt=0"`
+	Email  string   `json:"email" validate:"required,email"`
+	Kind   string   `json:"kind,omitempty" validate:"oneof=personal business"`
+	Amount string   `json:"amount" validate:"required,numeric"`
+	Tags   []string `json:"tags" validate:"max=3,dive,min=1"`
+	Secret string   `json:"-"`
+}
+
+type item struct {
+	Name string `json:"name"`
+}
+
+func noop(echo.Context) error { return nil }
+
+func TestGenerate(t *testing.T) {
+	e := echo.New()
+	e.POST("/items", noop)
+	e.GET("/items/:id", noop)
+	e.POST("/items/import", noop)
+	ops := []Operation{
+		{
+			Method: http.MethodPost, Path: "/items", ID: "createItem", Scope: "items:write",
+			Request:   createRequest{},
+			Responses: []Response{{Status: http.StatusCreated}},
+		},
+		{
+			Method: http.MethodGet, Path: "/items/:id", ID: "getItem",
+			Parameters: []Parameter{{Name: "id", In: "path", Example: 0}},
+			Responses:  []Response{{Status: http.StatusOK, Data: item{}, MediaTypes: []string{"text/csv"}}},
+		},
+		{
+			Method: http.MethodPost, Path: "/items/import", ID: "importItems",
+			Request: createRequest{}, RequestMediaType: "text/csv",
+			Responses: []Response{{Status: http.StatusOK}, {Status: http.StatusAccepted, MediaTypes: []string{"application/xml"}}},
+		},
+	}
+
+	doc, err := Generate(Info{Title: "test", Version: "1"}, e.Routes(), ops)
+	require.NoError(t, err)
+
+	create := doc.Paths["/items"].Post
+	require.NotNil(t, create)
+	assert.Equal(t, "items:write", create.RequiredScope)
+	assert.Len(t, create.Security, 2)
+	assert.Equal(t, "Created", create.Responses["201"].Description)
+
+	get := doc.Paths["/items/{id}"].Get
+	require.NotNil(t, get, "echo path parameters become templates")
+	assert.True(t, get.Parameters[0].Required)
+	assert.Empty(t, get.Security, "operations without a scope are public")
+	envelope := get.Responses["200"].Content[echo.MIMEApplicationJSON].Schema
+	require.Len(t, envelope.AllOf, 2)
+	assert.Equal(t, "#/components/schemas/item", envelope.AllOf[1].Properties["data"].Ref)
+	assert.Equal(t, "binary", get.Responses["200"].Content["text/csv"].Schema.Format)
+
+	imp := doc.Paths["/items/import"].Post
+	require.NotNil(t, imp)
+	assert.Equal(t, "string", imp.RequestBody.Content["text/csv"].Schema.Type)
+	assert.NotContains(t, imp.RequestBody.Content, echo.MIMEApplicationJSON)
+	assert.Contains(t, imp.Responses["200"].Content, echo.MIMEApplicationJSON)
+	assert.Len(t, imp.Responses["202"].Content, 1, "responses without data are only sent as their media types")
+	assert.Contains(t, imp.Responses["202"].Content, "application/xml")
+
+	schema := doc.Components.Schemas["createRequest"]
+	require.NotNil(t, schema)
+	assert.ElementsMatch(t, []string{"id", "email", "amount"}, schema.Required)
+	assert.NotContains(t, schema.Properties, "Secret")
+	assert.Equal(t, 0.0, *schema.Properties["id"].ExclusiveMinimum)
+	assert.Equal(t, "email", schema.Properties["email"].Format)
+	assert.Equal(t, []any{"personal", "business"}, schema.Properties["kind"].Enum)
+	assert.NotEmpty(t, schema.Properties["amount"].Pattern)
+	assert.Equal(t, 3, *schema.Properties["tags"].MaxItems)
+	assert.Nil(t, schema.Properties["tags"].MinItems, "rules after dive apply to elements")
+}
+
+func TestGenerate_Coverage(t *testing.T) {
+	e := echo.New()
+	e.GET("/undocumented", noop)
+	ops := []Operation{{Method: http.MethodGet, Path: "/gone", ID: "gone"}}
+
+	_, err := Generate(Info{}, e.Routes(), ops)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "route GET /undocumented has no operation")
+	assert.Contains(t, err.Error(), "operation GET /gone has no route")
+
+	_, err = Generate(Info{}, nil, []Operation{ops[0], ops[0]})
+	assert.ErrorContains(t, err, "described twice")
+}
+
+func TestOpenAPIPath(t *testing.T) {
+	assert.Equal(t, "/api/v1/accounts/{id}/statements/{format}", openAPIPath("/api/v1/accounts/:id/statements/:format"))
+}
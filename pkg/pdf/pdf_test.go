@@ -0,0 +1,56 @@
+package pdf
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDocument_WriteTo(t *testing.T) {
+	doc := &Document{Title: "Statement (test)"}
+	first := doc.AddPage()
+	first.Text(50, 800, HelveticaBold, 18, "Account (1) \\ café")
+	first.Line(50, 790, 545, 790, 0.5)
+	doc.AddPage().TextRight(545, 800, Courier, 10, "12.50")
+
+	var buf bytes.Buffer
+	n, err := doc.WriteTo(&buf)
+	require.NoError(t, err)
+	out := buf.Bytes()
+	assert.Equal(t, int64(len(out)), n)
+	assert.True(t, bytes.HasPrefix(out, []byte("%PDF-1.4\n")))
+	assert.True(t, bytes.HasSuffix(out, []byte("%%EOF\n")))
+	assert.Contains(t, buf.String(), "/Count 2")
+	assert.Contains(t, buf.String(), `(Account \(1\) \\ caf\351) Tj`)
+	assert.Contains(t, buf.String(), "/Title (Statement \\(test\\))")
+	assert.Contains(t, buf.String(), "BT /F3 10 Tf 515 800 Td (12.50) Tj ET", "right aligned by the Courier widths")
+
+	// Every cross-reference entry points at the start of its object.
+	startxref := regexp.MustCompile(`startxref\n(\d+)\n`).FindSubmatch(out)
+	require.NotNil(t, startxref)
+	xref, _ := strconv.Atoi(string(startxref[1]))
+	require.True(t, bytes.HasPrefix(out[xref:], []byte("xref\n")))
+	entries := regexp.MustCompile(`(\d{10}) 00000 n `).FindAllSubmatch(out[xref:], -1)
+	require.Len(t, entries, 10)
+	for i, entry := range entries {
+		offset, _ := strconv.Atoi(string(entry[1]))
+		assert.True(t, bytes.HasPrefix(out[offset:], fmt.Appendf(nil, "%d 0 obj\n", i+1)), "object %d", i+1)
+	}
+
+	// Stream lengths match their contents.
+	for _, m := range regexp.MustCompile(`(?s)<< /Length (\d+) >>\nstream\n(.*?)\nendstream`).FindAllSubmatch(out, -1) {
+		length, _ := strconv.Atoi(string(m[1]))
+		assert.Len(t, m[2], length)
+	}
+}
+
+func TestWidth(t *testing.T) {
+	assert.Equal(t, 30.0, Width(Courier, 10, "abcde"))
+	assert.InDelta(t, 5.56*3, Width(Helvetica, 10, "100"), 1e-9)
+	assert.InDelta(t, Width(Helvetica, 10, "1.50"), Width(HelveticaBold, 10, "1.50"), 1e-9)
+}
@@ -0,0 +1,123 @@
+// Package signing produces and verifies HMAC signatures for service-to-service requests.
+//
+// A signature covers the request method, path, a unix timestamp, a random nonce and the
+// SHA-256 digest of the body, so a captured request cannot be altered or replayed later.
+package signing
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Headers carrying the signature and the values it covers.
+const (
+	HeaderClientID  = "X-Client-ID"
+	HeaderTimestamp = "X-Signature-Timestamp"
+	HeaderNonce     = "X-Signature-Nonce"
+	HeaderSignature = "X-Signature"
+)
+
+// BodyDigest returns the hex encoded SHA-256 digest of body.
+func BodyDigest(body []byte) string {
+	sum := sha256.Sum256(body)
+	return hex.EncodeToString(sum[:])
+}
+
+// CanonicalString builds the newline separated string that is signed.
+func CanonicalString(method, path, timestamp, nonce, bodyDigest string) string {
+	return strings.Join([]string{strings.ToUpper(method), path, timestamp, nonce, bodyDigest}, "\n")
+}
+
+// Compute returns the hex encoded HMAC-SHA256 of the canonical string.
+func Compute(secret, method, path, timestamp, nonce, bodyDigest string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(CanonicalString(method, path, timestamp, nonce, bodyDigest)))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify reports whether signature matches the given request parts, in constant time.
+func Verify(secret, signature, method, path, timestamp, nonce, bodyDigest string) bool {
+	expected := Compute(secret, method, path, timestamp, nonce, bodyDigest)
+	return hmac.Equal([]byte(expected), []byte(signature))
+}
+
+// Signer signs outgoing requests on behalf of a single client ID.
+type Signer struct {
+	ClientID string
+	Secret   string
+	// Now defaults to time.Now and is only overridden in tests.
+	Now func() time.Time
+}
+
+// NewSigner creates a Signer for clientID using its shared secret.
+func NewSigner(clientID, secret string) *Signer {
+	return &Signer{ClientID: clientID, Secret: secret, Now: time.Now}
+}
+
+// Sign reads the request body, restores it, and sets the signature headers on req.
+func (s *Signer) Sign(req *http.Request) error {
+	var body []byte
+	if req.Body != nil {
+		var err error
+		body, err = io.ReadAll(req.Body)
+		if err != nil {
+			return err
+		}
+		req.Body.Close()
+		req.Body = io.NopCloser(bytes.NewReader(body))
+		req.GetBody = func() (io.ReadCloser, error) {
+			return io.NopCloser(bytes.NewReader(body)), nil
+		}
+	}
+
+	nonce, err := newNonce()
+	if err != nil {
+		return err
+	}
+	now := time.Now
+	if s.Now != nil {
+		now = s.Now
+	}
+	timestamp := strconv.FormatInt(now().Unix(), 10)
+
+	req.Header.Set(HeaderClientID, s.ClientID)
+	req.Header.Set(HeaderTimestamp, timestamp)
+	req.Header.Set(HeaderNonce, nonce)
+	req.Header.Set(HeaderSignature, Compute(s.Secret, req.Method, req.URL.Path, timestamp, nonce, BodyDigest(body)))
+	return nil
+}
+
+// Transport is an http.RoundTripper that signs every request before sending it.
+type Transport struct {
+	Signer *Signer
+	Base   http.RoundTripper
+}
+
+// RoundTrip signs a clone of req and passes it to the base transport.
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	clone := req.Clone(req.Context())
+	if err := t.Signer.Sign(clone); err != nil {
+		return nil, err
+	}
+	base := t.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return base.RoundTrip(clone)
+}
+
+func newNonce() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
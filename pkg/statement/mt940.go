@@ -0,0 +1,170 @@
+package statement
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rohanchauhan02/internal-transfer/dto"
+	"github.com/shopspring/decimal"
+)
+
+// MT940 field limits.
+const (
+	mt940AmountLength  = 15
+	mt940LineLength    = 65
+	mt940InfoLines     = 6
+	mt940ReferenceSize = 16
+)
+
+// mt940UnknownCurrency is the ISO 4217 code used for accounts without a currency.
+const mt940UnknownCurrency = "XXX"
+
+// WriteMT940 writes the statement as a SWIFT MT940 customer statement message: the
+// :60F: opening balance, a :61: statement line and :86: information per entry, and the
+// :62F: closing balance. Only the text block is written, with CRLF line endings and a
+// closing "-" line, which is what reconciliation tools read from MT940 files.
+func WriteMT940(w io.Writer, s dto.Statement) error {
+	currency := s.Currency
+	if currency == "" {
+		currency = mt940UnknownCurrency
+	}
+	var b strings.Builder
+	line := func(format string, args ...any) {
+		b.WriteString(fmt.Sprintf(format, args...))
+		b.WriteString("\r\n")
+	}
+
+	opening, err := mt940Balance(s.OpeningBalance, s.From, currency)
+	if err != nil {
+		return fmt.Errorf("opening balance: %w", err)
+	}
+	// To is exclusive, so the closing balance is dated the last day the statement covers.
+	closing, err := mt940Balance(s.ClosingBalance, s.To.Add(-time.Nanosecond), currency)
+	if err != nil {
+		return fmt.Errorf("closing balance: %w", err)
+	}
+	line(":20:%s", mt940Text(fmt.Sprintf("%d-%s", s.AccountID, s.From.UTC().Format("060102")), mt940ReferenceSize))
+	line(":25:%d", s.AccountID)
+	line(":28C:1/1")
+	line(":60F:%s", opening)
+	for _, e := range s.Entries {
+		amount, err := mt940Amount(e.Amount)
+		if err != nil {
+			return fmt.Errorf("transaction %d: %w", e.TransactionID, err)
+		}
+		mark := "C"
+		if e.Direction == dto.StatementDebit {
+			mark = "D"
+		}
+		date := e.Date.UTC()
+		line(":61:%s%s%s%sNTRF%s//%d", date.Format("060102"), date.Format("0102"), mark, amount,
+			mt940Reference(e.Reference), e.TransactionID)
+		for i, text := range mt940Information(e) {
+			if i == 0 {
+				line(":86:%s", text)
+			} else {
+				line("%s", text)
+			}
+		}
+	}
+	line(":62F:%s", closing)
+	b.WriteString("-\r\n")
+	_, err = io.WriteString(w, b.String())
+	return err
+}
+
+// mt940Information is the :86: field of an entry: where the transfer came from or went to, its
+// description and its transaction ID, wrapped to at most six lines of 65 characters.
+func mt940Information(e dto.StatementEntry) []string {
+	text := Description(e)
+	if e.Description != "" {
+		text += " " + e.Description
+	}
+	return mt940Wrap(mt940Text(text+" TX "+strconv.FormatUint(uint64(e.TransactionID), 10), mt940LineLength*mt940InfoLines))
+}
+
+// mt940Wrap splits text into lines of 65 characters, at most six. A line starting with ":" or
+// "-" would be read as a new field or the end of the message, so such lines are indented.
+func mt940Wrap(text string) []string {
+	var lines []string
+	for len(lines) < mt940InfoLines {
+		if len(lines) > 0 && (text[0] == ':' || text[0] == '-') {
+			text = " " + text
+		}
+		n := min(len(text), mt940LineLength)
+		lines = append(lines, text[:n])
+		if text = text[n:]; text == "" {
+			break
+		}
+	}
+	return lines
+}
+
+// mt940Reference is the account owner's reference in a :61: line: the transfer's reference cut
+// to 16 characters, or NONREF without one. Slashes are dropped, since "//" starts the bank's
+// reference.
+func mt940Reference(reference string) string {
+	reference = strings.TrimSpace(strings.ReplaceAll(mt940Text(reference, len(reference)), "/", " "))
+	if len(reference) > mt940ReferenceSize {
+		reference = strings.TrimSpace(reference[:mt940ReferenceSize])
+	}
+	if reference == "" {
+		return "NONREF"
+	}
+	return reference
+}
+
+// mt940Balance formats a balance field: the credit or debit mark, the date, the currency and
+// the amount.
+func mt940Balance(value string, date time.Time, currency string) (string, error) {
+	d, err := decimal.NewFromString(value)
+	if err != nil {
+		return "", err
+	}
+	mark := "C"
+	if d.IsNegative() {
+		mark = "D"
+	}
+	amount, err := mt940Amount(d.Abs().String())
+	if err != nil {
+		return "", err
+	}
+	return mark + date.UTC().Format("060102") + currency + amount, nil
+}
+
+// mt940Amount formats a non-negative amount with a decimal comma, which MT940 always requires.
+func mt940Amount(value string) (string, error) {
+	d, err := decimal.NewFromString(value)
+	if err != nil {
+		return "", err
+	}
+	amount := strings.Replace(d.String(), ".", ",", 1)
+	if !strings.Contains(amount, ",") {
+		amount += ","
+	}
+	if len(amount) > mt940AmountLength {
+		return "", fmt.Errorf("amount %s is longer than %d characters", value, mt940AmountLength)
+	}
+	return amount, nil
+}
+
+// mt940Text keeps s to the SWIFT x character set, replacing other characters with spaces,
+// and cuts it to n characters.
+func mt940Text(s string, n int) string {
+	var b strings.Builder
+	for _, r := range s {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', strings.ContainsRune("/-?:().,'+ ", r):
+			b.WriteRune(r)
+		default:
+			b.WriteByte(' ')
+		}
+		if b.Len() == n {
+			break
+		}
+	}
+	return b.String()
+}
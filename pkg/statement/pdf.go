@@ -0,0 +1,131 @@
+package statement
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/rohanchauhan02/internal-transfer/dto"
+	"github.com/rohanchauhan02/internal-transfer/pkg/pdf"
+)
+
+// Page layout in points.
+const (
+	margin     = 50.0
+	top        = pdf.PageHeight - margin
+	bottom     = 70.0
+	right      = pdf.PageWidth - margin
+	rowHeight  = 14.0
+	fontSize   = 9.0
+	footerLine = 30.0
+)
+
+// Table columns: left edges of text columns and right edges of amount columns.
+const (
+	colDate        = margin
+	colID          = 165.0
+	colDescription = 215.0
+	colDebit       = 405.0
+	colCredit      = 475.0
+	colBalance     = right
+)
+
+// WritePDF writes the statement as an A4 PDF: a header with the account details and totals,
+// then a table of the entries with their running balances, continued over as many pages as
+// needed.
+func WritePDF(w io.Writer, s dto.Statement) error {
+	doc := &pdf.Document{Title: fmt.Sprintf("Statement of account %d", s.AccountID)}
+	page := doc.AddPage()
+	y := top
+
+	page.Text(margin, y, pdf.HelveticaBold, 18, "Account Statement")
+	page.TextRight(right, y, pdf.Helvetica, fontSize, "Generated "+formatDate(s.GeneratedAt)+" UTC")
+	y -= 30
+
+	currency := s.Currency
+	if currency == "" {
+		currency = "-"
+	}
+	credits, debits := 0, 0
+	for _, e := range s.Entries {
+		if e.Direction == dto.StatementDebit {
+			debits++
+		} else {
+			credits++
+		}
+	}
+	details := [][2]string{
+		{"Account", strconv.Itoa(s.AccountID)},
+		{"Currency", currency},
+		{"Period", formatDate(s.From) + " to " + formatDate(s.To) + " UTC"},
+	}
+	totals := [][2]string{
+		{"Opening balance", s.OpeningBalance},
+		{fmt.Sprintf("Total credits (%d)", credits), s.TotalCredits},
+		{fmt.Sprintf("Total debits (%d)", debits), s.TotalDebits},
+		{"Closing balance", s.ClosingBalance},
+	}
+	for i := 0; i < max(len(details), len(totals)); i++ {
+		if i < len(details) {
+			page.Text(margin, y, pdf.HelveticaBold, 10, details[i][0])
+			page.Text(margin+70, y, pdf.Helvetica, 10, details[i][1])
+		}
+		if i < len(totals) {
+			font := pdf.Helvetica
+			if i == len(totals)-1 {
+				font = pdf.HelveticaBold
+			}
+			page.Text(colDebit-60, y, font, 10, totals[i][0])
+			page.TextRight(right, y, font, 10, totals[i][1])
+		}
+		y -= rowHeight + 2
+	}
+	y -= 10
+
+	y = tableHeader(page, y)
+	row := func(font pdf.Font, date, id, description, debit, credit, balance string) {
+		if y < bottom {
+			page = doc.AddPage()
+			y = tableHeader(page, top)
+		}
+		page.Text(colDate, y, font, fontSize, date)
+		page.Text(colID, y, font, fontSize, id)
+		page.Text(colDescription, y, font, fontSize, description)
+		page.TextRight(colDebit, y, font, fontSize, debit)
+		page.TextRight(colCredit, y, font, fontSize, credit)
+		page.TextRight(colBalance, y, font, fontSize, balance)
+		y -= rowHeight
+	}
+	row(pdf.Helvetica, formatDate(s.From), "", "Opening balance", "", "", s.OpeningBalance)
+	for _, e := range s.Entries {
+		debit, credit := debitCredit(e)
+		row(pdf.Helvetica, formatDate(e.Date), strconv.FormatUint(uint64(e.TransactionID), 10), Description(e),
+			debit, credit, e.Balance)
+	}
+	if y < bottom {
+		page = doc.AddPage()
+		y = tableHeader(page, top)
+	}
+	page.Line(margin, y+rowHeight-3, right, y+rowHeight-3, 0.5)
+	row(pdf.HelveticaBold, formatDate(s.To), "", "Closing balance", s.TotalDebits, s.TotalCredits, s.ClosingBalance)
+
+	pages := doc.Pages()
+	for i, p := range pages {
+		p.Text(margin, footerLine, pdf.Helvetica, 8, fmt.Sprintf("Statement of account %d", s.AccountID))
+		p.TextRight(right, footerLine, pdf.Helvetica, 8, fmt.Sprintf("Page %d of %d", i+1, len(pages)))
+	}
+	_, err := doc.WriteTo(w)
+	return err
+}
+
+// tableHeader draws the column titles at y and returns the y of the first row.
+func tableHeader(page *pdf.Page, y float64) float64 {
+	page.Text(colDate, y, pdf.HelveticaBold, fontSize, "Date (UTC)")
+	page.Text(colID, y, pdf.HelveticaBold, fontSize, "ID")
+	page.Text(colDescription, y, pdf.HelveticaBold, fontSize, "Description")
+	page.TextRight(colDebit, y, pdf.HelveticaBold, fontSize, "Debit")
+	page.TextRight(colCredit, y, pdf.HelveticaBold, fontSize, "Credit")
+	page.TextRight(colBalance, y, pdf.HelveticaBold, fontSize, "Balance")
+	page.Line(margin, y-5, right, y-5, 0.75)
+	return y - rowHeight - 4
+}
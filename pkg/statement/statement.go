@@ -0,0 +1,132 @@
+// Package statement renders account statements as CSV, PDF, camt.053 and MT940 documents,
+// for the statement endpoint and the admin CLI alike.
+package statement
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/rohanchauhan02/internal-transfer/dto"
+	"github.com/rohanchauhan02/internal-transfer/pkg/iso20022"
+)
+
+// Document formats.
+const (
+	FormatJSON = "json"
+	FormatCSV  = "csv"
+	FormatPDF  = "pdf"
+	// FormatCamt053 is the ISO 20022 bank to customer statement.
+	FormatCamt053 = "camt053"
+	// FormatMT940 is the SWIFT customer statement message.
+	FormatMT940 = "mt940"
+)
+
+// Statement periods default to the last DefaultDays and span at most MaxDays.
+const (
+	DefaultDays = 30
+	MaxDays     = 366
+)
+
+// Formats lists the supported formats.
+var Formats = []string{FormatJSON, FormatCSV, FormatPDF, FormatCamt053, FormatMT940}
+
+// dateLayout formats entry dates in documents.
+const dateLayout = "2006-01-02 15:04:05"
+
+// ContentType returns the media type of format.
+func ContentType(format string) string {
+	switch format {
+	case FormatCSV:
+		return "text/csv"
+	case FormatPDF:
+		return "application/pdf"
+	case FormatCamt053:
+		return "application/xml"
+	case FormatMT940:
+		return "text/plain"
+	}
+	return "application/json"
+}
+
+// Filename returns a download name for the statement in format.
+func Filename(s dto.Statement, format string) string {
+	extension := format
+	switch format {
+	case FormatCamt053:
+		extension = "xml"
+	case FormatMT940:
+		extension = "sta"
+	}
+	return fmt.Sprintf("statement-%d-%s-%s.%s", s.AccountID, s.From.UTC().Format("20060102"),
+		s.To.UTC().Format("20060102"), extension)
+}
+
+// Write writes the statement as a document in format; JSON is left to the caller.
+func Write(w io.Writer, format string, s dto.Statement) error {
+	switch format {
+	case FormatCSV:
+		return WriteCSV(w, s)
+	case FormatPDF:
+		return WritePDF(w, s)
+	case FormatCamt053:
+		return iso20022.WriteCamt053(w, s)
+	case FormatMT940:
+		return WriteMT940(w, s)
+	}
+	return fmt.Errorf("unsupported statement format %q", format)
+}
+
+// ParseTime parses an RFC 3339 time or a date, taken as midnight UTC. A date used as
+// the end of a period includes its whole day.
+func ParseTime(raw string, end bool) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, raw); err == nil {
+		return t, nil
+	}
+	t, err := time.Parse(time.DateOnly, raw)
+	if err != nil {
+		return time.Time{}, errors.New("must be a date (2006-01-02) or an RFC 3339 time")
+	}
+	if end {
+		t = t.AddDate(0, 0, 1)
+	}
+	return t, nil
+}
+
+// Description describes an entry, e.g. "Transfer to account 2".
+func Description(e dto.StatementEntry) string {
+	if e.Direction == dto.StatementDebit {
+		return "Transfer to account " + strconv.Itoa(e.CounterpartyAccountID)
+	}
+	return "Transfer from account " + strconv.Itoa(e.CounterpartyAccountID)
+}
+
+// WriteCSV writes the statement as CSV: an opening balance row, a row per entry with its
+// running balance, and a closing balance row.
+func WriteCSV(w io.Writer, s dto.Statement) error {
+	cw := csv.NewWriter(w)
+	cw.Write([]string{"date", "transaction_id", "description", "debit", "credit", "balance"})
+	cw.Write([]string{formatDate(s.From), "", "Opening balance", "", "", s.OpeningBalance})
+	for _, e := range s.Entries {
+		debit, credit := debitCredit(e)
+		cw.Write([]string{formatDate(e.Date), strconv.FormatUint(uint64(e.TransactionID), 10), Description(e), debit, credit, e.Balance})
+	}
+	cw.Write([]string{formatDate(s.To), "", "Closing balance", s.TotalDebits, s.TotalCredits, s.ClosingBalance})
+	cw.Flush()
+	return cw.Error()
+}
+
+// debitCredit returns the entry's amount in the debit or the credit column.
+func debitCredit(e dto.StatementEntry) (debit, credit string) {
+	if e.Direction == dto.StatementDebit {
+		return e.Amount, ""
+	}
+	return "", e.Amount
+}
+
+func formatDate(t time.Time) string {
+	return t.UTC().Format(dateLayout)
+}
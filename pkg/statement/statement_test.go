@@ -0,0 +1,141 @@
+package statement
+
+import (
+	"bytes"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rohanchauhan02/internal-transfer/dto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var start = time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+
+func testStatement(entries int) dto.Statement {
+	s := dto.Statement{
+		AccountID:      1,
+		Currency:       "USD",
+		From:           start,
+		To:             start.AddDate(0, 0, 31),
+		OpeningBalance: "100",
+		TotalDebits:    "0",
+		TotalCredits:   "0",
+		ClosingBalance: "100",
+		GeneratedAt:    start.AddDate(0, 1, 0),
+	}
+	for i := 0; i < entries; i++ {
+		s.Entries = append(s.Entries, dto.StatementEntry{
+			TransactionID:         uint(i + 1),
+			Date:                  start.Add(time.Duration(i+1) * time.Minute),
+			CounterpartyAccountID: 2,
+			Direction:             dto.StatementCredit,
+			Amount:                "1",
+			Balance:               strconv.Itoa(101 + i),
+		})
+	}
+	return s
+}
+
+func TestWriteCSV(t *testing.T) {
+	s := testStatement(1)
+	s.Entries = append(s.Entries, dto.StatementEntry{
+		TransactionID: 2, Date: start.Add(time.Hour), CounterpartyAccountID: 3,
+		Direction: dto.StatementDebit, Amount: "0.5", Balance: "100.5",
+		Reference: "INV/2026/10/000123", Description: "October rent",
+	})
+	s.TotalCredits, s.TotalDebits, s.ClosingBalance = "1", "0.5", "100.5"
+
+	var buf bytes.Buffer
+	require.NoError(t, WriteCSV(&buf, s))
+	assert.Equal(t, "date,transaction_id,description,debit,credit,balance\n"+
+		"2026-10-01 00:00:00,,Opening balance,,,100\n"+
+		"2026-10-01 00:01:00,1,Transfer from account 2,,1,101\n"+
+		"2026-10-01 01:00:00,2,Transfer to account 3,0.5,,100.5\n"+
+		"2026-11-01 00:00:00,,Closing balance,0.5,1,100.5\n", buf.String())
+}
+
+func TestWritePDF(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, WritePDF(&buf, testStatement(120)))
+	out := buf.String()
+	assert.True(t, bytes.HasPrefix(buf.Bytes(), []byte("%PDF-1.4")))
+	assert.Contains(t, out, "(Account Statement) Tj")
+	assert.Contains(t, out, "(2026-10-01 00:00:00 to 2026-11-01 00:00:00 UTC) Tj")
+	assert.Contains(t, out, "(Total credits \\(120\\)) Tj")
+	assert.Contains(t, out, "(Closing balance) Tj")
+	assert.Contains(t, out, "/Count 3", "entries continue over more pages")
+	assert.Contains(t, out, "(Page 3 of 3) Tj")
+}
+
+func TestParseTime(t *testing.T) {
+	from, err := ParseTime("2026-10-01", false)
+	require.NoError(t, err)
+	assert.Equal(t, start, from)
+	to, err := ParseTime("2026-10-31", true)
+	require.NoError(t, err)
+	assert.Equal(t, start.AddDate(0, 0, 31), to, "an end date includes its day")
+	exact, err := ParseTime("2026-10-01T12:30:00+02:00", true)
+	require.NoError(t, err)
+	assert.Equal(t, start.Add(10*time.Hour+30*time.Minute), exact.UTC())
+	_, err = ParseTime("01/10/2026", false)
+	assert.Error(t, err)
+}
+
+func TestWriteMT940(t *testing.T) {
+	s := testStatement(1)
+	s.Entries = append(s.Entries, dto.StatementEntry{
+		TransactionID: 2, Date: start.AddDate(0, 0, 2), CounterpartyAccountID: 3,
+		Direction: dto.StatementDebit, Amount: "0.5", Balance: "100.5",
+		Reference: "INV/2026/10/000123", Description: "October rent",
+	})
+	s.TotalCredits, s.TotalDebits, s.ClosingBalance = "1", "0.5", "100.5"
+
+	var buf bytes.Buffer
+	require.NoError(t, WriteMT940(&buf, s))
+	assert.Equal(t, ":20:1-261001\r\n"+
+		":25:1\r\n"+
+		":28C:1/1\r\n"+
+		":60F:C261001USD100,\r\n"+
+		":61:2610011001C1,NTRFNONREF//1\r\n"+
+		":86:Transfer from account 2 TX 1\r\n"+
+		":61:2610031003D0,5NTRFINV 2026 10 0001//2\r\n"+
+		":86:Transfer to account 3 October rent TX 2\r\n"+
+		":62F:C261031USD100,5\r\n"+
+		"-\r\n", buf.String())
+}
+
+func TestWriteMT940Balances(t *testing.T) {
+	s := testStatement(0)
+	s.Currency = ""
+	s.OpeningBalance, s.ClosingBalance = "-12.345", "0"
+	var buf bytes.Buffer
+	require.NoError(t, WriteMT940(&buf, s))
+	assert.Contains(t, buf.String(), ":60F:D261001XXX12,345\r\n")
+	assert.Contains(t, buf.String(), ":62F:C261031XXX0,\r\n")
+
+	s.OpeningBalance = "1234567890123456"
+	assert.Error(t, WriteMT940(&bytes.Buffer{}, s))
+}
+
+func TestMT940Information(t *testing.T) {
+	assert.Equal(t, "Transfer from account 2 TX 9", mt940Text("Transfer from account 2 TX 9", 390))
+	assert.Equal(t, "caf  50 ", mt940Text("café 50%", 390))
+	assert.Equal(t, "abc", mt940Text("abcdef", 3))
+
+	long := strings.Repeat("x", 64) + ":62F:" + strings.Repeat("y", 60) + "-"
+	assert.Equal(t, []string{strings.Repeat("x", 64) + ":", "62F:" + strings.Repeat("y", 60) + "-"}, mt940Wrap(long))
+	lines := mt940Wrap(strings.Repeat("x", 65) + ":62F:" + strings.Repeat("y", 60) + "-")
+	assert.Equal(t, []string{strings.Repeat("x", 65), " :62F:" + strings.Repeat("y", 59), "y-"}, lines,
+		"continuation lines never start with a colon or a hyphen")
+	assert.Len(t, mt940Wrap(strings.Repeat("-", 390)), 6)
+}
+
+func TestMT940Reference(t *testing.T) {
+	assert.Equal(t, "NONREF", mt940Reference(""))
+	assert.Equal(t, "E2E-0001", mt940Reference("E2E-0001"))
+	assert.Equal(t, "ABCDEFGHIJKLMNOP", mt940Reference("ABCDEFGHIJKLMNOPQRST"))
+	assert.Equal(t, "NONREF", mt940Reference("//"))
+}